@@ -2,4 +2,8 @@ module blog-system/common
 
 go 1.24.2
 
-require github.com/golang-jwt/jwt/v4 v4.5.2
+require (
+	github.com/golang-jwt/jwt/v4 v4.5.2
+	golang.org/x/sync v0.16.0
+	gopkg.in/yaml.v2 v2.4.0
+)
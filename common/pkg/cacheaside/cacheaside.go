@@ -0,0 +1,188 @@
+// Package cacheaside 旁路缓存：singleflight 合并回源、空值缓存、TTL 抖动与命名空间版本号
+package cacheaside
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrNotFound 数据不存在（含命中空值缓存）
+var ErrNotFound = errors.New("cacheaside: not found")
+
+// nilMarker 空值占位
+const nilMarker = "\x00nil"
+
+// Store 缓存存储，go-framework 的 cache.Cache 满足该接口
+type Store interface {
+	Get(ctx context.Context, k string) (any, error)
+	Set(ctx context.Context, k string, v any, expire time.Duration) error
+	Del(ctx context.Context, k string) error
+}
+
+// Cache 旁路缓存
+type Cache struct {
+	store       Store
+	group       singleflight.Group
+	prefix      string
+	ttl         time.Duration
+	negativeTTL time.Duration
+	jitter      float64
+	isNotFound  func(error) bool
+}
+
+// Option 配置项
+type Option func(c *Cache)
+
+// WithPrefix 所有键的前缀，如 user:
+func WithPrefix(prefix string) Option {
+	return func(c *Cache) { c.prefix = prefix }
+}
+
+// WithTTL 正常数据过期时间
+func WithTTL(ttl time.Duration) Option {
+	return func(c *Cache) { c.ttl = ttl }
+}
+
+// WithNegativeTTL 空值缓存过期时间，0 表示不缓存空值
+func WithNegativeTTL(ttl time.Duration) Option {
+	return func(c *Cache) { c.negativeTTL = ttl }
+}
+
+// WithJitter TTL 随机抖动比例（0~1），避免同批键同时过期
+func WithJitter(ratio float64) Option {
+	return func(c *Cache) { c.jitter = ratio }
+}
+
+// WithNotFound 判断回源错误是否表示“数据不存在”，命中时写入空值缓存
+func WithNotFound(fn func(error) bool) Option {
+	return func(c *Cache) { c.isNotFound = fn }
+}
+
+// New 创建旁路缓存，store 为空时退化为直接回源
+func New(store Store, opts ...Option) *Cache {
+	c := &Cache{
+		store:       store,
+		ttl:         30 * time.Minute,
+		negativeTTL: time.Minute,
+		jitter:      0.1,
+		isNotFound:  func(err error) bool { return errors.Is(err, ErrNotFound) },
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Key 拼接键：prefix + parts 以冒号连接
+func (c *Cache) Key(parts ...string) string {
+	k := c.prefix
+	for i, p := range parts {
+		if i > 0 {
+			k += ":"
+		}
+		k += p
+	}
+	return k
+}
+
+// Version 读取命名空间版本号，不存在时为 0
+func (c *Cache) Version(ctx context.Context, ns string) string {
+	if c.store == nil {
+		return "0"
+	}
+	v, err := c.store.Get(ctx, c.Key("ver", ns))
+	if err != nil {
+		return "0"
+	}
+	return string(toBytes(v))
+}
+
+// VersionedKey 带命名空间版本号的键，Bump 后旧键全部失效
+func (c *Cache) VersionedKey(ctx context.Context, ns string, parts ...string) string {
+	return c.Key(append([]string{ns, "v" + c.Version(ctx, ns)}, parts...)...)
+}
+
+// Bump 递增命名空间版本号（以纳秒时间戳代替自增，无需存储支持 INCR）
+func (c *Cache) Bump(ctx context.Context, ns string) error {
+	if c.store == nil {
+		return nil
+	}
+	return c.store.Set(ctx, c.Key("ver", ns), strconv.FormatInt(time.Now().UnixNano(), 10), 0)
+}
+
+// Del 删除键
+func (c *Cache) Del(ctx context.Context, keys ...string) {
+	if c.store == nil {
+		return
+	}
+	for _, k := range keys {
+		_ = c.store.Del(ctx, k)
+	}
+}
+
+// Get 读取 key，未命中时经 singleflight 调用 load 回源并回写缓存；
+// load 返回“不存在”错误时写入空值缓存，之后的读取直接返回 ErrNotFound
+func Get[T any](ctx context.Context, c *Cache, key string, load func(ctx context.Context) (T, error)) (T, error) {
+	var zero T
+	if c.store == nil {
+		return load(ctx)
+	}
+	if v, err := c.store.Get(ctx, key); err == nil {
+		raw := toBytes(v)
+		if string(raw) == nilMarker {
+			return zero, ErrNotFound
+		}
+		var out T
+		if err := json.Unmarshal(raw, &out); err == nil {
+			return out, nil
+		}
+		// 缓存内容损坏，删除后回源
+		_ = c.store.Del(ctx, key)
+	}
+	res, err, _ := c.group.Do(key, func() (any, error) {
+		// 合并后的回源由所有等待者共享，不随首个调用方取消而失败
+		ctx := context.WithoutCancel(ctx)
+		val, err := load(ctx)
+		if err != nil {
+			if c.negativeTTL > 0 && c.isNotFound(err) {
+				_ = c.store.Set(ctx, key, nilMarker, c.negativeTTL)
+				return nil, ErrNotFound
+			}
+			return nil, err
+		}
+		if data, er := json.Marshal(val); er == nil {
+			_ = c.store.Set(ctx, key, string(data), c.jittered(c.ttl))
+		}
+		return val, nil
+	})
+	if err != nil {
+		return zero, err
+	}
+	return res.(T), nil
+}
+
+// jittered 在 ttl 基础上增加 [0, ttl*jitter) 的随机时长
+func (c *Cache) jittered(ttl time.Duration) time.Duration {
+	if c.jitter <= 0 || ttl <= 0 {
+		return ttl
+	}
+	return ttl + time.Duration(rand.Int63n(int64(float64(ttl)*c.jitter)+1))
+}
+
+// toBytes 兼容本地缓存与 Redis 缓存返回的值类型
+func toBytes(v any) []byte {
+	switch t := v.(type) {
+	case string:
+		return []byte(t)
+	case []byte:
+		return t
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,151 @@
+package cacheaside
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// mapStore 内存 Store（忽略过期时间）
+type mapStore struct {
+	mu sync.Mutex
+	m  map[string]any
+}
+
+func newMapStore() *mapStore { return &mapStore{m: make(map[string]any)} }
+
+func (s *mapStore) Get(_ context.Context, k string) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if v, ok := s.m[k]; ok {
+		return v, nil
+	}
+	return nil, errors.New("miss")
+}
+
+func (s *mapStore) Set(_ context.Context, k string, v any, _ time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.m[k] = v
+	return nil
+}
+
+func (s *mapStore) Del(_ context.Context, k string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.m, k)
+	return nil
+}
+
+func TestGetMergesConcurrentLoads(t *testing.T) {
+	c := New(newMapStore())
+	var calls atomic.Int32
+	started, release := make(chan struct{}), make(chan struct{})
+	load := func(ctx context.Context) (string, error) {
+		if calls.Add(1) == 1 {
+			close(started)
+		}
+		<-release
+		// 回源不受首个调用方取消的影响
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		return "v", nil
+	}
+
+	first, cancel := context.WithCancel(context.Background())
+	const n = 8
+	results := make(chan error, n)
+	go func() {
+		_, err := Get(first, c, "k", load)
+		results <- err
+	}()
+	<-started
+	for i := 1; i < n; i++ {
+		go func() {
+			v, err := Get(context.Background(), c, "k", load)
+			if err == nil && v != "v" {
+				err = errors.New("unexpected value " + v)
+			}
+			results <- err
+		}()
+	}
+	time.Sleep(50 * time.Millisecond) // 等待其余调用方加入同一次回源
+	cancel()
+	close(release)
+	for i := 0; i < n; i++ {
+		if err := <-results; err != nil {
+			t.Fatalf("Get: %v", err)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("load called %d times, want 1", got)
+	}
+	// 回写后直接命中缓存
+	if v, err := Get(context.Background(), c, "k", load); err != nil || v != "v" || calls.Load() != 1 {
+		t.Fatalf("cached Get = %q, %v, calls = %d", v, err, calls.Load())
+	}
+}
+
+func TestGetNegativeCache(t *testing.T) {
+	tests := []struct {
+		name        string
+		negativeTTL time.Duration
+		wantCalls   int32
+	}{
+		{"cached", time.Minute, 1},
+		{"disabled", 0, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := New(newMapStore(), WithNegativeTTL(tt.negativeTTL))
+			var calls atomic.Int32
+			load := func(context.Context) (int, error) {
+				calls.Add(1)
+				return 0, ErrNotFound
+			}
+			for i := 0; i < 2; i++ {
+				if _, err := Get(context.Background(), c, "missing", load); !errors.Is(err, ErrNotFound) {
+					t.Fatalf("Get #%d: err = %v, want ErrNotFound", i, err)
+				}
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Fatalf("load called %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestVersionedKeyBump(t *testing.T) {
+	c := New(newMapStore(), WithPrefix("test:"))
+	ctx := context.Background()
+	var calls atomic.Int32
+	load := func(context.Context) (int32, error) { return calls.Add(1), nil }
+	get := func() int32 {
+		v, err := Get(ctx, c, c.VersionedKey(ctx, "post", "list"), load)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+
+	if v := get(); v != 1 {
+		t.Fatalf("first Get = %d, want 1", v)
+	}
+	if v := get(); v != 1 {
+		t.Fatalf("cached Get = %d, want 1", v)
+	}
+	// 递增版本号后旧键不再使用，重新回源
+	if err := c.Bump(ctx, "post"); err != nil {
+		t.Fatal(err)
+	}
+	if v := get(); v != 2 {
+		t.Fatalf("Get after Bump = %d, want 2", v)
+	}
+	if v := get(); v != 2 {
+		t.Fatalf("cached Get after Bump = %d, want 2", v)
+	}
+}
@@ -47,6 +47,20 @@ type CircuitBreakerConfig struct {
 	RecoveryTimeout  string `yaml:"recovery_timeout"`
 }
 
+// OAuthProviderConfig OAuth2 / OIDC login provider settings
+type OAuthProviderConfig struct {
+	Name         string   `yaml:"name"`
+	Kind         string   `yaml:"kind"` // github | oidc
+	ClientID     string   `yaml:"client_id"`
+	ClientSecret string   `yaml:"client_secret"`
+	RedirectURL  string   `yaml:"redirect_url"`
+	AuthURL      string   `yaml:"auth_url"`
+	TokenURL     string   `yaml:"token_url"`
+	UserInfoURL  string   `yaml:"userinfo_url"`
+	EmailsURL    string   `yaml:"emails_url"` // github only
+	Scopes       []string `yaml:"scopes"`
+}
+
 // AppConfig is the unified configuration for all services
 type AppConfig struct {
 	App struct {
@@ -76,7 +90,56 @@ type AppConfig struct {
 	Prometheus     struct {
 		Address string `yaml:"address"`
 	} `yaml:"prometheus"`
-	Log logger.Config `yaml:"log"`
+	Log   logger.Config `yaml:"log"`
+	OAuth struct {
+		Providers []OAuthProviderConfig `yaml:"providers"`
+	} `yaml:"oauth"`
+	Scheduler struct {
+		Interval string `yaml:"interval"` // 定时发布轮询间隔，如 30s
+	} `yaml:"scheduler"`
+	Search struct {
+		SyncInterval string `yaml:"sync_interval"` // 内存索引增量同步间隔（多副本收敛时间），如 30s
+	} `yaml:"search"`
+	Revision struct {
+		Retain int `yaml:"retain"` // 每篇文章保留的历史版本数，默认 50
+	} `yaml:"revision"`
+	Comment struct {
+		RateLimit   int    `yaml:"rate_limit"`   // 每个用户在窗口内可发表的评论数，默认 5
+		RateWindow  string `yaml:"rate_window"`  // 限流窗口，如 1m
+		AutoApprove bool   `yaml:"auto_approve"` // 新评论免审核直接公开
+	} `yaml:"comment"`
+	Feed struct {
+		Title       string `yaml:"title"`        // 站点名称
+		Description string `yaml:"description"`  // 站点描述
+		Link        string `yaml:"link"`         // 站点首页地址，文章链接为 link/article/<slug>
+		Limit       int    `yaml:"limit"`        // 每个订阅源的文章数，默认 20
+		FullContent bool   `yaml:"full_content"` // 条目附带全文 HTML，否则仅摘要
+	} `yaml:"feed"`
+	Sitemap struct {
+		BaseURL string `yaml:"base_url"` // sitemap 文件的对外访问前缀，默认 feed.link + /api/content
+	} `yaml:"sitemap"`
+	Media struct {
+		Storage      string   `yaml:"storage"`       // local 或 s3
+		LocalDir     string   `yaml:"local_dir"`     // 本地存储根目录
+		PublicURL    string   `yaml:"public_url"`    // 文件对外访问前缀，默认 feed.link + /api/content/media/file
+		MaxSize      int64    `yaml:"max_size"`      // 单文件大小上限（字节），默认 10MB
+		AllowedTypes []string `yaml:"allowed_types"` // 允许的类型（按内容嗅探），默认常见图片格式
+		Variants     []int    `yaml:"variants"`      // 图片缩放副本宽度
+		MaxPixels    int64    `yaml:"max_pixels"`    // 图片像素数（宽×高）上限，默认 4000 万
+		RateLimit    int      `yaml:"rate_limit"`    // 每个用户在窗口内可上传的文件数，默认 30
+		RateWindow   string   `yaml:"rate_window"`   // 上传限流窗口，默认 1h
+		S3           struct {
+			Endpoint  string `yaml:"endpoint"`
+			Region    string `yaml:"region"`
+			Bucket    string `yaml:"bucket"`
+			AccessKey string `yaml:"access_key"`
+			SecretKey string `yaml:"secret_key"`
+			PathStyle bool   `yaml:"path_style"` // MinIO 等 S3 兼容服务通常需开启
+		} `yaml:"s3"`
+	} `yaml:"media"`
+	Import struct {
+		MaxSize int64 `yaml:"max_size"` // 管理端上传的导入压缩包大小上限（字节），默认 30MB，不应超过网关请求体上限
+	} `yaml:"import"`
 }
 
 // ResolvePath tries typical locations for service config
@@ -127,4 +190,11 @@ func applyEnvOverrides(cfg *AppConfig) {
 			cfg.Redis.Cluster.Password = envVal
 		}
 	}
+	for i := range cfg.OAuth.Providers {
+		if secret := cfg.OAuth.Providers[i].ClientSecret; secret != "" {
+			if envVal := os.Getenv(secret); envVal != "" {
+				cfg.OAuth.Providers[i].ClientSecret = envVal
+			}
+		}
+	}
 }
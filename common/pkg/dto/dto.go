@@ -14,6 +14,14 @@ type PageResponse[T any] struct {
 	PageSize int   `json:"page_size"`
 }
 
+// CursorResponse 游标分页响应：NextCursor 为空表示没有下一页
+type CursorResponse[T any] struct {
+	List       []T    `json:"list"`
+	NextCursor string `json:"next_cursor"`
+	HasMore    bool   `json:"has_more"`
+	PageSize   int    `json:"page_size"`
+}
+
 // Response 通用响应
 type Response[T any] struct {
 	Code    int    `json:"code"`
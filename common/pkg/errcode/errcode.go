@@ -9,6 +9,7 @@ const (
 	ErrNotFound
 	ErrForbidden
 	ErrConflict
+	ErrTooManyRequests
 )
 
 // 管理服务错误码
@@ -29,6 +30,8 @@ const (
 	ErrPasswordInvalid
 	ErrTokenInvalid
 	ErrTokenExpired
+	ErrTwoFactorRequired
+	ErrTwoFactorInvalid
 )
 
 // 内容服务错误码
@@ -36,17 +39,22 @@ const (
 	ErrArticleNotFound = 50001 + iota
 	ErrTagNotFound
 	ErrCategoryNotFound
+	ErrCommentNotFound
+	ErrMediaNotFound
+	ErrMediaTooLarge
+	ErrMediaType
 )
 
 // ErrorMessage 错误码对应的消息
 var ErrorMessage = map[int]string{
-	OK:              "成功",
-	ErrInternal:     "内部服务器错误",
-	ErrParam:        "参数错误",
-	ErrUnauthorized: "未授权",
-	ErrNotFound:     "资源不存在",
-	ErrForbidden:    "禁止访问",
-	ErrConflict:     "资源冲突",
+	OK:                 "成功",
+	ErrInternal:        "内部服务器错误",
+	ErrParam:           "参数错误",
+	ErrUnauthorized:    "未授权",
+	ErrNotFound:        "资源不存在",
+	ErrForbidden:       "禁止访问",
+	ErrConflict:        "资源冲突",
+	ErrTooManyRequests: "请求过于频繁",
 
 	ErrAdminForbidden: "管理员权限不足",
 	ErrAdminNotFound:  "管理员不存在",
@@ -59,9 +67,16 @@ var ErrorMessage = map[int]string{
 	ErrTokenInvalid:    "令牌无效",
 	ErrTokenExpired:    "令牌已过期",
 
+	ErrTwoFactorRequired: "需要两步验证",
+	ErrTwoFactorInvalid:  "两步验证码错误",
+
 	ErrArticleNotFound:  "文章不存在",
 	ErrTagNotFound:      "标签不存在",
 	ErrCategoryNotFound: "分类不存在",
+	ErrCommentNotFound:  "评论不存在",
+	ErrMediaNotFound:    "文件不存在",
+	ErrMediaTooLarge:    "文件过大",
+	ErrMediaType:        "不支持的文件类型",
 }
 
 // GetMessage 获取错误消息
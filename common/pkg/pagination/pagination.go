@@ -0,0 +1,73 @@
+// Package pagination 分页参数规范化（统一每页条数上限）与键集分页的不透明游标
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// ErrInvalidCursor 游标无法解析
+var ErrInvalidCursor = errors.New("分页游标不合法")
+
+// Normalize 页码至少为 1；每页条数非正时取 DefaultPageSize，超过 MaxPageSize 时取 MaxPageSize
+func Normalize(page, pageSize int) (int, int) {
+	return max(page, 1), Size(pageSize, DefaultPageSize)
+}
+
+// Size 每页条数：非正时取 def，超过 MaxPageSize 时取 MaxPageSize
+func Size(pageSize, def int) int {
+	if pageSize <= 0 {
+		pageSize = def
+	}
+	return min(pageSize, MaxPageSize)
+}
+
+// FromQuery 解析查询参数 page、page_size（非法值按缺省处理）并规范化
+func FromQuery(q url.Values) (int, int) {
+	page, _ := strconv.Atoi(q.Get("page"))
+	pageSize, _ := strconv.Atoi(q.Get("page_size"))
+	return Normalize(page, pageSize)
+}
+
+// Cursor 键集分页位置：上一页最后一条记录的排序键。
+// 文章列表按 (Top, Time, ID) 降序，全文检索按 (Score, Time, ID) 降序；Time 为 nil 表示未发布（排在最后）
+type Cursor struct {
+	Top   bool       `json:"t,omitempty"`
+	Score float64    `json:"s,omitempty"`
+	Time  *time.Time `json:"p,omitempty"`
+	ID    int64      `json:"i"`
+}
+
+// Encode 编码为 URL 安全的不透明字符串
+func (c *Cursor) Encode() string {
+	if c == nil {
+		return ""
+	}
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// Decode 解析游标，空串表示从第一页开始（返回 nil）
+func Decode(s string) (*Cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	c := &Cursor{}
+	if err := json.Unmarshal(b, c); err != nil || c.ID <= 0 {
+		return nil, ErrInvalidCursor
+	}
+	return c, nil
+}
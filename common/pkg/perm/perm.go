@@ -0,0 +1,58 @@
+package perm
+
+// 权限码，格式为 资源:动作[:范围]
+const (
+	// ArticleCreate 创建文章（草稿）
+	ArticleCreate = "article:create"
+	// ArticlePublish 发布文章（status=1）
+	ArticlePublish = "article:publish"
+	// ArticleEdit 编辑任意文章
+	ArticleEdit = "article:edit"
+	// ArticleEditOwn 仅编辑自己的文章
+	ArticleEditOwn = "article:edit:own"
+	// ArticleDelete 删除文章
+	ArticleDelete = "article:delete"
+	// CategoryManage 管理分类
+	CategoryManage = "category:manage"
+	// TagManage 管理标签
+	TagManage = "tag:manage"
+	// UserManage 管理用户
+	UserManage = "user:manage"
+	// StatView 查看统计
+	StatView = "stat:view"
+	// SearchManage 维护全文索引
+	SearchManage = "search:manage"
+	// CommentModerate 审核与删除评论
+	CommentModerate = "comment:moderate"
+	// MediaUpload 上传文件（文章配图、封面与头像）
+	MediaUpload = "media:upload"
+	// MediaManage 管理上传的文件（列表、孤立文件、删除）
+	MediaManage = "media:manage"
+	// ContentImport 从 Markdown 文件批量导入文章（自动创建标签与分类）
+	ContentImport = "content:import"
+	// SiteBackup 整站导出与恢复（可包含用户）
+	SiteBackup = "site:backup"
+
+	// All 通配权限，拥有全部权限
+	All = "*"
+)
+
+// Has 判断权限集合中是否包含指定权限（支持通配 *）
+func Has(perms []string, p string) bool {
+	for _, v := range perms {
+		if v == p || v == All {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAny 判断是否包含任一权限
+func HasAny(perms []string, ps ...string) bool {
+	for _, p := range ps {
+		if Has(perms, p) {
+			return true
+		}
+	}
+	return false
+}
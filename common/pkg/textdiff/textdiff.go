@@ -0,0 +1,260 @@
+// Package textdiff 文本差异：Myers 算法，支持按行输出 unified diff 与按词输出片段序列
+package textdiff
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Op 编辑操作
+type Op string
+
+const (
+	Equal  Op = "equal"
+	Insert Op = "insert"
+	Delete Op = "delete"
+)
+
+// Segment 词级差异片段，相邻同类操作已合并
+type Segment struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// maxEditDistance 编辑距离上限：超出后剩余部分按整体删除+插入处理，避免超大文本耗尽内存
+const maxEditDistance = 2000
+
+// edit 单个元素的编辑操作
+type edit struct {
+	op   Op
+	text string
+}
+
+// Unified 按行比较，输出 unified diff（context 为每个变更块保留的上下文行数）；无差异时返回空串
+func Unified(fromName, toName, a, b string, context int) string {
+	al, bl := splitLines(a), splitLines(b)
+	es := diff(al, bl)
+	// 变更下标
+	var changed []int
+	for i, e := range es {
+		if e.op != Equal {
+			changed = append(changed, i)
+		}
+	}
+	if len(changed) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", fromName, toName)
+	// aLine/bLine 为 es[i] 之前已消耗的行数
+	aLine := make([]int, len(es)+1)
+	bLine := make([]int, len(es)+1)
+	for i, e := range es {
+		aLine[i+1], bLine[i+1] = aLine[i], bLine[i]
+		if e.op != Insert {
+			aLine[i+1]++
+		}
+		if e.op != Delete {
+			bLine[i+1]++
+		}
+	}
+	for i := 0; i < len(changed); {
+		start := max(changed[i]-context, 0)
+		end := changed[i] + 1
+		j := i + 1
+		// 两个变更间隔不超过 2*context 时合并为一个块
+		for j < len(changed) && changed[j]-end <= 2*context {
+			end = changed[j] + 1
+			j++
+		}
+		end = min(end+context, len(es))
+		aLen, bLen := aLine[end]-aLine[start], bLine[end]-bLine[start]
+		fmt.Fprintf(&sb, "@@ -%s +%s @@\n", hunkRange(aLine[start], aLen), hunkRange(bLine[start], bLen))
+		for _, e := range es[start:end] {
+			switch e.op {
+			case Equal:
+				sb.WriteByte(' ')
+			case Delete:
+				sb.WriteByte('-')
+			case Insert:
+				sb.WriteByte('+')
+			}
+			sb.WriteString(e.text)
+			sb.WriteByte('\n')
+		}
+		i = j
+	}
+	return sb.String()
+}
+
+// hunkRange 块范围：起始行从 1 计数，空范围的起始行为其前一行
+func hunkRange(before, n int) string {
+	switch n {
+	case 0:
+		return fmt.Sprintf("%d,0", before)
+	case 1:
+		return fmt.Sprintf("%d", before+1)
+	default:
+		return fmt.Sprintf("%d,%d", before+1, n)
+	}
+}
+
+// Words 按词比较：中日韩文字逐字切分，字母数字按连续片段，空白与标点单独成词
+func Words(a, b string) []Segment {
+	out := make([]Segment, 0)
+	for _, e := range diff(splitWords(a), splitWords(b)) {
+		if n := len(out); n > 0 && out[n-1].Op == e.op {
+			out[n-1].Text += e.text
+			continue
+		}
+		out = append(out, Segment{Op: e.op, Text: e.text})
+	}
+	return out
+}
+
+// splitLines 按换行切分，末尾换行不产生空行
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+func splitWords(s string) []string {
+	var out []string
+	start, kind := 0, 0 // kind: 1 字母数字 2 空白
+	flush := func(end int) {
+		if end > start {
+			out = append(out, s[start:end])
+		}
+		start = end
+	}
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		k := 0
+		switch {
+		case unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r):
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_':
+			k = 1
+		case unicode.IsSpace(r):
+			k = 2
+		}
+		if k == 0 || k != kind {
+			flush(i)
+		}
+		kind = k
+		i += size
+	}
+	flush(len(s))
+	return out
+}
+
+// diff 先去除公共前后缀，再对中间部分执行 Myers 算法
+func diff(a, b []string) []edit {
+	pre := 0
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	suf := 0
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+	out := make([]edit, 0, len(a)+len(b))
+	for _, s := range a[:pre] {
+		out = append(out, edit{Equal, s})
+	}
+	out = append(out, myers(a[pre:len(a)-suf], b[pre:len(b)-suf])...)
+	for _, s := range a[len(a)-suf:] {
+		out = append(out, edit{Equal, s})
+	}
+	return out
+}
+
+// myers 最短编辑脚本（删除先于插入）；编辑距离超过上限时退化为整体替换
+func myers(a, b []string) []edit {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		return replaceAll(a, b)
+	}
+	limit := min(n+m, maxEditDistance)
+	off := limit + 1
+	v := make([]int, 2*off+1)
+	// trace[d] 为第 d 轮开始前 v 在 [-d, d] 上的快照
+	var trace [][]int
+	for d := 0; d <= limit; d++ {
+		snap := make([]int, 2*d+1)
+		copy(snap, v[off-d:off+d+1])
+		trace = append(trace, snap)
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace)
+			}
+		}
+	}
+	return replaceAll(a, b)
+}
+
+func backtrack(a, b []string, trace [][]int) []edit {
+	x, y := len(a), len(b)
+	var rev []edit
+	for d := len(trace) - 1; d > 0; d-- {
+		v := trace[d]
+		at := func(k int) int { return v[k+d] }
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := at(prevK)
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			rev = append(rev, edit{Equal, a[x]})
+		}
+		if x == prevX {
+			y--
+			rev = append(rev, edit{Insert, b[y]})
+		} else {
+			x--
+			rev = append(rev, edit{Delete, a[x]})
+		}
+	}
+	for x > 0 && y > 0 {
+		x--
+		y--
+		rev = append(rev, edit{Equal, a[x]})
+	}
+	out := make([]edit, len(rev))
+	for i, e := range rev {
+		out[len(rev)-1-i] = e
+	}
+	return out
+}
+
+func replaceAll(a, b []string) []edit {
+	out := make([]edit, 0, len(a)+len(b))
+	for _, s := range a {
+		out = append(out, edit{Delete, s})
+	}
+	for _, s := range b {
+		out = append(out, edit{Insert, s})
+	}
+	return out
+}
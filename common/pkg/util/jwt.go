@@ -1,29 +1,51 @@
 package util
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"time"
 
+	"blog-system/common/pkg/perm"
+
 	"github.com/golang-jwt/jwt/v4"
 )
 
 // jwtKey 固定秘钥 正常应使用环境变量
 var jwtKey = []byte("coucou-mon-echo-0721")
 
+// PurposeTwoFactor 两步验证挑战令牌用途
+const PurposeTwoFactor = "2fa_challenge"
+
+// TokenTTL 访问令牌有效期
+const TokenTTL = 24 * time.Hour
+
+// ChallengeTTL 两步验证挑战令牌有效期
+const ChallengeTTL = 5 * time.Minute
+
 // Claims JWT 声明
 type Claims struct {
-	UserID int64  `json:"user_id"`
-	Role   string `json:"role"`
+	UserID      int64    `json:"user_id"`
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions,omitempty"`
+	// Purpose 非空表示受限用途令牌（如两步验证挑战），不能用于访问接口
+	Purpose string `json:"purpose,omitempty"`
 	jwt.RegisteredClaims
 }
 
-// GenerateToken 生成 JWT 令牌
-func GenerateToken(userID int64, role string) (string, error) {
+// HasPermission 判断令牌是否携带指定权限
+func (c *Claims) HasPermission(p string) bool {
+	return perm.Has(c.Permissions, p)
+}
+
+// GenerateToken 生成 JWT 令牌（携带角色权限）
+func GenerateToken(userID int64, role string, permissions []string) (string, error) {
 	claims := &Claims{
-		UserID: userID,
-		Role:   role,
+		UserID:      userID,
+		Role:        role,
+		Permissions: permissions,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(TokenTTL)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 		},
@@ -32,8 +54,52 @@ func GenerateToken(userID int64, role string) (string, error) {
 	return token.SignedString(jwtKey)
 }
 
-// ParseToken 解析 JWT 令牌
+// GenerateChallengeToken 生成两步验证挑战令牌（ChallengeTTL 内有效），同时返回随机令牌 ID，供调用方按令牌限制尝试次数
+func GenerateChallengeToken(userID int64) (string, string, error) {
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", err
+	}
+	id := hex.EncodeToString(raw)
+	claims := &Claims{
+		UserID:  userID,
+		Purpose: PurposeTwoFactor,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        id,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ChallengeTTL)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			NotBefore: jwt.NewNumericDate(time.Now()),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtKey)
+	return token, id, err
+}
+
+// ParseChallengeToken 解析两步验证挑战令牌
+func ParseChallengeToken(tokenString string) (*Claims, error) {
+	claims, err := parseClaims(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != PurposeTwoFactor {
+		return nil, errors.New("invalid challenge token")
+	}
+	return claims, nil
+}
+
+// ParseToken 解析 JWT 令牌（拒绝受限用途令牌）
 func ParseToken(tokenString string) (*Claims, error) {
+	claims, err := parseClaims(tokenString)
+	if err != nil {
+		return nil, err
+	}
+	if claims.Purpose != "" {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+func parseClaims(tokenString string) (*Claims, error) {
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
 		return jwtKey, nil
 	})
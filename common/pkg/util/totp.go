@@ -0,0 +1,90 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// TOTP 参数（RFC 6238，与 Google Authenticator 默认值一致）
+const (
+	totpPeriod = 30
+	totpDigits = 6
+	totpSkew   = 1 // 允许前后各 1 个时间窗口的时钟偏差
+)
+
+var b32 = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// GenerateTOTPSecret 生成 160 位随机密钥（Base32 无填充）
+func GenerateTOTPSecret() (string, error) {
+	buf := make([]byte, 20)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return b32.EncodeToString(buf), nil
+}
+
+// TOTPURI 生成 otpauth:// URI，供认证器扫码
+func TOTPURI(issuer, account, secret string) string {
+	v := url.Values{}
+	v.Set("secret", secret)
+	v.Set("issuer", issuer)
+	v.Set("algorithm", "SHA1")
+	v.Set("digits", fmt.Sprint(totpDigits))
+	v.Set("period", fmt.Sprint(totpPeriod))
+	label := url.PathEscape(issuer + ":" + account)
+	return "otpauth://totp/" + label + "?" + v.Encode()
+}
+
+// TOTPCode 计算指定时间的动态码
+func TOTPCode(secret string, t time.Time) (string, error) {
+	key, err := b32.DecodeString(strings.ToUpper(strings.TrimSpace(secret)))
+	if err != nil {
+		return "", err
+	}
+	return hotp(key, uint64(t.Unix()/totpPeriod)), nil
+}
+
+// ValidateTOTP 校验动态码，容忍 totpSkew 个窗口的偏差
+func ValidateTOTP(secret, code string, t time.Time) bool {
+	_, ok := MatchTOTP(secret, code, t)
+	return ok
+}
+
+// MatchTOTP 校验动态码并返回匹配的时间窗口计数，调用方据此拒绝重放（只接受大于上次计数的动态码）
+func MatchTOTP(secret, code string, t time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != totpDigits {
+		return 0, false
+	}
+	key, err := b32.DecodeString(strings.ToUpper(strings.TrimSpace(secret)))
+	if err != nil {
+		return 0, false
+	}
+	counter := t.Unix() / totpPeriod
+	for i := -totpSkew; i <= totpSkew; i++ {
+		if subtle.ConstantTimeCompare([]byte(hotp(key, uint64(counter+int64(i)))), []byte(code)) == 1 {
+			return counter + int64(i), true
+		}
+	}
+	return 0, false
+}
+
+// hotp RFC 4226 HMAC-SHA1 动态截断
+func hotp(key []byte, counter uint64) string {
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(sha1.New, key)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	bin := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	return fmt.Sprintf("%0*d", totpDigits, bin%1000000)
+}
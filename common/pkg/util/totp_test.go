@@ -0,0 +1,78 @@
+package util
+
+import (
+	"testing"
+	"time"
+)
+
+// rfcSecret RFC 4226 / RFC 6238 测试向量使用的 SHA-1 密钥
+var rfcSecret = []byte("12345678901234567890")
+
+func TestHOTP(t *testing.T) {
+	// RFC 4226 附录 D
+	want := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, code := range want {
+		if got := hotp(rfcSecret, uint64(counter)); got != code {
+			t.Errorf("hotp(counter=%d) = %s, want %s", counter, got, code)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	secret := b32.EncodeToString(rfcSecret)
+	// RFC 6238 附录 B（SHA-1，取 8 位结果的后 6 位）
+	tests := []struct {
+		unix int64
+		code string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+		{20000000000, "353130"},
+	}
+	for _, tt := range tests {
+		now := time.Unix(tt.unix, 0)
+		if got, err := TOTPCode(secret, now); err != nil || got != tt.code {
+			t.Errorf("TOTPCode(%d) = %s, %v, want %s", tt.unix, got, err, tt.code)
+		}
+		if !ValidateTOTP(secret, tt.code, now) {
+			t.Errorf("ValidateTOTP(%d, %s) = false", tt.unix, tt.code)
+		}
+		// 允许前后各一个窗口的时钟偏差，超出即拒绝
+		if !ValidateTOTP(secret, tt.code, now.Add(totpPeriod*time.Second)) {
+			t.Errorf("ValidateTOTP(%d+30s) = false", tt.unix)
+		}
+		if ValidateTOTP(secret, tt.code, now.Add(3*totpPeriod*time.Second)) {
+			t.Errorf("ValidateTOTP(%d+90s) = true", tt.unix)
+		}
+	}
+}
+
+func TestValidateTOTPMalformed(t *testing.T) {
+	secret := b32.EncodeToString(rfcSecret)
+	now := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870820", "abcdef"} {
+		if ValidateTOTP(secret, code, now) {
+			t.Errorf("ValidateTOTP(%q) = true", code)
+		}
+	}
+	if ValidateTOTP("not base32!", "287082", now) {
+		t.Error("ValidateTOTP with invalid secret = true")
+	}
+}
+
+func TestMatchTOTPCounter(t *testing.T) {
+	secret := b32.EncodeToString(rfcSecret)
+	now := time.Unix(1111111111, 0)
+	counter, ok := MatchTOTP(secret, "050471", now)
+	if !ok || counter != 1111111111/totpPeriod {
+		t.Fatalf("MatchTOTP = %d, %t", counter, ok)
+	}
+	// 前一个窗口的动态码匹配到更小的计数，调用方据此拒绝重放
+	prev, ok := MatchTOTP(secret, "050471", now.Add(totpPeriod*time.Second))
+	if !ok || prev != counter {
+		t.Fatalf("MatchTOTP(next window) = %d, %t, want %d", prev, ok, counter)
+	}
+}
@@ -3,25 +3,39 @@ CREATE DATABASE IF NOT EXISTS blog_system CHARACTER SET utf8mb4 COLLATE utf8mb4_
 USE blog_system;
 
 -- 删除旧表（注意外键依赖顺序）
+DROP TABLE IF EXISTS blog_media;
+DROP TABLE IF EXISTS blog_comment;
+DROP TABLE IF EXISTS blog_slug_redirect;
+DROP TABLE IF EXISTS blog_article_revision;
 DROP TABLE IF EXISTS blog_article_tags;
 DROP TABLE IF EXISTS blog_article;
 DROP TABLE IF EXISTS blog_tag;
 DROP TABLE IF EXISTS blog_category;
 DROP TABLE IF EXISTS blog_stat;
+DROP TABLE IF EXISTS blog_role_permission;
+DROP TABLE IF EXISTS blog_permission;
+DROP TABLE IF EXISTS blog_role;
+DROP TABLE IF EXISTS blog_user_identity;
+DROP TABLE IF EXISTS blog_user_recovery_code;
 DROP TABLE IF EXISTS blog_user;
 
 -- 用户表
 CREATE TABLE IF NOT EXISTS blog_user
 (
-    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
-    username   VARCHAR(50)  NOT NULL UNIQUE,
-    email      VARCHAR(100) NOT NULL UNIQUE,
-    password   VARCHAR(255) NOT NULL,
-    role       VARCHAR(20)  NOT NULL DEFAULT 'user' COMMENT 'user/admin',
-    avatar     VARCHAR(255),
-    status     TINYINT      NOT NULL DEFAULT 0 COMMENT '0: 正常, 1: 禁用',
-    created_at TIMESTAMP             DEFAULT CURRENT_TIMESTAMP,
-    updated_at TIMESTAMP             DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    id           BIGINT AUTO_INCREMENT PRIMARY KEY,
+    username     VARCHAR(50)  NOT NULL UNIQUE,
+    email        VARCHAR(100) NOT NULL UNIQUE,
+    password     VARCHAR(255) NOT NULL,
+    role         VARCHAR(20)  NOT NULL DEFAULT 'user' COMMENT '角色编码，见 blog_role.code',
+    avatar       VARCHAR(255),
+    status       TINYINT      NOT NULL DEFAULT 0 COMMENT '0: 正常, 1: 禁用',
+    totp_secret  VARCHAR(64) COMMENT '两步验证密钥（Base32）',
+    totp_enabled TINYINT(1)   NOT NULL DEFAULT 0 COMMENT '是否已启用两步验证',
+    totp_last_counter BIGINT  NOT NULL DEFAULT 0 COMMENT '最近通过校验的TOTP时间窗口计数（防重放）',
+    totp_failures     INT     NOT NULL DEFAULT 0 COMMENT '两步验证连续失败次数',
+    totp_locked_until TIMESTAMP NULL COMMENT '两步验证锁定截止时间',
+    created_at   TIMESTAMP             DEFAULT CURRENT_TIMESTAMP,
+    updated_at   TIMESTAMP             DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     INDEX idx_username (username),
     INDEX idx_email (email),
     INDEX idx_status (status)
@@ -29,7 +43,78 @@ CREATE TABLE IF NOT EXISTS blog_user
   DEFAULT CHARSET = utf8mb4
   COLLATE = utf8mb4_unicode_ci;
 
--- 单级分类表
+-- 两步验证恢复码表（仅存哈希，使用后标记）
+CREATE TABLE IF NOT EXISTS blog_user_recovery_code
+(
+    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id    BIGINT      NOT NULL,
+    code_hash  CHAR(64)    NOT NULL COMMENT 'SHA-256(小写去连字符的恢复码)',
+    used_at    TIMESTAMP   NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_user_id (user_id),
+    FOREIGN KEY (user_id) REFERENCES blog_user (id) ON DELETE CASCADE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+-- 第三方登录身份表（OAuth2 / OIDC）
+CREATE TABLE IF NOT EXISTS blog_user_identity
+(
+    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id    BIGINT       NOT NULL,
+    provider   VARCHAR(32)  NOT NULL COMMENT '提供方名称，如 github',
+    subject    VARCHAR(255) NOT NULL COMMENT '提供方侧用户唯一标识',
+    email      VARCHAR(100) COMMENT '绑定时提供方返回的已验证邮箱',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_provider_subject (provider, subject),
+    INDEX idx_user_id (user_id),
+    FOREIGN KEY (user_id) REFERENCES blog_user (id) ON DELETE CASCADE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+-- 角色表（blog_user.role 存角色编码）
+CREATE TABLE IF NOT EXISTS blog_role
+(
+    id          BIGINT AUTO_INCREMENT PRIMARY KEY,
+    code        VARCHAR(20)  NOT NULL UNIQUE COMMENT 'admin/editor/author/user',
+    name        VARCHAR(50)  NOT NULL,
+    description VARCHAR(255),
+    require_two_factor TINYINT(1) NOT NULL DEFAULT 0 COMMENT '该角色用户是否必须启用两步验证',
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+-- 权限表
+CREATE TABLE IF NOT EXISTS blog_permission
+(
+    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    code       VARCHAR(50) NOT NULL UNIQUE COMMENT '资源:动作[:范围]，* 表示全部',
+    name       VARCHAR(50) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+-- 角色权限关联表
+CREATE TABLE IF NOT EXISTS blog_role_permission
+(
+    id            BIGINT AUTO_INCREMENT PRIMARY KEY,
+    role_id       BIGINT NOT NULL,
+    permission_id BIGINT NOT NULL,
+    created_at    TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_role_permission (role_id, permission_id),
+    INDEX idx_permission_id (permission_id),
+    FOREIGN KEY (role_id) REFERENCES blog_role (id) ON DELETE CASCADE,
+    FOREIGN KEY (permission_id) REFERENCES blog_permission (id) ON DELETE CASCADE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+-- 分类表（树形：parent_id 指向上级，path 为物化路径）
 CREATE TABLE IF NOT EXISTS blog_category
 (
     id          BIGINT AUTO_INCREMENT PRIMARY KEY,
@@ -37,10 +122,14 @@ CREATE TABLE IF NOT EXISTS blog_category
     slug        VARCHAR(100) NOT NULL UNIQUE,
     description TEXT,
     sort        INT       DEFAULT 0,
+    parent_id   BIGINT       NOT NULL DEFAULT 0 COMMENT '上级分类，0 为顶级',
+    path        VARCHAR(255) NOT NULL DEFAULT '' COMMENT '物化路径，形如 /1/5/12/',
     created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
     updated_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     INDEX idx_slug (slug),
-    INDEX idx_sort (sort)
+    INDEX idx_sort (sort),
+    INDEX idx_parent_id (parent_id),
+    INDEX idx_path (path)
 ) ENGINE = InnoDB
   DEFAULT CHARSET = utf8mb4
   COLLATE = utf8mb4_unicode_ci;
@@ -70,7 +159,7 @@ CREATE TABLE IF NOT EXISTS blog_article
     cover         VARCHAR(500),
     author_id     BIGINT       NOT NULL,
     category_id   BIGINT       NOT NULL,
-    status        TINYINT      NOT NULL DEFAULT 0 COMMENT '0: 草稿, 1: 发布, 2: 私密',
+    status        TINYINT      NOT NULL DEFAULT 0 COMMENT '0: 草稿, 1: 发布, 2: 私密, 3: 定时发布',
     view_count    BIGINT                DEFAULT 0,
     like_count    BIGINT                DEFAULT 0,
     is_top        BOOLEAN               DEFAULT FALSE,
@@ -79,6 +168,7 @@ CREATE TABLE IF NOT EXISTS blog_article
     meta_desc     VARCHAR(500),
     meta_keywords VARCHAR(200),
     published_at  TIMESTAMP    NULL,
+    version       BIGINT       NOT NULL DEFAULT 1 COMMENT '乐观锁版本号，每次更新递增',
     created_at    TIMESTAMP             DEFAULT CURRENT_TIMESTAMP,
     updated_at    TIMESTAMP             DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     INDEX idx_slug (slug),
@@ -86,8 +176,8 @@ CREATE TABLE IF NOT EXISTS blog_article
     INDEX idx_category_id (category_id),
     INDEX idx_status (status),
     INDEX idx_published_at (published_at),
-    FOREIGN KEY (author_id) REFERENCES blog_user (id) ON DELETE CASCADE,
-    FOREIGN KEY (category_id) REFERENCES blog_category (id) ON DELETE CASCADE
+    FOREIGN KEY (author_id) REFERENCES blog_user (id) ON DELETE RESTRICT,
+    FOREIGN KEY (category_id) REFERENCES blog_category (id) ON DELETE RESTRICT
 ) ENGINE = InnoDB
   DEFAULT CHARSET = utf8mb4
   COLLATE = utf8mb4_unicode_ci;
@@ -108,6 +198,83 @@ CREATE TABLE IF NOT EXISTS blog_article_tags
   DEFAULT CHARSET = utf8mb4
   COLLATE = utf8mb4_unicode_ci;
 
+-- 文章历史版本表（每次修改前保存旧内容）
+CREATE TABLE IF NOT EXISTS blog_article_revision
+(
+    id          BIGINT AUTO_INCREMENT PRIMARY KEY,
+    article_id  BIGINT       NOT NULL,
+    title       VARCHAR(200) NOT NULL,
+    slug        VARCHAR(200) NOT NULL,
+    content     LONGTEXT     NOT NULL,
+    summary     TEXT,
+    cover       VARCHAR(500),
+    category_id BIGINT       NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_article_id (article_id, id),
+    FOREIGN KEY (article_id) REFERENCES blog_article (id) ON DELETE CASCADE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+-- 旧别名表（文章/分类/标签别名变更后，旧链接 301 重定向到当前别名）
+CREATE TABLE IF NOT EXISTS blog_slug_redirect
+(
+    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    kind       VARCHAR(20)  NOT NULL COMMENT '对象类型: article, category, tag',
+    slug       VARCHAR(200) NOT NULL COMMENT '旧别名',
+    target_id  BIGINT       NOT NULL COMMENT '对象ID',
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_kind_slug (kind, slug),
+    INDEX idx_target (kind, target_id)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+-- 评论表（root_id 为所在楼层的顶层评论，顶层评论为 0；deleted_at 非空表示已软删除）
+CREATE TABLE IF NOT EXISTS blog_comment
+(
+    id         BIGINT AUTO_INCREMENT PRIMARY KEY,
+    article_id BIGINT        NOT NULL,
+    parent_id  BIGINT        NOT NULL DEFAULT 0 COMMENT '回复的评论ID',
+    root_id    BIGINT        NOT NULL DEFAULT 0 COMMENT '楼层顶层评论ID',
+    user_id    BIGINT        NOT NULL,
+    content    VARCHAR(4000) NOT NULL,
+    status     TINYINT       NOT NULL DEFAULT 0 COMMENT '0: 待审核, 1: 已通过, 2: 垃圾',
+    ip         VARCHAR(64)   NOT NULL DEFAULT '',
+    deleted_at TIMESTAMP     NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_article_root (article_id, root_id, status),
+    INDEX idx_root (root_id),
+    INDEX idx_status (status, id),
+    INDEX idx_user (user_id),
+    FOREIGN KEY (article_id) REFERENCES blog_article (id) ON DELETE CASCADE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
+-- 上传文件表（按内容 SHA-256 去重；variants 为缩放副本的 JSON 数组）
+CREATE TABLE IF NOT EXISTS blog_media
+(
+    id          BIGINT AUTO_INCREMENT PRIMARY KEY,
+    hash        CHAR(64)     NOT NULL COMMENT '内容 SHA-256',
+    storage_key VARCHAR(255) NOT NULL,
+    url         VARCHAR(500) NOT NULL,
+    name        VARCHAR(255) NOT NULL DEFAULT '' COMMENT '原始文件名',
+    mime_type   VARCHAR(100) NOT NULL,
+    size        BIGINT       NOT NULL,
+    width       INT          NOT NULL DEFAULT 0,
+    height      INT          NOT NULL DEFAULT 0,
+    variants    TEXT         NOT NULL,
+    uploader_id BIGINT       NOT NULL,
+    created_at  TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uk_hash (hash),
+    INDEX idx_uploader (uploader_id),
+    INDEX idx_created (created_at)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_unicode_ci;
+
 -- 统计表
 CREATE TABLE IF NOT EXISTS blog_stat
 (
@@ -131,11 +298,50 @@ CREATE TABLE IF NOT EXISTS blog_stat
 INSERT INTO blog_user (username, email, password, role, status)
 VALUES ('admin', 'admin@example.com', '$2a$10$92IXUNpkjO0rOQ5byMi.Ye4oKoEa3Ro9llC/.og/at2.uheWG/igi', 'admin', 0);
 
+INSERT INTO blog_role (code, name, description)
+VALUES ('admin', '管理员', '拥有全部权限'),
+       ('editor', '编辑', '管理全部文章、分类与标签'),
+       ('author', '作者', '撰写并发布自己的文章'),
+       ('user', '读者', '普通注册用户');
+
+INSERT INTO blog_permission (code, name)
+VALUES ('*', '全部权限'),
+       ('article:create', '创建文章'),
+       ('article:publish', '发布文章'),
+       ('article:edit', '编辑任意文章'),
+       ('article:edit:own', '编辑自己的文章'),
+       ('article:delete', '删除文章'),
+       ('category:manage', '管理分类'),
+       ('tag:manage', '管理标签'),
+       ('user:manage', '管理用户'),
+       ('stat:view', '查看统计'),
+       ('search:manage', '维护全文索引'),
+       ('comment:moderate', '审核评论'),
+       ('media:upload', '上传文件'),
+       ('media:manage', '管理文件'),
+       ('content:import', '导入文章'),
+       ('site:backup', '整站备份与恢复');
+
+INSERT INTO blog_role_permission (role_id, permission_id)
+SELECT r.id, p.id
+FROM blog_role r
+         JOIN blog_permission p ON
+    (r.code = 'admin' AND p.code = '*')
+        OR (r.code = 'editor' AND p.code IN ('article:create', 'article:publish', 'article:edit', 'article:delete',
+                                             'category:manage', 'tag:manage', 'stat:view', 'search:manage',
+                                             'comment:moderate', 'media:upload', 'media:manage',
+                                             'content:import'))
+        OR (r.code = 'author' AND p.code IN ('article:create', 'article:publish', 'article:edit:own', 'media:upload'));
+
 INSERT INTO blog_category (name, slug, description, sort)
 VALUES ('技术', 'tech', '技术相关文章', 1),
        ('生活', 'life', '生活随笔', 2),
        ('随笔', 'essay', '个人随笔', 3);
 
+UPDATE blog_category
+SET path = CONCAT('/', id, '/')
+WHERE parent_id = 0;
+
 INSERT INTO blog_tag (name, slug, color)
 VALUES ('Go', 'go', '#00ADD8'),
        ('微服务', 'microservice', '#FF6B6B'),
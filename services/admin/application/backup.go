@@ -0,0 +1,248 @@
+package application
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"time"
+
+	"blog-system/common/pkg/logger"
+	"blog-system/common/pkg/pagination"
+	"blog-system/services/admin/domain"
+)
+
+// ExportSite 整站导出：content-service 生成文章、分类、标签与文件，withUsers 时追加不含密码的用户并更新清单。
+// 归档超过 maxSize（恢复时的上传上限）时返回 ErrBackupTooLarge，避免导出无法恢复的备份
+func (s *AdminService) ExportSite(ctx context.Context, withUsers, withMedia bool, maxSize int64) ([]byte, error) {
+	data, err := s.Content.ExportSite(ctx, withMedia, maxSize)
+	if errors.Is(err, domain.ErrBackupTooLarge) {
+		return nil, err
+	}
+	if err != nil {
+		logger.Log().Error("application: 导出站点失败: %v", err)
+		return nil, err
+	}
+	if !withUsers {
+		return data, nil
+	}
+	users, err := s.allUsers(ctx)
+	if err != nil {
+		logger.Log().Error("application: 导出用户失败: %v", err)
+		return nil, err
+	}
+	out, err := appendUsers(data, users)
+	if err != nil {
+		logger.Log().Error("application: 写入用户失败: %v", err)
+		return nil, err
+	}
+	if int64(len(out)) > maxSize {
+		return nil, domain.ErrBackupTooLarge
+	}
+	logger.Log().Info("application: 导出站点: users=%d media=%t size=%d", len(users), withMedia, len(out))
+	return out, nil
+}
+
+// allUsers 分页读取全部用户
+func (s *AdminService) allUsers(ctx context.Context) ([]*domain.User, error) {
+	var out []*domain.User
+	for page := 1; ; page++ {
+		list, total, err := s.Users.List(ctx, domain.UserFilter{}, page, pagination.MaxPageSize)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, list...)
+		if len(list) < pagination.MaxPageSize || int64(len(out)) >= total {
+			return out, nil
+		}
+	}
+}
+
+// appendUsers 复制归档中的文件（不重新压缩），写入 users.json，清单中记录用户数
+func appendUsers(data []byte, users []*domain.User) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	var manifest domain.BackupManifest
+	if err := readZipJSON(zr, domain.BackupManifestFile, &manifest); err != nil {
+		return nil, err
+	}
+	manifest.Users = len(users)
+	list := make([]*domain.BackupUser, 0, len(users))
+	for _, u := range users {
+		list = append(list, &domain.BackupUser{
+			ID: u.ID, Username: u.Username, Email: u.Email, Role: u.Role, Avatar: u.Avatar, Status: u.Status, CreatedAt: u.CreatedAt,
+		})
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		if f.Name == domain.BackupManifestFile || f.Name == domain.BackupUsersFile {
+			continue
+		}
+		if err := zw.Copy(f); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range []struct {
+		name string
+		v    any
+	}{{domain.BackupUsersFile, list}, {domain.BackupManifestFile, &manifest}} {
+		w, err := zw.Create(f.name)
+		if err != nil {
+			return nil, err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(f.v); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RestoreSite 整站恢复：校验格式版本后按用户名或邮箱匹配已有用户，withUsers 时以随机密码、普通用户角色创建缺失的用户
+// （需管理员强制重置密码后才能登录，调用方须有 user:manage）；未能对应到用户的文章作者取 adminID。
+// 文章、分类、标签与文件由 content-service 恢复
+func (s *AdminService) RestoreSite(ctx context.Context, data []byte, withUsers bool, adminID int64) (*domain.RestoreReport, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, domain.ErrImportArchive
+	}
+	root, manifest, err := backupRoot(zr)
+	if err != nil {
+		return nil, err
+	}
+	var users []*domain.BackupUser
+	if err := readZipJSON(root, domain.BackupUsersFile, &users); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, domain.ErrBackupData
+	}
+	report := &domain.RestoreReport{SchemaVersion: manifest.SchemaVersion}
+	authors, errs, err := s.restoreUsers(ctx, users, withUsers, &report.Users)
+	if err != nil {
+		logger.Log().Error("application: 恢复用户失败: %v", err)
+		return nil, err
+	}
+	r, err := s.Content.RestoreSite(ctx, data, authors, adminID)
+	if err != nil {
+		logger.Log().Error("application: 恢复站点失败: %v", err)
+		return nil, err
+	}
+	r.Users = report.Users
+	r.Errors = append(errs, r.Errors...)
+	logger.Log().Info("application: 恢复站点: version=%d users=%d/%d/%d articles=%d/%d/%d",
+		r.SchemaVersion, r.Users.Created, r.Users.Existing, r.Users.Failed, r.Articles.Created, r.Articles.Existing, r.Articles.Failed)
+	return r, nil
+}
+
+// restoreUsers 返回导出环境用户 ID 到当前环境的映射；create 为 false 时只匹配已有用户
+func (s *AdminService) restoreUsers(ctx context.Context, users []*domain.BackupUser, create bool, count *domain.RestoreCount) (map[int64]int64, []string, error) {
+	authors := make(map[int64]int64, len(users))
+	errs := make([]string, 0)
+	if len(users) == 0 {
+		return authors, errs, nil
+	}
+	existing, err := s.allUsers(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	byName := make(map[string]int64, len(existing))
+	byEmail := make(map[string]int64, len(existing))
+	for _, u := range existing {
+		byName[strings.ToLower(u.Username)] = u.ID
+		if u.Email != "" {
+			byEmail[strings.ToLower(u.Email)] = u.ID
+		}
+	}
+	for _, b := range users {
+		if id, ok := byName[strings.ToLower(b.Username)]; ok {
+			authors[b.ID] = id
+			count.Existing++
+			continue
+		}
+		if id, ok := byEmail[strings.ToLower(b.Email)]; ok && b.Email != "" {
+			authors[b.ID] = id
+			count.Existing++
+			continue
+		}
+		if !create {
+			continue
+		}
+		u := &domain.User{Username: b.Username, Email: b.Email, Password: randomPassword(), Role: domain.RestoredUserRole, Avatar: b.Avatar, Status: b.Status}
+		u.CreatedAt, u.UpdatedAt = time.Now(), time.Now()
+		if err := s.Users.Create(ctx, u); err != nil {
+			count.Failed++
+			errs = append(errs, "用户 "+b.Username+": "+err.Error())
+			logger.Log().Error("application: 恢复用户失败: %s err=%v", b.Username, err)
+			continue
+		}
+		authors[b.ID] = u.ID
+		byName[strings.ToLower(u.Username)] = u.ID
+		count.Created++
+	}
+	return authors, errs, nil
+}
+
+// backupRoot 定位归档根目录（根目录或唯一的顶层目录）并校验清单版本
+func backupRoot(zr *zip.Reader) (fs.FS, *domain.BackupManifest, error) {
+	var root fs.FS = zr
+	var manifest domain.BackupManifest
+	err := readZipJSON(root, domain.BackupManifestFile, &manifest)
+	if errors.Is(err, fs.ErrNotExist) {
+		if entries, _ := fs.ReadDir(zr, "."); len(entries) == 1 && entries[0].IsDir() {
+			if sub, e := fs.Sub(zr, entries[0].Name()); e == nil {
+				root = sub
+				err = readZipJSON(root, domain.BackupManifestFile, &manifest)
+			}
+		}
+	}
+	if err != nil {
+		return nil, nil, domain.ErrBackupManifest
+	}
+	if manifest.SchemaVersion < 1 || manifest.SchemaVersion > domain.BackupSchemaVersion {
+		return nil, nil, domain.ErrBackupVersion
+	}
+	return root, &manifest, nil
+}
+
+// readZipJSON 读取归档中的 JSON 文件：按声明的解压大小预先拒绝，读取时再以 LimitReader 兜底
+func readZipJSON(src fs.FS, name string, v any) error {
+	f, err := src.Open(name)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() > domain.BackupMaxJSONSize {
+		return fmt.Errorf("%s: 解压后超过 %d 字节", name, domain.BackupMaxJSONSize)
+	}
+	data, err := io.ReadAll(io.LimitReader(f, domain.BackupMaxJSONSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > domain.BackupMaxJSONSize {
+		return fmt.Errorf("%s: 解压后超过 %d 字节", name, domain.BackupMaxJSONSize)
+	}
+	return json.Unmarshal(data, v)
+}
+
+// randomPassword 恢复时新建用户的初始密码，不对外返回
+func randomPassword() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
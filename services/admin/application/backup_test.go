@@ -0,0 +1,95 @@
+package application
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"blog-system/services/admin/domain"
+)
+
+// fakeUsers 内存用户客户端
+type fakeUsers struct {
+	users []*domain.User
+}
+
+func (f *fakeUsers) Create(_ context.Context, u *domain.User) error {
+	u.ID = int64(len(f.users) + 1)
+	f.users = append(f.users, u)
+	return nil
+}
+
+func (f *fakeUsers) Update(context.Context, *domain.User) error { return nil }
+
+func (f *fakeUsers) Delete(context.Context, int64, bool) error { return nil }
+
+func (f *fakeUsers) List(context.Context, domain.UserFilter, int, int) ([]*domain.User, int64, error) {
+	return f.users, int64(len(f.users)), nil
+}
+
+func (f *fakeUsers) SetStatus(context.Context, int64, int) error { return nil }
+
+func (f *fakeUsers) ResetPassword(context.Context, int64, string) error { return nil }
+
+func (f *fakeUsers) SetRoleRequireTwoFactor(context.Context, string, bool) error { return nil }
+
+func TestRestoreUsersRole(t *testing.T) {
+	users := &fakeUsers{users: []*domain.User{{ID: 1, Username: "admin", Email: "admin@a.com", Role: "admin"}}}
+	s := &AdminService{Users: users}
+	backup := []*domain.BackupUser{
+		{ID: 10, Username: "Admin", Role: "admin"},
+		{ID: 11, Username: "eve", Email: "eve@a.com", Role: "admin", Status: 1},
+	}
+	var count domain.RestoreCount
+	authors, errs, err := s.restoreUsers(context.Background(), backup, true, &count)
+	if err != nil || len(errs) != 0 {
+		t.Fatalf("err=%v errs=%v", err, errs)
+	}
+	if count.Existing != 1 || count.Created != 1 || authors[10] != 1 || authors[11] != 2 {
+		t.Fatalf("count=%+v authors=%v", count, authors)
+	}
+	// 备份中的角色不沿用，新建用户一律为普通用户
+	if u := users.users[1]; u.Role != domain.RestoredUserRole || u.Status != 1 {
+		t.Fatalf("created user role=%q status=%d", u.Role, u.Status)
+	}
+}
+
+// fakeExportContent 只实现导出，返回只含清单的归档
+type fakeExportContent struct {
+	ContentClient
+	archive []byte
+}
+
+func (f *fakeExportContent) ExportSite(_ context.Context, _ bool, maxSize int64) ([]byte, error) {
+	if int64(len(f.archive)) > maxSize {
+		return nil, domain.ErrBackupTooLarge
+	}
+	return f.archive, nil
+}
+
+func TestExportSiteRespectsRestoreLimit(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, _ := zw.Create(domain.BackupManifestFile)
+	_, _ = w.Write([]byte(`{"schema_version":1}`))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	users := &fakeUsers{users: []*domain.User{{ID: 1, Username: "admin", Role: "admin"}}}
+	s := &AdminService{Users: users, Content: &fakeExportContent{archive: buf.Bytes()}}
+	ctx := context.Background()
+
+	if _, err := s.ExportSite(ctx, false, true, int64(buf.Len())-1); !errors.Is(err, domain.ErrBackupTooLarge) {
+		t.Fatalf("content archive over limit: err = %v", err)
+	}
+	withUsers, err := s.ExportSite(ctx, true, true, 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 追加 users.json 后超出上限同样拒绝
+	if _, err := s.ExportSite(ctx, true, true, int64(len(withUsers))-1); !errors.Is(err, domain.ErrBackupTooLarge) {
+		t.Fatalf("archive with users over limit: err = %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package application
 
 import (
+	"blog-system/common/pkg/cacheaside"
 	"blog-system/common/pkg/logger"
 	"blog-system/services/admin/domain"
 	"context"
@@ -17,14 +18,19 @@ type AdminService struct {
 	Cache   cache.Cache
 	Stat    StatClient
 	Prom    PromClient
+	cc      *cacheaside.Cache
 }
 
 // UserClient 抽象 user-service 能力（登录 + 管理）
 type UserClient interface {
 	Create(ctx context.Context, u *domain.User) error
 	Update(ctx context.Context, u *domain.User) error
-	Delete(ctx context.Context, id int64) error
-	List(ctx context.Context, page, pageSize int) ([]*domain.User, int64, error)
+	Delete(ctx context.Context, id int64, hard bool) error
+	List(ctx context.Context, filter domain.UserFilter, page, pageSize int) ([]*domain.User, int64, error)
+	SetStatus(ctx context.Context, id int64, status int) error
+	ResetPassword(ctx context.Context, id int64, newPassword string) error
+	// 角色
+	SetRoleRequireTwoFactor(ctx context.Context, code string, require bool) error
 }
 
 // ContentClient 抽象 content-service 能力（文章/分类/标签管理）
@@ -33,24 +39,68 @@ type ContentClient interface {
 	CreateArticle(ctx context.Context, a *domain.Article) error
 	UpdateArticle(ctx context.Context, a *domain.Article) error
 	DeleteArticle(ctx context.Context, id int64) error
-	ListArticles(ctx context.Context) ([]*domain.Article, int64, error)
+	GetArticle(ctx context.Context, id int64) (*domain.Article, error)
+	ListArticles(ctx context.Context, q domain.ArticleQuery) (*domain.ArticlePage, error)
 	CountArticles(ctx context.Context) (int64, error)
 	// 分类（全量）
 	CreateCategory(ctx context.Context, c *domain.Category) error
 	UpdateCategory(ctx context.Context, c *domain.Category) error
-	DeleteCategory(ctx context.Context, id int64) error
+	// DeleteCategory dryRun 时仅预览受影响文章数
+	DeleteCategory(ctx context.Context, id int64, mode string, targetID int64, dryRun bool) (*domain.CategoryDeletePlan, error)
 	ListCategories(ctx context.Context) ([]*domain.Category, int64, error)
 	CountCategories(ctx context.Context) (int64, error)
+	MoveCategory(ctx context.Context, id, parentID int64) error
+	CategoryTree(ctx context.Context) ([]*domain.CategoryNode, error)
 	// 标签（全量）
 	CreateTag(ctx context.Context, t *domain.Tag) error
 	UpdateTag(ctx context.Context, t *domain.Tag) error
-	DeleteTag(ctx context.Context, id int64) error
+	DeleteTag(ctx context.Context, id int64, dryRun bool) (*domain.TagChangePlan, error)
+	MergeTag(ctx context.Context, sourceID, targetID int64, dryRun bool) (*domain.TagChangePlan, error)
 	ListTags(ctx context.Context) ([]*domain.Tag, error)
 	CountTags(ctx context.Context) (int64, error)
+	// 检索
+	RebuildSearchIndex(ctx context.Context) (int64, error)
+	// 定时发布
+	ListScheduledArticles(ctx context.Context) ([]*domain.Article, error)
+	CancelScheduledArticle(ctx context.Context, id int64) error
+	// 历史版本
+	ListArticleRevisions(ctx context.Context, articleID int64) ([]*domain.ArticleRevision, error)
+	GetArticleRevision(ctx context.Context, articleID, revisionID int64) (*domain.ArticleRevision, error)
+	DiffArticleRevisions(ctx context.Context, articleID, fromID, toID int64, mode string) (*domain.RevisionDiff, error)
+	RestoreArticleRevision(ctx context.Context, articleID, revisionID int64) error
+
+	ListComments(ctx context.Context, status *int, articleID int64, page, pageSize int) ([]*domain.Comment, int64, error)
+	ModerateComments(ctx context.Context, ids []int64, status int) (int64, error)
+	DeleteComment(ctx context.Context, id int64) error
+
+	ListMedia(ctx context.Context, f domain.MediaFilter) ([]*domain.Media, int64, error)
+	DeleteMedia(ctx context.Context, id int64, force bool) error
+
+	ImportArticles(ctx context.Context, archive []byte, authorID int64, dryRun bool) (*domain.ImportReport, error)
+
+	// ExportSite 整站导出为 zip（不含用户）；超过 maxSize 时返回 ErrBackupTooLarge
+	ExportSite(ctx context.Context, withMedia bool, maxSize int64) ([]byte, error)
+	// RestoreSite 由 zip 恢复站点，authors 为导出环境用户 ID 到当前环境的映射，未映射的取 defaultAuthor
+	RestoreSite(ctx context.Context, archive []byte, authors map[int64]int64, defaultAuthor int64) (*domain.RestoreReport, error)
 }
 
 func NewAdminService(userCli UserClient, contentCli ContentClient, l logger.Logger, cache cache.Cache, stat StatClient, prom PromClient) *AdminService {
-	return &AdminService{Users: userCli, Content: contentCli, Logger: l, Cache: cache, Stat: stat, Prom: prom}
+	return &AdminService{
+		Users: userCli, Content: contentCli, Logger: l, Cache: cache, Stat: stat, Prom: prom,
+		cc: cacheaside.New(cache, cacheaside.WithTTL(10*time.Minute), cacheaside.WithNegativeTTL(0)),
+	}
+}
+
+// TokenActive 令牌是否仍在登录缓存中（禁用、删除用户时由 user 服务清除）；未配置缓存时不校验
+func (s *AdminService) TokenActive(ctx context.Context, token string) bool {
+	if s.Cache == nil {
+		return true
+	}
+	if _, err := s.Cache.Get(ctx, "token_"+token); err != nil {
+		s.Logger.Warn("application: token校验失败: %v", err)
+		return false
+	}
+	return true
 }
 
 // 用户管理
@@ -61,13 +111,41 @@ func (s *AdminService) CreateUser(ctx context.Context, u *domain.User) error {
 }
 func (s *AdminService) UpdateUser(ctx context.Context, u *domain.User) error {
 	u.UpdatedAt = time.Now()
-	return s.Users.Update(ctx, u)
+	if err := s.Users.Update(ctx, u); err != nil {
+		return err
+	}
+	if u.Password != "" {
+		return s.Users.ResetPassword(ctx, u.ID, u.Password)
+	}
+	return nil
+}
+func (s *AdminService) SetUserStatus(ctx context.Context, id int64, status int) error {
+	return s.Users.SetStatus(ctx, id, status)
+}
+func (s *AdminService) ResetUserPassword(ctx context.Context, id int64, newPassword string) error {
+	return s.Users.ResetPassword(ctx, id, newPassword)
+}
+
+// DeleteUser 软删除仅禁用；硬删除会级联删除其文章，存在文章时拒绝
+func (s *AdminService) DeleteUser(ctx context.Context, id int64, hard bool) error {
+	if hard {
+		p, err := s.Content.ListArticles(ctx, domain.ArticleQuery{PageSize: 1, AuthorID: id})
+		if err != nil {
+			return err
+		}
+		if len(p.List) > 0 {
+			return errors.New("该用户仍有文章，请先转移或删除后再物理删除")
+		}
+	}
+	return s.Users.Delete(ctx, id, hard)
 }
-func (s *AdminService) DeleteUser(ctx context.Context, id int64) error {
-	return s.Users.Delete(ctx, id)
+func (s *AdminService) ListUsers(ctx context.Context, filter domain.UserFilter, page, pageSize int) ([]*domain.User, int64, error) {
+	return s.Users.List(ctx, filter, page, pageSize)
 }
-func (s *AdminService) ListUsers(ctx context.Context, page, pageSize int) ([]*domain.User, int64, error) {
-	return s.Users.List(ctx, page, pageSize)
+
+// SetRoleRequireTwoFactor 设置角色是否强制两步验证
+func (s *AdminService) SetRoleRequireTwoFactor(ctx context.Context, code string, require bool) error {
+	return s.Users.SetRoleRequireTwoFactor(ctx, code, require)
 }
 
 // 文章管理
@@ -87,8 +165,55 @@ func (s *AdminService) UpdateArticle(ctx context.Context, a *domain.Article) err
 func (s *AdminService) DeleteArticle(ctx context.Context, id int64) error {
 	return s.Content.DeleteArticle(ctx, id)
 }
-func (s *AdminService) ListArticles(ctx context.Context) ([]*domain.Article, int64, error) {
-	return s.Content.ListArticles(ctx)
+
+// ListScheduledArticles 定时发布中的文章（按发布时间升序）
+func (s *AdminService) ListScheduledArticles(ctx context.Context) ([]*domain.Article, error) {
+	return s.Content.ListScheduledArticles(ctx)
+}
+
+// CancelScheduledArticle 取消定时发布，文章退回草稿
+func (s *AdminService) CancelScheduledArticle(ctx context.Context, id int64) error {
+	return s.Content.CancelScheduledArticle(ctx, id)
+}
+
+// ListArticleRevisions 文章历史版本（新到旧，不含正文）
+func (s *AdminService) ListArticleRevisions(ctx context.Context, articleID int64) ([]*domain.ArticleRevision, error) {
+	return s.Content.ListArticleRevisions(ctx, articleID)
+}
+
+// GetArticleRevision 历史版本详情
+func (s *AdminService) GetArticleRevision(ctx context.Context, articleID, revisionID int64) (*domain.ArticleRevision, error) {
+	return s.Content.GetArticleRevision(ctx, articleID, revisionID)
+}
+
+// DiffArticleRevisions 比较两个历史版本，toID 为 0 时与当前内容比较
+func (s *AdminService) DiffArticleRevisions(ctx context.Context, articleID, fromID, toID int64, mode string) (*domain.RevisionDiff, error) {
+	return s.Content.DiffArticleRevisions(ctx, articleID, fromID, toID, mode)
+}
+
+// RestoreArticleRevision 恢复历史版本（恢复前的内容会保存为新版本）
+func (s *AdminService) RestoreArticleRevision(ctx context.Context, articleID, revisionID int64) error {
+	return s.Content.RestoreArticleRevision(ctx, articleID, revisionID)
+}
+
+// GetArticle 文章详情（含版本号）
+func (s *AdminService) GetArticle(ctx context.Context, id int64) (*domain.Article, error) {
+	return s.Content.GetArticle(ctx, id)
+}
+
+// ListArticles 文章列表（游标分页）
+func (s *AdminService) ListArticles(ctx context.Context, q domain.ArticleQuery) (*domain.ArticlePage, error) {
+	return s.Content.ListArticles(ctx, q)
+}
+
+// IsArticleAuthor 判断用户是否为文章作者（用于 article:edit:own）
+func (s *AdminService) IsArticleAuthor(ctx context.Context, articleID, userID int64) (bool, error) {
+	a, err := s.Content.GetArticle(ctx, articleID)
+	if err != nil {
+		logger.Log().Error("application: 查询文章失败: id=%d err=%v", articleID, err)
+		return false, err
+	}
+	return a.AuthorID == userID, nil
 }
 
 // 分类管理
@@ -99,9 +224,6 @@ func (s *AdminService) CreateCategory(ctx context.Context, c *domain.Category) e
 		logger.Log().Error("application: 创建分类失败: %v", err)
 		return err
 	}
-	if s.Cache != nil {
-		_ = s.Cache.Del(ctx, "content:category_tree")
-	}
 	return nil
 }
 func (s *AdminService) UpdateCategory(ctx context.Context, c *domain.Category) error {
@@ -110,25 +232,38 @@ func (s *AdminService) UpdateCategory(ctx context.Context, c *domain.Category) e
 		logger.Log().Error("application: 更新分类失败: %v", err)
 		return err
 	}
-	if s.Cache != nil {
-		_ = s.Cache.Del(ctx, "content:category_tree")
-	}
 	return nil
 }
-func (s *AdminService) DeleteCategory(ctx context.Context, id int64) error {
-	if err := s.Content.DeleteCategory(ctx, id); err != nil {
-		logger.Log().Error("application: 删除分类失败: id=%d err=%v", id, err)
-		return err
-	}
-	if s.Cache != nil {
-		_ = s.Cache.Del(ctx, "content:category_tree")
+
+// DeleteCategory 按 mode 删除分类（refuse/reassign/uncategorized），dryRun 时仅预览
+func (s *AdminService) DeleteCategory(ctx context.Context, id int64, mode string, targetID int64, dryRun bool) (*domain.CategoryDeletePlan, error) {
+	p, err := s.Content.DeleteCategory(ctx, id, mode, targetID, dryRun)
+	if err != nil {
+		logger.Log().Error("application: 删除分类失败: id=%d mode=%s err=%v", id, mode, err)
+		return nil, err
 	}
-	return nil
+	return p, nil
 }
+
+// ListCategories 全量分类（缓存由 content-service 维护，写操作后即时失效）
 func (s *AdminService) ListCategories(ctx context.Context) ([]*domain.Category, int64, error) {
 	return s.Content.ListCategories(ctx)
 }
 
+// MoveCategory 将分类连同子树移动到 parentID 下，parentID 为 0 时移到顶级
+func (s *AdminService) MoveCategory(ctx context.Context, id, parentID int64) error {
+	if err := s.Content.MoveCategory(ctx, id, parentID); err != nil {
+		logger.Log().Error("application: 移动分类失败: id=%d parent=%d err=%v", id, parentID, err)
+		return err
+	}
+	return nil
+}
+
+// CategoryTree 分类树
+func (s *AdminService) CategoryTree(ctx context.Context) ([]*domain.CategoryNode, error) {
+	return s.Content.CategoryTree(ctx)
+}
+
 // 标签管理（全量）
 func (s *AdminService) CreateTag(ctx context.Context, t *domain.Tag) error {
 	return s.Content.CreateTag(ctx, t)
@@ -136,8 +271,20 @@ func (s *AdminService) CreateTag(ctx context.Context, t *domain.Tag) error {
 func (s *AdminService) UpdateTag(ctx context.Context, t *domain.Tag) error {
 	return s.Content.UpdateTag(ctx, t)
 }
-func (s *AdminService) DeleteTag(ctx context.Context, id int64) error {
-	return s.Content.DeleteTag(ctx, id)
+
+// DeleteTag 删除标签（文章仅解除关联），dryRun 时仅预览
+func (s *AdminService) DeleteTag(ctx context.Context, id int64, dryRun bool) (*domain.TagChangePlan, error) {
+	return s.Content.DeleteTag(ctx, id, dryRun)
+}
+
+// MergeTag 将 sourceID 合并到 targetID，dryRun 时仅预览
+func (s *AdminService) MergeTag(ctx context.Context, sourceID, targetID int64, dryRun bool) (*domain.TagChangePlan, error) {
+	p, err := s.Content.MergeTag(ctx, sourceID, targetID, dryRun)
+	if err != nil {
+		logger.Log().Error("application: 合并标签失败: source=%d target=%d err=%v", sourceID, targetID, err)
+		return nil, err
+	}
+	return p, nil
 }
 func (s *AdminService) ListTags(ctx context.Context) ([]*domain.Tag, error) {
 	return s.Content.ListTags(ctx)
@@ -146,6 +293,53 @@ func (s *AdminService) CountTags(ctx context.Context) (int64, error) {
 	return s.Content.CountTags(ctx)
 }
 
+// RebuildSearchIndex 重建全文索引，返回已索引文章数
+func (s *AdminService) RebuildSearchIndex(ctx context.Context) (int64, error) {
+	return s.Content.RebuildSearchIndex(ctx)
+}
+
+// ListComments 评论审核队列，status 为 nil 时返回全部状态
+func (s *AdminService) ListComments(ctx context.Context, status *int, articleID int64, page, pageSize int) ([]*domain.Comment, int64, error) {
+	return s.Content.ListComments(ctx, status, articleID, page, pageSize)
+}
+
+// ModerateComments 批量通过、标记垃圾或退回待审核，返回实际修改数
+func (s *AdminService) ModerateComments(ctx context.Context, ids []int64, status int) (int64, error) {
+	n, err := s.Content.ModerateComments(ctx, ids, status)
+	if err != nil {
+		logger.Log().Error("application: 审核评论失败: ids=%v status=%d err=%v", ids, status, err)
+		return 0, err
+	}
+	return n, nil
+}
+func (s *AdminService) DeleteComment(ctx context.Context, id int64) error {
+	return s.Content.DeleteComment(ctx, id)
+}
+
+// ListMedia 文件列表（新到旧），f.Orphan 时只返回孤立文件
+func (s *AdminService) ListMedia(ctx context.Context, f domain.MediaFilter) ([]*domain.Media, int64, error) {
+	return s.Content.ListMedia(ctx, f)
+}
+
+// DeleteMedia 删除文件及其副本，仍被引用时须 force
+func (s *AdminService) DeleteMedia(ctx context.Context, id int64, force bool) error {
+	if err := s.Content.DeleteMedia(ctx, id, force); err != nil {
+		logger.Log().Error("application: 删除文件失败: id=%d force=%t err=%v", id, force, err)
+		return err
+	}
+	return nil
+}
+
+// ImportArticles 导入 zip 中的 Markdown 文章（Hexo / Hugo），作者为当前管理员；按别名幂等
+func (s *AdminService) ImportArticles(ctx context.Context, archive []byte, authorID int64, dryRun bool) (*domain.ImportReport, error) {
+	r, err := s.Content.ImportArticles(ctx, archive, authorID, dryRun)
+	if err != nil {
+		logger.Log().Error("application: 导入文章失败: author=%d dry_run=%t err=%v", authorID, dryRun, err)
+		return nil, err
+	}
+	return r, nil
+}
+
 // Dashboard 概览
 func (s *AdminService) Dashboard(ctx context.Context) (map[string]int64, error) {
 	if s.Stat == nil {
@@ -2,7 +2,10 @@ package domain
 
 import (
 	"context"
+	"errors"
 	"time"
+
+	"blog-system/common/pkg/textdiff"
 )
 
 type User struct {
@@ -19,6 +22,13 @@ type User struct {
 
 func (User) TableName() string { return "blog_user" }
 
+// UserFilter 用户列表筛选条件，零值字段不参与过滤
+type UserFilter struct {
+	Role    string
+	Status  *int
+	Keyword string
+}
+
 type Article struct {
 	ID          int64      `json:"id"`
 	Title       string     `json:"title"`
@@ -32,23 +42,136 @@ type Article struct {
 	IsTop       bool       `json:"is_top"`
 	IsRecommend bool       `json:"is_recommend"`
 	PublishedAt *time.Time `json:"published_at"`
+	Version     int64      `json:"version"` // 乐观锁版本号，更新时为 0 表示不校验
+	TagIDs      []int64    `json:"tag_ids"` // 标签；更新时为 nil 表示保持不变
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// SEO 元信息，为空时前台回退到标题与摘要
+	MetaTitle    string `json:"meta_title"`
+	MetaDesc     string `json:"meta_desc"`
+	MetaKeywords string `json:"meta_keywords"`
+
+	// 渲染结果（仅详情返回，供预览）
+	HTML           string     `json:"html,omitempty"`
+	TOC            []*Heading `json:"toc,omitempty"`
+	WordCount      int        `json:"word_count,omitempty"`
+	ReadingMinutes int        `json:"reading_minutes,omitempty"`
+}
+
+// ArticleQuery 文章列表游标分页条件：Cursor 为上一页返回的游标（空为第一页），AuthorID 非 0 时限定作者
+type ArticleQuery struct {
+	Cursor   string
+	PageSize int
+	AuthorID int64
+}
+
+// ArticlePage 文章列表的一页：NextCursor 为空表示没有下一页；限定作者时不统计 Total
+type ArticlePage struct {
+	List       []*Article
+	NextCursor string
+	Total      int64
+}
+
+// Heading 目录项
+type Heading struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"`
+	Text  string `json:"text"`
 }
 
 func (Article) TableName() string { return "blog_article" }
 
+// ErrVersionConflict 文章已被他人修改（版本不一致）
+var ErrVersionConflict = errors.New("文章已被修改，请刷新后重试")
+
+// 别名（slug）错误：文章、分类、标签通用
+var (
+	ErrSlugInvalid = errors.New("别名只能包含小写字母、数字与连字符，且不能以连字符开头或结尾")
+	ErrSlugTaken   = errors.New("别名已被占用")
+)
+
+// 分类树错误（与 content-service 一致）
+var (
+	ErrCategoryNotFound    = errors.New("分类不存在")
+	ErrCategoryParent      = errors.New("上级分类不存在")
+	ErrCategoryCycle       = errors.New("不能将分类移动到自身或其子分类下")
+	ErrCategoryTooDeep     = errors.New("分类层级超出上限")
+	ErrCategoryHasChildren = errors.New("分类下仍有子分类")
+	ErrCategoryNotEmpty    = errors.New("分类下仍有文章，请指定转移方式")
+	ErrCategoryDeleteMode  = errors.New("不支持的分类删除方式")
+	ErrCategoryTarget      = errors.New("目标分类不存在或与待删除分类相同")
+)
+
+// 标签错误（与 content-service 一致）
+var (
+	ErrTagNotFound  = errors.New("标签不存在")
+	ErrTagNameTaken = errors.New("标签名已存在，如需合并请使用标签合并")
+	ErrTagMergeSelf = errors.New("不能将标签合并到自身")
+	ErrTagInvalid   = errors.New("包含不存在的标签")
+)
+
+// CategoryDeletePlan 删除分类的影响（mode 为 refuse/reassign/uncategorized）
+type CategoryDeletePlan struct {
+	ID       int64  `json:"id"`
+	Mode     string `json:"mode"`
+	TargetID int64  `json:"target_id"`
+	Articles int64  `json:"articles"`
+	DryRun   bool   `json:"dry_run"`
+}
+
+// TagChangePlan 删除或合并标签的影响
+type TagChangePlan struct {
+	SourceID int64 `json:"source_id"`
+	TargetID int64 `json:"target_id,omitempty"`
+	Articles int64 `json:"articles"`
+	Overlap  int64 `json:"overlap,omitempty"`
+	DryRun   bool  `json:"dry_run"`
+}
+
+// ArticleRevision 文章历史版本
+type ArticleRevision struct {
+	ID         int64     `json:"id"`
+	ArticleID  int64     `json:"article_id"`
+	Title      string    `json:"title"`
+	Slug       string    `json:"slug"`
+	Content    string    `json:"content,omitempty"`
+	Summary    string    `json:"summary"`
+	Cover      string    `json:"cover"`
+	CategoryID int64     `json:"category_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// RevisionDiff 版本差异：标题与摘要按词比较，正文按 Mode 输出 unified diff 或词级片段
+type RevisionDiff struct {
+	FromID  int64              `json:"from_id"`
+	ToID    int64              `json:"to_id"`
+	Mode    string             `json:"mode"`
+	Title   []textdiff.Segment `json:"title"`
+	Summary []textdiff.Segment `json:"summary"`
+	Unified string             `json:"unified,omitempty"`
+	Content []textdiff.Segment `json:"content,omitempty"`
+}
+
 type Category struct {
 	ID        int64     `json:"id"`
 	Name      string    `json:"name"`
 	Slug      string    `json:"slug"`
 	Sort      int       `json:"sort"`
+	ParentID  int64     `json:"parent_id"`
+	Path      string    `json:"path"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
 func (Category) TableName() string { return "blog_category" }
 
+// CategoryNode 分类树节点
+type CategoryNode struct {
+	*Category
+	Children []*CategoryNode `json:"children"`
+}
+
 // Tag 领域
 type Tag struct {
 	ID        int64     `json:"id"`
@@ -61,6 +184,173 @@ type Tag struct {
 
 func (Tag) TableName() string { return "blog_tag" }
 
+// 评论状态（与 content-service 一致）
+const (
+	CommentStatusPending  = 0
+	CommentStatusApproved = 1
+	CommentStatusSpam     = 2
+)
+
+// 评论错误（与 content-service 一致）
+var (
+	ErrCommentNotFound = errors.New("评论不存在")
+	ErrCommentStatus   = errors.New("不支持的评论状态")
+)
+
+// Comment 待审核或已审核的评论
+type Comment struct {
+	ID        int64     `json:"id"`
+	ArticleID int64     `json:"article_id"`
+	ParentID  int64     `json:"parent_id"`
+	RootID    int64     `json:"root_id"`
+	UserID    int64     `json:"user_id"`
+	Content   string    `json:"content"`
+	Status    int       `json:"status"`
+	IP        string    `json:"ip"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// 文件错误（与 content-service 一致）
+var (
+	ErrMediaNotFound = errors.New("文件不存在")
+	ErrMediaInUse    = errors.New("文件仍被文章或用户引用")
+)
+
+// Media 上传的文件及其缩放副本
+type Media struct {
+	ID         int64           `json:"id"`
+	Hash       string          `json:"hash"`
+	StorageKey string          `json:"storage_key"`
+	URL        string          `json:"url"`
+	Name       string          `json:"name"`
+	MimeType   string          `json:"mime_type"`
+	Size       int64           `json:"size"`
+	Width      int             `json:"width"`
+	Height     int             `json:"height"`
+	Variants   []*MediaVariant `json:"variants"`
+	UploaderID int64           `json:"uploader_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// MediaVariant 图片缩放副本
+type MediaVariant struct {
+	Name       string `json:"name"`
+	StorageKey string `json:"storage_key"`
+	URL        string `json:"url"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	Size       int64  `json:"size"`
+}
+
+// MediaFilter 文件管理查询：Orphan 为 true 时只返回上传超过 OlderThan 且未被引用的文件
+type MediaFilter struct {
+	MimePrefix string
+	UploaderID int64
+	Orphan     bool
+	OlderThan  time.Duration
+	Page       int
+	PageSize   int
+}
+
+// ImportMaxSize 默认导入压缩包大小上限（字节）
+const ImportMaxSize = 30 << 20
+
+// 导入错误（与 content-service 一致）
+var (
+	ErrImportNoDocs  = errors.New("未找到可导入的 Markdown 文件")
+	ErrImportArchive = errors.New("导入文件不是有效的 zip 压缩包")
+)
+
+// ImportItem 单个 Markdown 文件的导入结果，Action 为 create/update/unchanged/failed
+type ImportItem struct {
+	File      string   `json:"file"`
+	Slug      string   `json:"slug,omitempty"`
+	Title     string   `json:"title,omitempty"`
+	Action    string   `json:"action"`
+	ArticleID int64    `json:"article_id,omitempty"`
+	Images    int      `json:"images"`
+	Missing   []string `json:"missing_images,omitempty"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// ImportReport 导入报告；DryRun 时仅预览
+type ImportReport struct {
+	DryRun        bool          `json:"dry_run"`
+	Total         int           `json:"total"`
+	Created       int           `json:"created"`
+	Updated       int           `json:"updated"`
+	Unchanged     int           `json:"unchanged"`
+	Failed        int           `json:"failed"`
+	NewTags       []string      `json:"new_tags"`
+	NewCategories []string      `json:"new_categories"`
+	Items         []*ImportItem `json:"items"`
+}
+
+// BackupSchemaVersion 备份归档格式版本（与 content-service 一致）
+const BackupSchemaVersion = 1
+
+// 备份归档中由管理端读写的文件，其余文件由 content-service 处理
+const (
+	BackupManifestFile = "manifest.json"
+	BackupUsersFile    = "users.json"
+)
+
+// RestoredUserRole 恢复时新建用户的角色：不沿用备份中的角色，避免借恢复归档创建高权限账号
+const RestoredUserRole = "user"
+
+// BackupMaxJSONSize 管理端读取的归档 JSON 文件解压后的大小上限（防 zip 炸弹）
+const BackupMaxJSONSize = 64 << 20
+
+// 备份错误
+var (
+	ErrBackupManifest = errors.New("备份归档缺少 manifest.json 或格式不正确")
+	ErrBackupVersion  = errors.New("不支持的备份格式版本")
+	ErrBackupData     = errors.New("备份归档中的数据文件格式不正确")
+	ErrBackupTooLarge = errors.New("备份归档超过恢复上传上限，请不含文件导出（media=false）或使用命令行 content export")
+)
+
+// BackupManifest 备份归档清单
+type BackupManifest struct {
+	SchemaVersion int       `json:"schema_version"`
+	ExportedAt    time.Time `json:"exported_at"`
+	Articles      int       `json:"articles"`
+	Categories    int       `json:"categories"`
+	Tags          int       `json:"tags"`
+	ArticleTags   int       `json:"article_tags"`
+	Media         int       `json:"media"`
+	Users         int       `json:"users"`
+}
+
+// BackupUser 备份中的用户，不含密码哈希；恢复时新建的用户需重置密码
+type BackupUser struct {
+	ID        int64     `json:"id"`
+	Username  string    `json:"username"`
+	Email     string    `json:"email"`
+	Role      string    `json:"role"`
+	Avatar    string    `json:"avatar,omitempty"`
+	Status    int       `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// RestoreCount 某类对象的恢复结果：Existing 为当前环境中已有而沿用的
+type RestoreCount struct {
+	Created  int `json:"created"`
+	Existing int `json:"existing"`
+	Failed   int `json:"failed"`
+}
+
+// RestoreReport 恢复报告；已有的对象不覆盖，重复恢复同一归档是安全的
+type RestoreReport struct {
+	SchemaVersion int          `json:"schema_version"`
+	Users         RestoreCount `json:"users"`
+	Categories    RestoreCount `json:"categories"`
+	Tags          RestoreCount `json:"tags"`
+	Articles      RestoreCount `json:"articles"`
+	Media         RestoreCount `json:"media"`
+	Errors        []string     `json:"errors"`
+}
+
 type UserRepository interface {
 	Create(ctx context.Context, u *User) error
 	Update(ctx context.Context, u *User) error
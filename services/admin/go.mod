@@ -8,6 +8,7 @@ require (
 	blog-system/services/stat v0.0.0
 	blog-system/services/user v0.0.0
 	github.com/CoucouMonEcho/go-framework v0.1.7
+	github.com/redis/go-redis/v9 v9.11.0
 	go.etcd.io/etcd/client/v3 v3.6.2
 	google.golang.org/grpc v1.73.0
 )
@@ -31,7 +32,6 @@ require (
 	github.com/prometheus/client_model v0.6.1 // indirect
 	github.com/prometheus/common v0.62.0 // indirect
 	github.com/prometheus/procfs v0.15.1 // indirect
-	github.com/redis/go-redis/v9 v9.11.0 // indirect
 	go.etcd.io/etcd/api/v3 v3.6.2 // indirect
 	go.etcd.io/etcd/client/pkg/v3 v3.6.2 // indirect
 	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
@@ -3,10 +3,14 @@ package clients
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"time"
 
 	conf "blog-system/common/pkg/config"
 	"blog-system/common/pkg/logger"
+	"blog-system/common/pkg/pagination"
+	"blog-system/common/pkg/textdiff"
 	"blog-system/services/admin/application"
 	"blog-system/services/admin/domain"
 	cpb "blog-system/services/content/proto"
@@ -16,8 +20,13 @@ import (
 	regEtcd "github.com/CoucouMonEcho/go-framework/micro/registry/etcd"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
+// exportOverhead 导出响应中归档以外字段的余量
+const exportOverhead = 1 << 10
+
 type ContentClient struct {
 	cc  *grpc.ClientConn
 	cli cpb.ContentAdminServiceClient
@@ -38,28 +47,92 @@ func NewContentClient(cfg *conf.AppConfig) *ContentClient {
 }
 
 func (c *ContentClient) CreateArticle(ctx context.Context, a *domain.Article) error {
-	_, err := c.cli.CreateArticle(ctx, &cpb.Article{Title: a.Title, Slug: a.Slug, Content: a.Content, Summary: a.Summary, Cover: a.Cover, AuthorId: a.AuthorID, CategoryId: a.CategoryID, Status: int32(a.Status), IsTop: a.IsTop, IsRecommend: a.IsRecommend})
-	return err
+	_, err := c.cli.CreateArticle(ctx, &cpb.Article{Title: a.Title, Slug: a.Slug, Content: a.Content, Summary: a.Summary, Cover: a.Cover, AuthorId: a.AuthorID, CategoryId: a.CategoryID, Status: int32(a.Status), IsTop: a.IsTop, IsRecommend: a.IsRecommend, PublishedAt: formatTime(a.PublishedAt), TagIds: a.TagIDs,
+		MetaTitle: a.MetaTitle, MetaDesc: a.MetaDesc, MetaKeywords: a.MetaKeywords})
+	return contentErr(err)
 }
 func (c *ContentClient) UpdateArticle(ctx context.Context, a *domain.Article) error {
-	_, err := c.cli.UpdateArticle(ctx, &cpb.Article{Id: a.ID, Title: a.Title, Slug: a.Slug, Content: a.Content, Summary: a.Summary, Cover: a.Cover, CategoryId: a.CategoryID, Status: int32(a.Status), IsTop: a.IsTop, IsRecommend: a.IsRecommend})
+	_, err := c.cli.UpdateArticle(ctx, &cpb.Article{Id: a.ID, Title: a.Title, Slug: a.Slug, Content: a.Content, Summary: a.Summary, Cover: a.Cover, CategoryId: a.CategoryID, Status: int32(a.Status), IsTop: a.IsTop, IsRecommend: a.IsRecommend, PublishedAt: formatTime(a.PublishedAt), Version: a.Version, TagIds: a.TagIDs, SetTags: a.TagIDs != nil,
+		MetaTitle: a.MetaTitle, MetaDesc: a.MetaDesc, MetaKeywords: a.MetaKeywords})
+	return contentErr(err)
+}
+
+// contentErrs 按错误信息还原的领域错误（同一状态码对应多种错误）
+var contentErrs = []error{
+	domain.ErrSlugInvalid, domain.ErrCategoryNotFound, domain.ErrCategoryParent,
+	domain.ErrCategoryCycle, domain.ErrCategoryTooDeep, domain.ErrCategoryHasChildren,
+	domain.ErrCategoryNotEmpty, domain.ErrCategoryDeleteMode, domain.ErrCategoryTarget,
+	domain.ErrTagNotFound, domain.ErrTagNameTaken, domain.ErrTagMergeSelf, domain.ErrTagInvalid,
+	domain.ErrCommentNotFound, domain.ErrCommentStatus, domain.ErrMediaNotFound, domain.ErrMediaInUse,
+	domain.ErrImportNoDocs, domain.ErrImportArchive, pagination.ErrInvalidCursor, domain.ErrBackupManifest,
+}
+
+// contentErr 将 content 以 gRPC 状态码表示的业务错误还原为领域错误
+func contentErr(err error) error {
+	switch status.Code(err) {
+	case codes.Aborted:
+		return domain.ErrVersionConflict
+	case codes.AlreadyExists, codes.InvalidArgument, codes.NotFound, codes.FailedPrecondition:
+		msg := status.Convert(err).Message()
+		for _, e := range contentErrs {
+			if e.Error() == msg {
+				return e
+			}
+		}
+		if status.Code(err) == codes.AlreadyExists {
+			return domain.ErrSlugTaken
+		}
+	}
 	return err
 }
 func (c *ContentClient) DeleteArticle(ctx context.Context, id int64) error {
 	_, err := c.cli.DeleteArticle(ctx, &cpb.Id{Id: id})
 	return err
 }
-func (c *ContentClient) ListArticles(ctx context.Context) ([]*domain.Article, int64, error) {
-	resp, err := c.cli.ListArticles(ctx, &cpb.Empty{})
+func (c *ContentClient) GetArticle(ctx context.Context, id int64) (*domain.Article, error) {
+	a, err := c.cli.GetArticle(ctx, &cpb.Id{Id: id})
+	if err != nil {
+		logger.Log().Error("clients: 获取文章失败: id=%d err=%v", id, err)
+		return nil, err
+	}
+	return fromPBArticle(a), nil
+}
+
+// fromPBArticle pb 文章转换为领域模型
+func fromPBArticle(a *cpb.Article) *domain.Article {
+	out := &domain.Article{ID: a.Id, Title: a.Title, Slug: a.Slug, Content: a.Content, Summary: a.Summary, Cover: a.Cover, AuthorID: a.AuthorId, CategoryID: a.CategoryId, Status: int(a.Status), IsTop: a.IsTop, IsRecommend: a.IsRecommend, Version: a.Version, TagIDs: a.TagIds,
+		MetaTitle: a.MetaTitle, MetaDesc: a.MetaDesc, MetaKeywords: a.MetaKeywords}
+	if t, er := time.Parse(time.RFC3339, a.PublishedAt); er == nil {
+		out.PublishedAt = &t
+	}
+	out.CreatedAt, _ = time.Parse(time.RFC3339, a.CreatedAt)
+	out.UpdatedAt, _ = time.Parse(time.RFC3339, a.UpdatedAt)
+	out.HTML, out.WordCount, out.ReadingMinutes = a.Html, int(a.WordCount), int(a.ReadingMinutes)
+	for _, h := range a.Toc {
+		out.TOC = append(out.TOC, &domain.Heading{Level: int(h.Level), ID: h.Id, Text: h.Text})
+	}
+	return out
+}
+
+// formatTime 时间格式化为 RFC3339，nil 为空串
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func (c *ContentClient) ListArticles(ctx context.Context, q domain.ArticleQuery) (*domain.ArticlePage, error) {
+	resp, err := c.cli.ListArticles(ctx, &cpb.ListArticlesRequest{Cursor: q.Cursor, PageSize: int32(q.PageSize), AuthorId: q.AuthorID})
 	if err != nil {
 		logger.Log().Error("clients: 列表文章失败: %v", err)
-		return nil, 0, err
+		return nil, contentErr(err)
 	}
-	out := make([]*domain.Article, 0, len(resp.Data))
+	out := &domain.ArticlePage{List: make([]*domain.Article, 0, len(resp.Data)), NextCursor: resp.NextCursor, Total: resp.Total}
 	for _, a := range resp.Data {
-		out = append(out, &domain.Article{ID: a.Id, Title: a.Title})
+		out.List = append(out.List, fromPBArticle(a))
 	}
-	return out, resp.Total, nil
+	return out, nil
 }
 func (c *ContentClient) CountArticles(ctx context.Context) (int64, error) {
 	resp, err := c.cli.CountArticles(ctx, &cpb.Empty{})
@@ -71,16 +144,19 @@ func (c *ContentClient) CountArticles(ctx context.Context) (int64, error) {
 }
 
 func (c *ContentClient) CreateCategory(ctx context.Context, cat *domain.Category) error {
-	_, err := c.cli.CreateCategory(ctx, &cpb.Category{Name: cat.Name, Slug: cat.Slug, Sort: int32(cat.Sort)})
-	return err
+	_, err := c.cli.CreateCategory(ctx, &cpb.Category{Name: cat.Name, Slug: cat.Slug, Sort: int32(cat.Sort), ParentId: cat.ParentID})
+	return contentErr(err)
 }
 func (c *ContentClient) UpdateCategory(ctx context.Context, cat *domain.Category) error {
 	_, err := c.cli.UpdateCategory(ctx, &cpb.Category{Id: cat.ID, Name: cat.Name, Slug: cat.Slug, Sort: int32(cat.Sort)})
-	return err
+	return contentErr(err)
 }
-func (c *ContentClient) DeleteCategory(ctx context.Context, id int64) error {
-	_, err := c.cli.DeleteCategory(ctx, &cpb.Id{Id: id})
-	return err
+func (c *ContentClient) DeleteCategory(ctx context.Context, id int64, mode string, targetID int64, dryRun bool) (*domain.CategoryDeletePlan, error) {
+	p, err := c.cli.DeleteCategoryWithPlan(ctx, &cpb.DeleteCategoryRequest{Id: id, Mode: mode, TargetId: targetID, DryRun: dryRun})
+	if err != nil {
+		return nil, contentErr(err)
+	}
+	return &domain.CategoryDeletePlan{ID: p.Id, Mode: p.Mode, TargetID: p.TargetId, Articles: p.Articles, DryRun: p.DryRun}, nil
 }
 func (c *ContentClient) ListCategories(ctx context.Context) ([]*domain.Category, int64, error) {
 	resp, err := c.cli.ListCategories(ctx, &cpb.Empty{})
@@ -93,10 +169,34 @@ func (c *ContentClient) ListCategories(ctx context.Context) ([]*domain.Category,
 	}
 	out := make([]*domain.Category, 0, len(resp.Data))
 	for _, c0 := range resp.Data {
-		out = append(out, &domain.Category{ID: c0.Id, Name: c0.Name, Slug: c0.Slug, Sort: int(c0.Sort)})
+		out = append(out, fromPBCategory(c0))
 	}
 	return out, resp.Total, nil
 }
+func (c *ContentClient) MoveCategory(ctx context.Context, id, parentID int64) error {
+	_, err := c.cli.MoveCategory(ctx, &cpb.MoveCategoryRequest{Id: id, ParentId: parentID})
+	return contentErr(err)
+}
+func (c *ContentClient) CategoryTree(ctx context.Context) ([]*domain.CategoryNode, error) {
+	resp, err := c.cli.GetCategoryTree(ctx, &cpb.Empty{})
+	if err != nil {
+		logger.Log().Error("clients: 获取分类树失败: %v", err)
+		return nil, err
+	}
+	return fromPBCategoryNodes(resp.Data), nil
+}
+
+func fromPBCategory(c *cpb.Category) *domain.Category {
+	return &domain.Category{ID: c.Id, Name: c.Name, Slug: c.Slug, Sort: int(c.Sort), ParentID: c.ParentId, Path: c.Path}
+}
+
+func fromPBCategoryNodes(nodes []*cpb.CategoryNode) []*domain.CategoryNode {
+	out := make([]*domain.CategoryNode, 0, len(nodes))
+	for _, n := range nodes {
+		out = append(out, &domain.CategoryNode{Category: fromPBCategory(n.Category), Children: fromPBCategoryNodes(n.Children)})
+	}
+	return out
+}
 func (c *ContentClient) CountCategories(ctx context.Context) (int64, error) {
 	resp, err := c.cli.CountCategories(ctx, &cpb.Empty{})
 	if err != nil {
@@ -109,15 +209,29 @@ func (c *ContentClient) CountCategories(ctx context.Context) (int64, error) {
 // 标签管理
 func (c *ContentClient) CreateTag(ctx context.Context, t *domain.Tag) error {
 	_, err := c.cli.CreateTag(ctx, &cpb.Tag{Name: t.Name, Slug: t.Slug, Color: t.Color})
-	return err
+	return contentErr(err)
 }
 func (c *ContentClient) UpdateTag(ctx context.Context, t *domain.Tag) error {
 	_, err := c.cli.UpdateTag(ctx, &cpb.Tag{Id: t.ID, Name: t.Name, Slug: t.Slug, Color: t.Color})
-	return err
+	return contentErr(err)
 }
-func (c *ContentClient) DeleteTag(ctx context.Context, id int64) error {
-	_, err := c.cli.DeleteTag(ctx, &cpb.Id{Id: id})
-	return err
+func (c *ContentClient) DeleteTag(ctx context.Context, id int64, dryRun bool) (*domain.TagChangePlan, error) {
+	p, err := c.cli.DeleteTagWithPlan(ctx, &cpb.DeleteTagRequest{Id: id, DryRun: dryRun})
+	if err != nil {
+		return nil, contentErr(err)
+	}
+	return fromPBTagChangePlan(p), nil
+}
+func (c *ContentClient) MergeTag(ctx context.Context, sourceID, targetID int64, dryRun bool) (*domain.TagChangePlan, error) {
+	p, err := c.cli.MergeTag(ctx, &cpb.MergeTagRequest{SourceId: sourceID, TargetId: targetID, DryRun: dryRun})
+	if err != nil {
+		return nil, contentErr(err)
+	}
+	return fromPBTagChangePlan(p), nil
+}
+
+func fromPBTagChangePlan(p *cpb.TagChangePlan) *domain.TagChangePlan {
+	return &domain.TagChangePlan{SourceID: p.SourceId, TargetID: p.TargetId, Articles: p.Articles, Overlap: p.Overlap, DryRun: p.DryRun}
 }
 func (c *ContentClient) ListTags(ctx context.Context) ([]*domain.Tag, error) {
 	resp, err := c.cli.ListTags(ctx, &cpb.Empty{})
@@ -140,4 +254,220 @@ func (c *ContentClient) CountTags(ctx context.Context) (int64, error) {
 	return resp.Value, nil
 }
 
+func (c *ContentClient) RebuildSearchIndex(ctx context.Context) (int64, error) {
+	resp, err := c.cli.RebuildSearchIndex(ctx, &cpb.Empty{})
+	if err != nil {
+		logger.Log().Error("clients: 重建全文索引失败: %v", err)
+		return 0, err
+	}
+	return resp.Value, nil
+}
+
+// 定时发布
+func (c *ContentClient) ListScheduledArticles(ctx context.Context) ([]*domain.Article, error) {
+	resp, err := c.cli.ListScheduledArticles(ctx, &cpb.Empty{})
+	if err != nil {
+		logger.Log().Error("clients: 列表定时发布文章失败: %v", err)
+		return nil, err
+	}
+	out := make([]*domain.Article, 0, len(resp.Data))
+	for _, a := range resp.Data {
+		out = append(out, fromPBArticle(a))
+	}
+	return out, nil
+}
+func (c *ContentClient) CancelScheduledArticle(ctx context.Context, id int64) error {
+	_, err := c.cli.CancelScheduledArticle(ctx, &cpb.Id{Id: id})
+	return err
+}
+
+// Revision
+func (c *ContentClient) ListArticleRevisions(ctx context.Context, articleID int64) ([]*domain.ArticleRevision, error) {
+	resp, err := c.cli.ListArticleRevisions(ctx, &cpb.Id{Id: articleID})
+	if err != nil {
+		logger.Log().Error("clients: 列表历史版本失败: article_id=%d err=%v", articleID, err)
+		return nil, err
+	}
+	out := make([]*domain.ArticleRevision, 0, len(resp.Data))
+	for _, r := range resp.Data {
+		out = append(out, fromPBRevision(r))
+	}
+	return out, nil
+}
+func (c *ContentClient) GetArticleRevision(ctx context.Context, articleID, revisionID int64) (*domain.ArticleRevision, error) {
+	r, err := c.cli.GetArticleRevision(ctx, &cpb.RevisionRequest{ArticleId: articleID, RevisionId: revisionID})
+	if err != nil {
+		logger.Log().Error("clients: 获取历史版本失败: article_id=%d revision_id=%d err=%v", articleID, revisionID, err)
+		return nil, err
+	}
+	return fromPBRevision(r), nil
+}
+func (c *ContentClient) DiffArticleRevisions(ctx context.Context, articleID, fromID, toID int64, mode string) (*domain.RevisionDiff, error) {
+	d, err := c.cli.DiffArticleRevisions(ctx, &cpb.RevisionDiffRequest{ArticleId: articleID, FromId: fromID, ToId: toID, Mode: mode})
+	if err != nil {
+		logger.Log().Error("clients: 比较历史版本失败: article_id=%d err=%v", articleID, err)
+		return nil, err
+	}
+	return &domain.RevisionDiff{
+		FromID: d.FromId, ToID: d.ToId, Mode: d.Mode, Unified: d.Unified,
+		Title: fromPBSegments(d.Title), Summary: fromPBSegments(d.Summary), Content: fromPBSegments(d.Content),
+	}, nil
+}
+func (c *ContentClient) RestoreArticleRevision(ctx context.Context, articleID, revisionID int64) error {
+	_, err := c.cli.RestoreArticleRevision(ctx, &cpb.RevisionRequest{ArticleId: articleID, RevisionId: revisionID})
+	return contentErr(err)
+}
+
+// fromPBRevision pb 历史版本转换为领域模型
+func fromPBRevision(r *cpb.ArticleRevision) *domain.ArticleRevision {
+	out := &domain.ArticleRevision{ID: r.Id, ArticleID: r.ArticleId, Title: r.Title, Slug: r.Slug, Content: r.Content, Summary: r.Summary, Cover: r.Cover, CategoryID: r.CategoryId}
+	out.CreatedAt, _ = time.Parse(time.RFC3339, r.CreatedAt)
+	return out
+}
+
+func fromPBSegments(list []*cpb.DiffSegment) []textdiff.Segment {
+	if len(list) == 0 {
+		return nil
+	}
+	out := make([]textdiff.Segment, 0, len(list))
+	for _, seg := range list {
+		out = append(out, textdiff.Segment{Op: textdiff.Op(seg.Op), Text: seg.Text})
+	}
+	return out
+}
+
+// 评论审核
+// ListComments status 为 nil 时不按状态过滤，articleID 为 0 时不按文章过滤
+func (c *ContentClient) ListComments(ctx context.Context, status *int, articleID int64, page, pageSize int) ([]*domain.Comment, int64, error) {
+	req := &cpb.ListCommentsRequest{Status: -1, ArticleId: articleID, Page: int32(page), PageSize: int32(pageSize)}
+	if status != nil {
+		req.Status = int32(*status)
+	}
+	resp, err := c.cli.ListComments(ctx, req)
+	if err != nil {
+		logger.Log().Error("clients: 列表评论失败: %v", err)
+		return nil, 0, contentErr(err)
+	}
+	out := make([]*domain.Comment, 0, len(resp.Data))
+	for _, cm := range resp.Data {
+		out = append(out, fromPBComment(cm))
+	}
+	return out, resp.Total, nil
+}
+func (c *ContentClient) ModerateComments(ctx context.Context, ids []int64, status int) (int64, error) {
+	resp, err := c.cli.ModerateComments(ctx, &cpb.ModerateCommentsRequest{Ids: ids, Status: int32(status)})
+	if err != nil {
+		return 0, contentErr(err)
+	}
+	return resp.Value, nil
+}
+func (c *ContentClient) DeleteComment(ctx context.Context, id int64) error {
+	_, err := c.cli.DeleteComment(ctx, &cpb.Id{Id: id})
+	return contentErr(err)
+}
+
+// fromPBComment pb 评论转换为领域模型
+func fromPBComment(c *cpb.Comment) *domain.Comment {
+	out := &domain.Comment{
+		ID: c.Id, ArticleID: c.ArticleId, ParentID: c.ParentId, RootID: c.RootId, UserID: c.UserId,
+		Content: c.Content, Status: int(c.Status), IP: c.Ip,
+	}
+	out.CreatedAt, _ = time.Parse(time.RFC3339, c.CreatedAt)
+	out.UpdatedAt, _ = time.Parse(time.RFC3339, c.UpdatedAt)
+	return out
+}
+
+// 文件管理
+func (c *ContentClient) ListMedia(ctx context.Context, f domain.MediaFilter) ([]*domain.Media, int64, error) {
+	resp, err := c.cli.ListMedia(ctx, &cpb.ListMediaRequest{
+		MimePrefix: f.MimePrefix, UploaderId: f.UploaderID, Orphan: f.Orphan,
+		OlderThanSeconds: int64(f.OlderThan / time.Second), Page: int32(f.Page), PageSize: int32(f.PageSize),
+	})
+	if err != nil {
+		logger.Log().Error("clients: 列表文件失败: %v", err)
+		return nil, 0, contentErr(err)
+	}
+	out := make([]*domain.Media, 0, len(resp.Data))
+	for _, m := range resp.Data {
+		out = append(out, fromPBMedia(m))
+	}
+	return out, resp.Total, nil
+}
+func (c *ContentClient) DeleteMedia(ctx context.Context, id int64, force bool) error {
+	_, err := c.cli.DeleteMedia(ctx, &cpb.DeleteMediaRequest{Id: id, Force: force})
+	return contentErr(err)
+}
+
+func (c *ContentClient) ImportArticles(ctx context.Context, archive []byte, authorID int64, dryRun bool) (*domain.ImportReport, error) {
+	resp, err := c.cli.ImportArticles(ctx, &cpb.ImportRequest{Archive: archive, DryRun: dryRun, AuthorId: authorID})
+	if err != nil {
+		return nil, contentErr(err)
+	}
+	out := &domain.ImportReport{
+		DryRun: resp.DryRun, Total: int(resp.Total), Created: int(resp.Created), Updated: int(resp.Updated),
+		Unchanged: int(resp.Unchanged), Failed: int(resp.Failed),
+		NewTags: append(make([]string, 0, len(resp.NewTags)), resp.NewTags...), NewCategories: append(make([]string, 0, len(resp.NewCategories)), resp.NewCategories...),
+		Items: make([]*domain.ImportItem, 0, len(resp.Items)),
+	}
+	for _, it := range resp.Items {
+		out.Items = append(out.Items, &domain.ImportItem{
+			File: it.File, Slug: it.Slug, Title: it.Title, Action: it.Action, ArticleID: it.ArticleId,
+			Images: int(it.Images), Missing: it.MissingImages, Error: it.Error,
+		})
+	}
+	return out, nil
+}
+
+// ExportSite 整站导出；归档可能超过默认的 4MB 接收上限，按 maxSize 放宽，超出时返回 ErrBackupTooLarge
+func (c *ContentClient) ExportSite(ctx context.Context, withMedia bool, maxSize int64) ([]byte, error) {
+	resp, err := c.cli.ExportSite(ctx, &cpb.ExportRequest{Media: withMedia}, grpc.MaxCallRecvMsgSize(int(maxSize)+exportOverhead))
+	if status.Code(err) == codes.ResourceExhausted || (err == nil && int64(len(resp.Archive)) > maxSize) {
+		logger.Log().Error("clients: 导出站点失败: 归档超过 %d 字节", maxSize)
+		return nil, domain.ErrBackupTooLarge
+	}
+	if err != nil {
+		logger.Log().Error("clients: 导出站点失败: %v", err)
+		return nil, contentErr(err)
+	}
+	return resp.Archive, nil
+}
+
+func (c *ContentClient) RestoreSite(ctx context.Context, archive []byte, authors map[int64]int64, defaultAuthor int64) (*domain.RestoreReport, error) {
+	req := &cpb.RestoreRequest{Archive: archive, DefaultAuthor: defaultAuthor}
+	for oldID, newID := range authors {
+		req.Authors = append(req.Authors, &cpb.AuthorMapping{OldId: oldID, NewId: newID})
+	}
+	resp, err := c.cli.RestoreSite(ctx, req)
+	if err != nil {
+		// 数据文件错误的信息中附带文件名，按前缀还原
+		if msg := status.Convert(err).Message(); status.Code(err) == codes.InvalidArgument && strings.HasPrefix(msg, domain.ErrBackupData.Error()) {
+			return nil, fmt.Errorf("%w%s", domain.ErrBackupData, strings.TrimPrefix(msg, domain.ErrBackupData.Error()))
+		}
+		return nil, contentErr(err)
+	}
+	count := func(c *cpb.RestoreCount) domain.RestoreCount {
+		return domain.RestoreCount{Created: int(c.GetCreated()), Existing: int(c.GetExisting()), Failed: int(c.GetFailed())}
+	}
+	return &domain.RestoreReport{
+		SchemaVersion: int(resp.SchemaVersion), Categories: count(resp.Categories), Tags: count(resp.Tags),
+		Articles: count(resp.Articles), Media: count(resp.Media), Errors: append(make([]string, 0, len(resp.Errors)), resp.Errors...),
+	}, nil
+}
+
+// fromPBMedia pb 文件转换为领域模型
+func fromPBMedia(m *cpb.Media) *domain.Media {
+	out := &domain.Media{
+		ID: m.Id, Hash: m.Hash, StorageKey: m.StorageKey, URL: m.Url, Name: m.Name, MimeType: m.MimeType,
+		Size: m.Size, Width: int(m.Width), Height: int(m.Height), UploaderID: m.UploaderId,
+		Variants: make([]*domain.MediaVariant, 0, len(m.Variants)),
+	}
+	for _, v := range m.Variants {
+		out.Variants = append(out.Variants, &domain.MediaVariant{
+			Name: v.Name, StorageKey: v.StorageKey, URL: v.Url, Width: int(v.Width), Height: int(v.Height), Size: v.Size,
+		})
+	}
+	out.CreatedAt, _ = time.Parse(time.RFC3339, m.CreatedAt)
+	return out
+}
+
 var _ application.ContentClient = (*ContentClient)(nil)
@@ -41,22 +41,82 @@ func NewUserServiceClient(cfg *conf.AppConfig) *UserServiceClient {
 }
 
 func (c *UserServiceClient) Create(ctx context.Context, u *domain.User) error {
-	_, err := c.cli.Register(ctx, &upb.RegisterRequest{Username: u.Username, Email: u.Email, Password: u.Password})
-	return err
+	resp, err := c.cli.AdminCreateUser(ctx, &upb.AdminCreateUserRequest{
+		Username: u.Username,
+		Email:    u.Email,
+		Password: u.Password,
+		Role:     u.Role,
+		Avatar:   u.Avatar,
+		Status:   int32(u.Status),
+	})
+	if err != nil {
+		logger.Log().Error("clients: 创建用户失败: %v", err)
+		return err
+	}
+	if resp.Code != 0 {
+		return errors.New(resp.Message)
+	}
+	if resp.Data != nil {
+		u.ID = resp.Data.Id
+	}
+	return nil
 }
 
 func (c *UserServiceClient) Update(ctx context.Context, u *domain.User) error {
-	_, err := c.cli.UpdateUserInfo(ctx, &upb.UpdateUserInfoRequest{UserId: u.ID, Username: u.Username, Email: u.Email, Avatar: u.Avatar, Role: u.Role})
-	return err
+	resp, err := c.cli.UpdateUserInfo(ctx, &upb.UpdateUserInfoRequest{UserId: u.ID, Username: u.Username, Email: u.Email, Avatar: u.Avatar, Role: u.Role})
+	if err != nil {
+		logger.Log().Error("clients: 更新用户失败: id=%d err=%v", u.ID, err)
+		return err
+	}
+	if resp.Code != 0 {
+		return errors.New(resp.Message)
+	}
+	return nil
+}
+
+func (c *UserServiceClient) Delete(ctx context.Context, id int64, hard bool) error {
+	resp, err := c.cli.DeleteUser(ctx, &upb.DeleteUserRequest{UserId: id, Hard: hard})
+	if err != nil {
+		logger.Log().Error("clients: 删除用户失败: id=%d hard=%v err=%v", id, hard, err)
+		return err
+	}
+	if resp.Code != 0 {
+		return errors.New(resp.Message)
+	}
+	return nil
+}
+
+func (c *UserServiceClient) SetStatus(ctx context.Context, id int64, status int) error {
+	resp, err := c.cli.UpdateUserStatus(ctx, &upb.UpdateUserStatusRequest{UserId: id, Status: int32(status)})
+	if err != nil {
+		logger.Log().Error("clients: 更新用户状态失败: id=%d err=%v", id, err)
+		return err
+	}
+	if resp.Code != 0 {
+		return errors.New(resp.Message)
+	}
+	return nil
 }
 
-func (c *UserServiceClient) Delete(ctx context.Context, id int64) error {
-	_, err := c.cli.UpdateUserStatus(ctx, &upb.UpdateUserStatusRequest{UserId: id, Status: 1})
-	return err
+func (c *UserServiceClient) ResetPassword(ctx context.Context, id int64, newPassword string) error {
+	resp, err := c.cli.AdminResetPassword(ctx, &upb.AdminResetPasswordRequest{UserId: id, NewPassword: newPassword})
+	if err != nil {
+		logger.Log().Error("clients: 重置密码失败: id=%d err=%v", id, err)
+		return err
+	}
+	if resp.Code != 0 {
+		return errors.New(resp.Message)
+	}
+	return nil
 }
 
-func (c *UserServiceClient) List(ctx context.Context, page, pageSize int) ([]*domain.User, int64, error) {
-	resp, err := c.cli.ListUsers(ctx, &upb.ListUsersRequest{Page: int32(page), PageSize: int32(pageSize)})
+func (c *UserServiceClient) List(ctx context.Context, filter domain.UserFilter, page, pageSize int) ([]*domain.User, int64, error) {
+	req := &upb.ListUsersRequest{Page: int32(page), PageSize: int32(pageSize), Role: filter.Role, Keyword: filter.Keyword}
+	if filter.Status != nil {
+		status := int32(*filter.Status)
+		req.Status = &status
+	}
+	resp, err := c.cli.ListUsers(ctx, req)
 	if err != nil {
 		logger.Log().Error("clients: 列表用户失败: %v", err)
 		return nil, 0, err
@@ -66,9 +126,24 @@ func (c *UserServiceClient) List(ctx context.Context, page, pageSize int) ([]*do
 	}
 	out := make([]*domain.User, 0, len(resp.Data))
 	for _, u := range resp.Data {
-		out = append(out, &domain.User{ID: u.Id, Username: u.Username, Email: u.Email, Role: u.Role, Status: int(u.Status)})
+		du := &domain.User{ID: u.Id, Username: u.Username, Email: u.Email, Role: u.Role, Avatar: u.Avatar, Status: int(u.Status)}
+		du.CreatedAt, _ = time.Parse(time.RFC3339, u.CreatedAt)
+		du.UpdatedAt, _ = time.Parse(time.RFC3339, u.UpdatedAt)
+		out = append(out, du)
 	}
 	return out, resp.Total, nil
 }
 
+func (c *UserServiceClient) SetRoleRequireTwoFactor(ctx context.Context, code string, require bool) error {
+	resp, err := c.cli.UpdateRole(ctx, &upb.UpdateRoleRequest{Code: code, RequireTwoFactor: require})
+	if err != nil {
+		logger.Log().Error("clients: 更新角色失败: code=%s err=%v", code, err)
+		return err
+	}
+	if resp.Code != 0 {
+		return errors.New(resp.Message)
+	}
+	return nil
+}
+
 var _ application.UserClient = (*UserServiceClient)(nil)
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blog-system/common/pkg/perm"
+	"blog-system/common/pkg/util"
+	"blog-system/services/admin/application"
+	"blog-system/services/admin/domain"
+
+	"github.com/CoucouMonEcho/go-framework/web"
+)
+
+// fakeArticles 内存文章：作者 1 与作者 2 各一篇
+type fakeArticles struct {
+	application.ContentClient
+	articles  map[int64]*domain.Article
+	lastQuery domain.ArticleQuery
+}
+
+func newFakeArticles() *fakeArticles {
+	return &fakeArticles{articles: map[int64]*domain.Article{
+		10: {ID: 10, AuthorID: 1, Status: 0},
+		20: {ID: 20, AuthorID: 2, Status: 0},
+	}}
+}
+
+func (f *fakeArticles) GetArticle(_ context.Context, id int64) (*domain.Article, error) {
+	if a, ok := f.articles[id]; ok {
+		return a, nil
+	}
+	return nil, errors.New("文章不存在")
+}
+
+func (f *fakeArticles) ListScheduledArticles(context.Context) ([]*domain.Article, error) {
+	return []*domain.Article{f.articles[10], f.articles[20]}, nil
+}
+
+func (f *fakeArticles) CancelScheduledArticle(context.Context, int64) error { return nil }
+
+func (f *fakeArticles) ListArticles(_ context.Context, q domain.ArticleQuery) (*domain.ArticlePage, error) {
+	f.lastQuery = q
+	return &domain.ArticlePage{}, nil
+}
+
+// serveAs 以指定权限的令牌声明调用 handler，返回状态码
+func serveAs(h web.Handler, route, method, target string, uid int64, perms ...string) int {
+	return serve(h, route, method, target, uid, perms...).Code
+}
+
+// serve 以指定权限的令牌声明调用 handler，route 为注册的路由模式
+func serve(h web.Handler, route, method, target string, uid int64, perms ...string) *httptest.ResponseRecorder {
+	srv := web.NewHTTPServer(web.ServerWithMiddlewares(func(next web.Handler) web.Handler {
+		return func(ctx *web.Context) {
+			ctx.UserValues = map[string]any{"admin_claims": &util.Claims{UserID: uid, Permissions: perms}}
+			next(ctx)
+		}
+	}))
+	if method == http.MethodPost {
+		srv.Post(route, h)
+	} else {
+		srv.Get(route, h)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec
+}
+
+func TestArticleOwnership(t *testing.T) {
+	content := newFakeArticles()
+	s := &HTTPServer{app: &application.AdminService{Content: content}}
+
+	// 作者只能查看自己的文章，忽略 author_id
+	serveAs(s.listArticles, "/api/articles", http.MethodGet, "/api/articles?author_id=2", 1, perm.ArticleEditOwn)
+	if content.lastQuery.AuthorID != 1 {
+		t.Fatalf("author list: AuthorID = %d, want 1", content.lastQuery.AuthorID)
+	}
+	serveAs(s.listArticles, "/api/articles", http.MethodGet, "/api/articles?author_id=2", 1, perm.ArticleEdit)
+	if content.lastQuery.AuthorID != 2 {
+		t.Fatalf("editor list: AuthorID = %d, want 2", content.lastQuery.AuthorID)
+	}
+	serveAs(s.listArticles, "/api/articles", http.MethodGet, "/api/articles", 1, perm.ArticleEdit)
+	if content.lastQuery.AuthorID != 0 {
+		t.Fatalf("editor list: AuthorID = %d, want 0", content.lastQuery.AuthorID)
+	}
+
+	tests := []struct {
+		name  string
+		id    string
+		perms []string
+		want  int
+	}{
+		{"own article", "10", []string{perm.ArticleEditOwn}, http.StatusOK},
+		{"other author's draft", "20", []string{perm.ArticleEditOwn}, http.StatusForbidden},
+		{"create only", "20", []string{perm.ArticleCreate}, http.StatusForbidden},
+		{"editor", "20", []string{perm.ArticleEdit}, http.StatusOK},
+		{"missing", "30", []string{perm.ArticleEditOwn}, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if code := serveAs(s.getArticle, "/api/articles/:id", http.MethodGet, "/api/articles/"+tt.id, 1, tt.perms...); code != tt.want {
+			t.Errorf("get %s: code = %d, want %d", tt.name, code, tt.want)
+		}
+	}
+}
+
+func TestScheduledArticleOwnership(t *testing.T) {
+	s := &HTTPServer{app: &application.AdminService{Content: newFakeArticles()}}
+
+	list := func(perms ...string) int {
+		rec := serve(s.listScheduledArticles, "/api/articles/scheduled", http.MethodGet, "/api/articles/scheduled", 1, perms...)
+		var resp struct {
+			Data struct {
+				List []*domain.Article `json:"list"`
+			} `json:"data"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		return len(resp.Data.List)
+	}
+	if n := list(perm.ArticlePublish); n != 1 {
+		t.Fatalf("author scheduled list: %d articles, want 1", n)
+	}
+	if n := list(perm.ArticlePublish, perm.ArticleEdit); n != 2 {
+		t.Fatalf("editor scheduled list: %d articles, want 2", n)
+	}
+
+	tests := []struct {
+		name  string
+		id    string
+		perms []string
+		want  int
+	}{
+		{"own article", "10", []string{perm.ArticlePublish}, http.StatusOK},
+		{"other author's article", "20", []string{perm.ArticlePublish}, http.StatusForbidden},
+		{"editor", "20", []string{perm.ArticlePublish, perm.ArticleEdit}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		if code := serveAs(s.cancelScheduledArticle, "/api/articles/scheduled/cancel/:id", http.MethodPost,
+			"/api/articles/scheduled/cancel/"+tt.id, 1, tt.perms...); code != tt.want {
+			t.Errorf("cancel %s: code = %d, want %d", tt.name, code, tt.want)
+		}
+	}
+}
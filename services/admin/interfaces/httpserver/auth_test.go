@@ -0,0 +1,46 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"blog-system/common/pkg/logger"
+	"blog-system/common/pkg/perm"
+	"blog-system/common/pkg/util"
+	"blog-system/services/admin/application"
+
+	"github.com/CoucouMonEcho/go-framework/cache"
+	"github.com/CoucouMonEcho/go-framework/web"
+)
+
+func TestAuthenticateRejectsRevokedToken(t *testing.T) {
+	c := cache.NewBuildInMapCache(time.Minute)
+	s := &HTTPServer{app: &application.AdminService{Cache: c, Logger: logger.Log()}}
+	token, err := util.GenerateToken(1, "admin", []string{perm.ArticleEdit})
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/api/articles", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		ctx := &web.Context{Req: req, Resp: httptest.NewRecorder()}
+		if s.authenticate(ctx) {
+			return http.StatusOK
+		}
+		return ctx.RespCode
+	}
+
+	// 签名有效但不在登录缓存中（已吊销）
+	if code := auth(); code != http.StatusUnauthorized {
+		t.Fatalf("revoked token: code = %d, want 401", code)
+	}
+	if err := c.Set(context.Background(), "token_"+token, "1", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if code := auth(); code != http.StatusOK {
+		t.Fatalf("active token: code = %d, want 200", code)
+	}
+}
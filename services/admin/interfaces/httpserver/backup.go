@@ -0,0 +1,87 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"blog-system/common/pkg/dto"
+	"blog-system/common/pkg/errcode"
+	"blog-system/common/pkg/perm"
+	"blog-system/services/admin/domain"
+
+	"github.com/CoucouMonEcho/go-framework/web"
+)
+
+// exportSite 下载整站备份 zip：users=true 时包含用户（不含密码），media=false 时不含上传的文件；
+// 归档超过恢复上传上限时返回 413
+func (s *HTTPServer) exportSite(ctx *web.Context) {
+	q := ctx.Req.URL.Query()
+	withUsers, _ := strconv.ParseBool(q.Get("users"))
+	withMedia := true
+	if v := q.Get("media"); v != "" {
+		withMedia, _ = strconv.ParseBool(v)
+	}
+	data, err := s.app.ExportSite(ctx.Req.Context(), withUsers, withMedia, s.importMax)
+	if errors.Is(err, domain.ErrBackupTooLarge) {
+		_ = ctx.RespJSON(http.StatusRequestEntityTooLarge, dto.Error(errcode.ErrMediaTooLarge, err.Error()))
+		return
+	}
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		return
+	}
+	name := "backup-" + time.Now().Format("20060102-150405") + ".zip"
+	h := ctx.Resp.Header()
+	h.Set("Content-Type", "application/zip")
+	h.Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	ctx.RespCode = http.StatusOK
+	ctx.RespData = data
+}
+
+// restoreSite multipart 上传备份 zip（字段名 file）恢复站点；已有同别名的文章、分类与标签不覆盖。
+// users=true 时创建备份中缺失的用户（另需 user:manage），否则只按用户名或邮箱匹配已有用户，其余作者取当前管理员
+func (s *HTTPServer) restoreSite(ctx *web.Context) {
+	withUsers, _ := strconv.ParseBool(ctx.Req.URL.Query().Get("users"))
+	if withUsers && !perm.Has(claimsOf(ctx).Permissions, perm.UserManage) {
+		_ = ctx.RespJSON(http.StatusForbidden, dto.Error(errcode.ErrAdminForbidden, "创建用户需 user:manage 权限"))
+		return
+	}
+	ctx.Req.Body = http.MaxBytesReader(ctx.Resp, ctx.Req.Body, s.importMax+multipartOverhead)
+	file, _, err := ctx.Req.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			_ = ctx.RespJSON(http.StatusRequestEntityTooLarge, dto.Error(errcode.ErrMediaTooLarge, "备份文件过大"))
+			return
+		}
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "缺少文件字段 file"))
+		return
+	}
+	defer func() { _ = file.Close() }()
+	if ctx.Req.MultipartForm != nil {
+		defer func() { _ = ctx.Req.MultipartForm.RemoveAll() }()
+	}
+	data, err := io.ReadAll(io.LimitReader(file, s.importMax+1))
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	if int64(len(data)) > s.importMax {
+		_ = ctx.RespJSON(http.StatusRequestEntityTooLarge, dto.Error(errcode.ErrMediaTooLarge, "备份文件过大"))
+		return
+	}
+	report, err := s.app.RestoreSite(ctx.Req.Context(), data, withUsers, claimsOf(ctx).UserID)
+	if err != nil {
+		if errors.Is(err, domain.ErrImportArchive) || errors.Is(err, domain.ErrBackupManifest) ||
+			errors.Is(err, domain.ErrBackupVersion) || errors.Is(err, domain.ErrBackupData) {
+			_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+			return
+		}
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(report))
+}
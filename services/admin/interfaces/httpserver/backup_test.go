@@ -0,0 +1,24 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blog-system/common/pkg/perm"
+	"blog-system/common/pkg/util"
+
+	"github.com/CoucouMonEcho/go-framework/web"
+)
+
+func TestRestoreSiteUsersRequiresUserManage(t *testing.T) {
+	s := &HTTPServer{}
+	req := httptest.NewRequest(http.MethodPost, "/api/restore?users=true", nil)
+	ctx := &web.Context{Req: req, Resp: httptest.NewRecorder(), UserValues: map[string]any{
+		"admin_claims": &util.Claims{UserID: 1, Permissions: []string{perm.SiteBackup}},
+	}}
+	s.restoreSite(ctx)
+	if ctx.RespCode != http.StatusForbidden {
+		t.Fatalf("code = %d, want 403", ctx.RespCode)
+	}
+}
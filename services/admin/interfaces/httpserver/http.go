@@ -4,12 +4,16 @@ import (
 	"blog-system/common/pkg/dto"
 	"blog-system/common/pkg/errcode"
 	"blog-system/common/pkg/logger"
+	"blog-system/common/pkg/pagination"
+	"blog-system/common/pkg/perm"
 	"blog-system/common/pkg/util"
 	"blog-system/services/admin/application"
 	"blog-system/services/admin/domain"
+	"errors"
 	"net/http"
 	"runtime/debug"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/CoucouMonEcho/go-framework/web"
@@ -18,8 +22,9 @@ import (
 )
 
 type HTTPServer struct {
-	server *web.HTTPServer
-	app    *application.AdminService
+	server    *web.HTTPServer
+	app       *application.AdminService
+	importMax int64 // 导入压缩包大小上限（字节）
 }
 
 func NewHTTPServer() *HTTPServer {
@@ -59,7 +64,7 @@ func NewHTTPServer() *HTTPServer {
 			webprom.MiddlewareBuilder{Namespace: "blog", Subsystem: "admin", Name: "http", Help: "admin http latency"}.Build(),
 		),
 	)
-	s := &HTTPServer{server: server}
+	s := &HTTPServer{server: server, importMax: domain.ImportMaxSize}
 	s.server.Get("/health", func(ctx *web.Context) {
 		_ = ctx.RespJSONOK(dto.Success(map[string]any{"status": "ok", "service": "admin"}))
 	})
@@ -68,36 +73,73 @@ func NewHTTPServer() *HTTPServer {
 	s.server.Use(http.MethodPost, "/api/*", s.adminAuth())
 
 	// 用户管理
-	s.server.Get("/api/users", s.listUsers)
-	s.server.Post("/api/users", s.createUser)
-	s.server.Post("/api/users/update/:id", s.updateUser)
-	s.server.Post("/api/users/delete/:id", s.deleteUser)
+	s.server.Get("/api/users", s.guard(s.listUsers, perm.UserManage))
+	s.server.Post("/api/users", s.guard(s.createUser, perm.UserManage))
+	s.server.Post("/api/users/update/:id", s.guard(s.updateUser, perm.UserManage))
+	s.server.Post("/api/users/delete/:id", s.guard(s.deleteUser, perm.UserManage))
+	s.server.Post("/api/users/password/:id", s.guard(s.resetUserPassword, perm.UserManage))
+	s.server.Post("/api/roles/update/:code", s.guard(s.updateRole, perm.UserManage))
 
 	// 文章管理
-	s.server.Get("/api/articles", s.listArticles)
-	s.server.Post("/api/articles", s.createArticle)
-	s.server.Post("/api/articles/update/:id", s.updateArticle)
-	s.server.Post("/api/articles/delete/:id", s.deleteArticle)
+	s.server.Get("/api/articles", s.guard(s.listArticles, perm.ArticleCreate, perm.ArticleEdit, perm.ArticleEditOwn))
+	s.server.Post("/api/articles", s.guard(s.createArticle, perm.ArticleCreate))
+	s.server.Get("/api/articles/:id", s.guard(s.getArticle, perm.ArticleCreate, perm.ArticleEdit, perm.ArticleEditOwn))
+	s.server.Post("/api/articles/update/:id", s.guard(s.updateArticle, perm.ArticleEdit, perm.ArticleEditOwn))
+	s.server.Post("/api/articles/delete/:id", s.guard(s.deleteArticle, perm.ArticleDelete))
+	s.server.Get("/api/articles/scheduled", s.guard(s.listScheduledArticles, perm.ArticlePublish))
+	s.server.Post("/api/articles/scheduled/cancel/:id", s.guard(s.cancelScheduledArticle, perm.ArticlePublish))
+	// 历史版本（:id 为文章 ID，:rid 为版本 ID）
+	s.server.Get("/api/articles/revisions/:id", s.guard(s.listArticleRevisions, perm.ArticleEdit, perm.ArticleEditOwn))
+	s.server.Get("/api/articles/revisions/:id/diff", s.guard(s.diffArticleRevisions, perm.ArticleEdit, perm.ArticleEditOwn))
+	s.server.Get("/api/articles/revisions/:id/:rid", s.guard(s.getArticleRevision, perm.ArticleEdit, perm.ArticleEditOwn))
+	s.server.Post("/api/articles/revisions/:id/:rid/restore", s.guard(s.restoreArticleRevision, perm.ArticleEdit, perm.ArticleEditOwn))
 
 	// 分类管理
 	s.server.Get("/api/categories", s.listCategories)
-	s.server.Post("/api/categories", s.createCategory)
-	s.server.Post("/api/categories/update/:id", s.updateCategory)
-	s.server.Post("/api/categories/delete/:id", s.deleteCategory)
+	s.server.Post("/api/categories", s.guard(s.createCategory, perm.CategoryManage))
+	s.server.Post("/api/categories/update/:id", s.guard(s.updateCategory, perm.CategoryManage))
+	s.server.Post("/api/categories/delete/:id", s.guard(s.deleteCategory, perm.CategoryManage))
 	// 分级菜单（树）
 	s.server.Get("/api/categories/tree", s.categoryTree)
+	s.server.Post("/api/categories/move/:id", s.guard(s.moveCategory, perm.CategoryManage))
+
+	// 标签管理
+	s.server.Get("/api/tags", s.listTags)
+	s.server.Post("/api/tags", s.guard(s.createTag, perm.TagManage))
+	s.server.Post("/api/tags/update/:id", s.guard(s.updateTag, perm.TagManage))
+	s.server.Post("/api/tags/delete/:id", s.guard(s.deleteTag, perm.TagManage))
+	s.server.Post("/api/tags/merge/:id", s.guard(s.mergeTag, perm.TagManage))
+
+	// 评论审核
+	s.server.Get("/api/comments", s.guard(s.listComments, perm.CommentModerate))
+	s.server.Post("/api/comments/moderate", s.guard(s.moderateComments, perm.CommentModerate))
+	s.server.Post("/api/comments/delete/:id", s.guard(s.deleteComment, perm.CommentModerate))
+	s.server.Get("/api/media", s.guard(s.listMedia, perm.MediaManage))
+	s.server.Get("/api/media/orphans", s.guard(s.listOrphanMedia, perm.MediaManage))
+	s.server.Post("/api/media/delete/:id", s.guard(s.deleteMedia, perm.MediaManage))
+
+	// 导入（Hexo / Hugo Markdown）
+	s.server.Post("/api/import", s.guard(s.importArticles, perm.ContentImport))
+
+	// 整站备份与恢复
+	s.server.Get("/api/backup", s.guard(s.exportSite, perm.SiteBackup))
+	s.server.Post("/api/restore", s.guard(s.restoreSite, perm.SiteBackup))
+
+	// 全文索引
+	s.server.Post("/api/search/rebuild", s.guard(s.rebuildSearchIndex, perm.SearchManage))
+
 	// 仪表盘统计
-	s.server.Get("/api/stat/overview", s.statOverview)
-	s.server.Get("/api/stat/pv_timeseries", s.statPVSeries)
-	s.server.Get("/api/stat/error_rate", s.statErrorRate)
-	s.server.Get("/api/stat/latency_percentile", s.statLatency)
-	s.server.Get("/api/stat/top_endpoints", s.statTopEndpoints)
-	s.server.Get("/api/stat/active_users", s.statActiveUsers)
+	s.server.Get("/api/stat/overview", s.guard(s.statOverview, perm.StatView))
+	s.server.Get("/api/stat/pv_timeseries", s.guard(s.statPVSeries, perm.StatView))
+	s.server.Get("/api/stat/error_rate", s.guard(s.statErrorRate, perm.StatView))
+	s.server.Get("/api/stat/latency_percentile", s.guard(s.statLatency, perm.StatView))
+	s.server.Get("/api/stat/top_endpoints", s.guard(s.statTopEndpoints, perm.StatView))
+	s.server.Get("/api/stat/active_users", s.guard(s.statActiveUsers, perm.StatView))
 	return s
 }
 
-// 简单权限检查：基于 Authorization JWT 的 role == "admin"
-func (s *HTTPServer) requireAdmin(ctx *web.Context) bool {
+// authenticate 解析 Authorization JWT 并校验令牌未被吊销，要求令牌至少携带一项管理权限
+func (s *HTTPServer) authenticate(ctx *web.Context) bool {
 	token := ctx.Req.Header.Get("Authorization")
 	if token == "" {
 		_ = ctx.RespJSON(http.StatusUnauthorized, dto.Error(errcode.ErrUnauthorized, "缺少认证令牌"))
@@ -111,11 +153,20 @@ func (s *HTTPServer) requireAdmin(ctx *web.Context) bool {
 		_ = ctx.RespJSON(http.StatusUnauthorized, dto.Error(errcode.ErrTokenInvalid, err.Error()))
 		return false
 	}
-	if claims.Role != "admin" {
+	// 与网关一致：已吊销（不在缓存中）的令牌即使签名有效也拒绝
+	if !s.app.TokenActive(ctx.Req.Context(), token) {
+		_ = ctx.RespJSON(http.StatusUnauthorized, dto.Error(errcode.ErrTokenInvalid, "令牌已过期或无效"))
+		return false
+	}
+	if len(claims.Permissions) == 0 {
 		_ = ctx.RespJSON(http.StatusForbidden, dto.Error(errcode.ErrAdminForbidden, "需要管理员权限"))
 		return false
 	}
+	if ctx.UserValues == nil {
+		ctx.UserValues = make(map[string]any)
+	}
 	ctx.UserValues["admin_user_id"] = claims.UserID
+	ctx.UserValues["admin_claims"] = claims
 	return true
 }
 
@@ -127,7 +178,7 @@ func (s *HTTPServer) adminAuth() web.Middleware {
 				next(ctx)
 				return
 			}
-			if !s.requireAdmin(ctx) {
+			if !s.authenticate(ctx) {
 				return
 			}
 			next(ctx)
@@ -135,10 +186,39 @@ func (s *HTTPServer) adminAuth() web.Middleware {
 	}
 }
 
+// guard 要求令牌具备任一指定权限
+func (s *HTTPServer) guard(h web.Handler, perms ...string) web.Handler {
+	return func(ctx *web.Context) {
+		if !perm.HasAny(claimsOf(ctx).Permissions, perms...) {
+			_ = ctx.RespJSON(http.StatusForbidden, dto.Error(errcode.ErrAdminForbidden, "权限不足"))
+			return
+		}
+		h(ctx)
+	}
+}
+
+// claimsOf 读取 adminAuth 写入的令牌声明
+func claimsOf(ctx *web.Context) *util.Claims {
+	if c, ok := ctx.UserValues["admin_claims"].(*util.Claims); ok {
+		return c
+	}
+	return &util.Claims{}
+}
+
 // 具体处理
 func (s *HTTPServer) listUsers(ctx *web.Context) {
 	page, pageSize := parsePagination(ctx)
-	list, total, err := s.app.ListUsers(ctx.Req.Context(), page, pageSize)
+	q := ctx.Req.URL.Query()
+	filter := domain.UserFilter{Role: q.Get("role"), Keyword: q.Get("keyword")}
+	if v := q.Get("status"); v != "" {
+		status, err := strconv.Atoi(v)
+		if err != nil {
+			_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "status 不合法"))
+			return
+		}
+		filter.Status = &status
+	}
+	list, total, err := s.app.ListUsers(ctx.Req.Context(), filter, page, pageSize)
 	if err != nil {
 		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
 		return
@@ -179,17 +259,23 @@ func (s *HTTPServer) updateUser(ctx *web.Context) {
 		Password string `json:"password,omitempty"`
 		Role     string `json:"role,omitempty"`
 		Avatar   string `json:"avatar,omitempty"`
-		Status   int    `json:"status,omitempty"`
+		Status   *int   `json:"status,omitempty"`
 	}
 	if err := ctx.BindJSON(&req); err != nil {
 		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
 		return
 	}
-	u := &domain.User{ID: id, Username: req.Username, Email: req.Email, Password: req.Password, Role: req.Role, Avatar: req.Avatar, Status: req.Status}
+	u := &domain.User{ID: id, Username: req.Username, Email: req.Email, Password: req.Password, Role: req.Role, Avatar: req.Avatar}
 	if err := s.app.UpdateUser(ctx.Req.Context(), u); err != nil {
 		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
 		return
 	}
+	if req.Status != nil {
+		if err := s.app.SetUserStatus(ctx.Req.Context(), id, *req.Status); err != nil {
+			_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+			return
+		}
+	}
 	_ = ctx.RespJSONOK(dto.SuccessNil())
 }
 
@@ -199,20 +285,85 @@ func (s *HTTPServer) deleteUser(ctx *web.Context) {
 		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "id 不合法"))
 		return
 	}
-	if err := s.app.DeleteUser(ctx.Req.Context(), id); err != nil {
+	hard := ctx.Req.URL.Query().Get("hard") == "true"
+	if id == claimsOf(ctx).UserID {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "不能删除当前登录用户"))
+		return
+	}
+	if err := s.app.DeleteUser(ctx.Req.Context(), id, hard); err != nil {
 		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
 		return
 	}
 	_ = ctx.RespJSONOK(dto.SuccessNil())
 }
 
+func (s *HTTPServer) resetUserPassword(ctx *web.Context) {
+	id, err := ctx.PathValue("id").AsInt64()
+	if err != nil || id <= 0 {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "id 不合法"))
+		return
+	}
+	var req struct {
+		Password string `json:"password"`
+	}
+	if err := ctx.BindJSON(&req); err != nil || req.Password == "" {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "参数错误"))
+		return
+	}
+	if err := s.app.ResetUserPassword(ctx.Req.Context(), id, req.Password); err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	_ = ctx.RespJSONOK(dto.SuccessNil())
+}
+
+func (s *HTTPServer) updateRole(ctx *web.Context) {
+	code, err := ctx.PathValue("code").String()
+	if err != nil || code == "" {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "code 不合法"))
+		return
+	}
+	var req struct {
+		RequireTwoFactor bool `json:"require_two_factor"`
+	}
+	if err := ctx.BindJSON(&req); err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	if err := s.app.SetRoleRequireTwoFactor(ctx.Req.Context(), code, req.RequireTwoFactor); err != nil {
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		return
+	}
+	_ = ctx.RespJSONOK(dto.SuccessNil())
+}
+
+// listArticles 文章列表（游标分页）：cursor 为上一页返回的 next_cursor，可按 author_id 筛选（此时不返回 total）；
+// 没有 article:edit 时只列出自己的文章
 func (s *HTTPServer) listArticles(ctx *web.Context) {
-	list, total, err := s.app.ListArticles(ctx.Req.Context())
+	q := ctx.Req.URL.Query()
+	_, pageSize := parsePagination(ctx)
+	authorID, _ := strconv.ParseInt(q.Get("author_id"), 10, 64)
+	// 没有 article:edit 时只能查看自己的文章（含草稿、私密与定时发布）
+	if claims := claimsOf(ctx); !claims.HasPermission(perm.ArticleEdit) {
+		authorID = claims.UserID
+	}
+	p, err := s.app.ListArticles(ctx.Req.Context(), domain.ArticleQuery{Cursor: q.Get("cursor"), PageSize: pageSize, AuthorID: authorID})
 	if err != nil {
+		if errors.Is(err, pagination.ErrInvalidCursor) {
+			_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+			return
+		}
 		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
 		return
 	}
-	_ = ctx.RespJSONOK(dto.Success(dto.PageResponse[*domain.Article]{List: list, Total: total, Page: 1, PageSize: len(list)}))
+	type ArticlePageVO struct {
+		dto.CursorResponse[*domain.Article]
+		Total int64 `json:"total,omitempty"`
+	}
+	_ = ctx.RespJSONOK(dto.Success(ArticlePageVO{
+		CursorResponse: dto.CursorResponse[*domain.Article]{List: p.List, NextCursor: p.NextCursor, HasMore: p.NextCursor != "", PageSize: pageSize},
+		Total:          p.Total,
+	}))
 }
 
 func (s *HTTPServer) createArticle(ctx *web.Context) {
@@ -227,18 +378,38 @@ func (s *HTTPServer) createArticle(ctx *web.Context) {
 		IsTop       bool    `json:"is_top"`
 		IsRecommend bool    `json:"is_recommend"`
 		PublishedAt *string `json:"published_at,omitempty"`
+		TagIDs      []int64 `json:"tag_ids,omitempty"`
+
+		MetaTitle    string `json:"meta_title"`
+		MetaDesc     string `json:"meta_desc"`
+		MetaKeywords string `json:"meta_keywords"`
 	}
 	if err := ctx.BindJSON(&req); err != nil || req.Title == "" {
 		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "参数错误"))
 		return
 	}
+	publishedAt, err := parsePublishedAt(req.PublishedAt)
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "published_at 须为 RFC3339 时间"))
+		return
+	}
+	claims := claimsOf(ctx)
+	if publishing(req.Status) && !claims.HasPermission(perm.ArticlePublish) {
+		_ = ctx.RespJSON(http.StatusForbidden, dto.Error(errcode.ErrAdminForbidden, "无发布权限"))
+		return
+	}
+	// 无 article:edit 权限时只能以自己为作者
+	if req.AuthorID == 0 || !claims.HasPermission(perm.ArticleEdit) {
+		req.AuthorID = claims.UserID
+	}
 	a := &domain.Article{
 		Title: req.Title, Slug: req.Slug, Content: req.Content, Summary: req.Summary,
 		AuthorID: req.AuthorID, CategoryID: req.CategoryID, Status: req.Status,
-		IsTop: req.IsTop, IsRecommend: req.IsRecommend,
+		IsTop: req.IsTop, IsRecommend: req.IsRecommend, PublishedAt: publishedAt, TagIDs: req.TagIDs,
+		MetaTitle: req.MetaTitle, MetaDesc: req.MetaDesc, MetaKeywords: req.MetaKeywords,
 	}
 	if err := s.app.CreateArticle(ctx.Req.Context(), a); err != nil {
-		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		respWriteErr(ctx, err)
 		return
 	}
 	_ = ctx.RespJSONOK(dto.SuccessNil())
@@ -251,27 +422,291 @@ func (s *HTTPServer) updateArticle(ctx *web.Context) {
 		return
 	}
 	var req struct {
-		Title       string `json:"title,omitempty"`
-		Slug        string `json:"slug,omitempty"`
-		Content     string `json:"content,omitempty"`
-		Summary     string `json:"summary,omitempty"`
-		CategoryID  int64  `json:"category_id,omitempty"`
-		Status      int    `json:"status,omitempty"`
-		IsTop       bool   `json:"is_top,omitempty"`
-		IsRecommend bool   `json:"is_recommend,omitempty"`
+		Title       string   `json:"title,omitempty"`
+		Slug        string   `json:"slug,omitempty"`
+		Content     string   `json:"content,omitempty"`
+		Summary     string   `json:"summary,omitempty"`
+		CategoryID  int64    `json:"category_id,omitempty"`
+		Status      int      `json:"status,omitempty"`
+		IsTop       bool     `json:"is_top,omitempty"`
+		IsRecommend bool     `json:"is_recommend,omitempty"`
+		PublishedAt *string  `json:"published_at,omitempty"`
+		Version     int64    `json:"version,omitempty"`
+		TagIDs      *[]int64 `json:"tag_ids,omitempty"` // 省略时保持不变，[] 清除
+
+		MetaTitle    string `json:"meta_title,omitempty"`
+		MetaDesc     string `json:"meta_desc,omitempty"`
+		MetaKeywords string `json:"meta_keywords,omitempty"`
 	}
 	if err := ctx.BindJSON(&req); err != nil {
 		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
 		return
 	}
-	a := &domain.Article{ID: id, Title: req.Title, Slug: req.Slug, Content: req.Content, Summary: req.Summary, CategoryID: req.CategoryID, Status: req.Status, IsTop: req.IsTop, IsRecommend: req.IsRecommend}
+	publishedAt, err := parsePublishedAt(req.PublishedAt)
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "published_at 须为 RFC3339 时间"))
+		return
+	}
+	// 期望版本：If-Match 优先，其次请求体 version，均缺省时不校验
+	version, ifMatch, err := parseIfMatch(ctx.Req.Header.Get("If-Match"))
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	if !ifMatch {
+		version = req.Version
+	}
+	claims := claimsOf(ctx)
+	if publishing(req.Status) && !claims.HasPermission(perm.ArticlePublish) {
+		_ = ctx.RespJSON(http.StatusForbidden, dto.Error(errcode.ErrAdminForbidden, "无发布权限"))
+		return
+	}
+	if !s.canEditArticle(ctx, id) {
+		return
+	}
+	a := &domain.Article{ID: id, Title: req.Title, Slug: req.Slug, Content: req.Content, Summary: req.Summary, CategoryID: req.CategoryID, Status: req.Status, IsTop: req.IsTop, IsRecommend: req.IsRecommend, PublishedAt: publishedAt, Version: version,
+		MetaTitle: req.MetaTitle, MetaDesc: req.MetaDesc, MetaKeywords: req.MetaKeywords}
+	if req.TagIDs != nil {
+		a.TagIDs = append(make([]int64, 0, len(*req.TagIDs)), *req.TagIDs...)
+	}
 	if err := s.app.UpdateArticle(ctx.Req.Context(), a); err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			// If-Match 不满足按 HTTP 语义返回 412，请求体携带版本时返回 409
+			code := http.StatusConflict
+			if ifMatch {
+				code = http.StatusPreconditionFailed
+			}
+			_ = ctx.RespJSON(code, dto.Error(errcode.ErrConflict, err.Error()))
+			return
+		}
+		respWriteErr(ctx, err)
+		return
+	}
+	if version > 0 {
+		setETag(ctx, version+1)
+	}
+	_ = ctx.RespJSONOK(dto.SuccessNil())
+}
+
+// getArticle 文章详情，ETag 为当前版本号，可用于后续更新的 If-Match
+func (s *HTTPServer) getArticle(ctx *web.Context) {
+	id, err := ctx.PathValue("id").AsInt64()
+	if err != nil || id <= 0 {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "id 不合法"))
+		return
+	}
+	if !s.canEditArticle(ctx, id) {
+		return
+	}
+	a, err := s.app.GetArticle(ctx.Req.Context(), id)
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusNotFound, dto.Error(errcode.ErrArticleNotFound, err.Error()))
+		return
+	}
+	setETag(ctx, a.Version)
+	_ = ctx.RespJSONOK(dto.Success(a))
+}
+
+// respWriteErr 写操作失败的统一响应：别名已占用 409，别名格式错误 400，其余 500
+func respWriteErr(ctx *web.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrSlugTaken), errors.Is(err, domain.ErrTagNameTaken):
+		_ = ctx.RespJSON(http.StatusConflict, dto.Error(errcode.ErrConflict, err.Error()))
+	case errors.Is(err, domain.ErrSlugInvalid), errors.Is(err, domain.ErrCategoryParent),
+		errors.Is(err, domain.ErrCategoryCycle), errors.Is(err, domain.ErrCategoryTooDeep),
+		errors.Is(err, domain.ErrCategoryDeleteMode), errors.Is(err, domain.ErrCategoryTarget),
+		errors.Is(err, domain.ErrTagMergeSelf), errors.Is(err, domain.ErrTagInvalid),
+		errors.Is(err, domain.ErrCommentStatus):
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+	case errors.Is(err, domain.ErrCategoryHasChildren), errors.Is(err, domain.ErrCategoryNotEmpty),
+		errors.Is(err, domain.ErrMediaInUse):
+		_ = ctx.RespJSON(http.StatusConflict, dto.Error(errcode.ErrConflict, err.Error()))
+	case errors.Is(err, domain.ErrCategoryNotFound), errors.Is(err, domain.ErrTagNotFound),
+		errors.Is(err, domain.ErrCommentNotFound), errors.Is(err, domain.ErrMediaNotFound):
+		_ = ctx.RespJSON(http.StatusNotFound, dto.Error(errcode.ErrNotFound, err.Error()))
+	default:
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+	}
+}
+
+// setETag 以版本号作为强 ETag
+func setETag(ctx *web.Context, version int64) {
+	ctx.Resp.Header().Set("ETag", strconv.Quote(strconv.FormatInt(version, 10)))
+}
+
+// parseIfMatch 解析 If-Match（"3"、W/"3" 或 3），缺省或 * 时 ok 为 false
+func parseIfMatch(v string) (version int64, ok bool, err error) {
+	v = strings.TrimSpace(v)
+	if v == "" || v == "*" {
+		return 0, false, nil
+	}
+	v = strings.Trim(strings.TrimPrefix(v, "W/"), `"`)
+	version, err = strconv.ParseInt(v, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, false, errors.New("If-Match 须为文章版本号")
+	}
+	return version, true, nil
+}
+
+// canEditArticle 无 article:edit 时仅允许编辑自己的文章，不允许时已写入响应
+func (s *HTTPServer) canEditArticle(ctx *web.Context, id int64) bool {
+	claims := claimsOf(ctx)
+	if claims.HasPermission(perm.ArticleEdit) {
+		return true
+	}
+	own, err := s.app.IsArticleAuthor(ctx.Req.Context(), id, claims.UserID)
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusNotFound, dto.Error(errcode.ErrArticleNotFound, err.Error()))
+		return false
+	}
+	if !own {
+		_ = ctx.RespJSON(http.StatusForbidden, dto.Error(errcode.ErrAdminForbidden, "只能编辑自己的文章"))
+		return false
+	}
+	return true
+}
+
+// publishing 发布（1）与定时发布（3）均需 article:publish
+func publishing(status int) bool { return status == 1 || status == 3 }
+
+// parsePublishedAt 解析可选的 RFC3339 发布时间
+func parsePublishedAt(v *string) (*time.Time, error) {
+	if v == nil || *v == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, *v)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// listScheduledArticles 定时发布中的文章
+func (s *HTTPServer) listScheduledArticles(ctx *web.Context) {
+	list, err := s.app.ListScheduledArticles(ctx.Req.Context())
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		return
+	}
+	// 没有 article:edit 时只返回自己的文章
+	if claims := claimsOf(ctx); !claims.HasPermission(perm.ArticleEdit) {
+		own := make([]*domain.Article, 0, len(list))
+		for _, a := range list {
+			if a.AuthorID == claims.UserID {
+				own = append(own, a)
+			}
+		}
+		list = own
+	}
+	_ = ctx.RespJSONOK(dto.Success(dto.PageResponse[*domain.Article]{List: list, Total: int64(len(list)), Page: 1, PageSize: len(list)}))
+}
+
+// cancelScheduledArticle 取消定时发布（文章退回草稿）
+func (s *HTTPServer) cancelScheduledArticle(ctx *web.Context) {
+	id, err := ctx.PathValue("id").AsInt64()
+	if err != nil || id <= 0 {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "id 不合法"))
+		return
+	}
+	if !s.canEditArticle(ctx, id) {
+		return
+	}
+	if err := s.app.CancelScheduledArticle(ctx.Req.Context(), id); err != nil {
 		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
 		return
 	}
 	_ = ctx.RespJSONOK(dto.SuccessNil())
 }
 
+// revisionPath 解析文章 ID 与可选的版本 ID 并校验编辑权限，失败时已写入响应
+func (s *HTTPServer) revisionPath(ctx *web.Context, withRevision bool) (id, rid int64, ok bool) {
+	id, err := ctx.PathValue("id").AsInt64()
+	if err != nil || id <= 0 {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "id 不合法"))
+		return 0, 0, false
+	}
+	if withRevision {
+		rid, err = ctx.PathValue("rid").AsInt64()
+		if err != nil || rid <= 0 {
+			_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "rid 不合法"))
+			return 0, 0, false
+		}
+	}
+	return id, rid, s.canEditArticle(ctx, id)
+}
+
+// listArticleRevisions 文章历史版本列表（新到旧，不含正文）
+func (s *HTTPServer) listArticleRevisions(ctx *web.Context) {
+	id, _, ok := s.revisionPath(ctx, false)
+	if !ok {
+		return
+	}
+	list, err := s.app.ListArticleRevisions(ctx.Req.Context(), id)
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(dto.PageResponse[*domain.ArticleRevision]{List: list, Total: int64(len(list)), Page: 1, PageSize: len(list)}))
+}
+
+// getArticleRevision 历史版本详情
+func (s *HTTPServer) getArticleRevision(ctx *web.Context) {
+	id, rid, ok := s.revisionPath(ctx, true)
+	if !ok {
+		return
+	}
+	rev, err := s.app.GetArticleRevision(ctx.Req.Context(), id, rid)
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusNotFound, dto.Error(errcode.ErrNotFound, err.Error()))
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(rev))
+}
+
+// diffArticleRevisions 版本比较：?from=版本ID&to=版本ID（缺省为当前内容）&mode=unified|word
+func (s *HTTPServer) diffArticleRevisions(ctx *web.Context) {
+	id, _, ok := s.revisionPath(ctx, false)
+	if !ok {
+		return
+	}
+	q := ctx.Req.URL.Query()
+	from, err := strconv.ParseInt(q.Get("from"), 10, 64)
+	if err != nil || from <= 0 {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "from 不合法"))
+		return
+	}
+	var to int64
+	if v := q.Get("to"); v != "" {
+		if to, err = strconv.ParseInt(v, 10, 64); err != nil || to < 0 {
+			_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "to 不合法"))
+			return
+		}
+	}
+	d, err := s.app.DiffArticleRevisions(ctx.Req.Context(), id, from, to, q.Get("mode"))
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(d))
+}
+
+// restoreArticleRevision 恢复历史版本
+func (s *HTTPServer) restoreArticleRevision(ctx *web.Context) {
+	id, rid, ok := s.revisionPath(ctx, true)
+	if !ok {
+		return
+	}
+	if err := s.app.RestoreArticleRevision(ctx.Req.Context(), id, rid); err != nil {
+		if errors.Is(err, domain.ErrVersionConflict) {
+			_ = ctx.RespJSON(http.StatusConflict, dto.Error(errcode.ErrConflict, err.Error()))
+			return
+		}
+		respWriteErr(ctx, err)
+		return
+	}
+	_ = ctx.RespJSONOK(dto.SuccessNil())
+}
+
 func (s *HTTPServer) deleteArticle(ctx *web.Context) {
 	id, err := ctx.PathValue("id").AsInt64()
 	if err != nil || id <= 0 {
@@ -296,17 +731,18 @@ func (s *HTTPServer) listCategories(ctx *web.Context) {
 
 func (s *HTTPServer) createCategory(ctx *web.Context) {
 	var req struct {
-		Name string `json:"name"`
-		Slug string `json:"slug"`
-		Sort int    `json:"sort"`
+		Name     string `json:"name"`
+		Slug     string `json:"slug"`
+		Sort     int    `json:"sort"`
+		ParentID int64  `json:"parent_id"`
 	}
-	if err := ctx.BindJSON(&req); err != nil || req.Name == "" {
+	if err := ctx.BindJSON(&req); err != nil || req.Name == "" || req.ParentID < 0 {
 		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "参数错误"))
 		return
 	}
-	c := &domain.Category{Name: req.Name, Slug: req.Slug, Sort: req.Sort}
+	c := &domain.Category{Name: req.Name, Slug: req.Slug, Sort: req.Sort, ParentID: req.ParentID}
 	if err := s.app.CreateCategory(ctx.Req.Context(), c); err != nil {
-		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		respWriteErr(ctx, err)
 		return
 	}
 	_ = ctx.RespJSONOK(dto.SuccessNil())
@@ -329,7 +765,7 @@ func (s *HTTPServer) updateCategory(ctx *web.Context) {
 	}
 	c := &domain.Category{ID: id, Name: req.Name, Slug: req.Slug, Sort: req.Sort}
 	if err := s.app.UpdateCategory(ctx.Req.Context(), c); err != nil {
-		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		respWriteErr(ctx, err)
 		return
 	}
 	_ = ctx.RespJSONOK(dto.SuccessNil())
@@ -341,48 +777,273 @@ func (s *HTTPServer) deleteCategory(ctx *web.Context) {
 		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "id 不合法"))
 		return
 	}
-	if err := s.app.DeleteCategory(ctx.Req.Context(), id); err != nil {
-		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+	q := ctx.Req.URL.Query()
+	var targetID int64
+	if v := q.Get("target_id"); v != "" {
+		if targetID, err = strconv.ParseInt(v, 10, 64); err != nil {
+			_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "target_id 不合法"))
+			return
+		}
+	}
+	plan, err := s.app.DeleteCategory(ctx.Req.Context(), id, q.Get("mode"), targetID, q.Get("dry_run") == "true")
+	if err != nil {
+		respWriteErr(ctx, err)
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(plan))
+}
+
+// moveCategory 将分类连同子树移动到 parent_id 下（0 为顶级）
+func (s *HTTPServer) moveCategory(ctx *web.Context) {
+	id, err := ctx.PathValue("id").AsInt64()
+	if err != nil || id <= 0 {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "id 不合法"))
+		return
+	}
+	var req struct {
+		ParentID int64 `json:"parent_id"`
+	}
+	if err := ctx.BindJSON(&req); err != nil || req.ParentID < 0 {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "参数错误"))
+		return
+	}
+	if err := s.app.MoveCategory(ctx.Req.Context(), id, req.ParentID); err != nil {
+		respWriteErr(ctx, err)
 		return
 	}
 	_ = ctx.RespJSONOK(dto.SuccessNil())
 }
 
-// categoryTree 构建分类（单层，按 Sort 升序）
+// categoryTree 分类树：同级按 Sort、ID 升序
 func (s *HTTPServer) categoryTree(ctx *web.Context) {
-	list, total, err := s.app.ListCategories(ctx.Req.Context())
+	tree, err := s.app.CategoryTree(ctx.Req.Context())
 	if err != nil {
 		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
 		return
 	}
-	type Node struct {
-		ID   int64  `json:"id"`
-		Name string `json:"name"`
-		Slug string `json:"slug"`
-		Sort int    `json:"sort"`
+	_ = ctx.RespJSONOK(dto.Success(tree))
+}
+
+func (s *HTTPServer) listTags(ctx *web.Context) {
+	list, err := s.app.ListTags(ctx.Req.Context())
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		return
 	}
-	out := make([]Node, 0, len(list))
-	for _, c := range list {
-		out = append(out, Node{ID: c.ID, Name: c.Name, Slug: c.Slug, Sort: c.Sort})
+	_ = ctx.RespJSONOK(dto.Success(dto.PageResponse[*domain.Tag]{List: list, Total: int64(len(list)), Page: 1, PageSize: len(list)}))
+}
+
+func (s *HTTPServer) createTag(ctx *web.Context) {
+	var req struct {
+		Name  string `json:"name"`
+		Slug  string `json:"slug"`
+		Color string `json:"color"`
 	}
-	_ = ctx.RespJSONOK(dto.Success(dto.PageResponse[Node]{List: out, Total: total, Page: 1, PageSize: len(out)}))
+	if err := ctx.BindJSON(&req); err != nil || req.Name == "" {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "参数错误"))
+		return
+	}
+	t := &domain.Tag{Name: req.Name, Slug: req.Slug, Color: req.Color}
+	if err := s.app.CreateTag(ctx.Req.Context(), t); err != nil {
+		respWriteErr(ctx, err)
+		return
+	}
+	_ = ctx.RespJSONOK(dto.SuccessNil())
 }
 
-// parsePagination 统一分页解析
-func parsePagination(ctx *web.Context) (int, int) {
-	page := 1
-	pageSize := 10
-	if v := ctx.Req.URL.Query().Get("page"); v != "" {
-		if p, err := strconv.Atoi(v); err == nil && p > 0 {
-			page = p
+func (s *HTTPServer) updateTag(ctx *web.Context) {
+	id, err := ctx.PathValue("id").AsInt64()
+	if err != nil || id <= 0 {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "id 不合法"))
+		return
+	}
+	var req struct {
+		Name  string `json:"name,omitempty"`
+		Slug  string `json:"slug,omitempty"`
+		Color string `json:"color,omitempty"`
+	}
+	if err := ctx.BindJSON(&req); err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	t := &domain.Tag{ID: id, Name: req.Name, Slug: req.Slug, Color: req.Color}
+	if err := s.app.UpdateTag(ctx.Req.Context(), t); err != nil {
+		respWriteErr(ctx, err)
+		return
+	}
+	_ = ctx.RespJSONOK(dto.SuccessNil())
+}
+
+func (s *HTTPServer) deleteTag(ctx *web.Context) {
+	id, err := ctx.PathValue("id").AsInt64()
+	if err != nil || id <= 0 {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "id 不合法"))
+		return
+	}
+	plan, err := s.app.DeleteTag(ctx.Req.Context(), id, ctx.Req.URL.Query().Get("dry_run") == "true")
+	if err != nil {
+		respWriteErr(ctx, err)
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(plan))
+}
+
+// mergeTag 将标签合并到 target_id，dry_run=true 时仅预览
+func (s *HTTPServer) mergeTag(ctx *web.Context) {
+	id, err := ctx.PathValue("id").AsInt64()
+	if err != nil || id <= 0 {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "id 不合法"))
+		return
+	}
+	var req struct {
+		TargetID int64 `json:"target_id"`
+	}
+	if err := ctx.BindJSON(&req); err != nil || req.TargetID <= 0 {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "参数错误"))
+		return
+	}
+	plan, err := s.app.MergeTag(ctx.Req.Context(), id, req.TargetID, ctx.Req.URL.Query().Get("dry_run") == "true")
+	if err != nil {
+		respWriteErr(ctx, err)
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(plan))
+}
+
+// listComments 评论审核队列：默认返回待审核，status=all 返回全部状态，可按 article_id 过滤
+func (s *HTTPServer) listComments(ctx *web.Context) {
+	page, pageSize := parsePagination(ctx)
+	q := ctx.Req.URL.Query()
+	status := new(int)
+	switch v := q.Get("status"); v {
+	case "":
+		*status = domain.CommentStatusPending
+	case "all":
+		status = nil
+	default:
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "status 不合法"))
+			return
+		}
+		*status = n
+	}
+	var articleID int64
+	if v := q.Get("article_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "article_id 不合法"))
+			return
+		}
+		articleID = id
+	}
+	list, total, err := s.app.ListComments(ctx.Req.Context(), status, articleID, page, pageSize)
+	if err != nil {
+		respWriteErr(ctx, err)
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(dto.PageResponse[*domain.Comment]{List: list, Total: total, Page: page, PageSize: pageSize}))
+}
+
+// moderateComments 批量审核：status 为 1 通过、2 标记垃圾、0 退回待审核
+func (s *HTTPServer) moderateComments(ctx *web.Context) {
+	var req struct {
+		IDs    []int64 `json:"ids"`
+		Status *int    `json:"status"`
+	}
+	if err := ctx.BindJSON(&req); err != nil || len(req.IDs) == 0 || req.Status == nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "参数错误"))
+		return
+	}
+	n, err := s.app.ModerateComments(ctx.Req.Context(), req.IDs, *req.Status)
+	if err != nil {
+		respWriteErr(ctx, err)
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(map[string]any{"affected": n}))
+}
+
+// deleteComment 删除评论（软删除，楼层仍有回复时保留占位）
+func (s *HTTPServer) deleteComment(ctx *web.Context) {
+	id, err := ctx.PathValue("id").AsInt64()
+	if err != nil || id <= 0 {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "id 不合法"))
+		return
+	}
+	if err := s.app.DeleteComment(ctx.Req.Context(), id); err != nil {
+		respWriteErr(ctx, err)
+		return
+	}
+	_ = ctx.RespJSONOK(dto.SuccessNil())
+}
+
+// listMedia 文件列表（新到旧），可按 mime 前缀（如 image/）与 uploader_id 过滤
+func (s *HTTPServer) listMedia(ctx *web.Context) {
+	page, pageSize := parsePagination(ctx)
+	q := ctx.Req.URL.Query()
+	f := domain.MediaFilter{MimePrefix: q.Get("mime"), Page: page, PageSize: pageSize}
+	if v := q.Get("uploader_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "uploader_id 不合法"))
+			return
 		}
+		f.UploaderID = id
 	}
-	if v := ctx.Req.URL.Query().Get("page_size"); v != "" {
-		if ps, err := strconv.Atoi(v); err == nil && ps > 0 {
-			pageSize = ps
+	s.respMediaList(ctx, f)
+}
+
+// listOrphanMedia 孤立文件：未被文章封面、正文或用户头像引用，且上传超过 older_than（如 72h，默认 24h）
+func (s *HTTPServer) listOrphanMedia(ctx *web.Context) {
+	page, pageSize := parsePagination(ctx)
+	f := domain.MediaFilter{Orphan: true, Page: page, PageSize: pageSize}
+	if v := ctx.Req.URL.Query().Get("older_than"); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "older_than 不合法"))
+			return
 		}
+		f.OlderThan = d
+	}
+	s.respMediaList(ctx, f)
+}
+
+func (s *HTTPServer) respMediaList(ctx *web.Context, f domain.MediaFilter) {
+	list, total, err := s.app.ListMedia(ctx.Req.Context(), f)
+	if err != nil {
+		respWriteErr(ctx, err)
+		return
 	}
-	return page, pageSize
+	_ = ctx.RespJSONOK(dto.Success(dto.PageResponse[*domain.Media]{List: list, Total: total, Page: f.Page, PageSize: f.PageSize}))
+}
+
+// deleteMedia 删除文件及其缩放副本；仍被引用时返回 409，force=true 强制删除
+func (s *HTTPServer) deleteMedia(ctx *web.Context) {
+	id, err := ctx.PathValue("id").AsInt64()
+	if err != nil || id <= 0 {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "id 不合法"))
+		return
+	}
+	if err := s.app.DeleteMedia(ctx.Req.Context(), id, ctx.Req.URL.Query().Get("force") == "true"); err != nil {
+		respWriteErr(ctx, err)
+		return
+	}
+	_ = ctx.RespJSONOK(dto.SuccessNil())
+}
+
+// rebuildSearchIndex 从数据库全量重建全文索引
+func (s *HTTPServer) rebuildSearchIndex(ctx *web.Context) {
+	n, err := s.app.RebuildSearchIndex(ctx.Req.Context())
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(map[string]any{"indexed": n}))
+}
+
+// parsePagination 统一分页解析（每页条数上限见 pagination.MaxPageSize）
+func parsePagination(ctx *web.Context) (int, int) {
+	return pagination.FromQuery(ctx.Req.URL.Query())
 }
 
 // 仪表盘
@@ -482,3 +1143,10 @@ func (s *HTTPServer) Run(addr string) error { return s.server.Start(addr) }
 
 // SetApp 注入应用服务
 func (s *HTTPServer) SetApp(app *application.AdminService) { s.app = app }
+
+// SetImportMaxSize 设置导入压缩包大小上限，非正数时保持默认值
+func (s *HTTPServer) SetImportMaxSize(n int64) {
+	if n > 0 {
+		s.importMax = n
+	}
+}
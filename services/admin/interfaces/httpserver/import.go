@@ -0,0 +1,57 @@
+package api
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+
+	"blog-system/common/pkg/dto"
+	"blog-system/common/pkg/errcode"
+	"blog-system/services/admin/domain"
+
+	"github.com/CoucouMonEcho/go-framework/web"
+)
+
+// multipartOverhead 表单边界与其他字段的余量
+const multipartOverhead = 1 << 20
+
+// importArticles multipart 上传 zip（字段名 file）导入 Markdown 文章，作者为当前管理员；
+// dry_run=true 时不做修改，只返回将要创建 / 更新的文章、标签与分类
+func (s *HTTPServer) importArticles(ctx *web.Context) {
+	ctx.Req.Body = http.MaxBytesReader(ctx.Resp, ctx.Req.Body, s.importMax+multipartOverhead)
+	file, _, err := ctx.Req.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			_ = ctx.RespJSON(http.StatusRequestEntityTooLarge, dto.Error(errcode.ErrMediaTooLarge, "导入文件过大"))
+			return
+		}
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "缺少文件字段 file"))
+		return
+	}
+	defer func() { _ = file.Close() }()
+	if ctx.Req.MultipartForm != nil {
+		defer func() { _ = ctx.Req.MultipartForm.RemoveAll() }()
+	}
+	data, err := io.ReadAll(io.LimitReader(file, s.importMax+1))
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	if int64(len(data)) > s.importMax {
+		_ = ctx.RespJSON(http.StatusRequestEntityTooLarge, dto.Error(errcode.ErrMediaTooLarge, "导入文件过大"))
+		return
+	}
+	dryRun, _ := strconv.ParseBool(ctx.Req.URL.Query().Get("dry_run"))
+	report, err := s.app.ImportArticles(ctx.Req.Context(), data, claimsOf(ctx).UserID, dryRun)
+	if err != nil {
+		if errors.Is(err, domain.ErrImportArchive) || errors.Is(err, domain.ErrImportNoDocs) {
+			_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+			return
+		}
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(report))
+}
@@ -25,10 +25,15 @@ func main() {
 	contentCli := clients.NewContentClient(cfg)
 	statCli := clients.NewStatServiceClient(cfg)
 	promCli := infrastructure.NewPrometheusClient("", 0)
-	app := application.NewAdminService(userCli, contentCli, logger.Log(), nil, statCli, promCli)
+	cache, err := infrastructure.InitCache(cfg)
+	if err != nil {
+		logger.Log().Error("main: 缓存连接失败: %v", err)
+	}
+	app := application.NewAdminService(userCli, contentCli, logger.Log(), cache, statCli, promCli)
 
 	http := httpapi.NewHTTPServer()
 	http.SetApp(app)
+	http.SetImportMaxSize(cfg.Import.MaxSize)
 	// 注册服务发现（失败不阻断启动）
 	if err := infrastructure.RegisterService(cfg); err != nil {
 		log.Printf("注册中心失败: %v (忽略继续)", err)
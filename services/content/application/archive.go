@@ -0,0 +1,111 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"blog-system/common/pkg/cacheaside"
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/orm"
+)
+
+// archivePage 按月归档的一页（缓存用）
+type archivePage struct {
+	List  []*domain.ArticleSummary `json:"list"`
+	Total int64                    `json:"total"`
+}
+
+// Archive 已公开文章的归档树（年 -> 月，新到旧）；随 feed 命名空间（文章发布、修改、删除）失效
+func (s *ContentAppService) Archive(ctx context.Context) ([]*domain.ArchiveYear, error) {
+	key := s.cc.Key("archive", s.cc.Version(ctx, nsFeed))
+	tree, err := cacheaside.Get(ctx, s.cc, key, func(ctx context.Context) ([]*domain.ArchiveYear, error) {
+		counts, err := s.repo.CountArchive(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return domain.BuildArchiveTree(counts), nil
+	})
+	if err != nil {
+		s.logger.Error("application: 获取归档失败: %v", err)
+	}
+	return tree, err
+}
+
+// ArchiveMonth 某年某月发布的已公开文章（置顶在前，其余新到旧）
+func (s *ContentAppService) ArchiveMonth(ctx context.Context, year, month, page, pageSize int) ([]*domain.ArticleSummary, int64, error) {
+	from, to, err := domain.MonthRange(year, month, time.Local)
+	if err != nil {
+		return nil, 0, err
+	}
+	// 摘要含分类、标签名称，键同时带上两者的版本号
+	key := s.cc.Key("archive", strconv.Itoa(year), strconv.Itoa(month), strconv.Itoa(page), strconv.Itoa(pageSize),
+		s.cc.Version(ctx, nsFeed), s.cc.Version(ctx, nsCategory), s.cc.Version(ctx, nsTag))
+	p, err := cacheaside.Get(ctx, s.cc, key, func(ctx context.Context) (*archivePage, error) {
+		list, total, err := s.repo.ListArchiveSummaries(ctx, from, to, page, pageSize)
+		if err != nil {
+			return nil, err
+		}
+		return &archivePage{List: list, Total: total}, nil
+	})
+	if err != nil {
+		s.logger.Error("application: 获取月份归档失败: %d-%02d err=%v", year, month, err)
+		return nil, 0, err
+	}
+	return p.List, p.Total, nil
+}
+
+// ArchiveCalendar 某年某月每日发布的已公开文章数（仅含有文章的日期）
+func (s *ContentAppService) ArchiveCalendar(ctx context.Context, year, month int) ([]*domain.ArchiveCount, error) {
+	from, to, err := domain.MonthRange(year, month, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	key := s.cc.Key("calendar", strconv.Itoa(year), strconv.Itoa(month), s.cc.Version(ctx, nsFeed))
+	days, err := cacheaside.Get(ctx, s.cc, key, func(ctx context.Context) ([]*domain.ArchiveCount, error) {
+		return s.repo.CountArchiveDays(ctx, from, to)
+	})
+	if err != nil {
+		s.logger.Error("application: 获取归档日历失败: %d-%02d err=%v", year, month, err)
+	}
+	return days, err
+}
+
+// Nav 文章页的上一篇 / 下一篇（按发布时间，仅已公开文章），遵循读取方对原文章的可见性；
+// sameCategory 时限定同一分类，原文章未发布时两者均为空
+func (s *ContentAppService) Nav(ctx context.Context, id int64, v domain.Viewer, sameCategory bool) (*domain.ArticleNav, error) {
+	a, err := s.GetVisible(ctx, id, v)
+	if err != nil {
+		return nil, err
+	}
+	if a.PublishedAt == nil {
+		return &domain.ArticleNav{}, nil
+	}
+	key := s.cc.Key("nav", strconv.FormatInt(id, 10), strconv.FormatBool(sameCategory), s.cc.Version(ctx, nsFeed))
+	nav, err := cacheaside.Get(ctx, s.cc, key, func(ctx context.Context) (*domain.ArticleNav, error) {
+		var err error
+		nav := &domain.ArticleNav{}
+		if nav.Prev, err = s.adjacent(ctx, a, sameCategory, false); err != nil {
+			return nil, err
+		}
+		if nav.Next, err = s.adjacent(ctx, a, sameCategory, true); err != nil {
+			return nil, err
+		}
+		return nav, nil
+	})
+	if err != nil {
+		s.logger.Error("application: 获取文章导航失败: id=%d err=%v", id, err)
+	}
+	return nav, err
+}
+
+// adjacent 相邻文章，不存在时返回 nil
+func (s *ContentAppService) adjacent(ctx context.Context, a *domain.Article, sameCategory, next bool) (*domain.ArticleNavItem, error) {
+	item, err := s.repo.GetAdjacentArticle(ctx, a, sameCategory, next)
+	if errors.Is(err, orm.ErrNoRows) {
+		return nil, nil
+	}
+	return item, err
+}
@@ -0,0 +1,524 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"slices"
+	"strings"
+	"time"
+
+	"blog-system/common/pkg/logger"
+	"blog-system/common/pkg/pagination"
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/orm"
+)
+
+// backupPageSize 导出时分页读取文章与文件的每页条数
+const backupPageSize = 100
+
+// BackupAppService 整站导出与恢复：导出为带版本号的归档（JSON + 带前言的 Markdown + 原始文件），
+// 恢复时校验版本并重新分配 ID，用于在不同环境间迁移站点
+type BackupAppService struct {
+	repo      domain.ContentRepository
+	app       *ContentAppService
+	media     *MediaAppService
+	parser    domain.ImportParser
+	formatter domain.DocFormatter
+	logger    logger.Logger
+}
+
+func NewBackupService(repo domain.ContentRepository, app *ContentAppService, media *MediaAppService, parser domain.ImportParser, formatter domain.DocFormatter, lgr logger.Logger) *BackupAppService {
+	return &BackupAppService{repo: repo, app: app, media: media, parser: parser, formatter: formatter, logger: lgr}
+}
+
+// Export 将分类、标签、文章（含标签关联）写入 w，withMedia 时一并写入上传的文件；清单最后写入
+func (s *BackupAppService) Export(ctx context.Context, w domain.ArchiveWriter, withMedia bool) (*domain.BackupManifest, error) {
+	manifest := &domain.BackupManifest{SchemaVersion: domain.BackupSchemaVersion, ExportedAt: time.Now()}
+	cats, err := s.repo.ListAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*domain.Category, len(cats))
+	outCats := make([]*domain.BackupCategory, 0, len(cats))
+	for _, c := range cats {
+		byID[c.ID] = c
+		outCats = append(outCats, &domain.BackupCategory{
+			ID: c.ID, ParentID: c.ParentID, Name: c.Name, Slug: c.Slug, Description: nullString(c.Description), Sort: c.Sort, CreatedAt: c.CreatedAt,
+		})
+	}
+	tags, err := s.repo.ListAllTags(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tagByID := make(map[int64]*domain.Tag, len(tags))
+	outTags := make([]*domain.BackupTag, 0, len(tags))
+	for _, t := range tags {
+		tagByID[t.ID] = t
+		outTags = append(outTags, &domain.BackupTag{ID: t.ID, Name: t.Name, Slug: t.Slug, Color: nullString(t.Color), CreatedAt: t.CreatedAt})
+	}
+	links, err := s.repo.ListArticleTagLinks(ctx)
+	if err != nil {
+		return nil, err
+	}
+	outLinks := make([]*domain.BackupArticleTag, 0, len(links))
+	tagNames := make(map[int64][]string)
+	for _, l := range links {
+		outLinks = append(outLinks, &domain.BackupArticleTag{ArticleID: l.ArticleID, TagID: l.TagID})
+		if t, ok := tagByID[l.TagID]; ok {
+			tagNames[l.ArticleID] = append(tagNames[l.ArticleID], t.Name)
+		}
+	}
+
+	articles := make([]*domain.BackupArticle, 0)
+	var after *pagination.Cursor
+	for {
+		list, next, err := s.repo.ListArticlesAfter(ctx, 0, after, backupPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range list {
+			item, err := s.exportArticle(w, a, categoryPath(byID, a.CategoryID), tagNames[a.ID])
+			if err != nil {
+				return nil, err
+			}
+			articles = append(articles, item)
+		}
+		if next == nil {
+			break
+		}
+		after = next
+	}
+
+	media := make([]*domain.BackupMedia, 0)
+	if withMedia {
+		if media, err = s.exportMedia(ctx, w); err != nil {
+			return nil, err
+		}
+	}
+
+	manifest.Categories, manifest.Tags, manifest.ArticleTags = len(outCats), len(outTags), len(outLinks)
+	manifest.Articles, manifest.Media = len(articles), len(media)
+	for _, f := range []struct {
+		name string
+		v    any
+	}{
+		{domain.BackupCategoriesFile, outCats},
+		{domain.BackupTagsFile, outTags},
+		{domain.BackupArticlesFile, articles},
+		{domain.BackupArticleTagsFile, outLinks},
+		{domain.BackupMediaFile, media},
+		{domain.BackupManifestFile, manifest},
+	} {
+		if err := writeJSON(w, f.name, f.v); err != nil {
+			return nil, err
+		}
+	}
+	s.logger.Info("application: 导出站点: articles=%d categories=%d tags=%d media=%d",
+		manifest.Articles, manifest.Categories, manifest.Tags, manifest.Media)
+	return manifest, nil
+}
+
+// exportArticle 将正文写为 articles/<slug>.md 并返回元数据
+func (s *BackupAppService) exportArticle(w domain.ArchiveWriter, a *domain.Article, cats, tags []string) (*domain.BackupArticle, error) {
+	date := a.CreatedAt
+	if a.PublishedAt != nil {
+		date = *a.PublishedAt
+	}
+	doc := &domain.ImportDoc{
+		Title: a.Title, Slug: a.Slug, Date: &date, Tags: tags, Categories: cats, Keywords: splitKeywords(nullString(a.MetaKeywords)),
+		Draft: a.Status != domain.ArticleStatusPublished, Summary: nullString(a.Summary), Cover: nullString(a.Cover), Content: a.Content,
+	}
+	data, err := s.formatter.Format(doc)
+	if err != nil {
+		return nil, err
+	}
+	file := domain.BackupArticleDir + a.Slug + ".md"
+	f, err := w.Create(file)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := f.Write(data); err != nil {
+		return nil, err
+	}
+	return &domain.BackupArticle{
+		ID: a.ID, Slug: a.Slug, Title: a.Title, File: file, Summary: nullString(a.Summary), Cover: nullString(a.Cover),
+		AuthorID: a.AuthorID, CategoryID: a.CategoryID, Status: a.Status, ViewCount: a.ViewCount, LikeCount: a.LikeCount,
+		IsTop: a.IsTop, IsRecommend: a.IsRecommend, MetaTitle: nullString(a.MetaTitle), MetaDesc: nullString(a.MetaDesc),
+		MetaKeywords: nullString(a.MetaKeywords), PublishedAt: a.PublishedAt, CreatedAt: a.CreatedAt,
+	}, nil
+}
+
+// exportMedia 写入上传的原文件（media/<storage_key>），存储中读取失败的文件只记录日志并跳过
+func (s *BackupAppService) exportMedia(ctx context.Context, w domain.ArchiveWriter) ([]*domain.BackupMedia, error) {
+	out := make([]*domain.BackupMedia, 0)
+	for page := 1; ; page++ {
+		list, total, err := s.media.List(ctx, domain.MediaFilter{Page: page, PageSize: backupPageSize})
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range list {
+			file := domain.BackupMediaDir + m.StorageKey
+			if err := s.copyMedia(ctx, w, m.StorageKey, file); err != nil {
+				s.logger.Error("application: 导出文件失败: key=%s err=%v", m.StorageKey, err)
+				continue
+			}
+			out = append(out, &domain.BackupMedia{
+				ID: m.ID, Hash: m.Hash, File: file, URL: m.URL, Name: m.Name, MimeType: m.MimeType,
+				UploaderID: m.UploaderID, Variants: m.Variants, CreatedAt: m.CreatedAt,
+			})
+		}
+		if len(list) < backupPageSize || int64(page*backupPageSize) >= total {
+			return out, nil
+		}
+	}
+}
+
+func (s *BackupAppService) copyMedia(ctx context.Context, w domain.ArchiveWriter, key, file string) error {
+	r, err := s.media.Open(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = r.Close() }()
+	f, err := w.Create(file)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, r)
+	return err
+}
+
+// restoreRun 单次恢复的状态：导出环境 ID 到当前环境 ID 的映射
+type restoreRun struct {
+	*BackupAppService
+	files  *archiveReader
+	opts   domain.RestoreOptions
+	report *domain.RestoreReport
+	cats   map[int64]int64
+	tags   map[int64]int64
+	urls   map[string]string // 导出环境的文件地址 -> 当前环境的地址
+	bucket int64             // “未分类”，分类未能恢复的文章归入其中
+}
+
+// Restore 从 src 恢复站点：已有同别名的分类、标签与文章沿用现有对象，不覆盖；单个对象失败记录在报告中
+func (s *BackupAppService) Restore(ctx context.Context, src fs.FS, opts domain.RestoreOptions) (*domain.RestoreReport, error) {
+	if opts.DefaultAuthor <= 0 {
+		return nil, domain.ErrImportLogin
+	}
+	src, manifest, err := backupRoot(src)
+	if err != nil {
+		return nil, err
+	}
+	run := &restoreRun{
+		BackupAppService: s, files: newArchiveReader(src), opts: opts,
+		report: &domain.RestoreReport{SchemaVersion: manifest.SchemaVersion, Errors: make([]string, 0)},
+		cats:   make(map[int64]int64), tags: make(map[int64]int64), urls: make(map[string]string),
+	}
+	var (
+		cats     []*domain.BackupCategory
+		tags     []*domain.BackupTag
+		articles []*domain.BackupArticle
+		links    []*domain.BackupArticleTag
+		media    []*domain.BackupMedia
+	)
+	for name, v := range map[string]any{
+		domain.BackupCategoriesFile:  &cats,
+		domain.BackupTagsFile:        &tags,
+		domain.BackupArticlesFile:    &articles,
+		domain.BackupArticleTagsFile: &links,
+		domain.BackupMediaFile:       &media,
+	} {
+		if err := readJSON(run.files, name, v); err != nil {
+			return nil, err
+		}
+	}
+	if err := run.restoreCategories(ctx, cats); err != nil {
+		return nil, err
+	}
+	if err := run.restoreTags(ctx, tags); err != nil {
+		return nil, err
+	}
+	run.restoreMedia(ctx, media)
+	tagsOf := make(map[int64][]int64)
+	for _, l := range links {
+		if id, ok := run.tags[l.TagID]; ok {
+			tagsOf[l.ArticleID] = append(tagsOf[l.ArticleID], id)
+		}
+	}
+	for _, a := range articles {
+		run.restoreArticle(ctx, a, tagsOf[a.ID])
+	}
+	r := run.report
+	s.logger.Info("application: 恢复站点: version=%d articles=%d/%d/%d categories=%d/%d/%d tags=%d/%d/%d media=%d/%d/%d",
+		r.SchemaVersion, r.Articles.Created, r.Articles.Existing, r.Articles.Failed,
+		r.Categories.Created, r.Categories.Existing, r.Categories.Failed,
+		r.Tags.Created, r.Tags.Existing, r.Tags.Failed, r.Media.Created, r.Media.Existing, r.Media.Failed)
+	return r, nil
+}
+
+// fail 记录单个对象的恢复失败
+func (r *restoreRun) fail(count *domain.RestoreCount, kind, name string, err error) {
+	count.Failed++
+	r.report.Errors = append(r.report.Errors, kind+" "+name+": "+err.Error())
+	r.logger.Error("application: 恢复%s失败: %s err=%v", kind, name, err)
+}
+
+// author 映射后的作者，未映射时取默认作者
+func (r *restoreRun) author(id int64) int64 {
+	if mapped, ok := r.opts.AuthorMap[id]; ok && mapped > 0 {
+		return mapped
+	}
+	return r.opts.DefaultAuthor
+}
+
+// restoreCategories 按层级由上到下恢复分类，同别名的已有分类直接沿用；上级未能恢复时作为顶级分类
+func (r *restoreRun) restoreCategories(ctx context.Context, list []*domain.BackupCategory) error {
+	existing, err := r.repo.ListAllCategories(ctx)
+	if err != nil {
+		return err
+	}
+	bySlug := make(map[string]int64, len(existing))
+	for _, c := range existing {
+		bySlug[c.Slug] = c.ID
+	}
+	parents := make(map[int64]int64, len(list))
+	for _, c := range list {
+		parents[c.ID] = c.ParentID
+	}
+	depth := func(c *domain.BackupCategory) int {
+		d := 0
+		for p := c.ParentID; p != 0 && d < len(list); p = parents[p] {
+			d++
+		}
+		return d
+	}
+	list = slices.Clone(list)
+	slices.SortStableFunc(list, func(a, b *domain.BackupCategory) int { return depth(a) - depth(b) })
+	for _, c := range list {
+		if id, ok := bySlug[c.Slug]; ok {
+			r.cats[c.ID] = id
+			r.report.Categories.Existing++
+			continue
+		}
+		nc := &domain.Category{ParentID: r.cats[c.ParentID], Name: c.Name, Slug: c.Slug, Description: optional(c.Description), Sort: c.Sort}
+		if err := r.app.CreateCategory(ctx, nc); err != nil {
+			r.fail(&r.report.Categories, "分类", c.Slug, err)
+			continue
+		}
+		r.cats[c.ID] = nc.ID
+		bySlug[nc.Slug] = nc.ID
+		r.report.Categories.Created++
+	}
+	return nil
+}
+
+// restoreTags 恢复标签，别名或名称（不区分大小写）相同的已有标签直接沿用
+func (r *restoreRun) restoreTags(ctx context.Context, list []*domain.BackupTag) error {
+	existing, err := r.repo.ListAllTags(ctx)
+	if err != nil {
+		return err
+	}
+	bySlug := make(map[string]int64, len(existing))
+	byName := make(map[string]int64, len(existing))
+	for _, t := range existing {
+		bySlug[t.Slug], byName[strings.ToLower(t.Name)] = t.ID, t.ID
+	}
+	for _, t := range list {
+		if id, ok := bySlug[t.Slug]; ok {
+			r.tags[t.ID] = id
+			r.report.Tags.Existing++
+			continue
+		}
+		if id, ok := byName[strings.ToLower(t.Name)]; ok {
+			r.tags[t.ID] = id
+			r.report.Tags.Existing++
+			continue
+		}
+		nt := &domain.Tag{Name: t.Name, Slug: t.Slug, Color: optional(t.Color)}
+		if err := r.app.CreateTag(ctx, nt); err != nil {
+			r.fail(&r.report.Tags, "标签", t.Slug, err)
+			continue
+		}
+		r.tags[t.ID] = nt.ID
+		bySlug[nt.Slug], byName[strings.ToLower(nt.Name)] = nt.ID, nt.ID
+		r.report.Tags.Created++
+	}
+	return nil
+}
+
+// restoreMedia 重新上传文件（按内容去重，已有相同内容时沿用），记录新旧地址用于改写文章；
+// 缩放副本按当前环境的配置重新生成，旧副本地址映射到同规格的新副本，没有时映射到原图
+func (r *restoreRun) restoreMedia(ctx context.Context, list []*domain.BackupMedia) {
+	for _, m := range list {
+		data, err := r.files.ReadFile(m.File)
+		if err != nil {
+			r.fail(&r.report.Media, "文件", m.File, err)
+			continue
+		}
+		nm, err := r.media.FindByContent(ctx, data)
+		if err == nil && nm != nil {
+			r.report.Media.Existing++
+		} else if err == nil {
+			if nm, err = r.media.Upload(ctx, m.Name, bytes.NewReader(data), r.author(m.UploaderID)); err == nil {
+				r.report.Media.Created++
+			}
+		}
+		if err != nil {
+			r.fail(&r.report.Media, "文件", m.File, err)
+			continue
+		}
+		r.urls[m.URL] = nm.URL
+		for _, v := range m.Variants {
+			r.urls[v.URL] = nm.URL
+			for _, nv := range nm.Variants {
+				if nv.Name == v.Name {
+					r.urls[v.URL] = nv.URL
+					break
+				}
+			}
+		}
+	}
+}
+
+// restoreArticle 恢复单篇文章：已有同别名文章时跳过；正文取自归档中的 Markdown，文件地址改写为当前环境的地址
+func (r *restoreRun) restoreArticle(ctx context.Context, b *domain.BackupArticle, tagIDs []int64) {
+	count := &r.report.Articles
+	if _, err := r.repo.GetArticleBySlug(ctx, b.Slug); err == nil {
+		count.Existing++
+		return
+	} else if !errors.Is(err, orm.ErrNoRows) {
+		r.fail(count, "文章", b.Slug, err)
+		return
+	}
+	data, err := r.files.ReadFile(b.File)
+	if err != nil {
+		r.fail(count, "文章", b.Slug, err)
+		return
+	}
+	doc, err := r.parser.Parse(b.File, data)
+	if err != nil {
+		r.fail(count, "文章", b.Slug, err)
+		return
+	}
+	categoryID, ok := r.cats[b.CategoryID]
+	if !ok {
+		if categoryID, err = r.uncategorized(ctx); err != nil {
+			r.fail(count, "文章", b.Slug, err)
+			return
+		}
+	}
+	a := &domain.Article{
+		Title: b.Title, Slug: b.Slug, Content: r.rewrite(doc.Content), Summary: optional(b.Summary), Cover: optional(r.rewrite(b.Cover)),
+		AuthorID: r.author(b.AuthorID), CategoryID: categoryID, Status: b.Status, ViewCount: b.ViewCount, LikeCount: b.LikeCount,
+		IsTop: b.IsTop, IsRecommend: b.IsRecommend, MetaTitle: optional(b.MetaTitle), MetaDesc: optional(b.MetaDesc),
+		MetaKeywords: optional(b.MetaKeywords), PublishedAt: b.PublishedAt, CreatedAt: b.CreatedAt,
+	}
+	if _, err := r.app.Create(ctx, a, tagIDs); err != nil {
+		r.fail(count, "文章", b.Slug, err)
+		return
+	}
+	count.Created++
+}
+
+func (r *restoreRun) uncategorized(ctx context.Context) (int64, error) {
+	if r.bucket > 0 {
+		return r.bucket, nil
+	}
+	c, err := r.app.uncategorized(ctx, true)
+	if err != nil {
+		return 0, err
+	}
+	r.bucket = c.ID
+	return r.bucket, nil
+}
+
+// rewrite 将正文中导出环境的文件地址替换为当前环境的地址
+func (r *restoreRun) rewrite(s string) string {
+	if s == "" || len(r.urls) == 0 {
+		return s
+	}
+	pairs := make([]string, 0, len(r.urls)*2)
+	for from, to := range r.urls {
+		if from != "" && from != to {
+			pairs = append(pairs, from, to)
+		}
+	}
+	return strings.NewReplacer(pairs...).Replace(s)
+}
+
+// backupRoot 定位归档根目录并校验清单：清单可位于根目录，或唯一的顶层目录中（解压后重新打包的情形）
+func backupRoot(src fs.FS) (fs.FS, *domain.BackupManifest, error) {
+	data, err := newArchiveReader(src).ReadFile(domain.BackupManifestFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		if entries, _ := fs.ReadDir(src, "."); len(entries) == 1 && entries[0].IsDir() {
+			if sub, e := fs.Sub(src, entries[0].Name()); e == nil {
+				src = sub
+				data, err = newArchiveReader(src).ReadFile(domain.BackupManifestFile)
+			}
+		}
+	}
+	if err != nil {
+		return nil, nil, domain.ErrBackupManifest
+	}
+	var manifest domain.BackupManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, nil, domain.ErrBackupManifest
+	}
+	if err := domain.CheckBackupVersion(manifest.SchemaVersion); err != nil {
+		return nil, nil, err
+	}
+	return src, &manifest, nil
+}
+
+// categoryPath 根到分类自身的名称路径
+func categoryPath(byID map[int64]*domain.Category, id int64) []string {
+	var names []string
+	for c, depth := byID[id], 0; c != nil && depth < domain.CategoryMaxDepth; c, depth = byID[c.ParentID], depth+1 {
+		names = append([]string{c.Name}, names...)
+	}
+	return names
+}
+
+// splitKeywords 按逗号拆分 SEO 关键词
+func splitKeywords(v string) []string {
+	var out []string
+	for _, k := range strings.Split(v, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			out = append(out, k)
+		}
+	}
+	return out
+}
+
+func writeJSON(w domain.ArchiveWriter, name string, v any) error {
+	f, err := w.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// readJSON 读取归档中的 JSON 文件，缺失的文件视为空列表
+func readJSON(files *archiveReader, name string, v any) error {
+	data, err := files.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if errors.Is(err, domain.ErrArchiveTooLarge) {
+		return fmt.Errorf("%w: %v", domain.ErrBackupData, err)
+	}
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: %s: %v", domain.ErrBackupData, name, err)
+	}
+	return nil
+}
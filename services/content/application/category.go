@@ -0,0 +1,170 @@
+package application
+
+import (
+	"context"
+	"errors"
+
+	"blog-system/common/pkg/cacheaside"
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/orm"
+)
+
+// CategoryTree 分类树（旁路缓存，分类写操作后随命名空间版本号失效）
+func (s *ContentAppService) CategoryTree(ctx context.Context) ([]*domain.CategoryNode, error) {
+	return cacheaside.Get(ctx, s.cc, s.cc.VersionedKey(ctx, nsCategory, "tree"), func(ctx context.Context) ([]*domain.CategoryNode, error) {
+		list, err := s.repo.ListAllCategories(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return domain.BuildCategoryTree(list), nil
+	})
+}
+
+// subtreeCategoryIDs 分类及其全部子孙的 ID；分类不存在时仅返回自身
+func (s *ContentAppService) subtreeCategoryIDs(ctx context.Context, id int64) ([]int64, error) {
+	list, err := s.ListAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var root *domain.Category
+	for _, c := range list {
+		if c.ID == id {
+			root = c
+			break
+		}
+	}
+	ids := []int64{id}
+	if root == nil {
+		return ids, nil
+	}
+	for _, c := range list {
+		if c.ID != id && root.Contains(c) {
+			ids = append(ids, c.ID)
+		}
+	}
+	return ids, nil
+}
+
+// MoveCategory 将分类连同子树移动到 parentID 下，parentID 为 0 时移到顶级；
+// 不允许移动到自身或子孙下，移动后层级不得超过上限
+func (s *ContentAppService) MoveCategory(ctx context.Context, id, parentID int64) error {
+	c, err := s.repo.GetCategoryByID(ctx, id)
+	if errors.Is(err, orm.ErrNoRows) {
+		return domain.ErrCategoryNotFound
+	}
+	if err != nil {
+		return err
+	}
+	var parent *domain.Category
+	if parentID > 0 {
+		parent, err = s.repo.GetCategoryByID(ctx, parentID)
+		if errors.Is(err, orm.ErrNoRows) {
+			return domain.ErrCategoryParent
+		}
+		if err != nil {
+			return err
+		}
+	}
+	// 层级校验以数据库为准，不读缓存
+	list, err := s.repo.ListAllCategories(ctx)
+	if err != nil {
+		return err
+	}
+	depth := c.Depth()
+	for _, o := range list {
+		if c.Contains(o) {
+			depth = max(depth, o.Depth())
+		}
+	}
+	if err := c.CheckMove(parent, depth); err != nil {
+		return err
+	}
+	return s.bumpAfter(ctx, nsCategory, s.repo.MoveCategory(ctx, c, parent))
+}
+
+// DeleteCategory 删除分类，mode 决定其下文章的去向（见 domain.CategoryDelete*，默认拒绝）；
+// 仍有子分类时拒绝。dryRun 时仅返回影响预览
+func (s *ContentAppService) DeleteCategory(ctx context.Context, id int64, mode string, targetID int64, dryRun bool) (*domain.CategoryDeletePlan, error) {
+	if mode == "" {
+		mode = domain.CategoryDeleteRefuse
+	}
+	c, err := s.repo.GetCategoryByID(ctx, id)
+	if errors.Is(err, orm.ErrNoRows) {
+		return nil, domain.ErrCategoryNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	n, err := s.repo.CountChildCategories(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if n > 0 {
+		return nil, domain.ErrCategoryHasChildren
+	}
+	ids, err := s.repo.ListArticleIDsByCategory(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	plan := &domain.CategoryDeletePlan{ID: id, Mode: mode, Articles: int64(len(ids)), DryRun: dryRun}
+	switch mode {
+	case domain.CategoryDeleteRefuse:
+		if len(ids) > 0 && !dryRun {
+			return nil, domain.ErrCategoryNotEmpty
+		}
+	case domain.CategoryDeleteReassign:
+		if targetID <= 0 || targetID == id {
+			return nil, domain.ErrCategoryTarget
+		}
+		if _, err := s.repo.GetCategoryByID(ctx, targetID); errors.Is(err, orm.ErrNoRows) {
+			return nil, domain.ErrCategoryTarget
+		} else if err != nil {
+			return nil, err
+		}
+		plan.TargetID = targetID
+	case domain.CategoryDeleteUncategorized:
+		if c.Slug == domain.UncategorizedSlug {
+			return nil, domain.ErrCategoryTarget
+		}
+		bucket, err := s.uncategorized(ctx, !dryRun)
+		if err != nil {
+			return nil, err
+		}
+		if bucket != nil {
+			plan.TargetID = bucket.ID
+		}
+	default:
+		return nil, domain.ErrCategoryDeleteMode
+	}
+	if dryRun {
+		return plan, nil
+	}
+	if err := s.bumpAfter(ctx, nsCategory, s.repo.DeleteCategory(ctx, id, plan.TargetID)); err != nil {
+		return nil, err
+	}
+	s.invalidateArticles(ctx, ids...)
+	s.logger.Info("application: 删除分类: id=%d mode=%s target=%d articles=%d", id, mode, plan.TargetID, plan.Articles)
+	return plan, nil
+}
+
+// uncategorized 查找“未分类”，create 为 true 且不存在时创建为顶级分类
+func (s *ContentAppService) uncategorized(ctx context.Context, create bool) (*domain.Category, error) {
+	list, err := s.repo.ListAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range list {
+		if c.Slug == domain.UncategorizedSlug || c.Name == domain.UncategorizedName {
+			return c, nil
+		}
+	}
+	if !create {
+		return nil, nil
+	}
+	c := &domain.Category{Name: domain.UncategorizedName, Slug: domain.UncategorizedSlug}
+	if err := s.CreateCategory(ctx, c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
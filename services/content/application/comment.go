@@ -0,0 +1,239 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"blog-system/common/pkg/logger"
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/orm"
+)
+
+// 评论限流默认值：每个用户每分钟最多 5 条
+const (
+	defaultCommentRateLimit  = 5
+	defaultCommentRateWindow = time.Minute
+)
+
+// commentRateKeyPrefix 评论限流计数键前缀，后接用户 ID
+const commentRateKeyPrefix = "content:ratelimit:comment:"
+
+// RateLimiter 固定窗口限流
+type RateLimiter interface {
+	// Allow 在 window 内对 key 计数一次，超过 limit 时返回 false
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error)
+}
+
+// CommentAppService 评论应用服务：发表与展示面向读者，审核与删除面向管理后台
+type CommentAppService struct {
+	repo     domain.CommentRepository
+	articles *ContentAppService // 校验文章对评论者可见
+	limiter  RateLimiter
+	logger   logger.Logger
+
+	rateLimit   int
+	rateWindow  time.Duration
+	autoApprove bool // 为 true 时新评论直接通过，否则进入待审核队列
+}
+
+// NewCommentService limiter 为空时不限流（单机调试）
+func NewCommentService(repo domain.CommentRepository, articles *ContentAppService, limiter RateLimiter, lgr logger.Logger) *CommentAppService {
+	return &CommentAppService{
+		repo:       repo,
+		articles:   articles,
+		limiter:    limiter,
+		logger:     lgr,
+		rateLimit:  defaultCommentRateLimit,
+		rateWindow: defaultCommentRateWindow,
+	}
+}
+
+// SetRateLimit 设置每个用户在 window 内可发表的评论数，非正数时使用默认值
+func (s *CommentAppService) SetRateLimit(limit int, window time.Duration) {
+	if limit <= 0 {
+		limit = defaultCommentRateLimit
+	}
+	if window <= 0 {
+		window = defaultCommentRateWindow
+	}
+	s.rateLimit, s.rateWindow = limit, window
+}
+
+// SetAutoApprove 设置新评论是否免审核
+func (s *CommentAppService) SetAutoApprove(on bool) { s.autoApprove = on }
+
+// Post 发表评论或回复：作者为 v.UserID，文章须对其可见；回复的评论须属于同一文章且已通过。
+// 管理员与开启免审核时直接通过，否则进入待审核队列
+func (s *CommentAppService) Post(ctx context.Context, c *domain.Comment, v domain.Viewer) error {
+	if v.UserID <= 0 {
+		return domain.ErrCommentLogin
+	}
+	c.Content = strings.TrimSpace(c.Content)
+	if err := domain.CheckCommentContent(c.Content); err != nil {
+		return err
+	}
+	if _, err := s.articles.GetVisible(ctx, c.ArticleID, v); err != nil {
+		return domain.ErrCommentArticle
+	}
+	c.RootID = 0
+	if c.ParentID > 0 {
+		parent, err := s.repo.GetComment(ctx, c.ParentID)
+		if errors.Is(err, orm.ErrNoRows) {
+			return domain.ErrCommentParent
+		}
+		if err != nil {
+			return err
+		}
+		if parent.ArticleID != c.ArticleID || parent.Status != domain.CommentStatusApproved || parent.Deleted() {
+			return domain.ErrCommentParent
+		}
+		c.RootID = parent.RootID
+		if c.RootID == 0 {
+			c.RootID = parent.ID
+		}
+	}
+	if err := s.allow(ctx, v.UserID); err != nil {
+		return err
+	}
+	now := time.Now()
+	c.UserID = v.UserID
+	c.Status = domain.CommentStatusPending
+	if s.autoApprove || v.Admin {
+		c.Status = domain.CommentStatusApproved
+	}
+	c.DeletedAt = nil
+	c.CreatedAt, c.UpdatedAt = now, now
+	if err := s.repo.CreateComment(ctx, c); err != nil {
+		return err
+	}
+	s.logger.Info("application: 发表评论: id=%d article=%d user=%d status=%d", c.ID, c.ArticleID, c.UserID, c.Status)
+	return nil
+}
+
+// allow 评论限流；限流器故障时放行，避免 Redis 不可用导致无法评论
+func (s *CommentAppService) allow(ctx context.Context, userID int64) error {
+	if s.limiter == nil {
+		return nil
+	}
+	ok, err := s.limiter.Allow(ctx, commentRateKeyPrefix+strconv.FormatInt(userID, 10), s.rateLimit, s.rateWindow)
+	if err != nil {
+		s.logger.Error("application: 评论限流失败: user=%d err=%v", userID, err)
+		return nil
+	}
+	if !ok {
+		return domain.ErrCommentRateLimited
+	}
+	return nil
+}
+
+// Threads 文章评论楼层分页（顶层评论新到旧，楼内回复旧到新）；已删除的顶层评论仅保留占位
+func (s *CommentAppService) Threads(ctx context.Context, articleID int64, v domain.Viewer, page, pageSize int) ([]*domain.CommentThread, int64, error) {
+	if _, err := s.articles.GetVisible(ctx, articleID, v); err != nil {
+		return nil, 0, domain.ErrCommentArticle
+	}
+	roots, total, err := s.repo.ListCommentThreads(ctx, articleID, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+	ids := make([]int64, 0, len(roots))
+	for _, c := range roots {
+		ids = append(ids, c.ID)
+	}
+	replies, err := s.repo.ListCommentReplies(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	threads := make([]*domain.CommentThread, 0, len(roots))
+	byRoot := make(map[int64]*domain.CommentThread, len(roots))
+	for _, c := range roots {
+		view := &domain.CommentView{Comment: c}
+		if c.Deleted() {
+			masked := *c
+			masked.Content = ""
+			view = &domain.CommentView{Comment: &masked, Deleted: true}
+		}
+		t := &domain.CommentThread{CommentView: view, Replies: make([]*domain.CommentView, 0)}
+		threads = append(threads, t)
+		byRoot[c.ID] = t
+	}
+	for _, c := range replies {
+		if t, ok := byRoot[c.RootID]; ok {
+			t.Replies = append(t.Replies, &domain.CommentView{Comment: c})
+		}
+	}
+	return threads, total, nil
+}
+
+// Counts 各文章可见评论数，未出现的文章为 0
+func (s *CommentAppService) Counts(ctx context.Context, articleIDs []int64) (map[int64]int64, error) {
+	counts, err := s.repo.CountApprovedComments(ctx, articleIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range articleIDs {
+		if _, ok := counts[id]; !ok {
+			counts[id] = 0
+		}
+	}
+	return counts, nil
+}
+
+// DeleteOwn 作者删除自己的评论（软删除）
+func (s *CommentAppService) DeleteOwn(ctx context.Context, id, userID int64) error {
+	c, err := s.get(ctx, id)
+	if err != nil {
+		return err
+	}
+	if userID <= 0 || c.UserID != userID {
+		return domain.ErrCommentForbidden
+	}
+	return s.repo.SoftDeleteComment(ctx, id)
+}
+
+// List 评论管理列表
+func (s *CommentAppService) List(ctx context.Context, f domain.CommentFilter) ([]*domain.Comment, int64, error) {
+	if f.Status != nil && !domain.ValidCommentStatus(*f.Status) {
+		return nil, 0, domain.ErrCommentStatus
+	}
+	return s.repo.ListComments(ctx, f)
+}
+
+// Moderate 批量审核：通过、标记为垃圾或退回待审核，返回实际修改数
+func (s *CommentAppService) Moderate(ctx context.Context, ids []int64, status int) (int64, error) {
+	if !domain.ValidCommentStatus(status) {
+		return 0, domain.ErrCommentStatus
+	}
+	n, err := s.repo.SetCommentStatus(ctx, ids, status)
+	if err != nil {
+		return 0, err
+	}
+	s.logger.Info("application: 审核评论: ids=%v status=%d affected=%d", ids, status, n)
+	return n, nil
+}
+
+// Delete 管理员删除评论（软删除）
+func (s *CommentAppService) Delete(ctx context.Context, id int64) error {
+	if _, err := s.get(ctx, id); err != nil {
+		return err
+	}
+	return s.repo.SoftDeleteComment(ctx, id)
+}
+
+// get 未删除的评论，不存在或已删除时返回 ErrCommentNotFound
+func (s *CommentAppService) get(ctx context.Context, id int64) (*domain.Comment, error) {
+	c, err := s.repo.GetComment(ctx, id)
+	if errors.Is(err, orm.ErrNoRows) {
+		return nil, domain.ErrCommentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if c.Deleted() {
+		return nil, domain.ErrCommentNotFound
+	}
+	return c, nil
+}
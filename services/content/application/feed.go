@@ -0,0 +1,105 @@
+package application
+
+import (
+	"context"
+	"strconv"
+
+	"blog-system/common/pkg/cacheaside"
+	"blog-system/services/content/domain"
+)
+
+// defaultFeedLimit 每个订阅源默认文章数
+const defaultFeedLimit = 20
+
+// SetFeedSite 设置订阅源站点信息，Limit 非正数时使用默认值
+func (s *ContentAppService) SetFeedSite(site domain.FeedSite) {
+	if site.Limit <= 0 {
+		site.Limit = defaultFeedLimit
+	}
+	s.feedSite = site
+}
+
+// Feed 订阅源（旁路缓存）：文章发布、修改、删除后随 feed 命名空间失效，
+// 分类、标签改名后随其命名空间版本号失效
+func (s *ContentAppService) Feed(ctx context.Context, scope domain.FeedScope) (*domain.Feed, error) {
+	key := s.cc.VersionedKey(ctx, nsFeed, scope.Kind, strconv.FormatInt(scope.ID, 10),
+		s.cc.Version(ctx, nsCategory), s.cc.Version(ctx, nsTag))
+	return cacheaside.Get(ctx, s.cc, key, func(ctx context.Context) (*domain.Feed, error) {
+		return s.buildFeed(ctx, scope)
+	})
+}
+
+// buildFeed 由已公开文章构建订阅源：摘要、分类与标签取自文章摘要，作者取用户名
+func (s *ContentAppService) buildFeed(ctx context.Context, scope domain.FeedScope) (*domain.Feed, error) {
+	site := s.feedSite
+	feed := &domain.Feed{Title: site.Title, Description: site.Description, Link: site.Link, Items: make([]*domain.FeedItem, 0)}
+	var (
+		categoryID *int64
+		tagIDs     []int64
+	)
+	switch scope.Kind {
+	case domain.SlugKindCategory:
+		categoryID = &scope.ID
+	case domain.SlugKindTag:
+		tagIDs = []int64{scope.ID}
+	}
+	if scope.Kind != "" {
+		feed.Title = site.Title + " - " + scope.Name
+		feed.Link = site.PageLink(scope.Kind, scope.Slug)
+	}
+	articles, err := s.repo.ListFeedArticles(ctx, categoryID, tagIDs, site.Limit)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int64, 0, len(articles))
+	authorIDs := make([]int64, 0, len(articles))
+	for _, a := range articles {
+		ids = append(ids, a.ID)
+		authorIDs = append(authorIDs, a.AuthorID)
+	}
+	summaries, err := s.repo.ListArticleSummariesByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*domain.ArticleSummary, len(summaries))
+	for _, sm := range summaries {
+		byID[sm.ID] = sm
+	}
+	authors, err := s.repo.ListAuthorNames(ctx, authorIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range articles {
+		item := &domain.FeedItem{
+			ID: a.ID, Title: a.Title, Link: site.ArticleLink(a.Slug), Author: authors[a.AuthorID],
+			UpdatedAt: a.UpdatedAt,
+		}
+		if a.PublishedAt != nil {
+			item.PublishedAt = *a.PublishedAt
+		}
+		if item.UpdatedAt.Before(item.PublishedAt) {
+			item.UpdatedAt = item.PublishedAt
+		}
+		if sm, ok := byID[a.ID]; ok {
+			item.Summary = sm.Summary
+			if sm.Category != nil {
+				item.Category = sm.Category.Name
+			}
+			for _, t := range sm.Tags {
+				item.Tags = append(item.Tags, t.Name)
+			}
+		}
+		if site.FullContent {
+			r, err := s.Render(ctx, a.Content)
+			if err != nil {
+				return nil, err
+			}
+			item.ContentHTML = r.HTML
+		}
+		if item.UpdatedAt.After(feed.Updated) {
+			feed.Updated = item.UpdatedAt
+		}
+		feed.Items = append(feed.Items, item)
+	}
+	return feed, nil
+}
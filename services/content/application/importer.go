@@ -0,0 +1,540 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"blog-system/common/pkg/logger"
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/orm"
+)
+
+// importSkipDirs 遍历导入源时跳过的目录（主题、依赖、生成结果与压缩工具附带的元数据）
+var importSkipDirs = map[string]bool{"node_modules": true, "themes": true, "public": true, "resources": true, "__MACOSX": true}
+
+// datePrefix Jekyll / Hexo 文件名中的日期前缀，如 2020-01-02-hello.md
+var datePrefix = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}-`)
+
+// ImportAppService 从 Markdown 文件（Hexo / Hugo 导出）导入文章，按别名幂等：已有同别名文章时更新，内容一致时跳过
+type ImportAppService struct {
+	repo   domain.ContentRepository
+	app    *ContentAppService
+	media  *MediaAppService
+	parser domain.ImportParser
+	logger logger.Logger
+}
+
+func NewImportService(repo domain.ContentRepository, app *ContentAppService, media *MediaAppService, parser domain.ImportParser, lgr logger.Logger) *ImportAppService {
+	return &ImportAppService{repo: repo, app: app, media: media, parser: parser, logger: lgr}
+}
+
+// importRun 单次导入的状态：标签、分类按名称匹配（不区分大小写），缺失的首次用到时创建；
+// DryRun 时只记录，待创建的以 -1 占位
+type importRun struct {
+	*ImportAppService
+	src      fs.FS
+	files    *archiveReader
+	authorID int64
+	dryRun   bool
+	report   *domain.ImportReport
+	tags     map[string]int64  // 小写名称 -> ID
+	cats     map[string]int64  // 小写分类路径（以 / 分隔）-> ID
+	images   map[string]string // 导入源内路径 -> 上传后的地址
+	slugs    map[string]bool   // 本次已处理的别名
+	bucket   *int64            // “未分类”
+}
+
+// Import 导入 src 中的 Markdown 文章，作者为 authorID；单篇失败记录在报告中，不影响其余文章
+func (s *ImportAppService) Import(ctx context.Context, src fs.FS, authorID int64, dryRun bool) (*domain.ImportReport, error) {
+	if authorID <= 0 {
+		return nil, domain.ErrImportLogin
+	}
+	files, err := importFiles(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, domain.ErrImportNoDocs
+	}
+	run := &importRun{
+		ImportAppService: s, src: src, files: newArchiveReader(src), authorID: authorID, dryRun: dryRun,
+		report: &domain.ImportReport{DryRun: dryRun, NewTags: make([]string, 0), NewCategories: make([]string, 0), Items: make([]*domain.ImportItem, 0, len(files))},
+		images: make(map[string]string), slugs: make(map[string]bool),
+	}
+	if err := run.load(ctx); err != nil {
+		return nil, err
+	}
+	for _, name := range files {
+		item := run.importFile(ctx, name)
+		if item.Action == domain.ImportActionFailed {
+			s.logger.Error("application: 导入文章失败: file=%s err=%s", name, item.Error)
+		}
+		run.report.Add(item)
+	}
+	r := run.report
+	s.logger.Info("application: 导入文章: dry_run=%t author=%d total=%d created=%d updated=%d unchanged=%d failed=%d",
+		dryRun, authorID, r.Total, r.Created, r.Updated, r.Unchanged, r.Failed)
+	return r, nil
+}
+
+// importFiles 导入源中的 Markdown 文件（按路径排序）：含 Hexo 的 _posts / _drafts 目录时只取其中的文件，
+// 否则含 Hugo 的 content 目录时只取其下的文件；Hugo 的列表页 _index.md 不导入
+func importFiles(src fs.FS) ([]string, error) {
+	var all, hexo, hugo []string
+	err := fs.WalkDir(src, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		base := d.Name()
+		if d.IsDir() {
+			if name != "." && (strings.HasPrefix(base, ".") || importSkipDirs[base]) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		ext := strings.ToLower(path.Ext(base))
+		if strings.HasPrefix(base, ".") || base == "_index.md" || (ext != ".md" && ext != ".markdown") {
+			return nil
+		}
+		all = append(all, name)
+		segs := strings.Split(path.Dir(name), "/")
+		if slices.Contains(segs, "_posts") || slices.Contains(segs, "_drafts") {
+			hexo = append(hexo, name)
+		} else if slices.Contains(segs, "content") {
+			hugo = append(hugo, name)
+		}
+		return nil
+	})
+	switch {
+	case err != nil:
+		return nil, err
+	case len(hexo) > 0:
+		return hexo, nil
+	case len(hugo) > 0:
+		return hugo, nil
+	}
+	return all, nil
+}
+
+// load 读取已有标签与分类（分类以根到自身的名称路径为键）
+func (r *importRun) load(ctx context.Context) error {
+	tags, err := r.repo.ListAllTags(ctx)
+	if err != nil {
+		return err
+	}
+	r.tags = make(map[string]int64, len(tags))
+	for _, t := range tags {
+		r.tags[strings.ToLower(t.Name)] = t.ID
+	}
+	cats, err := r.repo.ListAllCategories(ctx)
+	if err != nil {
+		return err
+	}
+	byID := make(map[int64]*domain.Category, len(cats))
+	for _, c := range cats {
+		byID[c.ID] = c
+	}
+	r.cats = make(map[string]int64, len(cats))
+	for _, c := range cats {
+		names := []string{strings.ToLower(c.Name)}
+		for p, depth := byID[c.ParentID], 1; p != nil && depth < domain.CategoryMaxDepth; p, depth = byID[p.ParentID], depth+1 {
+			names = append([]string{strings.ToLower(p.Name)}, names...)
+		}
+		r.cats[strings.Join(names, "/")] = c.ID
+	}
+	return nil
+}
+
+// importFile 导入单个文件
+func (r *importRun) importFile(ctx context.Context, name string) *domain.ImportItem {
+	item := &domain.ImportItem{File: name, Action: domain.ImportActionFailed}
+	fail := func(err error) *domain.ImportItem {
+		item.Action, item.Error = domain.ImportActionFailed, err.Error()
+		return item
+	}
+	data, err := r.files.ReadFile(name)
+	if err != nil {
+		return fail(err)
+	}
+	doc, err := r.parser.Parse(name, data)
+	if err != nil {
+		return fail(err)
+	}
+	if doc.Title == "" {
+		doc.Title = docBaseName(name)
+	}
+	item.Title = doc.Title
+	if item.Slug = docSlug(doc); item.Slug == "" {
+		return fail(domain.ErrSlugInvalid)
+	}
+	if r.slugs[item.Slug] {
+		return fail(domain.ErrSlugTaken)
+	}
+	r.slugs[item.Slug] = true
+
+	old, err := r.repo.GetArticleBySlug(ctx, item.Slug)
+	if errors.Is(err, orm.ErrNoRows) {
+		old = nil
+	} else if err != nil {
+		return fail(err)
+	}
+	categoryID, err := r.category(ctx, doc.Categories)
+	if err != nil {
+		return fail(err)
+	}
+	tagIDs, err := r.tagIDs(ctx, doc.Tags)
+	if err != nil {
+		return fail(err)
+	}
+	pending := false // DryRun 中尚未上传过的图片，导入后正文必然变化
+	resolve := func(ref domain.ImageRef) (string, bool) {
+		addr, ok, isNew := r.image(ctx, name, ref, item)
+		pending = pending || isNew
+		return addr, ok
+	}
+	a := &domain.Article{
+		Title: doc.Title, Slug: item.Slug, Content: domain.RewriteImages(doc.Content, resolve),
+		Summary: optional(doc.Summary), AuthorID: r.authorID, CategoryID: categoryID,
+		MetaKeywords: optional(strings.Join(doc.Keywords, ", ")),
+	}
+	if doc.Cover != "" {
+		cover, ok := resolve(domain.ImageRef{Link: doc.Cover})
+		if !ok {
+			cover = doc.Cover
+		}
+		a.Cover = optional(cover)
+	}
+	date := r.docDate(name, doc, old)
+	a.CreatedAt = date
+	if doc.Draft {
+		a.Status = domain.ArticleStatusDraft
+	} else {
+		a.Status, a.PublishedAt = domain.ArticleStatusPublished, &date
+	}
+	if err := a.NormalizeStatus(time.Now()); err != nil {
+		return fail(err)
+	}
+
+	if old == nil {
+		item.Action = domain.ImportActionCreate
+		if r.dryRun {
+			return item
+		}
+		if _, err := r.app.Create(ctx, a, tagIDs); err != nil {
+			return fail(err)
+		}
+		item.ArticleID = a.ID
+		return item
+	}
+	item.ArticleID = old.ID
+	oldTags, err := r.repo.ListArticleTags(ctx, old.ID)
+	if err != nil {
+		return fail(err)
+	}
+	if !pending && sameImported(old, a) && sameTags(oldTags, tagIDs) {
+		item.Action = domain.ImportActionUnchanged
+		return item
+	}
+	item.Action = domain.ImportActionUpdate
+	if r.dryRun {
+		return item
+	}
+	// 只覆盖导入的字段，作者、置顶、推荐与 SEO 标题等保持不变
+	updated := *old
+	updated.Title, updated.Content, updated.Summary, updated.Cover = a.Title, a.Content, a.Summary, a.Cover
+	updated.CategoryID, updated.Status, updated.PublishedAt, updated.MetaKeywords = a.CategoryID, a.Status, a.PublishedAt, a.MetaKeywords
+	updated.Version = 0
+	if err := r.app.Update(ctx, &updated, tagIDs); err != nil {
+		return fail(err)
+	}
+	return item
+}
+
+// docDate 发布时间取前言中的 date（精确到秒）；缺省时已有文章沿用原时间，新文章取文件修改时间
+func (r *importRun) docDate(name string, doc *domain.ImportDoc, old *domain.Article) time.Time {
+	if doc.Date != nil {
+		return doc.Date.Truncate(time.Second)
+	}
+	if old != nil {
+		if old.PublishedAt != nil {
+			return *old.PublishedAt
+		}
+		return old.CreatedAt
+	}
+	if info, err := fs.Stat(r.src, name); err == nil && !info.ModTime().IsZero() {
+		return info.ModTime().Truncate(time.Second)
+	}
+	return time.Now().Truncate(time.Second)
+}
+
+// category 按名称路径查找分类，缺失的逐级创建（超出层级上限的部分忽略）；无分类时归入“未分类”
+func (r *importRun) category(ctx context.Context, names []string) (int64, error) {
+	if len(names) == 0 {
+		return r.uncategorized(ctx)
+	}
+	names = names[:min(len(names), domain.CategoryMaxDepth)]
+	var parentID int64
+	for i := range names {
+		key := strings.ToLower(strings.Join(names[:i+1], "/"))
+		if id, ok := r.cats[key]; ok {
+			parentID = id
+			continue
+		}
+		id := int64(-1)
+		if !r.dryRun {
+			c := &domain.Category{Name: names[i], ParentID: parentID}
+			if err := r.app.CreateCategory(ctx, c); err != nil {
+				return 0, err
+			}
+			id = c.ID
+		}
+		r.cats[key] = id
+		r.report.NewCategories = append(r.report.NewCategories, strings.Join(names[:i+1], "/"))
+		parentID = id
+	}
+	return parentID, nil
+}
+
+func (r *importRun) uncategorized(ctx context.Context) (int64, error) {
+	if r.bucket != nil {
+		return *r.bucket, nil
+	}
+	c, err := r.app.uncategorized(ctx, !r.dryRun)
+	if err != nil {
+		return 0, err
+	}
+	id := int64(-1)
+	if c != nil {
+		id = c.ID
+	} else {
+		r.report.NewCategories = append(r.report.NewCategories, domain.UncategorizedName)
+	}
+	r.bucket = &id
+	return id, nil
+}
+
+// tagIDs 按名称查找标签（去重），缺失的创建
+func (r *importRun) tagIDs(ctx context.Context, names []string) ([]int64, error) {
+	ids := make([]int64, 0, len(names))
+	for _, name := range names {
+		key := strings.ToLower(name)
+		id, ok := r.tags[key]
+		if !ok {
+			id = -1
+			if !r.dryRun {
+				t := &domain.Tag{Name: name}
+				if err := r.app.CreateTag(ctx, t); err != nil {
+					return nil, err
+				}
+				id = t.ID
+			}
+			r.tags[key] = id
+			r.report.NewTags = append(r.report.NewTags, name)
+		}
+		if !slices.Contains(ids, id) || id < 0 {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// image 将导入源内的相对图片上传到文件存储并返回其地址；外部链接原样保留。
+// 找不到或上传失败时记入 Missing；DryRun 时只查找已上传过的相同内容，isNew 表示需要新上传
+func (r *importRun) image(ctx context.Context, doc string, ref domain.ImageRef, item *domain.ImportItem) (addr string, ok, isNew bool) {
+	candidates := imageCandidates(doc, ref)
+	if candidates == nil {
+		return "", false, false
+	}
+	for _, name := range candidates {
+		if addr, ok := r.images[name]; ok {
+			item.Images++
+			return addr, true, false
+		}
+		data, err := r.files.ReadFile(name)
+		if err != nil {
+			continue
+		}
+		item.Images++
+		var m *domain.Media
+		if r.dryRun {
+			if m, err = r.media.FindByContent(ctx, data); err == nil && m == nil {
+				return "", false, true
+			}
+		} else {
+			m, err = r.media.Upload(ctx, path.Base(name), bytes.NewReader(data), r.authorID)
+		}
+		if err != nil {
+			item.Missing = append(item.Missing, ref.Link)
+			return "", false, false
+		}
+		r.images[name] = m.URL
+		return m.URL, true, false
+	}
+	item.Missing = append(item.Missing, ref.Link)
+	return "", false, false
+}
+
+// imageCandidates 图片链接在导入源内可能的位置，外部链接返回 nil：
+//   - 相对链接：文章所在目录，其次为同名资源目录（Hexo post_asset_folder）
+//   - {% asset_img %}：同名资源目录，其次为文章所在目录
+//   - 站点绝对路径（/images/a.png）：Hexo 的 source 目录、Hugo 的 static 与 assets 目录、导入源根目录
+func imageCandidates(doc string, ref domain.ImageRef) []string {
+	link := ref.Link
+	if i := strings.IndexAny(link, "?#"); i >= 0 {
+		link = link[:i]
+	}
+	lower := strings.ToLower(link)
+	if link == "" || strings.Contains(lower, "://") || strings.HasPrefix(lower, "//") || strings.HasPrefix(lower, "data:") {
+		return nil
+	}
+	if u, err := url.PathUnescape(link); err == nil {
+		link = u
+	}
+	dir := path.Dir(doc)
+	bundle := strings.TrimSuffix(doc, path.Ext(doc))
+	var raw []string
+	switch {
+	case ref.Asset:
+		raw = []string{path.Join(bundle, link), path.Join(dir, link)}
+	case strings.HasPrefix(link, "/"):
+		for _, root := range staticRoots(doc) {
+			raw = append(raw, path.Join(root, link))
+		}
+	default:
+		raw = []string{path.Join(dir, link), path.Join(bundle, link)}
+	}
+	out := make([]string, 0, len(raw))
+	for _, name := range raw {
+		name = strings.TrimPrefix(name, "/")
+		if fs.ValidPath(name) && !slices.Contains(out, name) {
+			out = append(out, name)
+		}
+	}
+	return out
+}
+
+// staticRoots 站点绝对路径对应的目录：文章位于 <站点>/source/_posts 时为 <站点>/source，
+// 位于 <站点>/content 时为 <站点>/static 与 <站点>/assets
+func staticRoots(doc string) []string {
+	segs := strings.Split(path.Dir(doc), "/")
+	var roots []string
+	for i, seg := range segs {
+		site := strings.Join(segs[:i], "/")
+		switch seg {
+		case "_posts", "_drafts":
+			roots = append(roots, site)
+		case "content":
+			roots = append(roots, path.Join(site, "static"), path.Join(site, "assets"))
+		}
+	}
+	return append(roots, ".", "source", "static")
+}
+
+// docSlug 别名依次取前言中的 slug、文件名（index.md 取所在目录名，去掉日期前缀）、标题，不合法时转写
+func docSlug(doc *domain.ImportDoc) string {
+	maxLen := domain.SlugMaxLen[domain.SlugKindArticle]
+	for _, source := range []string{doc.Slug, datePrefix.ReplaceAllString(docBaseName(doc.Path), ""), doc.Title} {
+		if source == "" {
+			continue
+		}
+		if domain.ValidSlug(domain.SlugKindArticle, source) {
+			return source
+		}
+		if out := slugify(source, maxLen); out != "" {
+			return out
+		}
+	}
+	return ""
+}
+
+// docBaseName 去掉扩展名的文件名，Hugo 页面包（index.md）取所在目录名
+func docBaseName(name string) string {
+	base := strings.TrimSuffix(path.Base(name), path.Ext(name))
+	if strings.EqualFold(base, "index") && path.Dir(name) != "." {
+		return path.Base(path.Dir(name))
+	}
+	return base
+}
+
+// sameImported 导入的字段是否与已有文章一致
+func sameImported(old, a *domain.Article) bool {
+	return old.Title == a.Title && old.Content == a.Content && old.CategoryID == a.CategoryID && old.Status == a.Status &&
+		nullString(old.Summary) == nullString(a.Summary) && nullString(old.Cover) == nullString(a.Cover) &&
+		nullString(old.MetaKeywords) == nullString(a.MetaKeywords) && sameTime(old.PublishedAt, a.PublishedAt)
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+func sameTags(tags []*domain.Tag, ids []int64) bool {
+	if len(tags) != len(ids) {
+		return false
+	}
+	for _, t := range tags {
+		if !slices.Contains(ids, t.ID) {
+			return false
+		}
+	}
+	return true
+}
+
+// optional 空串视为 NULL
+func optional(v string) *sql.NullString {
+	if v == "" {
+		return nil
+	}
+	return &sql.NullString{String: v, Valid: true}
+}
+
+// archiveReader 读取导入源或备份归档中的文件：先按声明的解压大小（zip 为 UncompressedSize64）拒绝，
+// 读取时再以 LimitReader 兜底；单个文件不超过 maxFile，累计不超过 left
+type archiveReader struct {
+	src     fs.FS
+	maxFile int64
+	left    int64
+}
+
+func newArchiveReader(src fs.FS) *archiveReader {
+	return &archiveReader{src: src, maxFile: domain.ArchiveMaxFileSize, left: domain.ArchiveMaxTotalSize}
+}
+
+func (a *archiveReader) ReadFile(name string) ([]byte, error) {
+	f, err := a.src.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	limit := min(a.maxFile, a.left)
+	if info.Size() > limit {
+		return nil, fmt.Errorf("%w: %s", domain.ErrArchiveTooLarge, name)
+	}
+	data, err := io.ReadAll(io.LimitReader(f, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: %s", domain.ErrArchiveTooLarge, name)
+	}
+	a.left -= int64(len(data))
+	return data, nil
+}
@@ -0,0 +1,55 @@
+package application
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+
+	"blog-system/services/content/domain"
+)
+
+// zipOf 构造 zip：name -> 内容全为 0 的 size 字节（压缩后很小）
+func zipOf(t *testing.T, files map[string]int) *zip.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, size := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write(make([]byte, size)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return zr
+}
+
+func TestArchiveReaderLimits(t *testing.T) {
+	zr := zipOf(t, map[string]int{"bomb.md": 1 << 20, "a.md": 600, "b.md": 600, "c.md": 600})
+	r := &archiveReader{src: zr, maxFile: 1 << 10, left: 1500}
+	// 按声明的解压大小拒绝，不计入累计
+	if _, err := r.ReadFile("bomb.md"); !errors.Is(err, domain.ErrArchiveTooLarge) {
+		t.Fatalf("bomb: err = %v, want ErrArchiveTooLarge", err)
+	}
+	for _, name := range []string{"a.md", "b.md"} {
+		if data, err := r.ReadFile(name); err != nil || len(data) != 600 {
+			t.Fatalf("%s: len=%d err=%v", name, len(data), err)
+		}
+	}
+	// 累计超出上限
+	if _, err := r.ReadFile("c.md"); !errors.Is(err, domain.ErrArchiveTooLarge) {
+		t.Fatalf("total: err = %v, want ErrArchiveTooLarge", err)
+	}
+	if _, err := r.ReadFile("missing.md"); err == nil {
+		t.Fatal("missing file: want error")
+	}
+}
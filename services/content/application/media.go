@@ -0,0 +1,282 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"blog-system/common/pkg/logger"
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/orm"
+)
+
+// defaultOrphanAge 孤立文件默认只统计上传超过该时长的，避免把刚上传、尚未保存到文章的文件算进去
+const defaultOrphanAge = 24 * time.Hour
+
+const (
+	defaultMediaRateLimit  = 30
+	defaultMediaRateWindow = time.Hour
+)
+
+// mediaRateKeyPrefix 上传限流计数键前缀，后接用户 ID
+const mediaRateKeyPrefix = "content:ratelimit:media:"
+
+// mediaExts 各类型的存储扩展名
+var mediaExts = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// MediaAppService 文件上传与管理：按内容哈希去重，图片按配置宽度生成缩放副本
+type MediaAppService struct {
+	repo    domain.MediaRepository
+	storage domain.MediaStorage
+	images  domain.ImageProcessor
+	limiter RateLimiter
+	logger  logger.Logger
+
+	maxSize    int64
+	maxPixels  int64
+	allowed    []string
+	widths     []int // 缩放副本宽度，原图不宽于该值时不生成
+	rateLimit  int
+	rateWindow time.Duration
+}
+
+// NewMediaService limiter 为空时不限制上传频率（单机调试）
+func NewMediaService(repo domain.MediaRepository, storage domain.MediaStorage, images domain.ImageProcessor, limiter RateLimiter, lgr logger.Logger) *MediaAppService {
+	return &MediaAppService{
+		repo:       repo,
+		storage:    storage,
+		images:     images,
+		limiter:    limiter,
+		logger:     lgr,
+		maxSize:    domain.MediaMaxSize,
+		maxPixels:  domain.MediaMaxPixels,
+		allowed:    domain.MediaAllowedTypes,
+		rateLimit:  defaultMediaRateLimit,
+		rateWindow: defaultMediaRateWindow,
+	}
+}
+
+// SetLimits 设置单文件大小上限与允许的类型，零值时使用默认值
+func (s *MediaAppService) SetLimits(maxSize int64, allowed []string) {
+	if maxSize > 0 {
+		s.maxSize = maxSize
+	}
+	if len(allowed) > 0 {
+		s.allowed = allowed
+	}
+}
+
+// SetMaxPixels 设置图片像素数（宽×高）上限，非正数时使用默认值
+func (s *MediaAppService) SetMaxPixels(n int64) {
+	if n <= 0 {
+		n = domain.MediaMaxPixels
+	}
+	s.maxPixels = n
+}
+
+// SetRateLimit 设置每个用户在 window 内可上传的文件数，非正数时使用默认值
+func (s *MediaAppService) SetRateLimit(limit int, window time.Duration) {
+	if limit <= 0 {
+		limit = defaultMediaRateLimit
+	}
+	if window <= 0 {
+		window = defaultMediaRateWindow
+	}
+	s.rateLimit, s.rateWindow = limit, window
+}
+
+// SetVariantWidths 设置图片缩放副本的宽度
+func (s *MediaAppService) SetVariantWidths(widths []int) {
+	s.widths = slices.DeleteFunc(slices.Clone(widths), func(w int) bool { return w <= 0 })
+	slices.Sort(s.widths)
+	s.widths = slices.Compact(s.widths)
+}
+
+// MaxSize 单文件大小上限（字节）
+func (s *MediaAppService) MaxSize() int64 { return s.maxSize }
+
+// AllowUpload 接口上传前的频率限制（导入与恢复不经过此处）；限流器故障时放行
+func (s *MediaAppService) AllowUpload(ctx context.Context, userID int64) error {
+	if s.limiter == nil {
+		return nil
+	}
+	ok, err := s.limiter.Allow(ctx, mediaRateKeyPrefix+strconv.FormatInt(userID, 10), s.rateLimit, s.rateWindow)
+	if err != nil {
+		s.logger.Error("application: 上传限流失败: user=%d err=%v", userID, err)
+		return nil
+	}
+	if !ok {
+		return domain.ErrMediaLimited
+	}
+	return nil
+}
+
+// Upload 上传文件：类型按内容嗅探，图片按头部声明的尺寸限制像素数，内容已存在时直接返回已有记录
+func (s *MediaAppService) Upload(ctx context.Context, name string, r io.Reader, uploaderID int64) (*domain.Media, error) {
+	if uploaderID <= 0 {
+		return nil, domain.ErrMediaLogin
+	}
+	data, err := io.ReadAll(io.LimitReader(r, s.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, domain.ErrMediaEmpty
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, domain.ErrMediaTooLarge
+	}
+	mimeType, _, _ := mime.ParseMediaType(http.DetectContentType(data))
+	ext, ok := mediaExts[mimeType]
+	if !ok || !slices.Contains(s.allowed, mimeType) {
+		return nil, domain.ErrMediaType
+	}
+	// 只读图片头，不解码；不支持解码的格式（如 WebP）不生成副本，也无需限制
+	width, height, sizeErr := s.images.Size(data)
+	if sizeErr == nil && int64(width)*int64(height) > s.maxPixels {
+		return nil, domain.ErrMediaPixels
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	if m, err := s.repo.GetMediaByHash(ctx, hash); err == nil {
+		return m, nil
+	} else if !errors.Is(err, orm.ErrNoRows) {
+		return nil, err
+	}
+
+	m := &domain.Media{
+		Hash: hash, StorageKey: mediaKey(hash, "", ext), Name: name, MimeType: mimeType, Size: int64(len(data)),
+		Variants: make([]*domain.MediaVariant, 0), UploaderID: uploaderID, CreatedAt: time.Now(),
+	}
+	m.URL = s.storage.URL(m.StorageKey)
+	if err := s.storage.Put(ctx, m.StorageKey, bytes.NewReader(data), m.Size, mimeType); err != nil {
+		s.logger.Error("application: 保存文件失败: key=%s err=%v", m.StorageKey, err)
+		return nil, err
+	}
+	if sizeErr == nil {
+		m.Width, m.Height = width, height
+		s.makeVariants(ctx, m, data)
+	}
+	if err := s.repo.CreateMedia(ctx, m); err != nil {
+		// 并发上传同一内容时唯一索引冲突，以先写入的记录为准（存储键相同，无需清理）
+		if existing, e := s.repo.GetMediaByHash(ctx, hash); e == nil {
+			return existing, nil
+		}
+		s.removeKeys(ctx, m.Keys())
+		return nil, err
+	}
+	s.logger.Info("application: 上传文件: id=%d hash=%s size=%d uploader=%d", m.ID, m.Hash, m.Size, m.UploaderID)
+	return m, nil
+}
+
+// makeVariants 解码一次生成全部缩放副本，失败只记录日志
+func (s *MediaAppService) makeVariants(ctx context.Context, m *domain.Media, data []byte) {
+	ext := ".png"
+	if m.MimeType == "image/jpeg" {
+		ext = ".jpg"
+	}
+	imgs, err := s.images.Resize(data, m.MimeType, s.widths, s.maxPixels)
+	if err != nil {
+		s.logger.Error("application: 生成缩放图失败: hash=%s err=%v", m.Hash, err)
+	}
+	for _, img := range imgs {
+		name := "w" + strconv.Itoa(img.Width)
+		v := &domain.MediaVariant{
+			Name: name, StorageKey: mediaKey(m.Hash, name, ext),
+			Width: img.Width, Height: img.Height, Size: int64(len(img.Data)),
+		}
+		v.URL = s.storage.URL(v.StorageKey)
+		if err := s.storage.Put(ctx, v.StorageKey, bytes.NewReader(img.Data), v.Size, mime.TypeByExtension(ext)); err != nil {
+			s.logger.Error("application: 保存缩放图失败: key=%s err=%v", v.StorageKey, err)
+			continue
+		}
+		m.Variants = append(m.Variants, v)
+	}
+}
+
+// FindByContent 按内容哈希查找已上传的文件，不存在时返回 nil
+func (s *MediaAppService) FindByContent(ctx context.Context, data []byte) (*domain.Media, error) {
+	sum := sha256.Sum256(data)
+	m, err := s.repo.GetMediaByHash(ctx, hex.EncodeToString(sum[:]))
+	if errors.Is(err, orm.ErrNoRows) {
+		return nil, nil
+	}
+	return m, err
+}
+
+// Open 读取存储中的文件（本地存储经内容服务对外提供）
+func (s *MediaAppService) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.storage.Open(ctx, key)
+}
+
+// List 文件管理列表（新到旧）
+func (s *MediaAppService) List(ctx context.Context, f domain.MediaFilter) ([]*domain.Media, int64, error) {
+	return s.repo.ListMedia(ctx, f)
+}
+
+// Orphans 未被文章封面、正文或用户头像引用且上传超过 olderThan 的文件，olderThan 非正数时取 24 小时
+func (s *MediaAppService) Orphans(ctx context.Context, olderThan time.Duration, page, pageSize int) ([]*domain.Media, int64, error) {
+	if olderThan <= 0 {
+		olderThan = defaultOrphanAge
+	}
+	before := time.Now().Add(-olderThan)
+	return s.repo.ListMedia(ctx, domain.MediaFilter{Orphan: true, Before: &before, Page: page, PageSize: pageSize})
+}
+
+// Delete 删除文件及其副本；仍被引用时须 force
+func (s *MediaAppService) Delete(ctx context.Context, id int64, force bool) error {
+	m, err := s.repo.GetMedia(ctx, id)
+	if errors.Is(err, orm.ErrNoRows) {
+		return domain.ErrMediaNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if !force {
+		used, err := s.repo.MediaReferenced(ctx, m)
+		if err != nil {
+			return err
+		}
+		if used {
+			return domain.ErrMediaInUse
+		}
+	}
+	if err := s.repo.DeleteMedia(ctx, id); err != nil {
+		return err
+	}
+	s.removeKeys(ctx, m.Keys())
+	s.logger.Info("application: 删除文件: id=%d hash=%s force=%t", m.ID, m.Hash, force)
+	return nil
+}
+
+// removeKeys 删除存储对象，失败只记录日志（元数据已删除，残留文件不影响使用）
+func (s *MediaAppService) removeKeys(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.storage.Delete(ctx, key); err != nil {
+			s.logger.Error("application: 删除存储文件失败: key=%s err=%v", key, err)
+		}
+	}
+}
+
+// mediaKey 存储键：哈希前两位分目录，副本追加规格名，如 ab/abcd…_w320.jpg
+func mediaKey(hash, variant, ext string) string {
+	name := hash
+	if variant != "" {
+		name += "_" + variant
+	}
+	return hash[:2] + "/" + name + ext
+}
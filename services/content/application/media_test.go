@@ -0,0 +1,155 @@
+package application
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/png"
+	"io"
+	"testing"
+	"time"
+
+	"blog-system/common/pkg/logger"
+	"blog-system/services/content/domain"
+	"blog-system/services/content/infrastructure/imaging"
+
+	"github.com/CoucouMonEcho/go-framework/orm"
+)
+
+// fakeMediaRepo 内存文件元数据仓储
+type fakeMediaRepo struct {
+	byHash map[string]*domain.Media
+}
+
+func (r *fakeMediaRepo) CreateMedia(_ context.Context, m *domain.Media) error {
+	m.ID = int64(len(r.byHash) + 1)
+	r.byHash[m.Hash] = m
+	return nil
+}
+
+func (r *fakeMediaRepo) GetMedia(context.Context, int64) (*domain.Media, error) {
+	return nil, orm.ErrNoRows
+}
+
+func (r *fakeMediaRepo) GetMediaByHash(_ context.Context, hash string) (*domain.Media, error) {
+	if m, ok := r.byHash[hash]; ok {
+		return m, nil
+	}
+	return nil, orm.ErrNoRows
+}
+
+func (r *fakeMediaRepo) ListMedia(context.Context, domain.MediaFilter) ([]*domain.Media, int64, error) {
+	return nil, 0, nil
+}
+
+func (r *fakeMediaRepo) DeleteMedia(context.Context, int64) error { return nil }
+
+func (r *fakeMediaRepo) MediaReferenced(context.Context, *domain.Media) (bool, error) {
+	return false, nil
+}
+
+// fakeStorage 内存文件存储
+type fakeStorage struct {
+	objects map[string][]byte
+}
+
+func (s *fakeStorage) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	data, err := io.ReadAll(r)
+	s.objects[key] = data
+	return err
+}
+
+func (s *fakeStorage) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, domain.ErrMediaNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *fakeStorage) Delete(_ context.Context, key string) error {
+	delete(s.objects, key)
+	return nil
+}
+
+func (s *fakeStorage) URL(key string) string { return "/media/" + key }
+
+// countingImages 统计 Resize 调用次数
+type countingImages struct {
+	*imaging.Processor
+	resizes int
+}
+
+func (p *countingImages) Resize(data []byte, mimeType string, widths []int, maxPixels int64) ([]*domain.ImageVariant, error) {
+	p.resizes++
+	return p.Processor.Resize(data, mimeType, widths, maxPixels)
+}
+
+// fixedLimiter 前 n 次放行
+type fixedLimiter struct{ n int }
+
+func (l *fixedLimiter) Allow(context.Context, string, int, time.Duration) (bool, error) {
+	l.n--
+	return l.n >= 0, nil
+}
+
+func newMediaTestService(limiter RateLimiter) (*MediaAppService, *fakeStorage, *countingImages) {
+	store := &fakeStorage{objects: make(map[string][]byte)}
+	images := &countingImages{Processor: imaging.NewProcessor()}
+	svc := NewMediaService(&fakeMediaRepo{byHash: make(map[string]*domain.Media)}, store, images, limiter, logger.Log())
+	return svc, store, images
+}
+
+func pngData(t *testing.T, w, h int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewNRGBA(image.Rect(0, 0, w, h))); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestUploadVariantsDecodeOnce(t *testing.T) {
+	svc, store, images := newMediaTestService(nil)
+	svc.SetVariantWidths([]int{16, 32, 64, 400})
+	m, err := svc.Upload(context.Background(), "a.png", bytes.NewReader(pngData(t, 100, 50)), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if images.resizes != 1 {
+		t.Fatalf("Resize called %d times, want 1", images.resizes)
+	}
+	if len(m.Variants) != 3 || len(store.objects) != 4 {
+		t.Fatalf("variants=%d objects=%d, want 3 and 4", len(m.Variants), len(store.objects))
+	}
+}
+
+func TestUploadRejectsLargeImages(t *testing.T) {
+	svc, store, images := newMediaTestService(nil)
+	svc.SetMaxPixels(100*50 - 1)
+	svc.SetVariantWidths([]int{16})
+	if _, err := svc.Upload(context.Background(), "a.png", bytes.NewReader(pngData(t, 100, 50)), 1); !errors.Is(err, domain.ErrMediaPixels) {
+		t.Fatalf("err = %v, want ErrMediaPixels", err)
+	}
+	if len(store.objects) != 0 || images.resizes != 0 {
+		t.Fatalf("stored %d objects and resized %d times before rejecting", len(store.objects), images.resizes)
+	}
+}
+
+func TestAllowUpload(t *testing.T) {
+	svc, _, _ := newMediaTestService(&fixedLimiter{n: 2})
+	for i := 0; i < 2; i++ {
+		if err := svc.AllowUpload(context.Background(), 1); err != nil {
+			t.Fatalf("upload %d: %v", i, err)
+		}
+	}
+	if err := svc.AllowUpload(context.Background(), 1); !errors.Is(err, domain.ErrMediaLimited) {
+		t.Fatalf("err = %v, want ErrMediaLimited", err)
+	}
+	// 未配置限流器时不限制
+	svc, _, _ = newMediaTestService(nil)
+	if err := svc.AllowUpload(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+}
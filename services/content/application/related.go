@@ -0,0 +1,69 @@
+package application
+
+import (
+	"context"
+	"strconv"
+
+	"blog-system/common/pkg/cacheaside"
+	"blog-system/services/content/domain"
+)
+
+// Related 文章的相关推荐（仅已公开文章），遵循读取方对原文章的可见性；limit 超出范围时取默认值
+func (s *ContentAppService) Related(ctx context.Context, id int64, v domain.Viewer, limit int) ([]*domain.RelatedArticle, error) {
+	if limit <= 0 || limit > domain.RelatedMaxLimit {
+		limit = domain.RelatedDefaultLimit
+	}
+	a, err := s.GetVisible(ctx, id, v)
+	if err != nil {
+		return nil, err
+	}
+	hits, err := s.relatedHits(ctx, a)
+	if err != nil {
+		s.logger.Error("application: 计算相关文章失败: id=%d err=%v", id, err)
+		return nil, err
+	}
+	hits = hits[:min(limit, len(hits))]
+	ids := make([]int64, 0, len(hits))
+	for _, h := range hits {
+		ids = append(ids, h.ID)
+	}
+	summaries, err := s.repo.ListArticleSummariesByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*domain.ArticleSummary, len(summaries))
+	for _, sm := range summaries {
+		byID[sm.ID] = sm
+	}
+	list := make([]*domain.RelatedArticle, 0, len(hits))
+	for _, h := range hits {
+		if sm, ok := byID[h.ID]; ok {
+			list = append(list, &domain.RelatedArticle{ArticleSummary: sm, Score: h.Score, Pinned: h.Pinned})
+		}
+	}
+	return list, nil
+}
+
+// relatedHits 按上限条数预计算并缓存打分结果：文章内容、状态变更随 feed 命名空间失效，标签变更随标签命名空间失效
+func (s *ContentAppService) relatedHits(ctx context.Context, a *domain.Article) ([]*domain.RelatedHit, error) {
+	key := s.cc.Key("related", strconv.FormatInt(a.ID, 10), s.cc.Version(ctx, nsFeed), s.cc.Version(ctx, nsTag))
+	return cacheaside.Get(ctx, s.cc, key, func(ctx context.Context) ([]*domain.RelatedHit, error) {
+		candidates, err := s.repo.ListRelatedCandidates(ctx)
+		if err != nil {
+			return nil, err
+		}
+		tags, err := s.repo.ListArticleTags(ctx, a.ID)
+		if err != nil {
+			return nil, err
+		}
+		src := &domain.RelatedCandidate{ID: a.ID, CategoryID: a.CategoryID, IsRecommend: a.IsRecommend}
+		for _, t := range tags {
+			src.TagIDs = append(src.TagIDs, t.ID)
+		}
+		sim, err := s.index.Similar(ctx, a.ID)
+		if err != nil {
+			return nil, err
+		}
+		return domain.RankRelated(src, candidates, sim, domain.RelatedMaxLimit), nil
+	})
+}
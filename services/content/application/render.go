@@ -0,0 +1,36 @@
+package application
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	"blog-system/common/pkg/cacheaside"
+	"blog-system/services/content/domain"
+)
+
+// renderVersion 渲染规则版本，调整渲染器时递增使旧缓存整体失效
+const renderVersion = "v1"
+
+// Render 渲染 Markdown 正文，结果按内容哈希缓存（内容不变则结果不变，无需主动失效）
+func (s *ContentAppService) Render(ctx context.Context, content string) (*domain.Rendered, error) {
+	sum := sha256.Sum256([]byte(content))
+	key := s.rc.Key(renderVersion, hex.EncodeToString(sum[:]))
+	return cacheaside.Get(ctx, s.rc, key, func(context.Context) (*domain.Rendered, error) {
+		return s.renderer.Render(content)
+	})
+}
+
+// GetVisibleDetail 按读取方可见性获取文章详情及渲染结果
+func (s *ContentAppService) GetVisibleDetail(ctx context.Context, id int64, v domain.Viewer) (*domain.ArticleDetail, error) {
+	a, err := s.GetVisible(ctx, id, v)
+	if err != nil {
+		return nil, err
+	}
+	r, err := s.Render(ctx, a.Content)
+	if err != nil {
+		s.logger.Error("application: 渲染文章失败: id=%d err=%v", id, err)
+		return nil, err
+	}
+	return &domain.ArticleDetail{Article: a, Rendered: r}, nil
+}
@@ -0,0 +1,117 @@
+package application
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"blog-system/common/pkg/textdiff"
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/orm"
+)
+
+// defaultRevisionRetain 每篇文章默认保留的历史版本数
+const defaultRevisionRetain = 50
+
+// unifiedContext unified diff 上下文行数
+const unifiedContext = 3
+
+// SetRevisionRetain 设置每篇文章保留的历史版本数，非正数时使用默认值
+func (s *ContentAppService) SetRevisionRetain(n int) {
+	if n <= 0 {
+		n = defaultRevisionRetain
+	}
+	s.revisionRetain = n
+}
+
+// ListRevisions 文章的历史版本（新到旧）
+func (s *ContentAppService) ListRevisions(ctx context.Context, articleID int64) ([]*domain.ArticleRevision, error) {
+	return s.repo.ListRevisions(ctx, articleID)
+}
+
+// GetRevision 获取文章的某个历史版本
+func (s *ContentAppService) GetRevision(ctx context.Context, articleID, revisionID int64) (*domain.ArticleRevision, error) {
+	rev, err := s.repo.GetRevision(ctx, revisionID)
+	if errors.Is(err, orm.ErrNoRows) || (err == nil && rev.ArticleID != articleID) {
+		return nil, domain.ErrRevisionNotFound
+	}
+	return rev, err
+}
+
+// DiffRevisions 比较同一文章的两个版本，toID 为 0 时与文章当前内容比较
+func (s *ContentAppService) DiffRevisions(ctx context.Context, articleID, fromID, toID int64, mode string) (*domain.RevisionDiff, error) {
+	switch mode {
+	case "":
+		mode = domain.DiffModeUnified
+	case domain.DiffModeUnified, domain.DiffModeWord:
+	default:
+		return nil, fmt.Errorf("不支持的差异模式: %s", mode)
+	}
+	from, err := s.GetRevision(ctx, articleID, fromID)
+	if err != nil {
+		return nil, err
+	}
+	var to *domain.ArticleRevision
+	toName := "current"
+	if toID == 0 {
+		a, err := s.repo.GetArticleByID(ctx, articleID)
+		if err != nil {
+			return nil, err
+		}
+		to = domain.NewArticleRevision(a, a.UpdatedAt)
+	} else {
+		if to, err = s.GetRevision(ctx, articleID, toID); err != nil {
+			return nil, err
+		}
+		toName = fmt.Sprintf("revision/%d", toID)
+	}
+	d := &domain.RevisionDiff{
+		FromID:  fromID,
+		ToID:    toID,
+		Mode:    mode,
+		Title:   textdiff.Words(from.Title, to.Title),
+		Summary: textdiff.Words(nullString(from.Summary), nullString(to.Summary)),
+	}
+	if mode == domain.DiffModeWord {
+		d.Content = textdiff.Words(from.Content, to.Content)
+	} else {
+		d.Unified = textdiff.Unified(fmt.Sprintf("revision/%d", fromID), toName, from.Content, to.Content, unifiedContext)
+	}
+	return d, nil
+}
+
+// RestoreRevision 以历史版本覆盖文章内容；与普通更新一样，覆盖前的内容会保存为新版本
+func (s *ContentAppService) RestoreRevision(ctx context.Context, articleID, revisionID int64) error {
+	rev, err := s.GetRevision(ctx, articleID, revisionID)
+	if err != nil {
+		return err
+	}
+	a, err := s.repo.GetArticleByID(ctx, articleID)
+	if err != nil {
+		return err
+	}
+	rev.ApplyTo(a)
+	return s.Update(ctx, a, nil)
+}
+
+// saveRevision 保存文章快照并裁剪超出保留数的旧版本（裁剪失败不影响本次写入）
+func (s *ContentAppService) saveRevision(ctx context.Context, repo domain.ContentRepository, a *domain.Article, now time.Time) error {
+	if err := repo.CreateRevision(ctx, domain.NewArticleRevision(a, now)); err != nil {
+		s.logger.Error("application: 保存历史版本失败: article_id=%d err=%v", a.ID, err)
+		return err
+	}
+	if _, err := repo.PruneRevisions(ctx, a.ID, s.revisionRetain); err != nil {
+		s.logger.Error("application: 裁剪历史版本失败: article_id=%d err=%v", a.ID, err)
+	}
+	return nil
+}
+
+func nullString(v *sql.NullString) string {
+	if v == nil || !v.Valid {
+		return ""
+	}
+	return v.String
+}
@@ -0,0 +1,118 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-system/services/content/domain"
+)
+
+// schedulerLockKey 定时发布调度锁，保证多副本中仅一个执行
+const schedulerLockKey = "content:lock:scheduler"
+
+// Locker 分布式锁
+type Locker interface {
+	// TryLock 尝试加锁，未抢到时 ok 为 false；持有者须调用 release 释放
+	TryLock(ctx context.Context, key string, ttl time.Duration) (release func(), ok bool, err error)
+}
+
+// RunScheduler 按 interval 轮询并发布到期的定时文章，ctx 取消时退出；locker 为空时不加锁（单副本部署）
+func (s *ContentAppService) RunScheduler(ctx context.Context, locker Locker, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if locker != nil {
+			release, ok, err := locker.TryLock(ctx, schedulerLockKey, interval)
+			if err != nil {
+				s.logger.Error("application: 获取调度锁失败: %v", err)
+				continue
+			}
+			if !ok {
+				continue
+			}
+			s.runDue(ctx, interval)
+			release()
+			continue
+		}
+		s.runDue(ctx, interval)
+	}
+}
+
+// runDue 单轮发布限时 interval（与锁的有效期一致），避免锁过期后其他副本并发执行同一批文章
+func (s *ContentAppService) runDue(ctx context.Context, interval time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, interval)
+	defer cancel()
+	n, err := s.PublishDue(ctx)
+	if err != nil {
+		s.logger.Error("application: 定时发布中断: published=%d err=%v", n, err)
+		return
+	}
+	if n > 0 {
+		s.logger.Info("application: 定时发布完成: count=%d", n)
+	}
+}
+
+// PublishDue 发布所有已到期的定时文章，返回发布数；单篇失败记录日志后继续，下一轮重试
+func (s *ContentAppService) PublishDue(ctx context.Context) (int, error) {
+	due, err := s.repo.ListScheduledArticles(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+	published := make([]int64, 0, len(due))
+	// 已发布的文章无论本轮是否超时都要失效缓存并同步索引
+	defer func() { s.invalidateArticles(context.WithoutCancel(ctx), published...) }()
+	for _, a := range due {
+		if err := ctx.Err(); err != nil {
+			return len(published), err
+		}
+		ok, err := s.repo.TransitArticleStatus(ctx, a.ID, domain.ArticleStatusScheduled, domain.ArticleStatusPublished)
+		if err != nil {
+			s.logger.Error("application: 定时发布文章失败: id=%d err=%v", a.ID, err)
+			continue
+		}
+		if ok {
+			published = append(published, a.ID)
+		}
+	}
+	return len(published), nil
+}
+
+// ListScheduled 全部定时发布的文章（按发布时间升序）
+func (s *ContentAppService) ListScheduled(ctx context.Context) ([]*domain.Article, error) {
+	return s.repo.ListScheduledArticles(ctx, time.Time{})
+}
+
+// CancelSchedule 取消定时发布，文章退回草稿
+func (s *ContentAppService) CancelSchedule(ctx context.Context, id int64) error {
+	ok, err := s.repo.TransitArticleStatus(ctx, id, domain.ArticleStatusScheduled, domain.ArticleStatusDraft)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errors.New("文章不存在或未处于定时发布状态")
+	}
+	s.invalidateArticles(ctx, id)
+	return nil
+}
+
+// invalidateArticles 文章写入或可见性变化后失效详情缓存、标签计数与订阅源，并同步索引；
+// 批量时各命名空间只递增一次，索引一次批量读取
+func (s *ContentAppService) invalidateArticles(ctx context.Context, ids ...int64) {
+	if len(ids) == 0 {
+		return
+	}
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, s.articleKey(id))
+	}
+	s.cc.Del(ctx, keys...)
+	_ = s.cc.Bump(ctx, nsTag)
+	_ = s.cc.Bump(ctx, nsFeed)
+	s.reindex(ctx, ids...)
+}
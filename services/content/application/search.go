@@ -0,0 +1,193 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"blog-system/common/pkg/cacheaside"
+	"blog-system/services/content/domain"
+)
+
+// Search 全文检索（仅已公开文章）：索引给出命中 ID、相关度与高亮，再批量组装摘要并补全分面名称；
+// q.After 非 nil 时按游标取下一页
+func (s *ContentAppService) Search(ctx context.Context, q domain.SearchQuery) (*domain.SearchPage, error) {
+	q.PublishedBefore = time.Now()
+	if q.CategoryID != nil && *q.CategoryID > 0 {
+		ids, err := s.subtreeCategoryIDs(ctx, *q.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		q.CategoryIDs = ids
+	}
+	res, err := s.index.Search(ctx, q)
+	if err != nil {
+		s.logger.Error("application: 全文检索失败: %v", err)
+		return nil, err
+	}
+	ids := make([]int64, 0, len(res.Hits))
+	for _, h := range res.Hits {
+		ids = append(ids, h.ID)
+	}
+	summaries, err := s.repo.ListArticleSummariesByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[int64]*domain.ArticleSummary, len(summaries))
+	for _, sm := range summaries {
+		byID[sm.ID] = sm
+	}
+	items := make([]*domain.SearchItem, 0, len(res.Hits))
+	for _, h := range res.Hits {
+		if sm, ok := byID[h.ID]; ok {
+			items = append(items, &domain.SearchItem{ArticleSummary: sm, Score: h.Score, Highlights: h.Highlights})
+		}
+	}
+	facets := res.Facets
+	if err := s.nameFacets(ctx, &facets); err != nil {
+		return nil, err
+	}
+	return &domain.SearchPage{Items: items, Total: res.Total, Facets: &facets, NextCursor: res.Next.Encode()}, nil
+}
+
+// RebuildSearchIndex 从仓储全量重建索引，返回已索引文档数
+func (s *ContentAppService) RebuildSearchIndex(ctx context.Context) (int, error) {
+	docs, err := s.repo.ListSearchDocs(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.index.Rebuild(ctx, docs); err != nil {
+		s.logger.Error("application: 重建索引失败: %v", err)
+		return 0, err
+	}
+	s.logger.Info("application: 重建索引完成: docs=%d", s.index.Count())
+	return s.index.Count(), nil
+}
+
+// searchSyncOverlap 增量同步的回看窗口，覆盖副本与数据库的时钟偏差以及晚于 updated_at 提交的事务
+const searchSyncOverlap = time.Minute
+
+// RunSearchSync 按 interval 增量同步内存索引，ctx 取消时退出。索引按进程驻留，本副本的写入即时生效，
+// 其他副本（含定时发布、命令行导入与恢复）的写入在一个周期内同步过来；since 为启动时全量构建的开始时刻
+func (s *ContentAppService) RunSearchSync(ctx context.Context, since time.Time, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		next, err := s.SyncSearchIndex(ctx, since)
+		if err != nil {
+			s.logger.Error("application: 增量同步索引失败: %v", err)
+			continue
+		}
+		since = next
+	}
+}
+
+// SyncSearchIndex 重新索引 since 之后变更的文章，移除已删除的文章并补齐漏掉的文章，返回下次同步的起点
+func (s *ContentAppService) SyncSearchIndex(ctx context.Context, since time.Time) (time.Time, error) {
+	changes, err := s.repo.ListSearchDocChanges(ctx, since.Add(-searchSyncOverlap))
+	if err != nil {
+		return since, err
+	}
+	for _, d := range changes.Docs {
+		if err := s.index.Index(ctx, d); err != nil {
+			s.logger.Error("application: 写入索引失败: id=%d err=%v", d.ID, err)
+		}
+	}
+	for _, id := range changes.Removed {
+		_ = s.index.Delete(ctx, id)
+	}
+	// 物理删除的文章不会出现在变更中，按已发布 ID 集合比对
+	ids, err := s.repo.ListPublishedArticleIDs(ctx)
+	if err != nil {
+		return since, err
+	}
+	published := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		published[id] = struct{}{}
+	}
+	indexed := make(map[int64]struct{}, len(ids))
+	removed := 0
+	for _, id := range s.index.IDs() {
+		indexed[id] = struct{}{}
+		if _, ok := published[id]; !ok {
+			_ = s.index.Delete(ctx, id)
+			removed++
+		}
+	}
+	var missed []int64
+	for _, id := range ids {
+		if _, ok := indexed[id]; !ok {
+			missed = append(missed, id)
+		}
+	}
+	s.reindex(ctx, missed...)
+	if n := len(changes.Docs) + len(changes.Removed) + removed + len(missed); n > 0 {
+		s.logger.Info("application: 增量同步索引: updated=%d hidden=%d deleted=%d missed=%d",
+			len(changes.Docs), len(changes.Removed), removed, len(missed))
+	}
+	if changes.Watermark.After(since) {
+		return changes.Watermark, nil
+	}
+	return since, nil
+}
+
+// reindex 同步文章索引：已发布则写入，否则（含已删除）移除；多篇时文章与标签各一次批量查询。
+// 索引为派生数据，失败仅记录日志，可由增量同步或重建修复
+func (s *ContentAppService) reindex(ctx context.Context, ids ...int64) {
+	if len(ids) == 0 {
+		return
+	}
+	changes, err := s.repo.ListSearchDocsByIDs(ctx, ids)
+	if err != nil {
+		s.logger.Error("application: 同步索引读取文章失败: ids=%v err=%v", ids, err)
+		return
+	}
+	for _, d := range changes.Docs {
+		if err := s.index.Index(ctx, d); err != nil {
+			s.logger.Error("application: 写入索引失败: id=%d err=%v", d.ID, err)
+		}
+	}
+	for _, id := range changes.Removed {
+		if err := s.index.Delete(ctx, id); err != nil {
+			s.logger.Error("application: 删除索引失败: id=%d err=%v", id, err)
+		}
+	}
+}
+
+// nameFacets 填充分面名称，已删除的分类/标签不再返回
+func (s *ContentAppService) nameFacets(ctx context.Context, f *domain.SearchFacets) error {
+	cats, err := s.ListAllCategories(ctx)
+	if err != nil {
+		return err
+	}
+	tags, err := cacheaside.Get(ctx, s.cc, s.cc.VersionedKey(ctx, nsTag, "all"), s.repo.ListAllTags)
+	if err != nil {
+		return err
+	}
+	catNames := make(map[int64]string, len(cats))
+	for _, c := range cats {
+		catNames[c.ID] = c.Name
+	}
+	tagNames := make(map[int64]string, len(tags))
+	for _, t := range tags {
+		tagNames[t.ID] = t.Name
+	}
+	f.Categories = named(f.Categories, catNames)
+	f.Tags = named(f.Tags, tagNames)
+	return nil
+}
+
+func named(list []*domain.FacetCount, names map[int64]string) []*domain.FacetCount {
+	out := make([]*domain.FacetCount, 0, len(list))
+	for _, fc := range list {
+		if name, ok := names[fc.ID]; ok {
+			fc.Name = name
+			out = append(out, fc)
+		}
+	}
+	return out
+}
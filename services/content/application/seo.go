@@ -0,0 +1,210 @@
+package application
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+	"time"
+
+	"blog-system/common/pkg/cacheaside"
+	"blog-system/services/content/domain"
+)
+
+// SetSitemapBase 设置 sitemap 文件的对外访问前缀（如 https://blog.example.com/api/content），
+// 为空时取站点地址下的 /api/content
+func (s *ContentAppService) SetSitemapBase(base string) {
+	s.sitemapBase = strings.TrimRight(base, "/")
+}
+
+// sitemapLoc 分页 sitemap 文件的绝对地址
+func (s *ContentAppService) sitemapLoc(name string) string {
+	base := s.sitemapBase
+	if base == "" {
+		base = strings.TrimRight(s.feedSite.Link, "/") + "/api/content"
+	}
+	return base + "/sitemap/" + name
+}
+
+// sitemapEntries 某类对象的全部 sitemap 条目（旁路缓存）：文章变更随 feed 命名空间失效，
+// 分类、标签变更随其命名空间版本号失效
+func (s *ContentAppService) sitemapEntries(ctx context.Context, kind string) ([]*domain.SitemapEntry, error) {
+	key := s.cc.Key("sitemap", kind, s.cc.Version(ctx, nsFeed),
+		s.cc.Version(ctx, nsCategory), s.cc.Version(ctx, nsTag))
+	return cacheaside.Get(ctx, s.cc, key, func(ctx context.Context) ([]*domain.SitemapEntry, error) {
+		list, err := s.repo.ListSitemapEntries(ctx, kind)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range list {
+			if kind == domain.SlugKindArticle {
+				e.Loc = s.feedSite.ArticleLink(e.Slug)
+			} else {
+				e.Loc = s.feedSite.PageLink(kind, e.Slug)
+			}
+		}
+		return list, nil
+	})
+}
+
+// SitemapIndex sitemap 索引：各类对象按 SitemapPageSize 分页，每个文件的 Lastmod 取其条目中最晚者
+func (s *ContentAppService) SitemapIndex(ctx context.Context) ([]*domain.SitemapFile, error) {
+	files := make([]*domain.SitemapFile, 0)
+	for _, kind := range domain.SitemapKinds {
+		entries, err := s.sitemapEntries(ctx, kind)
+		if err != nil {
+			s.logger.Error("application: 获取 sitemap 条目失败: kind=%s err=%v", kind, err)
+			return nil, err
+		}
+		for start := 0; start < len(entries); start += domain.SitemapPageSize {
+			f := &domain.SitemapFile{Kind: kind, Page: start/domain.SitemapPageSize + 1}
+			for _, e := range entries[start:min(start+domain.SitemapPageSize, len(entries))] {
+				if e.Lastmod.After(f.Lastmod) {
+					f.Lastmod = e.Lastmod
+				}
+			}
+			f.Loc = s.sitemapLoc(f.Name())
+			files = append(files, f)
+		}
+	}
+	return files, nil
+}
+
+// Sitemap 某类对象第 page 页（从 1 开始）的 sitemap 条目，类型未知或页码越界时返回 ErrSitemapNotFound
+func (s *ContentAppService) Sitemap(ctx context.Context, kind string, page int) ([]*domain.SitemapEntry, error) {
+	if !slices.Contains(domain.SitemapKinds, kind) || page < 1 {
+		return nil, domain.ErrSitemapNotFound
+	}
+	entries, err := s.sitemapEntries(ctx, kind)
+	if err != nil {
+		s.logger.Error("application: 获取 sitemap 条目失败: kind=%s err=%v", kind, err)
+		return nil, err
+	}
+	// 先按页数判断越界，避免超大页码相乘溢出
+	if page > (len(entries)+domain.SitemapPageSize-1)/domain.SitemapPageSize {
+		return nil, domain.ErrSitemapNotFound
+	}
+	start := (page - 1) * domain.SitemapPageSize
+	return entries[start:min(start+domain.SitemapPageSize, len(entries))], nil
+}
+
+// ArticleSEO 文章页 SEO 信息（OpenGraph、Twitter Card 与 JSON-LD），遵循读取方可见性
+func (s *ContentAppService) ArticleSEO(ctx context.Context, id int64, v domain.Viewer) (*domain.ArticleSEO, error) {
+	a, err := s.GetVisible(ctx, id, v)
+	if err != nil {
+		return nil, err
+	}
+	summaries, err := s.repo.ListArticleSummariesByIDs(ctx, []int64{a.ID})
+	if err != nil {
+		return nil, err
+	}
+	var sm *domain.ArticleSummary
+	if len(summaries) > 0 {
+		sm = summaries[0]
+	} else {
+		sm = &domain.ArticleSummary{ID: a.ID, Title: a.Title}
+	}
+	authors, err := s.repo.ListAuthorNames(ctx, []int64{a.AuthorID})
+	if err != nil {
+		return nil, err
+	}
+	return s.buildArticleSEO(a, sm, authors[a.AuthorID]), nil
+}
+
+// buildArticleSEO 组装 SEO 信息：标题、描述、关键词依次回退到标题、摘要、标签名
+func (s *ContentAppService) buildArticleSEO(a *domain.Article, sm *domain.ArticleSummary, author string) *domain.ArticleSEO {
+	site := s.feedSite
+	seo := &domain.ArticleSEO{
+		Title:       nullOr(a.MetaTitle, a.Title),
+		Description: nullOr(a.MetaDesc, sm.Summary),
+		Keywords:    domain.SplitKeywords(nullString(a.MetaKeywords)),
+		Canonical:   site.ArticleLink(a.Slug),
+		Image:       nullString(a.Cover),
+	}
+	var tags []string
+	for _, t := range sm.Tags {
+		tags = append(tags, t.Name)
+	}
+	if len(seo.Keywords) == 0 {
+		seo.Keywords = domain.SplitKeywords(strings.Join(tags, ","))
+	}
+	published, modified := a.UpdatedAt, a.UpdatedAt
+	if a.PublishedAt != nil {
+		published = *a.PublishedAt
+	}
+	if modified.Before(published) {
+		modified = published
+	}
+
+	og := []domain.MetaTag{
+		{Property: "og:type", Content: "article"},
+		{Property: "og:title", Content: seo.Title},
+		{Property: "og:description", Content: seo.Description},
+		{Property: "og:url", Content: seo.Canonical},
+		{Property: "og:site_name", Content: site.Title},
+	}
+	if seo.Image != "" {
+		og = append(og, domain.MetaTag{Property: "og:image", Content: seo.Image})
+	}
+	og = append(og,
+		domain.MetaTag{Property: "article:published_time", Content: published.Format(time.RFC3339)},
+		domain.MetaTag{Property: "article:modified_time", Content: modified.Format(time.RFC3339)},
+	)
+	if author != "" {
+		og = append(og, domain.MetaTag{Property: "article:author", Content: author})
+	}
+	if sm.Category != nil {
+		og = append(og, domain.MetaTag{Property: "article:section", Content: sm.Category.Name})
+	}
+	for _, t := range tags {
+		og = append(og, domain.MetaTag{Property: "article:tag", Content: t})
+	}
+	seo.OpenGraph = og
+
+	card := "summary"
+	if seo.Image != "" {
+		card = "summary_large_image"
+	}
+	seo.Twitter = []domain.MetaTag{
+		{Name: "twitter:card", Content: card},
+		{Name: "twitter:title", Content: seo.Title},
+		{Name: "twitter:description", Content: seo.Description},
+	}
+	if seo.Image != "" {
+		seo.Twitter = append(seo.Twitter, domain.MetaTag{Name: "twitter:image", Content: seo.Image})
+	}
+
+	ld := map[string]any{
+		"@context":         "https://schema.org",
+		"@type":            "BlogPosting",
+		"headline":         seo.Title,
+		"description":      seo.Description,
+		"url":              seo.Canonical,
+		"mainEntityOfPage": map[string]any{"@type": "WebPage", "@id": seo.Canonical},
+		"datePublished":    published.Format(time.RFC3339),
+		"dateModified":     modified.Format(time.RFC3339),
+		"publisher":        map[string]any{"@type": "Organization", "name": site.Title, "url": site.Link},
+	}
+	if author != "" {
+		ld["author"] = map[string]any{"@type": "Person", "name": author}
+	}
+	if seo.Image != "" {
+		ld["image"] = seo.Image
+	}
+	if sm.Category != nil {
+		ld["articleSection"] = sm.Category.Name
+	}
+	if len(seo.Keywords) > 0 {
+		ld["keywords"] = strings.Join(seo.Keywords, ", ")
+	}
+	seo.JSONLD = ld
+	return seo
+}
+
+// nullOr 可空字符串非空时取其值，否则取回退值
+func nullOr(v *sql.NullString, fallback string) string {
+	if s := nullString(v); s != "" {
+		return s
+	}
+	return fallback
+}
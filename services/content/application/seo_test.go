@@ -0,0 +1,56 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"blog-system/common/pkg/logger"
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/cache"
+)
+
+// fakeSitemapRepo 只实现 sitemap 条目查询
+type fakeSitemapRepo struct {
+	domain.ContentRepository
+	n int
+}
+
+func (r *fakeSitemapRepo) ListSitemapEntries(context.Context, string) ([]*domain.SitemapEntry, error) {
+	list := make([]*domain.SitemapEntry, r.n)
+	for i := range list {
+		list[i] = &domain.SitemapEntry{}
+	}
+	return list, nil
+}
+
+func TestSitemapPageBounds(t *testing.T) {
+	s := NewContentService(&fakeSitemapRepo{n: domain.SitemapPageSize + 1}, nil, nil, logger.Log(), cache.NewBuildInMapCache(time.Minute))
+	ctx := context.Background()
+	tests := []struct {
+		page int
+		want int // -1 表示不存在
+	}{
+		{0, -1},
+		{1, domain.SitemapPageSize},
+		{2, 1},
+		{3, -1},
+		{1844674407370958, -1},
+		{math.MaxInt, -1},
+	}
+	for _, tt := range tests {
+		list, err := s.Sitemap(ctx, domain.SlugKindArticle, tt.page)
+		if tt.want < 0 {
+			if !errors.Is(err, domain.ErrSitemapNotFound) {
+				t.Errorf("page %d: err = %v, want ErrSitemapNotFound", tt.page, err)
+			}
+			continue
+		}
+		if err != nil || len(list) != tt.want {
+			t.Errorf("page %d: len = %d err = %v, want %d", tt.page, len(list), err, tt.want)
+		}
+	}
+}
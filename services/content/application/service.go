@@ -2,60 +2,179 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"math"
+	"strconv"
 	"time"
 
+	"blog-system/common/pkg/cacheaside"
 	"blog-system/common/pkg/logger"
+	"blog-system/common/pkg/pagination"
 	"blog-system/services/content/domain"
 
 	"github.com/CoucouMonEcho/go-framework/cache"
+	"github.com/CoucouMonEcho/go-framework/orm"
+)
+
+// 缓存命名空间（版本号递增即整体失效）
+const (
+	nsCategory = "category"
+	nsTag      = "tag"
+	nsFeed     = "feed"
 )
 
 // ContentAppService 内容应用服务
 type ContentAppService struct {
-	repo   domain.ContentRepository
-	logger logger.Logger
-	cache  cache.Cache
+	repo     domain.ContentRepository
+	index    domain.SearchIndex
+	renderer domain.Renderer
+	logger   logger.Logger
+	cache    cache.Cache
+	cc       *cacheaside.Cache
+	rc       *cacheaside.Cache // 渲染结果缓存，键为内容哈希
+
+	revisionRetain int             // 每篇文章保留的历史版本数
+	feedSite       domain.FeedSite // 订阅源站点信息
+	sitemapBase    string          // sitemap 文件的对外访问前缀
 }
 
-func NewContentService(repo domain.ContentRepository, lgr logger.Logger, c cache.Cache) *ContentAppService {
-	return &ContentAppService{repo: repo, logger: lgr, cache: c}
+func NewContentService(repo domain.ContentRepository, index domain.SearchIndex, renderer domain.Renderer, lgr logger.Logger, c cache.Cache) *ContentAppService {
+	return &ContentAppService{
+		repo:           repo,
+		index:          index,
+		renderer:       renderer,
+		logger:         lgr,
+		cache:          c,
+		revisionRetain: defaultRevisionRetain,
+		feedSite:       domain.FeedSite{Limit: defaultFeedLimit},
+		cc: cacheaside.New(c,
+			cacheaside.WithPrefix("content:"),
+			cacheaside.WithTTL(10*time.Minute),
+			cacheaside.WithNegativeTTL(30*time.Second),
+			cacheaside.WithNotFound(func(err error) bool { return errors.Is(err, orm.ErrNoRows) }),
+		),
+		rc: cacheaside.New(c,
+			cacheaside.WithPrefix("content:render:"),
+			cacheaside.WithTTL(24*time.Hour),
+		),
+	}
 }
 
 // 文章相关
-func (s *ContentAppService) Create(ctx context.Context, a *domain.Article) (*domain.Article, error) {
-	if a.PublishedAt == nil && a.Status == 1 {
-		now := time.Now()
-		a.PublishedAt = &now
-	}
+
+// Create 创建文章，文章与 tagIDs 在同一事务中写入
+func (s *ContentAppService) Create(ctx context.Context, a *domain.Article, tagIDs []int64) (*domain.Article, error) {
 	now := time.Now()
+	if err := a.NormalizeStatus(now); err != nil {
+		return nil, err
+	}
+	tagIDs, err := s.checkTagIDs(ctx, tagIDs)
+	if err != nil {
+		return nil, err
+	}
 	if a.CreatedAt.IsZero() {
 		a.CreatedAt = now
 	}
 	a.UpdatedAt = now
-	if err := s.repo.CreateArticle(ctx, a); err != nil {
+	a.Version = 1
+	if a.Slug, err = s.resolveSlug(ctx, domain.SlugKindArticle, a.Slug, a.Title, 0); err != nil {
+		return nil, err
+	}
+	err = s.repo.Transaction(ctx, func(ctx context.Context, repo domain.ContentRepository) error {
+		if err := repo.CreateArticle(ctx, a); err != nil {
+			return err
+		}
+		if len(tagIDs) == 0 {
+			return nil
+		}
+		return repo.UpdateArticleTags(ctx, a.ID, tagIDs)
+	})
+	if err != nil {
 		s.logger.Error("application: 创建文章失败: %v", err)
 		return nil, err
 	}
+	// 新文章可能命中此前缓存的空值
+	s.invalidateArticles(ctx, a.ID)
 	return a, nil
 }
 
+// GetByID 文章详情（旁路缓存）
 func (s *ContentAppService) GetByID(ctx context.Context, id int64) (*domain.Article, error) {
-	return s.repo.GetArticleByID(ctx, id)
+	a, err := cacheaside.Get(ctx, s.cc, s.articleKey(id), func(ctx context.Context) (*domain.Article, error) {
+		return s.repo.GetArticleByID(ctx, id)
+	})
+	if errors.Is(err, cacheaside.ErrNotFound) {
+		return nil, errors.New("文章不存在")
+	}
+	return a, err
+}
+
+// GetVisible 按读取方可见性获取文章详情，不可见时与不存在同样处理
+func (s *ContentAppService) GetVisible(ctx context.Context, id int64, v domain.Viewer) (*domain.Article, error) {
+	a, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !v.CanSee(a, time.Now()) {
+		return nil, errors.New("文章不存在")
+	}
+	return a, nil
 }
 
-// Update 更新文章
-func (s *ContentAppService) Update(ctx context.Context, a *domain.Article) error {
+// Update 更新文章；a.Version 为调用方读取时的版本，为 0 时不校验（以读取到的最新版本为准）。
+// tagIDs 为 nil 时保持标签不变，否则覆盖（空切片清除）；历史版本、文章与标签在同一事务中写入
+func (s *ContentAppService) Update(ctx context.Context, a *domain.Article, tagIDs []int64) error {
 	if a.ID == 0 {
 		return fmt.Errorf("invalid id")
 	}
-	if a.PublishedAt == nil && a.Status == 1 {
-		now := time.Now()
-		a.PublishedAt = &now
+	now := time.Now()
+	if err := a.NormalizeStatus(now); err != nil {
+		return err
 	}
-	a.UpdatedAt = time.Now()
-	return s.repo.UpdateArticle(ctx, a)
+	setTags := tagIDs != nil
+	tagIDs, err := s.checkTagIDs(ctx, tagIDs)
+	if err != nil {
+		return err
+	}
+	// 覆盖前保存旧内容快照
+	old, err := s.repo.GetArticleByID(ctx, a.ID)
+	if errors.Is(err, orm.ErrNoRows) {
+		return errors.New("文章不存在")
+	}
+	if err != nil {
+		return err
+	}
+	if a.Version == 0 {
+		a.Version = old.Version
+	} else if a.Version != old.Version {
+		return domain.ErrVersionConflict
+	}
+	// 未指定别名时沿用原别名，避免标题修改导致链接变化
+	if a.Slug == "" {
+		a.Slug = old.Slug
+	}
+	if a.Slug, err = s.resolveSlug(ctx, domain.SlugKindArticle, a.Slug, a.Title, a.ID); err != nil {
+		return err
+	}
+	a.UpdatedAt = now
+	err = s.repo.Transaction(ctx, func(ctx context.Context, repo domain.ContentRepository) error {
+		if err := s.saveRevision(ctx, repo, old, now); err != nil {
+			return err
+		}
+		if err := repo.UpdateArticle(ctx, a); err != nil {
+			return err
+		}
+		if !setTags {
+			return nil
+		}
+		return repo.UpdateArticleTags(ctx, a.ID, tagIDs)
+	})
+	if err != nil {
+		return err
+	}
+	s.recordSlugChange(ctx, domain.SlugKindArticle, old.Slug, a.Slug, a.ID)
+	s.invalidateArticles(ctx, a.ID)
+	return nil
 }
 
 // Delete 删除文章（物理删除 + 关联删除在仓储实现）
@@ -63,91 +182,173 @@ func (s *ContentAppService) Delete(ctx context.Context, id int64) error {
 	if id == 0 {
 		return fmt.Errorf("invalid id")
 	}
-	return s.repo.DeleteArticle(ctx, id)
+	if err := s.repo.DeleteArticle(ctx, id); err != nil {
+		return err
+	}
+	s.cc.Del(ctx, s.articleKey(id))
+	_ = s.cc.Bump(ctx, nsTag)
+	_ = s.cc.Bump(ctx, nsFeed)
+	if err := s.index.Delete(ctx, id); err != nil {
+		s.logger.Error("application: 删除索引失败: id=%d err=%v", id, err)
+	}
+	return nil
 }
 
 // ListSummaries 分页查询文章摘要（内部复用）
-func (s *ContentAppService) ListSummaries(ctx context.Context, page, pageSize int) ([]*domain.ArticleSummary, int64, error) {
-	return s.repo.ListArticleSummaries(ctx, page, pageSize)
+func (s *ContentAppService) ListSummaries(ctx context.Context, v domain.Viewer, page, pageSize int) ([]*domain.ArticleSummary, int64, error) {
+	return s.repo.ListArticleSummaries(ctx, v, page, pageSize)
 }
 
-// ListSummariesFiltered 支持分类与标签过滤的文章摘要列表
-func (s *ContentAppService) ListSummariesFiltered(ctx context.Context, categoryID *int64, tagIDs []int64, page, pageSize int) ([]*domain.ArticleSummary, int64, error) {
-	// 优先调用有过滤实现的方法；若仓储未实现则回退到无过滤，并在上层过滤（此处假定已有实现）
-	type repoWithFilter interface {
-		ListArticleSummariesFiltered(ctx context.Context, categoryID *int64, tagIDs []int64, page, pageSize int) ([]*domain.ArticleSummary, int64, error)
-	}
-	if rf, ok := s.repo.(repoWithFilter); ok {
-		return rf.ListArticleSummariesFiltered(ctx, categoryID, tagIDs, page, pageSize)
-	}
-	return s.repo.ListArticleSummaries(ctx, page, pageSize)
+// ListSummariesFiltered 支持分类与标签过滤的文章摘要列表（仅返回读取方可见的文章）
+func (s *ContentAppService) ListSummariesFiltered(ctx context.Context, v domain.Viewer, categoryID *int64, tagIDs []int64, page, pageSize int) ([]*domain.ArticleSummary, int64, error) {
+	return s.repo.ListArticleSummariesFiltered(ctx, v, categoryID, tagIDs, page, pageSize)
 }
 
-// SearchSummaries 关键词搜索文章摘要
-func (s *ContentAppService) SearchSummaries(ctx context.Context, keyword string, page, pageSize int) ([]*domain.ArticleSummary, int64, error) {
-	return s.repo.SearchArticleSummaries(ctx, keyword, page, pageSize)
+// ListSummariesAfter 过滤条件同 ListSummariesFiltered 的游标分页，返回下一页游标，没有更多时为空
+func (s *ContentAppService) ListSummariesAfter(ctx context.Context, v domain.Viewer, categoryID *int64, tagIDs []int64, cursor string, pageSize int) ([]*domain.ArticleSummary, string, error) {
+	after, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	list, next, err := s.repo.ListArticleSummariesAfter(ctx, v, categoryID, tagIDs, after, pagination.Size(pageSize, pagination.DefaultPageSize))
+	if err != nil {
+		return nil, "", err
+	}
+	return list, next.Encode(), nil
 }
 
-// 全量文章列表与计数（用于对外接口不分页）
+// 文章计数与管理端列表
 func (s *ContentAppService) CountArticles(ctx context.Context) (int64, error) {
 	return s.repo.CountArticles(ctx)
 }
 
-func (s *ContentAppService) ListAllArticles(ctx context.Context) ([]*domain.Article, int64, error) {
-	// 复用分页接口：一次拉取全部
-	const m = int(^uint(0) >> 1)
-	list, total, err := s.repo.ListArticles(ctx, 1, m)
+// ListArticlesPage 全部文章的游标分页（管理端），authorID 非 0 时限定作者；cursor 为空时从第一页开始，
+// 返回下一页游标，没有更多时为空
+func (s *ContentAppService) ListArticlesPage(ctx context.Context, authorID int64, cursor string, pageSize int) ([]*domain.Article, string, error) {
+	after, err := pagination.Decode(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	list, next, err := s.repo.ListArticlesAfter(ctx, authorID, after, pagination.Size(pageSize, pagination.DefaultPageSize))
 	if err != nil {
 		logger.Log().Error("application: 列出文章失败: %v", err)
-		return nil, 0, err
+		return nil, "", err
 	}
-	return list, total, nil
+	return list, next.Encode(), nil
 }
 
-// 分类（单级）
+// 分类（树形）
 func (s *ContentAppService) ListCategories(ctx context.Context, page, pageSize int) ([]*domain.Category, int64, error) {
 	return s.repo.ListCategories(ctx, page, pageSize)
 }
 
 func (s *ContentAppService) ListAllCategories(ctx context.Context) ([]*domain.Category, error) {
-	return s.repo.ListAllCategories(ctx)
+	return cacheaside.Get(ctx, s.cc, s.cc.VersionedKey(ctx, nsCategory, "all"), s.repo.ListAllCategories)
 }
 
 func (s *ContentAppService) CountCategories(ctx context.Context) (int64, error) {
 	return s.repo.CountCategories(ctx)
 }
 
+// UpdateCategory 更新分类；未指定别名或描述时沿用原值，别名变更后旧别名重定向到新别名
 func (s *ContentAppService) UpdateCategory(ctx context.Context, c *domain.Category) error {
+	old, err := s.repo.GetCategoryByID(ctx, c.ID)
+	if errors.Is(err, orm.ErrNoRows) {
+		return domain.ErrCategoryNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if c.Slug == "" {
+		c.Slug = old.Slug
+	}
+	if c.Description == nil {
+		c.Description = old.Description
+	}
+	c.ParentID, c.Path = old.ParentID, old.Path
+	if c.Slug, err = s.resolveSlug(ctx, domain.SlugKindCategory, c.Slug, c.Name, c.ID); err != nil {
+		return err
+	}
 	c.UpdatedAt = time.Now()
-	return s.repo.UpdateCategory(ctx, c)
-}
-
-func (s *ContentAppService) DeleteCategory(ctx context.Context, id int64) error {
-	return s.repo.DeleteCategory(ctx, id)
+	if err := s.bumpAfter(ctx, nsCategory, s.repo.UpdateCategory(ctx, c)); err != nil {
+		return err
+	}
+	s.recordSlugChange(ctx, domain.SlugKindCategory, old.Slug, c.Slug, c.ID)
+	return nil
 }
 
+// CreateCategory 创建分类，ParentID 为 0 时为顶级分类；未指定别名时由名称生成
 func (s *ContentAppService) CreateCategory(ctx context.Context, c *domain.Category) error {
+	c.Path = ""
+	if c.ParentID > 0 {
+		parent, err := s.repo.GetCategoryByID(ctx, c.ParentID)
+		if errors.Is(err, orm.ErrNoRows) {
+			return domain.ErrCategoryParent
+		}
+		if err != nil {
+			return err
+		}
+		if parent.Depth() >= domain.CategoryMaxDepth {
+			return domain.ErrCategoryTooDeep
+		}
+		c.Path = parent.Path
+	}
+	var err error
+	if c.Slug, err = s.resolveSlug(ctx, domain.SlugKindCategory, c.Slug, c.Name, 0); err != nil {
+		return err
+	}
 	now := time.Now()
 	c.CreatedAt = now
 	c.UpdatedAt = now
-	return s.repo.CreateCategory(ctx, c)
+	return s.bumpAfter(ctx, nsCategory, s.repo.CreateCategory(ctx, c))
 }
 
-// 标签
+// CreateTag 创建标签，未指定别名时由名称生成
 func (s *ContentAppService) CreateTag(ctx context.Context, t *domain.Tag) error {
+	if err := s.checkTagName(ctx, t.Name, 0); err != nil {
+		return err
+	}
+	var err error
+	if t.Slug, err = s.resolveSlug(ctx, domain.SlugKindTag, t.Slug, t.Name, 0); err != nil {
+		return err
+	}
 	now := time.Now()
 	t.CreatedAt = now
 	t.UpdatedAt = now
-	return s.repo.CreateTag(ctx, t)
+	return s.bumpAfter(ctx, nsTag, s.repo.CreateTag(ctx, t))
 }
 
+// UpdateTag 更新标签；未指定名称、别名或颜色时沿用原值，别名变更后旧别名重定向到新别名。
+// 不能重命名为已有标签名，应改用 MergeTag
 func (s *ContentAppService) UpdateTag(ctx context.Context, t *domain.Tag) error {
+	old, err := s.repo.GetTagByID(ctx, t.ID)
+	if errors.Is(err, orm.ErrNoRows) {
+		return domain.ErrTagNotFound
+	}
+	if err != nil {
+		return err
+	}
+	if t.Name == "" {
+		t.Name = old.Name
+	}
+	if err := s.checkTagName(ctx, t.Name, t.ID); err != nil {
+		return err
+	}
+	if t.Slug == "" {
+		t.Slug = old.Slug
+	}
+	if t.Color == nil {
+		t.Color = old.Color
+	}
+	if t.Slug, err = s.resolveSlug(ctx, domain.SlugKindTag, t.Slug, t.Name, t.ID); err != nil {
+		return err
+	}
 	t.UpdatedAt = time.Now()
-	return s.repo.UpdateTag(ctx, t)
-}
-
-func (s *ContentAppService) DeleteTag(ctx context.Context, id int64) error {
-	return s.repo.DeleteTag(ctx, id)
+	if err := s.bumpAfter(ctx, nsTag, s.repo.UpdateTag(ctx, t)); err != nil {
+		return err
+	}
+	s.recordSlugChange(ctx, domain.SlugKindTag, old.Slug, t.Slug, t.ID)
+	return nil
 }
 
 func (s *ContentAppService) ListTags(ctx context.Context, page, pageSize int) ([]*domain.Tag, int64, error) {
@@ -159,37 +360,44 @@ func (s *ContentAppService) CountTags(ctx context.Context) (int64, error) {
 }
 
 func (s *ContentAppService) ListAllTags(ctx context.Context) ([]*domain.Tag, int64, error) {
-	list, total, err := s.repo.ListTags(ctx, 1, math.MaxInt)
+	list, err := s.repo.ListAllTags(ctx)
 	if err != nil {
 		logger.Log().Error("application: 列出标签失败: %v", err)
 		return nil, 0, err
 	}
-	return list, total, nil
+	return list, int64(len(list)), nil
 }
 
 func (s *ContentAppService) ListAllTagsWithCount(ctx context.Context) ([]struct {
 	Tag   *domain.Tag
 	Count int64
+}, error) {
+	return cacheaside.Get(ctx, s.cc, s.cc.VersionedKey(ctx, nsTag, "counts"), s.loadTagsWithCount)
+}
+
+func (s *ContentAppService) loadTagsWithCount(ctx context.Context) ([]struct {
+	Tag   *domain.Tag
+	Count int64
 }, error) {
 	all, err := s.repo.ListAllTags(ctx)
 	if err != nil {
 		logger.Log().Error("application: 查询标签列表失败: %v", err)
 		return nil, err
 	}
+	counts, err := s.repo.CountArticlesGroupByTag(ctx)
+	if err != nil {
+		logger.Log().Error("application: 统计标签文章数失败: %v", err)
+		return nil, err
+	}
 	res := make([]struct {
 		Tag   *domain.Tag
 		Count int64
 	}, 0, len(all))
 	for _, t := range all {
-		c, err := s.repo.CountArticlesByTag(ctx, t.ID)
-		if err != nil {
-			logger.Log().Error("application: 统计标签文章数失败: tagID=%d err=%v", t.ID, err)
-			return nil, err
-		}
 		res = append(res, struct {
 			Tag   *domain.Tag
 			Count int64
-		}{Tag: t, Count: c})
+		}{Tag: t, Count: counts[t.ID]})
 	}
 	return res, nil
 }
@@ -199,5 +407,28 @@ func (s *ContentAppService) GetArticleTags(ctx context.Context, articleID int64)
 }
 
 func (s *ContentAppService) SetArticleTags(ctx context.Context, articleID int64, tagIDs []int64) error {
-	return s.repo.UpdateArticleTags(ctx, articleID, tagIDs)
+	tagIDs, err := s.checkTagIDs(ctx, tagIDs)
+	if err != nil {
+		return err
+	}
+	if err := s.bumpAfter(ctx, nsTag, s.repo.UpdateArticleTags(ctx, articleID, tagIDs)); err != nil {
+		return err
+	}
+	_ = s.cc.Bump(ctx, nsFeed)
+	s.reindex(ctx, articleID)
+	return nil
+}
+
+// articleKey 文章详情缓存键
+func (s *ContentAppService) articleKey(id int64) string {
+	return s.cc.Key("article", strconv.FormatInt(id, 10))
+}
+
+// bumpAfter 写操作成功后递增命名空间版本号，使相关列表缓存失效
+func (s *ContentAppService) bumpAfter(ctx context.Context, ns string, err error) error {
+	if err != nil {
+		return err
+	}
+	_ = s.cc.Bump(ctx, ns)
+	return nil
 }
@@ -0,0 +1,140 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+
+	"blog-system/common/pkg/cacheaside"
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/orm"
+	"github.com/gosimple/slug"
+)
+
+// maxSlugAttempts 生成别名时追加序号的尝试上限
+const maxSlugAttempts = 100
+
+// resolveSlug 确定对象别名：指定时校验格式与唯一性；未指定时由 source（标题/名称）生成，
+// 中文转写为拼音，与已有别名冲突时依次追加 -2、-3 …
+func (s *ContentAppService) resolveSlug(ctx context.Context, kind, value, source string, id int64) (string, error) {
+	if value != "" {
+		if !domain.ValidSlug(kind, value) {
+			return "", domain.ErrSlugInvalid
+		}
+		taken, err := s.repo.SlugExists(ctx, kind, value, id)
+		if err != nil {
+			return "", err
+		}
+		if taken {
+			return "", domain.ErrSlugTaken
+		}
+		return value, nil
+	}
+	maxLen := domain.SlugMaxLen[kind]
+	base := slugify(source, maxLen)
+	if base == "" {
+		base = kind
+	}
+	for i := 1; i <= maxSlugAttempts; i++ {
+		candidate := base
+		if i > 1 {
+			suffix := "-" + strconv.Itoa(i)
+			candidate = strings.TrimRight(base[:min(len(base), maxLen-len(suffix))], "-") + suffix
+		}
+		taken, err := s.repo.SlugExists(ctx, kind, candidate, id)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+	return "", domain.ErrSlugTaken
+}
+
+// slugify 转写为别名，超长时在连字符处截断
+func slugify(source string, maxLen int) string {
+	out := slug.Make(source)
+	if len(out) <= maxLen {
+		return out
+	}
+	out = out[:maxLen]
+	if i := strings.LastIndexByte(out, '-'); i > 0 {
+		out = out[:i]
+	}
+	return strings.TrimRight(out, "-")
+}
+
+// recordSlugChange 别名变更后记录旧别名，旧链接可永久重定向；失败仅记录日志
+func (s *ContentAppService) recordSlugChange(ctx context.Context, kind, oldSlug, newSlug string, id int64) {
+	if oldSlug == "" || oldSlug == newSlug {
+		return
+	}
+	if err := s.repo.SaveSlugRedirect(ctx, kind, oldSlug, id); err != nil {
+		s.logger.Error("application: 记录旧别名失败: kind=%s id=%d slug=%s err=%v", kind, id, oldSlug, err)
+	}
+}
+
+// GetVisibleDetailBySlug 按别名获取文章详情；按旧别名访问时返回 *domain.SlugMovedError
+func (s *ContentAppService) GetVisibleDetailBySlug(ctx context.Context, value string, v domain.Viewer) (*domain.ArticleDetail, error) {
+	a, err := s.repo.GetArticleBySlug(ctx, value)
+	if err == nil {
+		return s.GetVisibleDetail(ctx, a.ID, v)
+	}
+	if !errors.Is(err, orm.ErrNoRows) {
+		return nil, err
+	}
+	id, err := s.repo.FindSlugRedirect(ctx, domain.SlugKindArticle, value)
+	if err != nil {
+		return nil, errors.New("文章不存在")
+	}
+	// 旧别名同样遵循可见性，避免泄露不可见文章的新别名
+	if a, err = s.GetVisible(ctx, id, v); err != nil {
+		return nil, err
+	}
+	return nil, &domain.SlugMovedError{Slug: a.Slug}
+}
+
+// GetCategoryBySlug 按别名获取分类；按旧别名访问时返回 *domain.SlugMovedError
+func (s *ContentAppService) GetCategoryBySlug(ctx context.Context, value string) (*domain.Category, error) {
+	list, err := s.ListAllCategories(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range list {
+		if c.Slug == value {
+			return c, nil
+		}
+	}
+	if id, err := s.repo.FindSlugRedirect(ctx, domain.SlugKindCategory, value); err == nil {
+		for _, c := range list {
+			if c.ID == id {
+				return nil, &domain.SlugMovedError{Slug: c.Slug}
+			}
+		}
+	}
+	return nil, domain.ErrCategoryNotFound
+}
+
+// GetTagBySlug 按别名获取标签；按旧别名访问时返回 *domain.SlugMovedError
+func (s *ContentAppService) GetTagBySlug(ctx context.Context, value string) (*domain.Tag, error) {
+	list, err := cacheaside.Get(ctx, s.cc, s.cc.VersionedKey(ctx, nsTag, "all"), s.repo.ListAllTags)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range list {
+		if t.Slug == value {
+			return t, nil
+		}
+	}
+	if id, err := s.repo.FindSlugRedirect(ctx, domain.SlugKindTag, value); err == nil {
+		for _, t := range list {
+			if t.ID == id {
+				return nil, &domain.SlugMovedError{Slug: t.Slug}
+			}
+		}
+	}
+	return nil, domain.ErrTagNotFound
+}
@@ -0,0 +1,104 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/orm"
+)
+
+// checkTagName 标签名不可与其他标签重复（与表的大小写不敏感唯一键一致）
+func (s *ContentAppService) checkTagName(ctx context.Context, name string, id int64) error {
+	list, err := s.repo.ListAllTags(ctx)
+	if err != nil {
+		return err
+	}
+	for _, t := range list {
+		if t.ID != id && strings.EqualFold(t.Name, name) {
+			return domain.ErrTagNameTaken
+		}
+	}
+	return nil
+}
+
+// DeleteTag 删除标签，文章仅解除关联；dryRun 时仅返回影响预览
+func (s *ContentAppService) DeleteTag(ctx context.Context, id int64, dryRun bool) (*domain.TagChangePlan, error) {
+	if _, err := s.repo.GetTagByID(ctx, id); errors.Is(err, orm.ErrNoRows) {
+		return nil, domain.ErrTagNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	ids, err := s.repo.ListArticleIDsByTag(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	plan := &domain.TagChangePlan{SourceID: id, Articles: int64(len(ids)), DryRun: dryRun}
+	if dryRun {
+		return plan, nil
+	}
+	if err := s.bumpAfter(ctx, nsTag, s.repo.DeleteTag(ctx, id)); err != nil {
+		return nil, err
+	}
+	s.invalidateArticles(ctx, ids...)
+	return plan, nil
+}
+
+// MergeTag 将 sourceID 合并到 targetID：文章改带目标标签，源标签的别名重定向到目标，随后删除源标签；
+// dryRun 时仅返回影响预览
+func (s *ContentAppService) MergeTag(ctx context.Context, sourceID, targetID int64, dryRun bool) (*domain.TagChangePlan, error) {
+	if sourceID == targetID {
+		return nil, domain.ErrTagMergeSelf
+	}
+	src, err := s.repo.GetTagByID(ctx, sourceID)
+	if errors.Is(err, orm.ErrNoRows) {
+		return nil, domain.ErrTagNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetTagByID(ctx, targetID); errors.Is(err, orm.ErrNoRows) {
+		return nil, domain.ErrTagNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	ids, err := s.repo.ListArticleIDsByTag(ctx, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	overlap, err := s.repo.CountArticlesWithBothTags(ctx, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	plan := &domain.TagChangePlan{SourceID: sourceID, TargetID: targetID, Articles: int64(len(ids)), Overlap: overlap, DryRun: dryRun}
+	if dryRun {
+		return plan, nil
+	}
+	if err := s.bumpAfter(ctx, nsTag, s.repo.MergeTag(ctx, src, targetID)); err != nil {
+		return nil, err
+	}
+	s.invalidateArticles(ctx, ids...)
+	s.logger.Info("application: 合并标签: source=%d target=%d articles=%d", sourceID, targetID, plan.Articles)
+	return plan, nil
+}
+
+// checkTagIDs 去重并校验标签均存在；nil 原样返回
+func (s *ContentAppService) checkTagIDs(ctx context.Context, ids []int64) ([]int64, error) {
+	if ids == nil {
+		return nil, nil
+	}
+	ids, err := domain.NormalizeTagIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.repo.ListTagsByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != len(ids) {
+		return nil, domain.ErrTagInvalid
+	}
+	return ids, nil
+}
@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrArchiveMonth 归档年月不合法
+var ErrArchiveMonth = errors.New("归档年月不合法")
+
+// ArchiveCount 某年某月（某日）已公开文章数，Day 仅在日历中使用
+type ArchiveCount struct {
+	Year  int   `json:"year"`
+	Month int   `json:"month"`
+	Day   int   `json:"day,omitempty"`
+	Count int64 `json:"count"`
+}
+
+// ArchiveMonth 归档树中的月份
+type ArchiveMonth struct {
+	Month int   `json:"month"`
+	Count int64 `json:"count"`
+}
+
+// ArchiveYear 归档树中的年份，月份新到旧
+type ArchiveYear struct {
+	Year   int             `json:"year"`
+	Count  int64           `json:"count"`
+	Months []*ArchiveMonth `json:"months"`
+}
+
+// BuildArchiveTree 将按年月统计的结果（须按年月降序）组装为年 -> 月的归档树
+func BuildArchiveTree(counts []*ArchiveCount) []*ArchiveYear {
+	tree := make([]*ArchiveYear, 0)
+	for _, c := range counts {
+		if n := len(tree); n == 0 || tree[n-1].Year != c.Year {
+			tree = append(tree, &ArchiveYear{Year: c.Year, Months: make([]*ArchiveMonth, 0, 12)})
+		}
+		y := tree[len(tree)-1]
+		y.Count += c.Count
+		y.Months = append(y.Months, &ArchiveMonth{Month: c.Month, Count: c.Count})
+	}
+	return tree
+}
+
+// MonthRange 某年某月的起止时间 [from, to)，年月不合法时返回 ErrArchiveMonth
+func MonthRange(year, month int, loc *time.Location) (time.Time, time.Time, error) {
+	if year < 1 || year > 9999 || month < 1 || month > 12 {
+		return time.Time{}, time.Time{}, ErrArchiveMonth
+	}
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, loc)
+	return from, from.AddDate(0, 1, 0), nil
+}
+
+// ArticleNavItem 上一篇 / 下一篇
+type ArticleNavItem struct {
+	ID          int64     `json:"id"`
+	Title       string    `json:"title"`
+	Slug        string    `json:"slug"`
+	PublishedAt time.Time `json:"published_at"`
+}
+
+// ArticleNav 文章页导航：Prev 为发布更早的一篇，Next 为发布更晚的一篇，不存在时为 nil
+type ArticleNav struct {
+	Prev *ArticleNavItem `json:"prev"`
+	Next *ArticleNavItem `json:"next"`
+}
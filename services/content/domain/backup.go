@@ -0,0 +1,152 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// BackupSchemaVersion 备份归档格式版本；格式不兼容地变化时递增，恢复时只接受不高于当前版本的归档
+const BackupSchemaVersion = 1
+
+// 备份归档中的文件：JSON 为权威数据，articles/ 下带前言的 Markdown 便于阅读，也可直接按导入处理
+const (
+	BackupManifestFile    = "manifest.json"
+	BackupCategoriesFile  = "categories.json"
+	BackupTagsFile        = "tags.json"
+	BackupArticlesFile    = "articles.json"
+	BackupArticleTagsFile = "article_tags.json"
+	BackupMediaFile       = "media.json"
+	BackupUsersFile       = "users.json" // 由管理端写入与恢复，内容服务忽略
+	BackupArticleDir      = "articles/"
+	BackupMediaDir        = "media/"
+)
+
+var (
+	ErrBackupManifest = errors.New("备份归档缺少 manifest.json 或格式不正确")
+	ErrBackupData     = errors.New("备份归档中的数据文件格式不正确")
+)
+
+// BackupVersionError 备份归档的格式版本不受支持
+type BackupVersionError struct {
+	Version int
+}
+
+func (e *BackupVersionError) Error() string {
+	return fmt.Sprintf("不支持的备份格式版本 %d（当前为 %d）", e.Version, BackupSchemaVersion)
+}
+
+// CheckBackupVersion 校验归档格式版本
+func CheckBackupVersion(version int) error {
+	if version < 1 || version > BackupSchemaVersion {
+		return &BackupVersionError{Version: version}
+	}
+	return nil
+}
+
+// BackupManifest 备份归档清单
+type BackupManifest struct {
+	SchemaVersion int       `json:"schema_version"`
+	ExportedAt    time.Time `json:"exported_at"`
+	Articles      int       `json:"articles"`
+	Categories    int       `json:"categories"`
+	Tags          int       `json:"tags"`
+	ArticleTags   int       `json:"article_tags"`
+	Media         int       `json:"media"`
+	Users         int       `json:"users"` // 0 表示未包含用户
+}
+
+// BackupCategory 备份中的分类，ID 与 ParentID 为导出环境中的 ID
+type BackupCategory struct {
+	ID          int64     `json:"id"`
+	ParentID    int64     `json:"parent_id"`
+	Name        string    `json:"name"`
+	Slug        string    `json:"slug"`
+	Description string    `json:"description,omitempty"`
+	Sort        int       `json:"sort"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// BackupTag 备份中的标签
+type BackupTag struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Slug      string    `json:"slug"`
+	Color     string    `json:"color,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// BackupArticle 备份中的文章元数据，正文位于 File（articles/<slug>.md）
+type BackupArticle struct {
+	ID           int64      `json:"id"`
+	Slug         string     `json:"slug"`
+	Title        string     `json:"title"`
+	File         string     `json:"file"`
+	Summary      string     `json:"summary,omitempty"`
+	Cover        string     `json:"cover,omitempty"`
+	AuthorID     int64      `json:"author_id"`
+	CategoryID   int64      `json:"category_id"`
+	Status       int        `json:"status"`
+	ViewCount    int64      `json:"view_count"`
+	LikeCount    int64      `json:"like_count"`
+	IsTop        bool       `json:"is_top"`
+	IsRecommend  bool       `json:"is_recommend"`
+	MetaTitle    string     `json:"meta_title,omitempty"`
+	MetaDesc     string     `json:"meta_desc,omitempty"`
+	MetaKeywords string     `json:"meta_keywords,omitempty"`
+	PublishedAt  *time.Time `json:"published_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// BackupArticleTag 备份中的文章-标签关联
+type BackupArticleTag struct {
+	ArticleID int64 `json:"article_id"`
+	TagID     int64 `json:"tag_id"`
+}
+
+// BackupMedia 备份中的文件，原文件位于 File（media/<storage_key>），缩放副本恢复时重新生成
+type BackupMedia struct {
+	ID         int64           `json:"id"`
+	Hash       string          `json:"hash"`
+	File       string          `json:"file"`
+	URL        string          `json:"url"`
+	Name       string          `json:"name"`
+	MimeType   string          `json:"mime_type"`
+	UploaderID int64           `json:"uploader_id"`
+	Variants   []*MediaVariant `json:"variants,omitempty"` // 仅用于恢复时改写正文中副本的地址
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// ArchiveWriter 归档写入（*zip.Writer 即满足）
+type ArchiveWriter interface {
+	Create(name string) (io.Writer, error)
+}
+
+// DocFormatter 将文章写为带前言的 Markdown（与 ImportParser 可互逆）
+type DocFormatter interface {
+	Format(doc *ImportDoc) ([]byte, error)
+}
+
+// RestoreOptions 恢复选项：AuthorMap 为导出环境用户 ID 到当前环境的映射，未映射的作者与上传者取 DefaultAuthor
+type RestoreOptions struct {
+	AuthorMap     map[int64]int64
+	DefaultAuthor int64
+}
+
+// RestoreCount 某类对象的恢复结果：Existing 为当前环境中已有（按别名或名称匹配）而沿用的
+type RestoreCount struct {
+	Created  int `json:"created"`
+	Existing int `json:"existing"`
+	Failed   int `json:"failed"`
+}
+
+// RestoreReport 恢复报告；已有同别名的文章不覆盖，重复恢复同一归档是安全的
+type RestoreReport struct {
+	SchemaVersion int          `json:"schema_version"`
+	Categories    RestoreCount `json:"categories"`
+	Tags          RestoreCount `json:"tags"`
+	Articles      RestoreCount `json:"articles"`
+	Media         RestoreCount `json:"media"`
+	Errors        []string     `json:"errors"`
+}
@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CategoryMaxDepth 分类最大层级
+const CategoryMaxDepth = 3
+
+var (
+	ErrCategoryNotFound    = errors.New("分类不存在")
+	ErrCategoryParent      = errors.New("上级分类不存在")
+	ErrCategoryCycle       = errors.New("不能将分类移动到自身或其子分类下")
+	ErrCategoryTooDeep     = errors.New("分类层级超出上限")
+	ErrCategoryHasChildren = errors.New("分类下仍有子分类")
+	ErrCategoryNotEmpty    = errors.New("分类下仍有文章，请指定转移方式")
+	ErrCategoryDeleteMode  = errors.New("不支持的分类删除方式")
+	ErrCategoryTarget      = errors.New("目标分类不存在或与待删除分类相同")
+)
+
+// 删除分类时其下文章的处理方式
+const (
+	CategoryDeleteRefuse        = "refuse"        // 仍有文章时拒绝（默认）
+	CategoryDeleteReassign      = "reassign"      // 文章转移到指定分类
+	CategoryDeleteUncategorized = "uncategorized" // 文章转移到“未分类”，不存在时自动创建
+)
+
+// “未分类”分类
+const (
+	UncategorizedName = "未分类"
+	UncategorizedSlug = "uncategorized"
+)
+
+// CategoryDeletePlan 删除分类的影响；DryRun 时仅预览，不做任何修改
+type CategoryDeletePlan struct {
+	ID       int64  `json:"id"`
+	Mode     string `json:"mode"`
+	TargetID int64  `json:"target_id"` // 文章转移到的分类；“未分类”尚未创建时预览为 0
+	Articles int64  `json:"articles"`  // 受影响文章数
+	DryRun   bool   `json:"dry_run"`
+}
+
+// ChildPath 子分类的物化路径
+func ChildPath(parentPath string, id int64) string {
+	if parentPath == "" {
+		parentPath = "/"
+	}
+	return parentPath + strconv.FormatInt(id, 10) + "/"
+}
+
+// Depth 分类层级，顶级为 1
+func (c *Category) Depth() int {
+	return strings.Count(c.Path, "/") - 1
+}
+
+// Contains 是否为自身或其子孙
+func (c *Category) Contains(o *Category) bool {
+	return c.Path != "" && strings.HasPrefix(o.Path, c.Path)
+}
+
+// CheckMove 校验将 c（子树最大层级为 subtreeDepth）移动到 parent 下：
+// 不能移动到自身或子孙下，移动后的层级不超过上限；parent 为 nil 表示移到顶级
+func (c *Category) CheckMove(parent *Category, subtreeDepth int) error {
+	base := 0
+	if parent != nil {
+		if c.Contains(parent) {
+			return ErrCategoryCycle
+		}
+		base = parent.Depth()
+	}
+	if base+subtreeDepth-c.Depth()+1 > CategoryMaxDepth {
+		return ErrCategoryTooDeep
+	}
+	return nil
+}
+
+// CategoryNode 分类树节点
+type CategoryNode struct {
+	*Category
+	Children []*CategoryNode `json:"children"`
+}
+
+// BuildCategoryTree 由全量分类构建树，同级按 Sort、ID 升序；上级缺失的分类作为顶级
+func BuildCategoryTree(list []*Category) []*CategoryNode {
+	nodes := make(map[int64]*CategoryNode, len(list))
+	for _, c := range list {
+		nodes[c.ID] = &CategoryNode{Category: c, Children: make([]*CategoryNode, 0)}
+	}
+	roots := make([]*CategoryNode, 0)
+	for _, c := range list {
+		n := nodes[c.ID]
+		if p, ok := nodes[c.ParentID]; ok && c.ParentID != c.ID {
+			p.Children = append(p.Children, n)
+		} else {
+			roots = append(roots, n)
+		}
+	}
+	sortNodes(roots)
+	return roots
+}
+
+func sortNodes(nodes []*CategoryNode) {
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Sort != nodes[j].Sort {
+			return nodes[i].Sort < nodes[j].Sort
+		}
+		return nodes[i].ID < nodes[j].ID
+	})
+	for _, n := range nodes {
+		sortNodes(n.Children)
+	}
+}
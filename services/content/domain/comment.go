@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+	"unicode/utf8"
+)
+
+// 评论状态
+const (
+	CommentStatusPending  = 0 // 待审核
+	CommentStatusApproved = 1 // 已通过，公开可见
+	CommentStatusSpam     = 2 // 垃圾评论
+)
+
+// CommentMaxLength 评论内容最大字符数
+const CommentMaxLength = 2000
+
+var (
+	ErrCommentNotFound    = errors.New("评论不存在")
+	ErrCommentArticle     = errors.New("文章不存在或不可评论")
+	ErrCommentLogin       = errors.New("请登录后再评论")
+	ErrCommentEmpty       = errors.New("评论内容不能为空")
+	ErrCommentTooLong     = errors.New("评论内容过长")
+	ErrCommentParent      = errors.New("回复的评论不存在或不属于该文章")
+	ErrCommentStatus      = errors.New("不支持的评论状态")
+	ErrCommentForbidden   = errors.New("只能删除自己的评论")
+	ErrCommentRateLimited = errors.New("评论过于频繁，请稍后再试")
+)
+
+// Comment 文章评论：RootID 为所在楼层的顶层评论（顶层评论为 0），ParentID 为直接回复的评论
+type Comment struct {
+	ID        int64      `json:"id"`
+	ArticleID int64      `json:"article_id"`
+	ParentID  int64      `json:"parent_id"`
+	RootID    int64      `json:"root_id"`
+	UserID    int64      `json:"user_id"`
+	Content   string     `json:"content"`
+	Status    int        `json:"status"`
+	IP        string     `json:"-"`
+	DeletedAt *time.Time `json:"-"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+func (Comment) TableName() string { return "blog_comment" }
+
+// Deleted 是否已软删除
+func (c *Comment) Deleted() bool { return c.DeletedAt != nil }
+
+// ValidCommentStatus 是否为合法的评论状态
+func ValidCommentStatus(status int) bool {
+	return status >= CommentStatusPending && status <= CommentStatusSpam
+}
+
+// CheckCommentContent 校验评论内容非空且不超长
+func CheckCommentContent(content string) error {
+	if content == "" {
+		return ErrCommentEmpty
+	}
+	if utf8.RuneCountInString(content) > CommentMaxLength {
+		return ErrCommentTooLong
+	}
+	return nil
+}
+
+// CommentView 公开展示的评论；已删除但仍有回复的顶层评论保留占位，内容置空
+type CommentView struct {
+	*Comment
+	Deleted bool `json:"deleted,omitempty"`
+}
+
+// CommentThread 评论楼层：顶层评论及其全部已通过的回复（按时间升序）
+type CommentThread struct {
+	*CommentView
+	Replies []*CommentView `json:"replies"`
+}
+
+// CommentFilter 评论管理查询条件，零值字段不过滤
+type CommentFilter struct {
+	ArticleID int64
+	Status    *int
+	Page      int
+	PageSize  int
+}
+
+// CommentRepository 评论仓储
+type CommentRepository interface {
+	CreateComment(ctx context.Context, c *Comment) error
+	GetComment(ctx context.Context, id int64) (*Comment, error)
+	// ListCommentThreads 文章的顶层评论（已通过，新到旧）；已删除的顶层评论仅在仍有可见回复时返回
+	ListCommentThreads(ctx context.Context, articleID int64, page, pageSize int) ([]*Comment, int64, error)
+	// ListCommentReplies 楼层内已通过且未删除的回复（旧到新）
+	ListCommentReplies(ctx context.Context, rootIDs []int64) ([]*Comment, error)
+	// ListComments 评论管理列表（新到旧，不含已删除）
+	ListComments(ctx context.Context, f CommentFilter) ([]*Comment, int64, error)
+	// SetCommentStatus 批量修改状态（不含已删除），返回修改数
+	SetCommentStatus(ctx context.Context, ids []int64, status int) (int64, error)
+	SoftDeleteComment(ctx context.Context, id int64) error
+	// CountApprovedComments 各文章已通过且未删除的评论数
+	CountApprovedComments(ctx context.Context, articleIDs []int64) (map[int64]int64, error)
+}
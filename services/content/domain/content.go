@@ -3,7 +3,10 @@ package domain
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
+
+	"blog-system/common/pkg/pagination"
 )
 
 type Article struct {
@@ -24,12 +27,65 @@ type Article struct {
 	MetaDesc     *sql.NullString `json:"meta_desc"`
 	MetaKeywords *sql.NullString `json:"meta_keywords"`
 	PublishedAt  *time.Time      `json:"published_at"`
+	Version      int64           `json:"version"` // 乐观锁版本号，每次写入递增
 	CreatedAt    time.Time       `json:"created_at"`
 	UpdatedAt    time.Time       `json:"updated_at"`
 }
 
 func (Article) TableName() string { return "blog_article" }
 
+// ErrVersionConflict 更新时文章版本与期望不一致（已被他人修改）
+var ErrVersionConflict = errors.New("文章已被修改，请刷新后重试")
+
+// 文章状态
+const (
+	ArticleStatusDraft     = 0 // 草稿
+	ArticleStatusPublished = 1 // 已发布
+	ArticleStatusPrivate   = 2 // 私密
+	ArticleStatusScheduled = 3 // 定时发布（到达 PublishedAt 后由调度器转为已发布）
+)
+
+// ErrScheduleTimeRequired 定时发布缺少发布时间
+var ErrScheduleTimeRequired = errors.New("定时发布需指定发布时间")
+
+// NormalizeStatus 规范化发布状态：
+//   - 已发布未指定时间取 now，发布时间在未来则转为定时发布
+//   - 定时发布必须指定时间，时间已到则直接发布
+func (a *Article) NormalizeStatus(now time.Time) error {
+	switch a.Status {
+	case ArticleStatusPublished:
+		if a.PublishedAt == nil {
+			a.PublishedAt = &now
+		} else if a.PublishedAt.After(now) {
+			a.Status = ArticleStatusScheduled
+		}
+	case ArticleStatusScheduled:
+		if a.PublishedAt == nil {
+			return ErrScheduleTimeRequired
+		}
+		if !a.PublishedAt.After(now) {
+			a.Status = ArticleStatusPublished
+		}
+	}
+	return nil
+}
+
+// IsPublic 已发布且发布时间不晚于 now
+func (a *Article) IsPublic(now time.Time) bool {
+	return a.Status == ArticleStatusPublished && a.PublishedAt != nil && !a.PublishedAt.After(now)
+}
+
+// Viewer 读取方身份：零值为匿名读者
+type Viewer struct {
+	UserID int64 // 登录用户，可看到自己的草稿/私密文章
+	Admin  bool  // 管理员，可看到全部文章
+}
+
+// CanSee 可见性规则：管理员全部可见；作者可见自己的文章；其他人仅可见已公开文章
+func (v Viewer) CanSee(a *Article, now time.Time) bool {
+	return v.Admin || (v.UserID > 0 && a.AuthorID == v.UserID) || a.IsPublic(now)
+}
+
 // ArticleSummary 文章摘要
 type ArticleSummary struct {
 	ID       int64          `json:"id"`
@@ -41,9 +97,11 @@ type ArticleSummary struct {
 	CoverURL string         `json:"cover_url,omitempty"`
 }
 
-// Category 分类领域模型（单级）
+// Category 分类领域模型（树形，物化路径）
 type Category struct {
 	ID          int64           `json:"id"`
+	ParentID    int64           `json:"parent_id"` // 0 为顶级分类
+	Path        string          `json:"path"`      // 根到自身的 ID 路径，形如 /1/5/12/
 	Name        string          `json:"name"`
 	Slug        string          `json:"slug"`
 	Description *sql.NullString `json:"description"`
@@ -93,34 +151,118 @@ func (ArticleTag) TableName() string { return "blog_article_tags" }
 
 // ContentRepository 聚合仓储接口
 type ContentRepository interface {
+	// Transaction 工作单元：fn 经 repo 的读写在同一事务中提交，fn 返回错误时整体回滚
+	Transaction(ctx context.Context, fn func(ctx context.Context, repo ContentRepository) error) error
 
 	// Article 文章
+	// CreateArticle 写入文章并回填 a.ID
 	CreateArticle(ctx context.Context, a *Article) error
 	GetArticleByID(ctx context.Context, id int64) (*Article, error)
-	ListArticles(ctx context.Context, page, pageSize int) ([]*Article, int64, error)
+	// ListArticlesAfter 全部文章的游标分页（置顶在前，其余按发布时间新到旧，未发布的在最后），authorID 非 0 时限定作者；
+	// 返回下一页游标，没有更多时为 nil
+	ListArticlesAfter(ctx context.Context, authorID int64, after *pagination.Cursor, limit int) ([]*Article, *pagination.Cursor, error)
 	CountArticles(ctx context.Context) (int64, error)
+	// UpdateArticle 仅当版本等于 a.Version 时更新并递增版本，否则返回 ErrVersionConflict
 	UpdateArticle(ctx context.Context, a *Article) error
+	// ListScheduledArticles 定时发布的文章（按发布时间升序），dueBefore 非零时仅返回已到期的
+	ListScheduledArticles(ctx context.Context, dueBefore time.Time) ([]*Article, error)
+	// TransitArticleStatus 仅当当前状态为 from 时改为 to，返回是否更新
+	TransitArticleStatus(ctx context.Context, id int64, from, to int) (bool, error)
 	DeleteArticle(ctx context.Context, id int64) error
-	ListArticleSummaries(ctx context.Context, page, pageSize int) ([]*ArticleSummary, int64, error)
-	SearchArticleSummaries(ctx context.Context, keyword string, page, pageSize int) ([]*ArticleSummary, int64, error)
+	ListArticleSummaries(ctx context.Context, v Viewer, page, pageSize int) ([]*ArticleSummary, int64, error)
+	ListArticleSummariesByIDs(ctx context.Context, ids []int64) ([]*ArticleSummary, error)
+	ListSearchDocs(ctx context.Context) ([]*SearchDoc, error)
+	// ListSearchDocChanges updated_at 不早于 since 的文章：已发布的给出索引文档，其余给出待移除 ID
+	ListSearchDocChanges(ctx context.Context, since time.Time) (*SearchDocChanges, error)
+	// ListSearchDocsByIDs 指定文章：已发布的给出索引文档，其余及不存在的给出待移除 ID
+	ListSearchDocsByIDs(ctx context.Context, ids []int64) (*SearchDocChanges, error)
+	// ListPublishedArticleIDs 全部已发布文章 ID，用于发现已删除或漏同步的文档
+	ListPublishedArticleIDs(ctx context.Context) ([]int64, error)
+
+	// Revision 历史版本
+	CreateRevision(ctx context.Context, r *ArticleRevision) error
+	// ListRevisions 文章的历史版本（新到旧）
+	ListRevisions(ctx context.Context, articleID int64) ([]*ArticleRevision, error)
+	GetRevision(ctx context.Context, id int64) (*ArticleRevision, error)
+	// PruneRevisions 仅保留最新的 keep 个版本，返回删除数
+	PruneRevisions(ctx context.Context, articleID int64, keep int) (int64, error)
+
+	// Slug 别名
+	GetArticleBySlug(ctx context.Context, slug string) (*Article, error)
+	// SlugExists 别名是否已被同类对象中 excludeID 以外的对象占用
+	SlugExists(ctx context.Context, kind, slug string, excludeID int64) (bool, error)
+	// SaveSlugRedirect 记录旧别名指向 targetID，已存在时改为指向 targetID
+	SaveSlugRedirect(ctx context.Context, kind, slug string, targetID int64) error
+	// FindSlugRedirect 旧别名指向的对象 ID，不存在时返回 orm.ErrNoRows
+	FindSlugRedirect(ctx context.Context, kind, slug string) (int64, error)
 
 	// Category 分类
+	GetCategoryByID(ctx context.Context, id int64) (*Category, error)
 	ListAllCategories(ctx context.Context) ([]*Category, error)
 	ListCategories(ctx context.Context, page, pageSize int) ([]*Category, int64, error)
 	CountCategories(ctx context.Context) (int64, error)
+	// CreateCategory 写入分类并按 c.ParentID 生成物化路径（c.Path 由调用方填入上级路径）
 	CreateCategory(ctx context.Context, c *Category) error
+	// UpdateCategory 更新名称、别名、描述与排序（不改变层级）
 	UpdateCategory(ctx context.Context, c *Category) error
-	DeleteCategory(ctx context.Context, id int64) error
+	// MoveCategory 将分类连同子树移动到 parent 下（parent 为 nil 时移到顶级）
+	MoveCategory(ctx context.Context, c *Category, parent *Category) error
+	// CountChildCategories 直接子分类数
+	CountChildCategories(ctx context.Context, id int64) (int64, error)
+	// ListArticleIDsByCategory 分类（不含子分类）下的文章 ID
+	ListArticleIDsByCategory(ctx context.Context, categoryID int64) ([]int64, error)
+	// DeleteCategory 在同一事务中删除分类：targetID > 0 时先将其下文章转移到 targetID，
+	// 为 0 时若仍有文章返回 ErrCategoryNotEmpty
+	DeleteCategory(ctx context.Context, id, targetID int64) error
 
 	// Tag 标签
+	GetTagByID(ctx context.Context, id int64) (*Tag, error)
 	CreateTag(ctx context.Context, t *Tag) error
 	UpdateTag(ctx context.Context, t *Tag) error
+	// DeleteTag 在同一事务中删除标签及其文章关联、旧别名
 	DeleteTag(ctx context.Context, id int64) error
+	// MergeTag 在同一事务中将 source 合并到 targetID：文章关联与旧别名改指向目标，
+	// source 的别名重定向到目标，最后删除 source
+	MergeTag(ctx context.Context, source *Tag, targetID int64) error
+	// ListArticleIDsByTag 带有该标签的文章 ID
+	ListArticleIDsByTag(ctx context.Context, tagID int64) ([]int64, error)
+	// CountArticlesWithBothTags 同时带有两个标签的文章数
+	CountArticlesWithBothTags(ctx context.Context, a, b int64) (int64, error)
 	ListTags(ctx context.Context, page, pageSize int) ([]*Tag, int64, error)
 	CountTags(ctx context.Context) (int64, error)
 	ListArticleTags(ctx context.Context, articleID int64) ([]*Tag, error)
+	// UpdateArticleTags 以 tagIDs 覆盖文章标签
 	UpdateArticleTags(ctx context.Context, articleID int64, tagIDs []int64) error
+	// ListArticleTagLinks 全部文章-标签关联（按文章、标签 ID 升序），用于整站导出
+	ListArticleTagLinks(ctx context.Context) ([]*ArticleTag, error)
+	ListTagsByIDs(ctx context.Context, ids []int64) ([]*Tag, error)
 	ListAllTags(ctx context.Context) ([]*Tag, error)
 	CountArticlesByTag(ctx context.Context, tagID int64) (int64, error)
-	ListArticleSummariesFiltered(ctx context.Context, categoryID *int64, tagIDs []int64, page, pageSize int) ([]*ArticleSummary, int64, error)
+	CountArticlesGroupByTag(ctx context.Context) (map[int64]int64, error)
+	ListArticleSummariesFiltered(ctx context.Context, v Viewer, categoryID *int64, tagIDs []int64, page, pageSize int) ([]*ArticleSummary, int64, error)
+	// ListArticleSummariesAfter 过滤条件同 ListArticleSummariesFiltered 的游标分页，返回下一页游标，没有更多时为 nil
+	ListArticleSummariesAfter(ctx context.Context, v Viewer, categoryID *int64, tagIDs []int64, after *pagination.Cursor, limit int) ([]*ArticleSummary, *pagination.Cursor, error)
+
+	// Feed 订阅
+	// ListFeedArticles 已公开文章（发布时间新到旧，至多 limit 篇）
+	ListFeedArticles(ctx context.Context, categoryID *int64, tagIDs []int64, limit int) ([]*Article, error)
+	// ListAuthorNames 作者 ID 到用户名
+	ListAuthorNames(ctx context.Context, ids []int64) (map[int64]string, error)
+
+	// ListSitemapEntries 已公开文章、全部分类或标签的别名与最后修改时间（按 ID 升序）
+	ListSitemapEntries(ctx context.Context, kind string) ([]*SitemapEntry, error)
+
+	// ListRelatedCandidates 全部已公开文章的分类、推荐标记与标签
+	ListRelatedCandidates(ctx context.Context) ([]*RelatedCandidate, error)
+
+	// Archive 归档
+	// CountArchive 已公开文章按发布年月统计（新到旧）
+	CountArchive(ctx context.Context) ([]*ArchiveCount, error)
+	// CountArchiveDays 发布时间在 [from, to) 内的已公开文章按日统计
+	CountArchiveDays(ctx context.Context, from, to time.Time) ([]*ArchiveCount, error)
+	// ListArchiveSummaries 发布时间在 [from, to) 内的已公开文章摘要（置顶在前，其余新到旧）
+	ListArchiveSummaries(ctx context.Context, from, to time.Time, page, pageSize int) ([]*ArticleSummary, int64, error)
+	// GetAdjacentArticle 与 a 相邻的已公开文章（next 为 true 时取发布更晚的），sameCategory 时限定同一分类；
+	// 不存在时返回 orm.ErrNoRows
+	GetAdjacentArticle(ctx context.Context, a *Article, sameCategory, next bool) (*ArticleNavItem, error)
 }
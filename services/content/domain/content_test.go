@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestViewerCanSee(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	const author, other = 1, 2
+	articles := map[string]*Article{
+		"published":        {AuthorID: author, Status: ArticleStatusPublished, PublishedAt: &past},
+		"published-now":    {AuthorID: author, Status: ArticleStatusPublished, PublishedAt: &now},
+		"published-future": {AuthorID: author, Status: ArticleStatusPublished, PublishedAt: &future},
+		"published-nodate": {AuthorID: author, Status: ArticleStatusPublished},
+		"draft":            {AuthorID: author, Status: ArticleStatusDraft},
+		"private":          {AuthorID: author, Status: ArticleStatusPrivate, PublishedAt: &past},
+		"scheduled":        {AuthorID: author, Status: ArticleStatusScheduled, PublishedAt: &future},
+		"others-draft":     {AuthorID: other, Status: ArticleStatusDraft},
+		"others-scheduled": {AuthorID: other, Status: ArticleStatusScheduled, PublishedAt: &future},
+		"others-private":   {AuthorID: other, Status: ArticleStatusPrivate, PublishedAt: &past},
+	}
+	viewers := map[string]Viewer{
+		"anonymous": {},
+		"reader":    {UserID: 3},
+		"author":    {UserID: author},
+		"editor":    {UserID: 4, Admin: true},
+	}
+	// 每个读者可见的文章，其余均不可见
+	visible := map[string][]string{
+		"anonymous": {"published", "published-now"},
+		"reader":    {"published", "published-now"},
+		"author": {"published", "published-now", "published-future", "published-nodate",
+			"draft", "private", "scheduled"},
+		"editor": {"published", "published-now", "published-future", "published-nodate",
+			"draft", "private", "scheduled", "others-draft", "others-scheduled", "others-private"},
+	}
+	for vname, v := range viewers {
+		want := make(map[string]bool, len(visible[vname]))
+		for _, name := range visible[vname] {
+			want[name] = true
+		}
+		for aname, a := range articles {
+			if got := v.CanSee(a, now); got != want[aname] {
+				t.Errorf("%s.CanSee(%s) = %t, want %t", vname, aname, got, want[aname])
+			}
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// 订阅格式
+const (
+	FeedRSS  = "rss"
+	FeedAtom = "atom"
+	FeedJSON = "json"
+)
+
+// FeedFormats 支持的订阅格式
+var FeedFormats = []string{FeedRSS, FeedAtom, FeedJSON}
+
+// FeedSite 订阅源的站点信息
+type FeedSite struct {
+	Title       string
+	Description string
+	Link        string // 站点首页地址，文章链接为 Link/article/<slug>
+	Limit       int    // 每个订阅源的文章数
+	FullContent bool   // 为 true 时条目附带渲染后的全文 HTML
+}
+
+// ArticleLink 文章页地址
+func (s FeedSite) ArticleLink(slug string) string {
+	return strings.TrimRight(s.Link, "/") + "/article/" + slug
+}
+
+// PageLink 分类、标签等页面地址
+func (s FeedSite) PageLink(kind, slug string) string {
+	return strings.TrimRight(s.Link, "/") + "/" + kind + "/" + slug
+}
+
+// FeedScope 订阅范围：零值为全站，Kind 为 category 或 tag 时按该分类（含子分类）或标签过滤
+type FeedScope struct {
+	Kind string
+	ID   int64
+	Name string
+	Slug string
+}
+
+// Feed 与格式无关的订阅源
+type Feed struct {
+	Title       string      `json:"title"`
+	Description string      `json:"description"`
+	Link        string      `json:"link"`
+	Updated     time.Time   `json:"updated"` // 条目中最晚的更新时间，用作 Last-Modified
+	Items       []*FeedItem `json:"items"`
+}
+
+// FeedItem 订阅条目
+type FeedItem struct {
+	ID          int64     `json:"id"`
+	Title       string    `json:"title"`
+	Link        string    `json:"link"`
+	Summary     string    `json:"summary"`
+	ContentHTML string    `json:"content_html,omitempty"`
+	Author      string    `json:"author"`
+	Category    string    `json:"category,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	PublishedAt time.Time `json:"published_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
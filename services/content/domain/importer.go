@@ -0,0 +1,131 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// ImportMaxSize 默认导入压缩包大小上限（字节）
+const ImportMaxSize = 30 << 20
+
+// 导入源与备份归档的解压上限（防 zip 炸弹）：单个文件，以及单次导入或恢复累计读取的字节数
+const (
+	ArchiveMaxFileSize  = 64 << 20
+	ArchiveMaxTotalSize = 1 << 30
+)
+
+var (
+	ErrImportNoDocs    = errors.New("未找到可导入的 Markdown 文件")
+	ErrImportArchive   = errors.New("导入文件不是有效的 zip 压缩包")
+	ErrImportLogin     = errors.New("导入需指定作者")
+	ErrArchiveTooLarge = errors.New("压缩包中的文件解压后过大")
+)
+
+// 导入结果中单篇文章的处理方式（DryRun 时为将要执行的操作）
+const (
+	ImportActionCreate    = "create"
+	ImportActionUpdate    = "update"
+	ImportActionUnchanged = "unchanged" // 按别名找到已有文章且内容一致，重复导入不做修改
+	ImportActionFailed    = "failed"
+)
+
+// ImportDoc 由 Markdown 文件（YAML / TOML 前言）解析出的待导入文章
+type ImportDoc struct {
+	Path       string     // 导入源内的路径，以 / 分隔
+	Title      string     // 缺省时取文件名
+	Slug       string     // 前言中的别名，缺省时由文件名生成
+	Date       *time.Time // 发布时间，缺省时取文件修改时间
+	Tags       []string
+	Categories []string // 分类路径，由上级到下级（Hexo 约定）
+	Keywords   []string
+	Draft      bool
+	Summary    string
+	Cover      string // 封面图链接，相对路径同正文图片一样解析
+	Content    string // 去掉前言后的正文
+}
+
+// ImportParser 解析单个 Markdown 文件
+type ImportParser interface {
+	Parse(path string, data []byte) (*ImportDoc, error)
+}
+
+// ImportItem 单个文件的导入结果
+type ImportItem struct {
+	File      string   `json:"file"`
+	Slug      string   `json:"slug,omitempty"`
+	Title     string   `json:"title,omitempty"`
+	Action    string   `json:"action"`
+	ArticleID int64    `json:"article_id,omitempty"`
+	Images    int      `json:"images"`                   // 已解析到导入源内文件的图片数
+	Missing   []string `json:"missing_images,omitempty"` // 找不到或上传失败的相对图片链接（原样保留）
+	Error     string   `json:"error,omitempty"`
+}
+
+// ImportReport 导入报告；DryRun 时不做任何修改，仅预览将要创建的文章、标签与分类
+type ImportReport struct {
+	DryRun        bool          `json:"dry_run"`
+	Total         int           `json:"total"`
+	Created       int           `json:"created"`
+	Updated       int           `json:"updated"`
+	Unchanged     int           `json:"unchanged"`
+	Failed        int           `json:"failed"`
+	NewTags       []string      `json:"new_tags"`
+	NewCategories []string      `json:"new_categories"` // 分类路径，以 / 分隔
+	Items         []*ImportItem `json:"items"`
+}
+
+// Add 记录单个文件的结果并计数
+func (r *ImportReport) Add(item *ImportItem) {
+	r.Items = append(r.Items, item)
+	r.Total++
+	switch item.Action {
+	case ImportActionCreate:
+		r.Created++
+	case ImportActionUpdate:
+		r.Updated++
+	case ImportActionUnchanged:
+		r.Unchanged++
+	case ImportActionFailed:
+		r.Failed++
+	}
+}
+
+// ImageRef 正文中的图片引用；Asset 为 Hexo {% asset_img %} 标签，链接相对于文章资源目录
+type ImageRef struct {
+	Link  string
+	Asset bool
+}
+
+var (
+	mdImagePattern    = regexp.MustCompile(`!\[([^\]]*)\]\(\s*<?([^)\s>]+)>?((?:\s+"[^"]*")?\s*)\)`)
+	htmlImagePattern  = regexp.MustCompile(`(<img\b[^>]*?\bsrc\s*=\s*["'])([^"']+)(["'])`)
+	assetImagePattern = regexp.MustCompile(`{%\s*asset_img\s+(\S+)(?:\s+(.*?))?\s*%}`)
+)
+
+// RewriteImages 替换正文中的图片链接：Markdown 图片、HTML <img> 与 Hexo {% asset_img %}（替换为 Markdown 图片）；
+// resolve 返回 false 时保留原文
+func RewriteImages(content string, resolve func(ref ImageRef) (string, bool)) string {
+	content = mdImagePattern.ReplaceAllStringFunc(content, func(s string) string {
+		m := mdImagePattern.FindStringSubmatch(s)
+		if url, ok := resolve(ImageRef{Link: m[2]}); ok {
+			return "![" + m[1] + "](" + url + m[3] + ")"
+		}
+		return s
+	})
+	content = htmlImagePattern.ReplaceAllStringFunc(content, func(s string) string {
+		m := htmlImagePattern.FindStringSubmatch(s)
+		if url, ok := resolve(ImageRef{Link: m[2]}); ok {
+			return m[1] + url + m[3]
+		}
+		return s
+	})
+	return assetImagePattern.ReplaceAllStringFunc(content, func(s string) string {
+		m := assetImagePattern.FindStringSubmatch(s)
+		if url, ok := resolve(ImageRef{Link: m[1], Asset: true}); ok {
+			return "![" + strings.Trim(m[2], `"' `) + "](" + url + ")"
+		}
+		return s
+	})
+}
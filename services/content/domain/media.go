@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// MediaMaxSize 默认单个文件大小上限（字节）
+const MediaMaxSize = 10 << 20
+
+// MediaMaxPixels 默认图片像素数（宽×高）上限：解码前按图片头校验，避免小文件解码出超大位图
+const MediaMaxPixels = 40_000_000
+
+// MediaAllowedTypes 默认允许上传的类型（按文件内容嗅探，不信任客户端声明）
+var MediaAllowedTypes = []string{"image/jpeg", "image/png", "image/gif", "image/webp"}
+
+var (
+	ErrMediaNotFound = errors.New("文件不存在")
+	ErrMediaLogin    = errors.New("请登录后再上传")
+	ErrMediaEmpty    = errors.New("文件不能为空")
+	ErrMediaTooLarge = errors.New("文件过大")
+	ErrMediaType     = errors.New("不支持的文件类型")
+	ErrMediaInUse    = errors.New("文件仍被文章或用户引用")
+	ErrMediaPixels   = errors.New("图片尺寸过大")
+	ErrMediaDenied   = errors.New("无上传文件权限")
+	ErrMediaLimited  = errors.New("上传过于频繁，请稍后再试")
+)
+
+// Media 上传的文件：按内容 SHA-256 去重，同一内容只存一份；Variants 为按宽度缩放的图片副本
+type Media struct {
+	ID         int64           `json:"id"`
+	Hash       string          `json:"hash"`
+	StorageKey string          `json:"storage_key"`
+	URL        string          `json:"url"`
+	Name       string          `json:"name"` // 上传时的原始文件名
+	MimeType   string          `json:"mime_type"`
+	Size       int64           `json:"size"`
+	Width      int             `json:"width"`
+	Height     int             `json:"height"`
+	Variants   []*MediaVariant `json:"variants"`
+	UploaderID int64           `json:"uploader_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// MediaVariant 图片缩放副本，Name 为规格名（如 w320）
+type MediaVariant struct {
+	Name       string `json:"name"`
+	StorageKey string `json:"storage_key"`
+	URL        string `json:"url"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	Size       int64  `json:"size"`
+}
+
+// Keys 原文件与全部副本的存储键
+func (m *Media) Keys() []string {
+	keys := []string{m.StorageKey}
+	for _, v := range m.Variants {
+		keys = append(keys, v.StorageKey)
+	}
+	return keys
+}
+
+// MediaFilter 文件管理查询：Orphan 为 true 时只返回未被文章封面、正文或用户头像引用的文件
+type MediaFilter struct {
+	MimePrefix string // 如 image/
+	UploaderID int64
+	Orphan     bool
+	Before     *time.Time // 只返回此时间之前上传的，用于孤立文件排除刚上传尚未引用的
+	Page       int
+	PageSize   int
+}
+
+// MediaStorage 文件存储：本地文件系统或 S3 兼容对象存储
+type MediaStorage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// URL 文件的对外访问地址
+	URL(key string) string
+}
+
+// ImageVariant 图片处理结果
+type ImageVariant struct {
+	Width  int
+	Height int
+	Data   []byte
+}
+
+// ImageProcessor 图片尺寸读取与等比缩放
+type ImageProcessor interface {
+	// Size 图片宽高；不支持解码的格式返回错误
+	Size(data []byte) (int, int, error)
+	// Resize 只解码一次，依次等比缩放到 widths 中小于原图宽度的各宽度，输出与原图相同的编码格式（GIF 输出首帧 PNG）；
+	// 像素数超过 maxPixels 时不解码直接返回 ErrMediaPixels
+	Resize(data []byte, mimeType string, widths []int, maxPixels int64) ([]*ImageVariant, error)
+}
+
+// MediaRepository 文件元数据仓储
+type MediaRepository interface {
+	CreateMedia(ctx context.Context, m *Media) error
+	GetMedia(ctx context.Context, id int64) (*Media, error)
+	GetMediaByHash(ctx context.Context, hash string) (*Media, error)
+	ListMedia(ctx context.Context, f MediaFilter) ([]*Media, int64, error)
+	DeleteMedia(ctx context.Context, id int64) error
+	// MediaReferenced 文件（含副本）是否被文章封面、正文或用户头像引用
+	MediaReferenced(ctx context.Context, m *Media) (bool, error)
+}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"math"
+	"sort"
+)
+
+// 相关文章条数：默认值与上限（缓存按上限预计算）
+const (
+	RelatedDefaultLimit = 5
+	RelatedMaxLimit     = 20
+)
+
+// 相关度各项权重，三项得分均已归一化到 [0, 1]
+const (
+	relatedTagWeight      = 0.5
+	relatedCategoryWeight = 0.2
+	relatedTextWeight     = 0.3
+)
+
+// RelatedCandidate 参与相关度计算的已公开文章
+type RelatedCandidate struct {
+	ID          int64
+	CategoryID  int64
+	IsRecommend bool
+	TagIDs      []int64
+}
+
+// RelatedHit 相关文章打分结果；Pinned 为编辑推荐，排在其他结果之前
+type RelatedHit struct {
+	ID     int64   `json:"id"`
+	Score  float64 `json:"score"`
+	Pinned bool    `json:"pinned"`
+}
+
+// RelatedArticle 相关文章：文章摘要 + 相关度
+type RelatedArticle struct {
+	*ArticleSummary
+	Score  float64 `json:"score"`
+	Pinned bool    `json:"pinned,omitempty"`
+}
+
+// RankRelated 计算 src 与各候选文章的相关度，返回至多 limit 条：
+//   - 标签：共有标签按稀有度加权（idf = ln(1 + N/df)），除以 src 全部标签权重之和
+//   - 分类：同一分类得满分
+//   - 文本：textSim 给出的标题/摘要 TF-IDF 余弦相似度
+//
+// 相关度为 0 的文章不返回；编辑推荐（IsRecommend）的文章置顶，组内按相关度、ID 降序
+func RankRelated(src *RelatedCandidate, candidates []*RelatedCandidate, textSim map[int64]float64, limit int) []*RelatedHit {
+	df := make(map[int64]int, len(src.TagIDs))
+	for _, id := range src.TagIDs {
+		df[id] = 0
+	}
+	for _, c := range candidates {
+		for _, id := range c.TagIDs {
+			if _, ok := df[id]; ok {
+				df[id]++
+			}
+		}
+	}
+	n := float64(len(candidates))
+	idf := make(map[int64]float64, len(df))
+	var tagTotal float64
+	for id, d := range df {
+		idf[id] = math.Log(1 + n/math.Max(float64(d), 1))
+		tagTotal += idf[id]
+	}
+
+	hits := make([]*RelatedHit, 0, len(candidates))
+	for _, c := range candidates {
+		if c.ID == src.ID {
+			continue
+		}
+		var tagScore float64
+		for _, id := range c.TagIDs {
+			tagScore += idf[id]
+		}
+		if tagTotal > 0 {
+			tagScore /= tagTotal
+		}
+		var catScore float64
+		if src.CategoryID > 0 && c.CategoryID == src.CategoryID {
+			catScore = 1
+		}
+		score := relatedTagWeight*tagScore + relatedCategoryWeight*catScore + relatedTextWeight*textSim[c.ID]
+		if score <= 0 {
+			continue
+		}
+		hits = append(hits, &RelatedHit{ID: c.ID, Score: math.Round(score*1e4) / 1e4, Pinned: c.IsRecommend})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Pinned != hits[j].Pinned {
+			return hits[i].Pinned
+		}
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID > hits[j].ID
+	})
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
@@ -0,0 +1,37 @@
+package domain
+
+// Heading 目录项：正文中的标题及其锚点
+type Heading struct {
+	Level int    `json:"level"`
+	ID    string `json:"id"` // 锚点，对应 HTML 中标题的 id 属性
+	Text  string `json:"text"`
+}
+
+// Rendered Markdown 渲染结果
+type Rendered struct {
+	HTML           string     `json:"html"` // 已按白名单过滤的 HTML
+	TOC            []*Heading `json:"toc"`
+	WordCount      int        `json:"word_count"`      // 字数：中日韩文字逐字计，其余按单词计
+	ReadingMinutes int        `json:"reading_minutes"` // 预计阅读分钟数
+	PlainSummary   string     `json:"plain_summary"`   // 纯文本摘要
+}
+
+// SummaryLength 纯文本摘要的最大字符数
+const SummaryLength = 120
+
+// Summarizer 从 Markdown 正文提取纯文本摘要
+type Summarizer interface {
+	Summarize(content string, limit int) string
+}
+
+// Renderer Markdown 渲染器：输出安全的 HTML、目录、字数与阅读时长
+type Renderer interface {
+	Summarizer
+	Render(content string) (*Rendered, error)
+}
+
+// ArticleDetail 文章详情：文章字段与渲染结果平铺返回
+type ArticleDetail struct {
+	*Article
+	*Rendered
+}
@@ -0,0 +1,68 @@
+package domain
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	"blog-system/common/pkg/textdiff"
+)
+
+// ArticleRevision 文章历史版本：每次更新前保存旧内容快照
+type ArticleRevision struct {
+	ID         int64           `json:"id"`
+	ArticleID  int64           `json:"article_id"`
+	Title      string          `json:"title"`
+	Slug       string          `json:"slug"`
+	Content    string          `json:"content"`
+	Summary    *sql.NullString `json:"summary"`
+	Cover      *sql.NullString `json:"cover"`
+	CategoryID int64           `json:"category_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+func (ArticleRevision) TableName() string { return "blog_article_revision" }
+
+// ErrRevisionNotFound 版本不存在或不属于该文章
+var ErrRevisionNotFound = errors.New("历史版本不存在")
+
+// NewArticleRevision 以文章当前内容生成快照
+func NewArticleRevision(a *Article, now time.Time) *ArticleRevision {
+	return &ArticleRevision{
+		ArticleID:  a.ID,
+		Title:      a.Title,
+		Slug:       a.Slug,
+		Content:    a.Content,
+		Summary:    a.Summary,
+		Cover:      a.Cover,
+		CategoryID: a.CategoryID,
+		CreatedAt:  now,
+	}
+}
+
+// ApplyTo 将快照内容写回文章（状态、发布时间等不随版本回滚）
+func (r *ArticleRevision) ApplyTo(a *Article) {
+	a.Title = r.Title
+	a.Slug = r.Slug
+	a.Content = r.Content
+	a.Summary = r.Summary
+	a.Cover = r.Cover
+	a.CategoryID = r.CategoryID
+}
+
+// 差异模式
+const (
+	DiffModeUnified = "unified" // 正文按行输出 unified diff
+	DiffModeWord    = "word"    // 正文按词输出差异片段
+)
+
+// RevisionDiff 两个版本的差异；标题与摘要总是按词比较，正文按 Mode 比较
+type RevisionDiff struct {
+	FromID  int64              `json:"from_id"`
+	ToID    int64              `json:"to_id"` // 0 表示文章当前内容
+	Mode    string             `json:"mode"`
+	Title   []textdiff.Segment `json:"title"`
+	Summary []textdiff.Segment `json:"summary"`
+	Unified string             `json:"unified,omitempty"`
+	Content []textdiff.Segment `json:"content,omitempty"`
+}
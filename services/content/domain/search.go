@@ -0,0 +1,113 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"blog-system/common/pkg/pagination"
+)
+
+// SearchDoc 全文索引文档（仅索引已发布文章）
+type SearchDoc struct {
+	ID          int64
+	Title       string
+	Summary     string
+	Content     string
+	CategoryID  int64
+	TagIDs      []int64
+	PublishedAt *time.Time
+}
+
+// SearchDocChanges 自某时刻以来变更的文章
+type SearchDocChanges struct {
+	Docs    []*SearchDoc // 已发布，重新写入
+	Removed []int64      // 未发布（草稿、定时、下线），从索引移除
+	// Watermark 本批最大的 updated_at（数据库时钟），无变更时为零值
+	Watermark time.Time
+}
+
+// NewSearchDoc 由文章构造索引文档（标签由调用方填充）
+func NewSearchDoc(a *Article) *SearchDoc {
+	d := &SearchDoc{ID: a.ID, Title: a.Title, Content: a.Content, CategoryID: a.CategoryID, PublishedAt: a.PublishedAt}
+	if a.Summary != nil && a.Summary.Valid {
+		d.Summary = a.Summary.String
+	}
+	return d
+}
+
+// SearchQuery 全文检索条件
+type SearchQuery struct {
+	Keyword    string
+	CategoryID *int64
+	// CategoryIDs 分类及其子分类，非空时代替 CategoryID 过滤
+	CategoryIDs []int64
+	TagIDs      []int64
+	// PublishedBefore 仅返回发布时间不晚于该时刻的文档，零值不过滤
+	PublishedBefore time.Time
+	Page            int
+	PageSize        int
+	// After 非 nil 时返回排在该游标之后的 PageSize 条（忽略 Page）
+	After *pagination.Cursor
+}
+
+// SearchHit 命中文档
+type SearchHit struct {
+	ID         int64
+	Score      float64
+	Highlights map[string]string // 字段 -> 高亮片段（命中词以 <em> 包裹，其余已转义）
+}
+
+// FacetCount 分面计数
+type FacetCount struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// SearchFacets 按分类/标签统计的分面
+type SearchFacets struct {
+	Categories []*FacetCount `json:"categories"`
+	Tags       []*FacetCount `json:"tags"`
+}
+
+// SearchResult 检索结果
+type SearchResult struct {
+	Hits   []*SearchHit
+	Total  int64
+	Facets SearchFacets
+	// Next 本页最后一条的游标，没有更多结果时为 nil
+	Next *pagination.Cursor
+}
+
+// SearchItem 检索结果项：文章摘要 + 相关度 + 高亮
+type SearchItem struct {
+	*ArticleSummary
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights,omitempty"`
+}
+
+// SearchPage 检索结果页；NextCursor 为下一页游标，没有更多结果时为空
+type SearchPage struct {
+	Items      []*SearchItem
+	Total      int64
+	Facets     *SearchFacets
+	NextCursor string
+}
+
+// SearchIndex 全文索引
+type SearchIndex interface {
+	// Index 写入或覆盖文档
+	Index(ctx context.Context, doc *SearchDoc) error
+	// Delete 删除文档（不存在时忽略）
+	Delete(ctx context.Context, id int64) error
+	// Search 检索，结果按相关度降序
+	Search(ctx context.Context, q SearchQuery) (*SearchResult, error)
+	// Rebuild 以给定文档整体替换索引
+	Rebuild(ctx context.Context, docs []*SearchDoc) error
+	// Count 已索引文档数
+	Count() int
+	// IDs 已索引的全部文档 ID
+	IDs() []int64
+	// Similar 其他文档与 id 在标题/摘要上的 TF-IDF 余弦相似度（仅含大于 0 的），id 未索引时为空
+	Similar(ctx context.Context, id int64) (map[int64]float64, error)
+}
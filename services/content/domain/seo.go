@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SitemapPageSize 默认每个 sitemap 文件的条目数（协议上限 50000）
+const SitemapPageSize = 5000
+
+// SitemapKinds sitemap 覆盖的对象类型，文件名为 <kind>-<page>.xml
+var SitemapKinds = []string{SlugKindArticle, SlugKindCategory, SlugKindTag}
+
+var ErrSitemapNotFound = errors.New("sitemap 不存在")
+
+// SitemapEntry sitemap 条目：Lastmod 对分类、标签取其自身与其下已公开文章的最晚更新时间
+type SitemapEntry struct {
+	Slug    string    `json:"slug"`
+	Loc     string    `json:"loc"`
+	Lastmod time.Time `json:"lastmod"`
+}
+
+// SitemapFile sitemap 索引中的一个分页文件
+type SitemapFile struct {
+	Kind    string    `json:"kind"`
+	Page    int       `json:"page"`
+	Loc     string    `json:"loc"`
+	Lastmod time.Time `json:"lastmod"`
+}
+
+// Name 文件名，形如 article-1.xml
+func (f *SitemapFile) Name() string {
+	return f.Kind + "-" + strconv.Itoa(f.Page) + ".xml"
+}
+
+// MetaTag 页面 meta 标签：OpenGraph 使用 Property，Twitter Card 使用 Name
+type MetaTag struct {
+	Property string `json:"property,omitempty"`
+	Name     string `json:"name,omitempty"`
+	Content  string `json:"content"`
+}
+
+// ArticleSEO 文章页 SEO 信息：标题取 MetaTitle 否则标题，描述取 MetaDesc 否则摘要，
+// 关键词取 MetaKeywords 否则标签名
+type ArticleSEO struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	Keywords    []string       `json:"keywords"`
+	Canonical   string         `json:"canonical"`
+	Image       string         `json:"image,omitempty"`
+	OpenGraph   []MetaTag      `json:"open_graph"`
+	Twitter     []MetaTag      `json:"twitter"`
+	JSONLD      map[string]any `json:"json_ld"` // schema.org BlogPosting
+}
+
+// SplitKeywords 按中英文逗号拆分关键词，去除空白与重复
+func SplitKeywords(s string) []string {
+	out := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, k := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '，' }) {
+		k = strings.TrimSpace(k)
+		if _, ok := seen[k]; k == "" || ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		out = append(out, k)
+	}
+	return out
+}
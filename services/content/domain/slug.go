@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+// 别名（slug）所属对象
+const (
+	SlugKindArticle  = "article"
+	SlugKindCategory = "category"
+	SlugKindTag      = "tag"
+)
+
+// SlugMaxLen 各类对象别名的最大长度（与表结构一致）
+var SlugMaxLen = map[string]int{
+	SlugKindArticle:  200,
+	SlugKindCategory: 100,
+	SlugKindTag:      50,
+}
+
+var (
+	ErrSlugInvalid = errors.New("别名只能包含小写字母、数字与连字符，且不能以连字符开头或结尾")
+	ErrSlugTaken   = errors.New("别名已被占用")
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(?:-[a-z0-9]+)*$`)
+
+// ValidSlug 校验别名格式与长度
+func ValidSlug(kind, slug string) bool {
+	return len(slug) <= SlugMaxLen[kind] && slugPattern.MatchString(slug)
+}
+
+// SlugMovedError 按旧别名访问：对象仍存在但别名已变更为 Slug
+type SlugMovedError struct {
+	Slug string
+}
+
+func (e *SlugMovedError) Error() string { return fmt.Sprintf("别名已变更为 %s", e.Slug) }
+
+// SlugRedirect 旧别名记录：别名变更后旧链接永久重定向到对象的当前别名
+type SlugRedirect struct {
+	ID        int64     `json:"id"`
+	Kind      string    `json:"kind"`
+	Slug      string    `json:"slug"`
+	TargetID  int64     `json:"target_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (SlugRedirect) TableName() string { return "blog_slug_redirect" }
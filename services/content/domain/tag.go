@@ -0,0 +1,36 @@
+package domain
+
+import "errors"
+
+var (
+	ErrTagNotFound  = errors.New("标签不存在")
+	ErrTagNameTaken = errors.New("标签名已存在，如需合并请使用标签合并")
+	ErrTagMergeSelf = errors.New("不能将标签合并到自身")
+	ErrTagInvalid   = errors.New("包含不存在的标签")
+)
+
+// NormalizeTagIDs 去重并保持原有顺序；存在非正数 ID 时返回 ErrTagInvalid
+func NormalizeTagIDs(ids []int64) ([]int64, error) {
+	out := make([]int64, 0, len(ids))
+	seen := make(map[int64]struct{}, len(ids))
+	for _, id := range ids {
+		if id <= 0 {
+			return nil, ErrTagInvalid
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	return out, nil
+}
+
+// TagChangePlan 删除或合并标签的影响；DryRun 时仅预览，不做任何修改
+type TagChangePlan struct {
+	SourceID int64 `json:"source_id"`
+	TargetID int64 `json:"target_id,omitempty"` // 合并目标，删除时为 0
+	Articles int64 `json:"articles"`            // 带有源标签的文章数
+	Overlap  int64 `json:"overlap,omitempty"`   // 合并时已同时带有两个标签的文章数
+	DryRun   bool  `json:"dry_run"`
+}
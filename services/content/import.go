@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"blog-system/services/content/application"
+	"blog-system/services/content/infrastructure/importer"
+)
+
+// runImport 子命令 import：content import [-dry-run] [-author=ID] <目录或 zip>，
+// 导入报告以 JSON 输出到标准输出，返回进程退出码
+func runImport(ctx context.Context, svc *application.ImportAppService, args []string) int {
+	fset := flag.NewFlagSet("import", flag.ContinueOnError)
+	dryRun := fset.Bool("dry-run", false, "只预览，不写入")
+	author := fset.Int64("author", 1, "导入文章的作者 ID")
+	fset.Usage = func() {
+		fmt.Fprintln(fset.Output(), "用法: content import [-dry-run] [-author=ID] <目录或 zip>")
+		fset.PrintDefaults()
+	}
+	if err := fset.Parse(args); err != nil {
+		return 2
+	}
+	if fset.NArg() != 1 {
+		fset.Usage()
+		return 2
+	}
+	src, closeSrc, err := importer.Open(fset.Arg(0))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "打开导入源失败: %v\n", err)
+		return 1
+	}
+	defer func() { _ = closeSrc() }()
+	report, err := svc.Import(ctx, src, *author, *dryRun)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "导入失败: %v\n", err)
+		return 1
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(report)
+	fmt.Fprintf(os.Stderr, "共 %d 篇：新建 %d，更新 %d，未变化 %d，失败 %d\n",
+		report.Total, report.Created, report.Updated, report.Unchanged, report.Failed)
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
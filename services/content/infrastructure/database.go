@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/CoucouMonEcho/go-framework/cache"
 	"github.com/CoucouMonEcho/go-framework/orm"
 	ormotel "github.com/CoucouMonEcho/go-framework/orm/middlewares/opentelemetry"
 	ormprom "github.com/CoucouMonEcho/go-framework/orm/middlewares/prometheus"
@@ -43,11 +42,12 @@ func InitDB(cfg *conf.AppConfig) (*orm.DB, error) {
 	))
 }
 
-func InitCache(cfg *conf.AppConfig) (cache.Cache, error) {
+// InitRedis 创建 Redis Cluster 客户端（缓存与分布式锁共用）
+func InitRedis(cfg *conf.AppConfig) (*redis.ClusterClient, error) {
 	if len(cfg.Redis.Cluster.Addrs) == 0 {
 		return nil, fmt.Errorf("未配置Redis Cluster地址")
 	}
-	client := redis.NewClusterClient(&redis.ClusterOptions{
+	return redis.NewClusterClient(&redis.ClusterOptions{
 		Addrs:        cfg.Redis.Cluster.Addrs,
 		Password:     cfg.Redis.Cluster.Password,
 		PoolSize:     cfg.Redis.Cluster.PoolSize,
@@ -56,6 +56,13 @@ func InitCache(cfg *conf.AppConfig) (cache.Cache, error) {
 		DialTimeout:  parseDuration(cfg.Redis.Cluster.DialTimeout),
 		ReadTimeout:  parseDuration(cfg.Redis.Cluster.ReadTimeout),
 		WriteTimeout: parseDuration(cfg.Redis.Cluster.WriteTimeout),
-	})
-	return cache.NewRedisCache(client), nil
+	}), nil
+}
+
+// ParseDurationOr 解析配置中的时长，为空或非法时返回 def
+func ParseDurationOr(s string, def time.Duration) time.Duration {
+	if d := parseDuration(s); d > 0 {
+		return d
+	}
+	return def
 }
@@ -0,0 +1,110 @@
+// Package imaging 图片尺寸读取与缩放：仅依赖标准库，支持 JPEG、PNG、GIF（取首帧）
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+
+	_ "image/gif"
+
+	"blog-system/services/content/domain"
+)
+
+// jpegQuality 缩放副本的 JPEG 质量
+const jpegQuality = 85
+
+var errUnsupported = errors.New("不支持处理的图片格式")
+
+// Processor 以区域平均（box filter）做等比缩小，不小于原图宽度的规格跳过
+type Processor struct{}
+
+func NewProcessor() *Processor { return &Processor{} }
+
+func (p *Processor) Size(data []byte) (int, int, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return 0, 0, errUnsupported
+	}
+	return cfg.Width, cfg.Height, nil
+}
+
+func (p *Processor) Resize(data []byte, mimeType string, widths []int, maxPixels int64) ([]*domain.ImageVariant, error) {
+	w, h, err := p.Size(data)
+	if err != nil {
+		return nil, err
+	}
+	if maxPixels > 0 && int64(w)*int64(h) > maxPixels {
+		return nil, domain.ErrMediaPixels
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errUnsupported
+	}
+	src := toNRGBA(decoded)
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+
+	variants := make([]*domain.ImageVariant, 0, len(widths))
+	for _, width := range widths {
+		if width <= 0 || width >= sw {
+			continue
+		}
+		height := max(1, sh*width/sw)
+		dst := shrink(src, width, height)
+		var buf bytes.Buffer
+		if mimeType == "image/jpeg" {
+			err = jpeg.Encode(&buf, dst, &jpeg.Options{Quality: jpegQuality})
+		} else {
+			err = png.Encode(&buf, dst)
+		}
+		if err != nil {
+			return variants, err
+		}
+		variants = append(variants, &domain.ImageVariant{Width: width, Height: height, Data: buf.Bytes()})
+	}
+	return variants, nil
+}
+
+func toNRGBA(src image.Image) *image.NRGBA {
+	if m, ok := src.(*image.NRGBA); ok && m.Rect.Min == (image.Point{}) {
+		return m
+	}
+	b := src.Bounds()
+	m := image.NewNRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(m, m.Rect, src, b.Min, draw.Src)
+	return m
+}
+
+// shrink 目标像素取其在原图覆盖区域内像素的平均值（按 alpha 加权，避免透明边缘发黑）
+func shrink(src *image.NRGBA, w, h int) *image.NRGBA {
+	sw, sh := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		y0, y1 := y*sh/h, max((y+1)*sh/h, y*sh/h+1)
+		for x := 0; x < w; x++ {
+			x0, x1 := x*sw/w, max((x+1)*sw/w, x*sw/w+1)
+			var r, g, bl, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				row := src.Pix[sy*src.Stride:]
+				for sx := x0; sx < x1; sx++ {
+					px := row[sx*4 : sx*4+4]
+					pa := uint64(px[3])
+					r += uint64(px[0]) * pa
+					g += uint64(px[1]) * pa
+					bl += uint64(px[2]) * pa
+					a += pa
+					n++
+				}
+			}
+			o := dst.Pix[y*dst.Stride+x*4:]
+			if a > 0 {
+				o[0], o[1], o[2] = uint8(r/a), uint8(g/a), uint8(bl/a)
+			}
+			o[3] = uint8(a / n)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,59 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"blog-system/services/content/domain"
+)
+
+func encodePNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x), G: uint8(y), B: 0x80, A: 0xff})
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestResize(t *testing.T) {
+	data := encodePNG(t, 200, 100)
+	variants, err := NewProcessor().Resize(data, "image/png", []int{50, 100, 200, 400}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 不小于原图宽度的规格跳过
+	if len(variants) != 2 {
+		t.Fatalf("got %d variants, want 2", len(variants))
+	}
+	for i, want := range []image.Point{{50, 25}, {100, 50}} {
+		v := variants[i]
+		if v.Width != want.X || v.Height != want.Y {
+			t.Errorf("variant %d: %dx%d, want %dx%d", i, v.Width, v.Height, want.X, want.Y)
+		}
+		cfg, err := png.DecodeConfig(bytes.NewReader(v.Data))
+		if err != nil || cfg.Width != want.X || cfg.Height != want.Y {
+			t.Errorf("variant %d: decoded %+v, err %v", i, cfg, err)
+		}
+	}
+}
+
+func TestResizeRejectsLargeImages(t *testing.T) {
+	data := encodePNG(t, 200, 100)
+	if _, err := NewProcessor().Resize(data, "image/png", []int{50}, 200*100-1); !errors.Is(err, domain.ErrMediaPixels) {
+		t.Fatalf("err = %v, want ErrMediaPixels", err)
+	}
+	if _, err := NewProcessor().Resize(data, "image/png", []int{50}, 200*100); err != nil {
+		t.Fatalf("at limit: %v", err)
+	}
+}
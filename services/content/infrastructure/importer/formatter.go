@@ -0,0 +1,45 @@
+package importer
+
+import (
+	"bytes"
+	"time"
+
+	"blog-system/services/content/domain"
+
+	"gopkg.in/yaml.v2"
+)
+
+// frontMatter 导出的前言字段，与 Parse 识别的字段一致
+type frontMatter struct {
+	Title       string   `yaml:"title"`
+	Slug        string   `yaml:"slug,omitempty"`
+	Date        string   `yaml:"date,omitempty"`
+	Tags        []string `yaml:"tags,omitempty"`
+	Categories  []string `yaml:"categories,omitempty"`
+	Keywords    []string `yaml:"keywords,omitempty"`
+	Draft       bool     `yaml:"draft,omitempty"`
+	Description string   `yaml:"description,omitempty"`
+	Cover       string   `yaml:"cover,omitempty"`
+}
+
+// Format 写为 YAML 前言（--- 包围）加正文，Parse 可还原同样的字段
+func (p *Parser) Format(doc *domain.ImportDoc) ([]byte, error) {
+	fm := frontMatter{
+		Title: doc.Title, Slug: doc.Slug, Tags: doc.Tags, Categories: doc.Categories, Keywords: doc.Keywords,
+		Draft: doc.Draft, Description: doc.Summary, Cover: doc.Cover,
+	}
+	if doc.Date != nil {
+		fm.Date = doc.Date.Format(time.RFC3339)
+	}
+	head, err := yaml.Marshal(fm)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.Grow(len(head) + len(doc.Content) + 8)
+	buf.WriteString("---\n")
+	buf.Write(head)
+	buf.WriteString("---\n\n")
+	buf.WriteString(doc.Content)
+	return buf.Bytes(), nil
+}
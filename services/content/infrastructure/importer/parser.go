@@ -0,0 +1,206 @@
+// Package importer Markdown 导入：解析 Hexo / Hugo 文章的 YAML（---）或 TOML（+++）前言，打开目录或 zip 导入源
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"blog-system/services/content/domain"
+
+	"gopkg.in/yaml.v2"
+)
+
+// dateLayouts 前言中日期的常见格式，不含时区的按本地时间解析
+var dateLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02 15:04",
+	"2006/01/02",
+}
+
+var errFrontMatter = errors.New("前言未闭合")
+
+// Parser domain.ImportParser 的实现
+type Parser struct{}
+
+func NewParser() *Parser { return &Parser{} }
+
+// Parse 解析前言并映射字段：title、slug、date、tags、categories、keywords、draft / published、
+// description / summary / excerpt（摘要）、cover / thumbnail / image / banner（封面）；
+// 位于 _drafts 目录下的文件视为草稿
+func (p *Parser) Parse(name string, data []byte) (*domain.ImportDoc, error) {
+	text := strings.TrimPrefix(string(data), "\ufeff")
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	meta, body, err := splitFrontMatter(text)
+	if err != nil {
+		return nil, err
+	}
+	doc := &domain.ImportDoc{
+		Path:       name,
+		Title:      strings.TrimSpace(stringOf(meta["title"])),
+		Slug:       strings.TrimSpace(stringOf(meta["slug"])),
+		Tags:       stringsOf(meta["tags"]),
+		Categories: categoryPath(meta["categories"]),
+		Keywords:   stringsOf(meta["keywords"]),
+		Summary:    strings.TrimSpace(firstString(meta, "description", "summary", "excerpt")),
+		Cover:      strings.TrimSpace(firstString(meta, "cover", "thumbnail", "image", "banner")),
+		Content:    strings.TrimLeft(body, "\n"),
+	}
+	if len(doc.Categories) == 0 {
+		doc.Categories = categoryPath(meta["category"])
+	}
+	if doc.Date, err = dateOf(meta["date"]); err != nil {
+		return nil, err
+	}
+	doc.Draft = boolOf(meta["draft"])
+	if v, ok := meta["published"]; ok && !boolOf(v) {
+		doc.Draft = true
+	}
+	if strings.Contains("/"+path.Dir(name)+"/", "/_drafts/") {
+		doc.Draft = true
+	}
+	return doc, nil
+}
+
+// splitFrontMatter 拆分前言与正文：--- 包围的 YAML、+++ 包围的 TOML，
+// 以及 Hexo 允许的省略起始 --- 的写法（首个 --- 行之前能解析为含 title 的 YAML）
+func splitFrontMatter(text string) (map[string]any, string, error) {
+	for _, delim := range []string{"---", "+++"} {
+		if !strings.HasPrefix(text, delim+"\n") {
+			continue
+		}
+		head, body, ok := cutLine(text[len(delim)+1:], delim)
+		if !ok {
+			return nil, "", errFrontMatter
+		}
+		if delim == "+++" {
+			meta, err := parseTOML(head)
+			return meta, body, err
+		}
+		meta, err := parseYAML(head)
+		return meta, body, err
+	}
+	if head, body, ok := cutLine(text, "---"); ok {
+		if meta, err := parseYAML(head); err == nil && meta["title"] != nil {
+			return meta, body, nil
+		}
+	}
+	return map[string]any{}, text, nil
+}
+
+// cutLine 在第一处内容恰为 delim 的行处切分，返回其前后内容
+func cutLine(text, delim string) (string, string, bool) {
+	if strings.HasPrefix(text, delim+"\n") || text == delim {
+		return "", strings.TrimPrefix(text[len(delim):], "\n"), true
+	}
+	i := strings.Index(text, "\n"+delim+"\n")
+	if i < 0 {
+		if !strings.HasSuffix(text, "\n"+delim) {
+			return "", "", false
+		}
+		return text[:len(text)-len(delim)-1], "", true
+	}
+	return text[:i], text[i+len(delim)+2:], true
+}
+
+func parseYAML(head string) (map[string]any, error) {
+	meta := map[string]any{}
+	if err := yaml.Unmarshal([]byte(head), &meta); err != nil {
+		return nil, fmt.Errorf("前言解析失败: %w", err)
+	}
+	return meta, nil
+}
+
+// dateOf 解析日期，缺省时返回 nil
+func dateOf(v any) (*time.Time, error) {
+	switch d := v.(type) {
+	case nil:
+		return nil, nil
+	case time.Time:
+		return &d, nil
+	}
+	s := strings.TrimSpace(stringOf(v))
+	if s == "" {
+		return nil, nil
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("无法解析日期: %s", s)
+}
+
+// categoryPath Hexo 的分类为层级路径（[上级, 下级]），多条路径（嵌套列表）时取第一条
+func categoryPath(v any) []string {
+	if list, ok := v.([]any); ok {
+		for _, item := range list {
+			if sub, ok := item.([]any); ok {
+				return stringsOf(sub)
+			}
+		}
+	}
+	return stringsOf(v)
+}
+
+// stringsOf 列表或单个值（含逗号分隔的字符串）转为去空的字符串列表
+func stringsOf(v any) []string {
+	var raw []string
+	switch list := v.(type) {
+	case nil:
+		return nil
+	case []any:
+		for _, item := range list {
+			raw = append(raw, stringOf(item))
+		}
+	default:
+		raw = strings.Split(stringOf(v), ",")
+	}
+	out := make([]string, 0, len(raw))
+	for _, s := range raw {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func stringOf(v any) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	default:
+		return fmt.Sprint(s)
+	}
+}
+
+func firstString(meta map[string]any, keys ...string) string {
+	for _, k := range keys {
+		if s := stringOf(meta[k]); s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func boolOf(v any) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		ok, _ := strconv.ParseBool(strings.TrimSpace(b))
+		return ok
+	}
+	return false
+}
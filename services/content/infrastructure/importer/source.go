@@ -0,0 +1,40 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"blog-system/services/content/domain"
+)
+
+// Open 打开导入源：目录或 .zip 文件；用完后调用返回的 close 释放文件句柄
+func Open(path string) (fs.FS, func() error, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	if info.IsDir() {
+		return os.DirFS(path), func() error { return nil }, nil
+	}
+	if !strings.EqualFold(filepath.Ext(path), ".zip") {
+		return nil, nil, domain.ErrImportArchive
+	}
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, nil, domain.ErrImportArchive
+	}
+	return zr, zr.Close, nil
+}
+
+// FromZip 由内存中的 zip 内容构造导入源（管理端上传）
+func FromZip(data []byte) (fs.FS, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, domain.ErrImportArchive
+	}
+	return zr, nil
+}
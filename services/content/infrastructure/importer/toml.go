@@ -0,0 +1,134 @@
+package importer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parseTOML 解析 Hugo TOML 前言中的顶层键：字符串、布尔、数字、日期与（可跨行的）字符串数组；
+// 首个表头（[params] 等）之后的内容与文章字段无关，忽略
+func parseTOML(head string) (map[string]any, error) {
+	meta := map[string]any{}
+	lines := strings.Split(head, "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			break
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("前言解析失败: 第 %d 行缺少 =", i+1)
+		}
+		key = strings.Trim(strings.TrimSpace(key), `"'`)
+		value = strings.TrimSpace(value)
+		// 跨行数组：拼接到方括号闭合为止
+		for strings.HasPrefix(value, "[") && !arrayClosed(value) && i+1 < len(lines) {
+			i++
+			value += " " + strings.TrimSpace(lines[i])
+		}
+		v, err := tomlValue(value)
+		if err != nil {
+			return nil, fmt.Errorf("前言解析失败: %s: %w", key, err)
+		}
+		meta[key] = v
+	}
+	return meta, nil
+}
+
+// tomlValue 解析单个值，数组元素递归解析
+func tomlValue(s string) (any, error) {
+	switch {
+	case strings.HasPrefix(s, "["):
+		items, err := splitArray(s)
+		if err != nil {
+			return nil, err
+		}
+		list := make([]any, 0, len(items))
+		for _, item := range items {
+			v, err := tomlValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		return list, nil
+	case strings.HasPrefix(s, `"`):
+		end := closingQuote(s, '"')
+		if end < 0 {
+			return nil, fmt.Errorf("字符串未闭合")
+		}
+		return strconv.Unquote(s[:end+1])
+	case strings.HasPrefix(s, "'"):
+		end := closingQuote(s, '\'')
+		if end < 0 {
+			return nil, fmt.Errorf("字符串未闭合")
+		}
+		return s[1:end], nil
+	}
+	// 裸值：去掉行尾注释
+	if i := strings.Index(s, " #"); i >= 0 {
+		s = strings.TrimSpace(s[:i])
+	}
+	if b, err := strconv.ParseBool(s); err == nil {
+		return b, nil
+	}
+	return s, nil
+}
+
+// splitArray 按顶层逗号拆分数组元素（忽略引号与嵌套数组内的逗号）
+func splitArray(s string) ([]string, error) {
+	var (
+		items []string
+		depth int
+		start = 1
+	)
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"', '\'':
+			end := closingQuote(s[i:], s[i])
+			if end < 0 {
+				return nil, fmt.Errorf("字符串未闭合")
+			}
+			i += end
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				if last := strings.TrimSpace(s[start:i]); last != "" {
+					items = append(items, last)
+				}
+				return items, nil
+			}
+		case ',':
+			if depth == 1 {
+				items = append(items, strings.TrimSpace(s[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return nil, fmt.Errorf("数组未闭合")
+}
+
+// arrayClosed 方括号是否已配对（忽略引号内的字符）
+func arrayClosed(s string) bool {
+	_, err := splitArray(s)
+	return err == nil
+}
+
+// closingQuote s 以引号 q 开头，返回配对引号的下标；双引号字符串支持反斜杠转义
+func closingQuote(s string, q byte) int {
+	for i := 1; i < len(s); i++ {
+		switch {
+		case q == '"' && s[i] == '\\':
+			i++
+		case s[i] == q:
+			return i
+		}
+	}
+	return -1
+}
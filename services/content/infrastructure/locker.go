@@ -0,0 +1,40 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"blog-system/common/pkg/logger"
+
+	"github.com/CoucouMonEcho/go-framework/cache"
+	redis "github.com/redis/go-redis/v9"
+)
+
+// RedisLocker 基于 Redis 的分布式锁（不重试，抢不到即放弃本轮）
+type RedisLocker struct {
+	client *cache.Client
+}
+
+func NewRedisLocker(rdb redis.Cmdable) *RedisLocker {
+	c, _ := cache.NewClient(rdb)
+	return &RedisLocker{client: c}
+}
+
+func (l *RedisLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	lock, err := l.client.Lock(ctx, key, ttl, time.Second, &cache.FixedIntervalRetryStrategy{})
+	if errors.Is(err, cache.ErrRetryLimitIsExceeded) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	release := func() {
+		uctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := lock.Unlock(uctx); err != nil {
+			logger.Log().Warn("infrastructure: 释放锁失败: key=%s err=%v", key, err)
+		}
+	}
+	return release, true, nil
+}
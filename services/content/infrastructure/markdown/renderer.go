@@ -0,0 +1,283 @@
+// Package markdown Markdown 渲染：goldmark 解析（GFM），chroma 代码高亮，bluemonday 白名单过滤
+package markdown
+
+import (
+	"bytes"
+	"html"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"blog-system/services/content/domain"
+
+	"github.com/alecthomas/chroma/v2"
+	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/renderer"
+	goldhtml "github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+// 阅读速度：中日韩文字每分钟 300 字，其余每分钟 200 词
+const (
+	cjkPerMinute  = 300
+	wordPerMinute = 200
+)
+
+// Renderer domain.Renderer 的 goldmark 实现，可并发使用
+type Renderer struct {
+	md     goldmark.Markdown
+	policy *bluemonday.Policy
+}
+
+var _ domain.Renderer = (*Renderer)(nil)
+
+// NewRenderer 创建渲染器；正文中的原始 HTML 会保留，再统一经白名单过滤
+func NewRenderer() *Renderer {
+	return &Renderer{
+		md: goldmark.New(
+			goldmark.WithExtensions(extension.GFM),
+			goldmark.WithParserOptions(parser.WithAutoHeadingID()),
+			goldmark.WithRendererOptions(
+				goldhtml.WithUnsafe(),
+				renderer.WithNodeRenderers(util.Prioritized(newCodeBlockRenderer(), 100)),
+			),
+		),
+		policy: newPolicy(),
+	}
+}
+
+// newPolicy 在 UGC 白名单基础上放行代码高亮的 class、标题锚点与任务列表复选框
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^[a-zA-Z0-9_\- ]+$`)).OnElements("pre", "code", "span")
+	p.AllowAttrs("id").Matching(regexp.MustCompile(`^[\p{L}\p{N}_\-]+$`)).OnElements("h1", "h2", "h3", "h4", "h5", "h6")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	return p
+}
+
+// Render 渲染正文并统计目录、字数、阅读时长与纯文本摘要
+func (r *Renderer) Render(content string) (*domain.Rendered, error) {
+	src := []byte(content)
+	doc := r.parse(src)
+	var buf bytes.Buffer
+	if err := r.md.Renderer().Render(&buf, src, doc); err != nil {
+		return nil, err
+	}
+	out := &domain.Rendered{
+		HTML:         r.policy.Sanitize(buf.String()),
+		TOC:          make([]*domain.Heading, 0),
+		PlainSummary: truncate(summaryText(doc, src), domain.SummaryLength),
+	}
+	var body strings.Builder
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n := n.(type) {
+		case *ast.Heading:
+			id, _ := n.AttributeString("id")
+			idBytes, _ := id.([]byte)
+			title := inlineText(n, src)
+			out.TOC = append(out.TOC, &domain.Heading{Level: n.Level, ID: string(idBytes), Text: title})
+			body.WriteString(title)
+			body.WriteByte('\n')
+			return ast.WalkSkipChildren, nil
+		case *ast.Paragraph, *ast.TextBlock:
+			body.WriteString(inlineText(n, src))
+			body.WriteByte('\n')
+			return ast.WalkSkipChildren, nil
+		case *ast.FencedCodeBlock, *ast.CodeBlock:
+			body.Write(n.Lines().Value(src))
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	cjk, words := countWords(body.String())
+	out.WordCount = cjk + words
+	if out.WordCount > 0 {
+		out.ReadingMinutes = max(1, int(math.Ceil(float64(cjk)/cjkPerMinute+float64(words)/wordPerMinute)))
+	}
+	return out, nil
+}
+
+// Summarize 提取纯文本摘要：仅取段落文字（跳过标题、代码与 HTML），超出 limit 个字符时截断
+func (r *Renderer) Summarize(content string, limit int) string {
+	src := []byte(content)
+	return truncate(summaryText(r.parse(src), src), limit)
+}
+
+func (r *Renderer) parse(src []byte) ast.Node {
+	ctx := parser.NewContext(parser.WithIDs(newHeadingIDs()))
+	return r.md.Parser().Parse(text.NewReader(src), parser.WithContext(ctx))
+}
+
+// summaryText 按顺序拼接所有段落文字（含列表与引用中的段落）
+func summaryText(doc ast.Node, src []byte) string {
+	var parts []string
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.(type) {
+		case *ast.Paragraph, *ast.TextBlock:
+			if s := inlineText(n, src); s != "" {
+				parts = append(parts, s)
+			}
+			return ast.WalkSkipChildren, nil
+		case *ast.Heading, *ast.FencedCodeBlock, *ast.CodeBlock, *ast.HTMLBlock:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.Join(parts, " ")
+}
+
+// inlineText 行内节点的纯文本，去除标记符号与原始 HTML，空白折叠为单个空格
+func inlineText(n ast.Node, src []byte) string {
+	var sb strings.Builder
+	_ = ast.Walk(n, func(c ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch c := c.(type) {
+		case *ast.Text:
+			sb.Write(c.Segment.Value(src))
+			if c.SoftLineBreak() || c.HardLineBreak() {
+				sb.WriteByte(' ')
+			}
+		case *ast.String:
+			sb.Write(c.Value)
+		case *ast.AutoLink:
+			sb.Write(c.Label(src))
+		case *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.Join(strings.Fields(html.UnescapeString(sb.String())), " ")
+}
+
+// truncate 按字符截断，截断时追加省略号
+func truncate(s string, limit int) string {
+	if limit <= 0 || utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimRightFunc(string(runes[:limit]), unicode.IsSpace) + "…"
+}
+
+// countWords 统计中日韩文字数与其余单词数（连续字母数字为一个词）
+func countWords(s string) (cjk, words int) {
+	inWord := false
+	for _, r := range s {
+		switch {
+		case isCJK(r):
+			cjk++
+			inWord = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if !inWord {
+				words++
+			}
+			inWord = true
+		default:
+			inWord = false
+		}
+	}
+	return cjk, words
+}
+
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
+
+// headingIDs 标题锚点生成：保留各语言的字母与数字（goldmark 默认实现会丢弃中文），重复时追加序号
+type headingIDs struct {
+	seen map[string]struct{}
+}
+
+func newHeadingIDs() *headingIDs { return &headingIDs{seen: make(map[string]struct{})} }
+
+func (s *headingIDs) Generate(value []byte, _ ast.NodeKind) []byte {
+	var sb strings.Builder
+	dash := false
+	for _, r := range string(value) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			dash = false
+			sb.WriteRune(unicode.ToLower(r))
+		case unicode.IsSpace(r) || r == '-' || r == '_':
+			dash = true
+		}
+	}
+	id := sb.String()
+	if id == "" {
+		id = "section"
+	}
+	out := id
+	for i := 1; ; i++ {
+		if _, ok := s.seen[out]; !ok {
+			break
+		}
+		out = id + "-" + strconv.Itoa(i)
+	}
+	s.seen[out] = struct{}{}
+	return []byte(out)
+}
+
+// Put 记录正文中显式指定的锚点，避免自动生成的锚点与之重复
+func (s *headingIDs) Put(value []byte) { s.seen[string(value)] = struct{}{} }
+
+// codeBlockRenderer 围栏代码块高亮：输出带 class 的 token（样式由前端引入 chroma 样式表），
+// 未知语言按纯文本输出
+type codeBlockRenderer struct {
+	formatter *chromahtml.Formatter
+	style     *chroma.Style
+}
+
+func newCodeBlockRenderer() *codeBlockRenderer {
+	return &codeBlockRenderer{formatter: chromahtml.New(chromahtml.WithClasses(true)), style: styles.Fallback}
+}
+
+func (r *codeBlockRenderer) RegisterFuncs(reg renderer.NodeRendererFuncRegisterer) {
+	reg.Register(ast.KindFencedCodeBlock, r.renderFencedCode)
+}
+
+func (r *codeBlockRenderer) renderFencedCode(w util.BufWriter, src []byte, node ast.Node, entering bool) (ast.WalkStatus, error) {
+	if !entering {
+		return ast.WalkContinue, nil
+	}
+	n := node.(*ast.FencedCodeBlock)
+	code := string(n.Lines().Value(src))
+	lexer := lexers.Get(string(n.Language(src)))
+	if lexer == nil {
+		lexer = lexers.Fallback
+	}
+	it, err := chroma.Coalesce(lexer).Tokenise(nil, code)
+	if err == nil {
+		var buf bytes.Buffer
+		if err = r.formatter.Format(&buf, r.style, it); err == nil {
+			_, _ = w.Write(buf.Bytes())
+			return ast.WalkSkipChildren, nil
+		}
+	}
+	// 高亮失败时退化为普通代码块
+	_, _ = w.WriteString("<pre><code>")
+	_, _ = w.WriteString(html.EscapeString(code))
+	_, _ = w.WriteString("</code></pre>\n")
+	return ast.WalkSkipChildren, nil
+}
@@ -0,0 +1,66 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderSanitizes(t *testing.T) {
+	r := NewRenderer()
+	tests := []struct {
+		name    string
+		src     string
+		removed []string // 输出中不得出现（不区分大小写）
+		kept    []string // 输出中须保留
+	}{
+		{"script", "<script>alert(1)</script>\n\ntext", []string{"<script", "alert"}, []string{"<p>text</p>"}},
+		{"javascript link", "[x](javascript:alert(1)) <a href=\"JaVaScRiPt:alert(1)\">y</a>", []string{"javascript:", "href"}, []string{"x y"}},
+		{"data link", "[d](data:text/html;base64,PHNjcmlwdD4=) <img src=\"data:image/png;base64,AAAA\">", []string{"data:", "<img"}, []string{"d"}},
+		{"event handlers", "<img src=\"/a.png\" onerror=\"alert(1)\"> <p onclick=\"x()\">p</p>", []string{"onerror", "onclick", "alert"}, []string{`<img src="/a.png">`}},
+		{"iframe", "<iframe src=\"https://evil.example\"></iframe>\n\nafter", []string{"<iframe", "evil.example"}, []string{"<p>after</p>"}},
+		{"heading handler", "<h2 onmouseover=\"alert(1)\">t</h2>", []string{"onmouseover", "alert"}, []string{"<h2>t</h2>"}},
+		{"code highlight classes", "```go\nfunc main() {}\n```\n", nil, []string{`<pre class="chroma">`, `<span class="kd">func</span>`, `<span class="nf">main</span>`}},
+		{"task list", "- [x] done", nil, []string{`<input checked="" disabled="" type="checkbox">`}},
+	}
+	for _, tt := range tests {
+		out, err := r.Render(tt.src)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		lower := strings.ToLower(out.HTML)
+		for _, s := range tt.removed {
+			if strings.Contains(lower, strings.ToLower(s)) {
+				t.Errorf("%s: output contains %q: %s", tt.name, s, out.HTML)
+			}
+		}
+		for _, s := range tt.kept {
+			if !strings.Contains(out.HTML, s) {
+				t.Errorf("%s: output lacks %q: %s", tt.name, s, out.HTML)
+			}
+		}
+	}
+}
+
+func TestRenderTOCAnchors(t *testing.T) {
+	out, err := NewRenderer().Render("## 安装 步骤\n\ntext\n\n### Quick Start\n\n## 安装 步骤\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		level int
+		id    string
+	}{{2, "安装-步骤"}, {3, "quick-start"}, {2, "安装-步骤-1"}}
+	if len(out.TOC) != len(want) {
+		t.Fatalf("toc = %+v", out.TOC)
+	}
+	for i, w := range want {
+		h := out.TOC[i]
+		if h.Level != w.level || h.ID != w.id {
+			t.Errorf("toc[%d] = %+v, want level %d id %q", i, h, w.level, w.id)
+		}
+		// 锚点经过滤后仍保留在正文中，目录链接可跳转
+		if anchor := `id="` + w.id + `"`; !strings.Contains(out.HTML, anchor) {
+			t.Errorf("html lacks anchor %s: %s", anchor, out.HTML)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package infrastructure
+
+import (
+	"strings"
+
+	conf "blog-system/common/pkg/config"
+	"blog-system/services/content/domain"
+	"blog-system/services/content/infrastructure/storage"
+)
+
+// InitMediaStorage 按 media.storage 创建文件存储：s3 为 S3 兼容对象存储，其余为本地文件系统
+func InitMediaStorage(cfg *conf.AppConfig) (domain.MediaStorage, error) {
+	m := cfg.Media
+	if m.Storage == "s3" {
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint: m.S3.Endpoint, Region: m.S3.Region, Bucket: m.S3.Bucket,
+			AccessKey: m.S3.AccessKey, SecretKey: m.S3.SecretKey, PathStyle: m.S3.PathStyle, PublicURL: m.PublicURL,
+		})
+	}
+	dir := m.LocalDir
+	if dir == "" {
+		dir = "data/media"
+	}
+	publicURL := m.PublicURL
+	if publicURL == "" {
+		publicURL = strings.TrimRight(cfg.Feed.Link, "/") + "/api/content/media/file"
+	}
+	return storage.NewLocalStorage(dir, publicURL)
+}
@@ -0,0 +1,153 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"blog-system/common/pkg/aggregate"
+	"blog-system/common/pkg/logger"
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/orm"
+)
+
+// Comment
+// CreateComment 写入评论并回填自增 ID
+func (r *ContentRepository) CreateComment(ctx context.Context, c *domain.Comment) error {
+	res := orm.NewInserter[domain.Comment](r.sess).Values(c).Exec(ctx)
+	if err := res.Err(); err != nil {
+		logger.Log().Error("infrastructure: CreateComment 写入失败: article_id=%d err=%v", c.ArticleID, err)
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	c.ID = id
+	return nil
+}
+
+func (r *ContentRepository) GetComment(ctx context.Context, id int64) (*domain.Comment, error) {
+	return orm.NewSelector[domain.Comment](r.sess).Where(orm.C("ID").Eq(id)).Get(ctx)
+}
+
+// ListCommentThreads 顶层评论分页：已删除的顶层评论在仍有可见回复时保留为占位
+func (r *ContentRepository) ListCommentThreads(ctx context.Context, articleID int64, page, pageSize int) ([]*domain.Comment, int64, error) {
+	pred := orm.Raw("article_id = ? AND root_id = 0 AND status = ? AND (deleted_at IS NULL OR EXISTS "+
+		"(SELECT 1 FROM blog_comment r WHERE r.root_id = blog_comment.id AND r.status = ? AND r.deleted_at IS NULL))",
+		articleID, domain.CommentStatusApproved, domain.CommentStatusApproved).AsPredicate()
+	list, err := orm.NewSelector[domain.Comment](r.sess).
+		Where(pred).
+		OrderBy(orm.Desc("ID")).
+		Limit(pageSize).Offset((page - 1) * pageSize).
+		GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListCommentThreads 查询失败: article_id=%d err=%v", articleID, err)
+		return nil, 0, err
+	}
+	cnt, err := orm.NewSelector[aggregate.Result](r.sess).
+		From(orm.TableOf(&domain.Comment{})).
+		Select(orm.Count("ID").As("count")).
+		Where(pred).
+		Get(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListCommentThreads 统计失败: article_id=%d err=%v", articleID, err)
+		return nil, 0, err
+	}
+	return list, cnt.Count, nil
+}
+
+func (r *ContentRepository) ListCommentReplies(ctx context.Context, rootIDs []int64) ([]*domain.Comment, error) {
+	if len(rootIDs) == 0 {
+		return []*domain.Comment{}, nil
+	}
+	query, args := inClause("root_id", rootIDs)
+	args = append(args, domain.CommentStatusApproved)
+	list, err := orm.NewSelector[domain.Comment](r.sess).
+		Where(orm.Raw(query+" AND status = ? AND deleted_at IS NULL", args...).AsPredicate()).
+		OrderBy(orm.Asc("ID")).
+		GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListCommentReplies 查询失败: %v", err)
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *ContentRepository) ListComments(ctx context.Context, f domain.CommentFilter) ([]*domain.Comment, int64, error) {
+	preds := []orm.Predicate{orm.Raw("deleted_at IS NULL").AsPredicate()}
+	if f.ArticleID > 0 {
+		preds = append(preds, orm.C("ArticleID").Eq(f.ArticleID))
+	}
+	if f.Status != nil {
+		preds = append(preds, orm.C("Status").Eq(*f.Status))
+	}
+	list, err := orm.NewSelector[domain.Comment](r.sess).
+		Where(preds...).
+		OrderBy(orm.Desc("ID")).
+		Limit(f.PageSize).Offset((f.Page - 1) * f.PageSize).
+		GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListComments 查询失败: %v", err)
+		return nil, 0, err
+	}
+	cnt, err := orm.NewSelector[aggregate.Result](r.sess).
+		From(orm.TableOf(&domain.Comment{})).
+		Select(orm.Count("ID").As("count")).
+		Where(preds...).
+		Get(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListComments 统计失败: %v", err)
+		return nil, 0, err
+	}
+	return list, cnt.Count, nil
+}
+
+func (r *ContentRepository) SetCommentStatus(ctx context.Context, ids []int64, status int) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	query, args := inClause("id", ids)
+	res := orm.RawQuery[domain.Comment](r.sess,
+		"UPDATE blog_comment SET status = ?, updated_at = ? WHERE "+query+" AND deleted_at IS NULL",
+		append([]any{status, time.Now()}, args...)...).
+		Exec(ctx)
+	if err := res.Err(); err != nil {
+		logger.Log().Error("infrastructure: SetCommentStatus 更新失败: status=%d err=%v", status, err)
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *ContentRepository) SoftDeleteComment(ctx context.Context, id int64) error {
+	now := time.Now()
+	err := orm.RawQuery[domain.Comment](r.sess,
+		"UPDATE blog_comment SET deleted_at = ?, updated_at = ? WHERE id = ? AND deleted_at IS NULL", now, now, id).
+		Exec(ctx).Err()
+	if err != nil {
+		logger.Log().Error("infrastructure: SoftDeleteComment 删除失败: id=%d err=%v", id, err)
+	}
+	return err
+}
+
+// CountApprovedComments 一次 GROUP BY 统计各文章可见评论数（ID 为 article_id）
+func (r *ContentRepository) CountApprovedComments(ctx context.Context, articleIDs []int64) (map[int64]int64, error) {
+	out := make(map[int64]int64, len(articleIDs))
+	if len(articleIDs) == 0 {
+		return out, nil
+	}
+	query, args := inClause("article_id", articleIDs)
+	args = append(args, domain.CommentStatusApproved)
+	rows, err := orm.RawQuery[aggregate.Result](r.sess,
+		"SELECT article_id AS id, COUNT(*) AS count FROM blog_comment WHERE "+query+
+			" AND status = ? AND deleted_at IS NULL GROUP BY article_id", args...).
+		GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: CountApprovedComments 统计失败: %v", err)
+		return nil, err
+	}
+	for _, row := range rows {
+		out[row.ID] = row.Count
+	}
+	return out, nil
+}
@@ -0,0 +1,156 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"blog-system/common/pkg/aggregate"
+	"blog-system/common/pkg/logger"
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/orm"
+)
+
+// mediaRow blog_media 表行：副本列表以 JSON 存于 variants 列
+type mediaRow struct {
+	ID         int64
+	Hash       string
+	StorageKey string
+	URL        string
+	Name       string
+	MimeType   string
+	Size       int64
+	Width      int
+	Height     int
+	Variants   string
+	UploaderID int64
+	CreatedAt  time.Time
+}
+
+func (mediaRow) TableName() string { return "blog_media" }
+
+func toMediaRow(m *domain.Media) (*mediaRow, error) {
+	variants, err := json.Marshal(m.Variants)
+	if err != nil {
+		return nil, err
+	}
+	return &mediaRow{
+		ID: m.ID, Hash: m.Hash, StorageKey: m.StorageKey, URL: m.URL, Name: m.Name, MimeType: m.MimeType,
+		Size: m.Size, Width: m.Width, Height: m.Height, Variants: string(variants),
+		UploaderID: m.UploaderID, CreatedAt: m.CreatedAt,
+	}, nil
+}
+
+func (row *mediaRow) toDomain() *domain.Media {
+	m := &domain.Media{
+		ID: row.ID, Hash: row.Hash, StorageKey: row.StorageKey, URL: row.URL, Name: row.Name, MimeType: row.MimeType,
+		Size: row.Size, Width: row.Width, Height: row.Height, Variants: make([]*domain.MediaVariant, 0),
+		UploaderID: row.UploaderID, CreatedAt: row.CreatedAt,
+	}
+	if row.Variants != "" {
+		if err := json.Unmarshal([]byte(row.Variants), &m.Variants); err != nil {
+			logger.Log().Error("infrastructure: 解析文件副本失败: id=%d err=%v", row.ID, err)
+		}
+	}
+	return m
+}
+
+// mediaRefPred 引用判断：存储键均含内容哈希，文章封面、正文或用户头像中出现哈希即视为引用（含副本）
+const mediaRefPred = "(EXISTS (SELECT 1 FROM blog_article a WHERE a.cover LIKE CONCAT('%', blog_media.hash, '%') " +
+	"OR a.content LIKE CONCAT('%', blog_media.hash, '%')) " +
+	"OR EXISTS (SELECT 1 FROM blog_user u WHERE u.avatar LIKE CONCAT('%', blog_media.hash, '%')))"
+
+// Media
+// CreateMedia 写入文件元数据并回填自增 ID
+func (r *ContentRepository) CreateMedia(ctx context.Context, m *domain.Media) error {
+	row, err := toMediaRow(m)
+	if err != nil {
+		return err
+	}
+	res := orm.NewInserter[mediaRow](r.sess).Values(row).Exec(ctx)
+	if err := res.Err(); err != nil {
+		logger.Log().Error("infrastructure: CreateMedia 写入失败: hash=%s err=%v", m.Hash, err)
+		return err
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	m.ID = id
+	return nil
+}
+
+func (r *ContentRepository) GetMedia(ctx context.Context, id int64) (*domain.Media, error) {
+	row, err := orm.NewSelector[mediaRow](r.sess).Where(orm.C("ID").Eq(id)).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return row.toDomain(), nil
+}
+
+func (r *ContentRepository) GetMediaByHash(ctx context.Context, hash string) (*domain.Media, error) {
+	row, err := orm.NewSelector[mediaRow](r.sess).Where(orm.C("Hash").Eq(hash)).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return row.toDomain(), nil
+}
+
+func (r *ContentRepository) ListMedia(ctx context.Context, f domain.MediaFilter) ([]*domain.Media, int64, error) {
+	var preds []orm.Predicate
+	if f.MimePrefix != "" {
+		preds = append(preds, orm.Raw("mime_type LIKE CONCAT(?, '%')", f.MimePrefix).AsPredicate())
+	}
+	if f.UploaderID > 0 {
+		preds = append(preds, orm.C("UploaderID").Eq(f.UploaderID))
+	}
+	if f.Orphan {
+		preds = append(preds, orm.Raw("NOT "+mediaRefPred).AsPredicate())
+	}
+	if f.Before != nil {
+		preds = append(preds, orm.C("CreatedAt").Lt(*f.Before))
+	}
+	rows, err := orm.NewSelector[mediaRow](r.sess).
+		Where(preds...).
+		OrderBy(orm.Desc("ID")).
+		Limit(f.PageSize).Offset((f.Page - 1) * f.PageSize).
+		GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListMedia 查询失败: %v", err)
+		return nil, 0, err
+	}
+	cnt, err := orm.NewSelector[aggregate.Result](r.sess).
+		From(orm.TableOf(&mediaRow{})).
+		Select(orm.Count("ID").As("count")).
+		Where(preds...).
+		Get(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListMedia 统计失败: %v", err)
+		return nil, 0, err
+	}
+	list := make([]*domain.Media, 0, len(rows))
+	for _, row := range rows {
+		list = append(list, row.toDomain())
+	}
+	return list, cnt.Count, nil
+}
+
+func (r *ContentRepository) DeleteMedia(ctx context.Context, id int64) error {
+	err := orm.RawQuery[mediaRow](r.sess, "DELETE FROM blog_media WHERE id = ?", id).Exec(ctx).Err()
+	if err != nil {
+		logger.Log().Error("infrastructure: DeleteMedia 删除失败: id=%d err=%v", id, err)
+	}
+	return err
+}
+
+func (r *ContentRepository) MediaReferenced(ctx context.Context, m *domain.Media) (bool, error) {
+	cnt, err := orm.RawQuery[aggregate.Result](r.sess,
+		"SELECT COUNT(*) AS count FROM blog_media WHERE id = ? AND "+mediaRefPred, m.ID).
+		Get(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: MediaReferenced 查询失败: id=%d err=%v", m.ID, err)
+		return false, err
+	}
+	return cnt.Count > 0, nil
+}
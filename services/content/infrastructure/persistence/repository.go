@@ -2,10 +2,13 @@ package infrastructure
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"time"
 
 	"blog-system/common/pkg/aggregate"
 	"blog-system/common/pkg/logger"
+	"blog-system/common/pkg/pagination"
 	"blog-system/services/content/domain"
 
 	"strings"
@@ -14,121 +17,291 @@ import (
 )
 
 type ContentRepository struct {
-	db *orm.DB
+	db         *orm.DB
+	sess       orm.Session // 读写所用会话：默认为 db，Transaction 内为事务
+	tx         *orm.Tx
+	summarizer domain.Summarizer // 未填写摘要时从正文提取纯文本摘要
 }
 
-func NewContentRepository(db *orm.DB) *ContentRepository { return &ContentRepository{db: db} }
+func NewContentRepository(db *orm.DB, summarizer domain.Summarizer) *ContentRepository {
+	return &ContentRepository{db: db, sess: db, summarizer: summarizer}
+}
 
 // Article
+// CreateArticle 写入文章并回填自增 ID
 func (r *ContentRepository) CreateArticle(ctx context.Context, a *domain.Article) error {
-	res := orm.NewInserter[domain.Article](r.db).
+	res := orm.NewInserter[domain.Article](r.sess).
 		Values(a).Exec(ctx)
-	return res.Err()
+	if err := res.Err(); err != nil {
+		return slugConflict(err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return err
+	}
+	a.ID = id
+	return nil
 }
 
 func (r *ContentRepository) GetArticleByID(ctx context.Context, id int64) (*domain.Article, error) {
-	return orm.NewSelector[domain.Article](r.db).Where(orm.C("ID").Eq(id)).Get(ctx)
+	return orm.NewSelector[domain.Article](r.sess).Where(orm.C("ID").Eq(id)).Get(ctx)
 }
 
-func (r *ContentRepository) ListArticles(ctx context.Context, page, pageSize int) ([]*domain.Article, int64, error) {
-	offset := (page - 1) * pageSize
-	list, err := orm.NewSelector[domain.Article](r.db).
-		OrderBy(orm.Desc("PublishedAt")).
-		Limit(pageSize).Offset(offset).GetMulti(ctx)
-	if err != nil {
-		logger.Log().Error("infrastructure: ListArticles 查询失败: %v", err)
-		return nil, 0, err
+func (r *ContentRepository) ListArticlesAfter(ctx context.Context, authorID int64, after *pagination.Cursor, limit int) ([]*domain.Article, *pagination.Cursor, error) {
+	var preds []orm.Predicate
+	if authorID > 0 {
+		preds = append(preds, orm.C("AuthorID").Eq(authorID))
 	}
-	cnt, err := orm.NewSelector[aggregate.Result](r.db).
-		From(orm.TableOf(&domain.Article{})).
-		Select(orm.Count("ID").As("count")).
-		Get(ctx)
+	return r.selectAfter(ctx, "ListArticlesAfter", preds, after, limit)
+}
+
+// ListArticleSummaries 按可见性分页查询文章摘要
+func (r *ContentRepository) ListArticleSummaries(ctx context.Context, v domain.Viewer, page, pageSize int) ([]*domain.ArticleSummary, int64, error) {
+	return r.ListArticleSummariesFiltered(ctx, v, nil, nil, page, pageSize)
+}
+
+// visibleTo 可见性谓词（与 domain.Viewer.CanSee 一致），管理员返回 false 表示不加限制
+func visibleTo(v domain.Viewer, now time.Time) (orm.Predicate, bool) {
+	if v.Admin {
+		return orm.Predicate{}, false
+	}
+	if v.UserID > 0 {
+		return orm.Raw("((status = ? AND published_at <= ?) OR author_id = ?)",
+			domain.ArticleStatusPublished, now, v.UserID).AsPredicate(), true
+	}
+	return orm.Raw("(status = ? AND published_at <= ?)", domain.ArticleStatusPublished, now).AsPredicate(), true
+}
+
+// ListArticleSummariesByIDs 按给定 ID 顺序返回摘要（不存在的 ID 被跳过）
+func (r *ContentRepository) ListArticleSummariesByIDs(ctx context.Context, ids []int64) ([]*domain.ArticleSummary, error) {
+	if len(ids) == 0 {
+		return []*domain.ArticleSummary{}, nil
+	}
+	rows, err := orm.NewSelector[domain.Article](r.sess).Where(inInt64("id", ids)).GetMulti(ctx)
 	if err != nil {
-		logger.Log().Error("infrastructure: ListArticles 统计失败: %v", err)
-		return nil, 0, err
+		logger.Log().Error("infrastructure: ListArticleSummariesByIDs 查询失败: %v", err)
+		return nil, err
 	}
-	return list, cnt.Count, nil
+	byID := make(map[int64]*domain.Article, len(rows))
+	for _, a := range rows {
+		byID[a.ID] = a
+	}
+	ordered := make([]*domain.Article, 0, len(rows))
+	for _, id := range ids {
+		if a, ok := byID[id]; ok {
+			ordered = append(ordered, a)
+		}
+	}
+	return r.buildSummaries(ctx, ordered)
 }
 
-// ListArticleSummaries 仅返回 ID 与 Title（扩展：填充 summary/author/category/tags/cover_url）
-func (r *ContentRepository) ListArticleSummaries(ctx context.Context, page, pageSize int) ([]*domain.ArticleSummary, int64, error) {
-	offset := (page - 1) * pageSize
-	rows, err := orm.NewSelector[domain.Article](r.db).
-		OrderBy(orm.Desc("PublishedAt")).
-		Limit(pageSize).Offset(offset).
+// articleTagIDRow 文章-标签 ID 对
+type articleTagIDRow struct {
+	ArticleID int64
+	TagID     int64
+}
+
+// ListSearchDocs 全部已发布文章的索引文档（文章、标签关联各一次查询）
+func (r *ContentRepository) ListSearchDocs(ctx context.Context) ([]*domain.SearchDoc, error) {
+	rows, err := orm.NewSelector[domain.Article](r.sess).Where(orm.C("Status").Eq(domain.ArticleStatusPublished)).GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListSearchDocs 查询文章失败: %v", err)
+		return nil, err
+	}
+	links, err := orm.RawQuery[articleTagIDRow](r.sess,
+		"SELECT at.article_id, at.tag_id FROM blog_article_tags at JOIN blog_article a ON a.id = at.article_id WHERE a.status = 1").
 		GetMulti(ctx)
 	if err != nil {
-		logger.Log().Error("infrastructure: ListArticleSummaries 查询失败: %v", err)
-		return nil, 0, err
+		logger.Log().Error("infrastructure: ListSearchDocs 查询标签关联失败: %v", err)
+		return nil, err
 	}
-	summaries := make([]*domain.ArticleSummary, 0, len(rows))
+	tagIDs := make(map[int64][]int64, len(rows))
+	for _, l := range links {
+		tagIDs[l.ArticleID] = append(tagIDs[l.ArticleID], l.TagID)
+	}
+	docs := make([]*domain.SearchDoc, 0, len(rows))
 	for _, a := range rows {
-		if a == nil {
-			continue
-		}
-		s := &domain.ArticleSummary{ID: a.ID, Title: a.Title, AuthorID: a.AuthorID}
-		// summary：若空则取 content 前 120 字符（近似）
-		if a.Summary != nil && a.Summary.Valid && a.Summary.String != "" {
-			s.Summary = a.Summary.String
-		} else {
-			runes := []rune(a.Content)
-			if len(runes) > 120 {
-				s.Summary = string(runes[:120])
-			} else {
-				s.Summary = string(runes)
-			}
-		}
-		// cover_url：取 Cover 字段
-		if a.Cover != nil && a.Cover.Valid {
-			s.CoverURL = a.Cover.String
+		d := domain.NewSearchDoc(a)
+		d.TagIDs = tagIDs[a.ID]
+		docs = append(docs, d)
+	}
+	return docs, nil
+}
+
+// ListSearchDocChanges 变更文章一次查询，已发布文章的标签一次批量查询
+func (r *ContentRepository) ListSearchDocChanges(ctx context.Context, since time.Time) (*domain.SearchDocChanges, error) {
+	return r.searchDocChanges(ctx, "ListSearchDocChanges", orm.Raw("updated_at >= ?", since).AsPredicate())
+}
+
+// ListSearchDocsByIDs 指定文章的索引文档，不存在（已删除）的 ID 计入 Removed
+func (r *ContentRepository) ListSearchDocsByIDs(ctx context.Context, ids []int64) (*domain.SearchDocChanges, error) {
+	out, err := r.searchDocChanges(ctx, "ListSearchDocsByIDs", inInt64("id", ids))
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[int64]struct{}, len(out.Docs)+len(out.Removed))
+	for _, d := range out.Docs {
+		found[d.ID] = struct{}{}
+	}
+	for _, id := range out.Removed {
+		found[id] = struct{}{}
+	}
+	for _, id := range ids {
+		if _, ok := found[id]; !ok {
+			out.Removed = append(out.Removed, id)
 		}
-		// category 简要
-		if a.CategoryID > 0 {
-			c, er := orm.NewSelector[domain.Category](r.db).Where(orm.C("ID").Eq(a.CategoryID)).Get(ctx)
-			if er == nil && c != nil {
-				s.Category = &domain.CategoryBrief{ID: c.ID, Name: c.Name, Slug: c.Slug}
-			}
+	}
+	return out, nil
+}
+
+// searchDocChanges 按条件读取文章，已发布的组装索引文档（标签一次批量查询），其余记为待移除
+func (r *ContentRepository) searchDocChanges(ctx context.Context, op string, where orm.Predicate) (*domain.SearchDocChanges, error) {
+	rows, err := orm.NewSelector[domain.Article](r.sess).Where(where).GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: %s 查询文章失败: %v", op, err)
+		return nil, err
+	}
+	out := &domain.SearchDocChanges{}
+	published := make([]int64, 0, len(rows))
+	for _, a := range rows {
+		if a.UpdatedAt.After(out.Watermark) {
+			out.Watermark = a.UpdatedAt
 		}
-		// tags 简要
-		ts, er2 := r.ListArticleTags(ctx, a.ID)
-		if er2 == nil && len(ts) > 0 {
-			for _, t := range ts {
-				color := ""
-				if t.Color != nil && t.Color.Valid {
-					color = t.Color.String
-				}
-				s.Tags = append(s.Tags, &domain.TagBrief{ID: t.ID, Name: t.Name, Slug: t.Slug, Color: color})
-			}
+		if a.Status != domain.ArticleStatusPublished {
+			out.Removed = append(out.Removed, a.ID)
+			continue
 		}
-		summaries = append(summaries, s)
+		published = append(published, a.ID)
+		out.Docs = append(out.Docs, domain.NewSearchDoc(a))
 	}
-	cnt, err := orm.NewSelector[aggregate.Result](r.db).
-		From(orm.TableOf(&domain.Article{})).
-		Select(orm.Count("ID").As("count")).
-		Get(ctx)
+	if len(published) == 0 {
+		return out, nil
+	}
+	query, args := inClause("article_id", published)
+	links, err := orm.RawQuery[articleTagIDRow](r.sess,
+		"SELECT article_id, tag_id FROM blog_article_tags WHERE "+query, args...).GetMulti(ctx)
 	if err != nil {
-		logger.Log().Error("infrastructure: ListArticleSummaries 统计失败: %v", err)
-		return nil, 0, err
+		logger.Log().Error("infrastructure: %s 查询标签关联失败: %v", op, err)
+		return nil, err
 	}
-	return summaries, cnt.Count, nil
+	tagIDs := make(map[int64][]int64, len(published))
+	for _, l := range links {
+		tagIDs[l.ArticleID] = append(tagIDs[l.ArticleID], l.TagID)
+	}
+	for _, d := range out.Docs {
+		d.TagIDs = tagIDs[d.ID]
+	}
+	return out, nil
 }
 
-// SearchArticleSummaries 模糊搜索标题或摘要（扩展同上）
-func (r *ContentRepository) SearchArticleSummaries(ctx context.Context, keyword string, page, pageSize int) ([]*domain.ArticleSummary, int64, error) {
-	offset := (page - 1) * pageSize
-	if keyword == "" {
-		keyword = "%"
-	} else {
-		keyword = "%" + keyword + "%"
+// idRow 单列 ID
+type idRow struct {
+	ID int64
+}
+
+func (r *ContentRepository) ListPublishedArticleIDs(ctx context.Context) ([]int64, error) {
+	rows, err := orm.RawQuery[idRow](r.sess, "SELECT id FROM blog_article WHERE status = ?", domain.ArticleStatusPublished).GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListPublishedArticleIDs 查询失败: %v", err)
+		return nil, err
 	}
-	rows, err := orm.NewSelector[domain.Article](r.db).
-		Where(orm.Raw("Title LIKE ? OR Summary LIKE ?", keyword, keyword).AsPredicate()).
-		OrderBy(orm.Desc("PublishedAt")).
-		Limit(pageSize).Offset(offset).
-		GetMulti(ctx)
+	ids := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	return ids, nil
+}
+
+// relatedRow 相关文章候选（不含标签）
+type relatedRow struct {
+	ID          int64
+	CategoryID  int64
+	IsRecommend bool
+}
+
+// ListRelatedCandidates 文章、标签关联各一次查询
+func (r *ContentRepository) ListRelatedCandidates(ctx context.Context) ([]*domain.RelatedCandidate, error) {
+	now := time.Now()
+	rows, err := orm.RawQuery[relatedRow](r.sess,
+		"SELECT id, category_id, is_recommend FROM blog_article WHERE status = ? AND published_at <= ?",
+		domain.ArticleStatusPublished, now).GetMulti(ctx)
 	if err != nil {
-		logger.Log().Error("infrastructure: SearchArticleSummaries 查询失败: %v", err)
-		return nil, 0, err
+		logger.Log().Error("infrastructure: ListRelatedCandidates 查询文章失败: %v", err)
+		return nil, err
+	}
+	links, err := orm.RawQuery[articleTagIDRow](r.sess,
+		"SELECT at.article_id, at.tag_id FROM blog_article_tags at JOIN blog_article a ON a.id = at.article_id "+
+			"WHERE a.status = ? AND a.published_at <= ?", domain.ArticleStatusPublished, now).GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListRelatedCandidates 查询标签关联失败: %v", err)
+		return nil, err
+	}
+	tagIDs := make(map[int64][]int64, len(rows))
+	for _, l := range links {
+		tagIDs[l.ArticleID] = append(tagIDs[l.ArticleID], l.TagID)
+	}
+	list := make([]*domain.RelatedCandidate, 0, len(rows))
+	for _, row := range rows {
+		list = append(list, &domain.RelatedCandidate{
+			ID: row.ID, CategoryID: row.CategoryID, IsRecommend: row.IsRecommend, TagIDs: tagIDs[row.ID],
+		})
+	}
+	return list, nil
+}
+
+// articleTagRow 文章-标签联表查询结果
+type articleTagRow struct {
+	ArticleID int64
+	TagID     int64
+	Name      string
+	Slug      string
+	Color     *sql.NullString
+}
+
+// buildSummaries 组装摘要：分类与标签各一次批量查询，查询次数与行数无关
+func (r *ContentRepository) buildSummaries(ctx context.Context, rows []*domain.Article) ([]*domain.ArticleSummary, error) {
+	articleIDs := make([]int64, 0, len(rows))
+	categoryIDs := make([]int64, 0, len(rows))
+	seenCat := make(map[int64]struct{}, len(rows))
+	for _, a := range rows {
+		if a == nil {
+			continue
+		}
+		articleIDs = append(articleIDs, a.ID)
+		if _, ok := seenCat[a.CategoryID]; a.CategoryID > 0 && !ok {
+			seenCat[a.CategoryID] = struct{}{}
+			categoryIDs = append(categoryIDs, a.CategoryID)
+		}
+	}
+	categories := make(map[int64]*domain.CategoryBrief, len(categoryIDs))
+	if len(categoryIDs) > 0 {
+		list, err := orm.NewSelector[domain.Category](r.sess).Where(inInt64("id", categoryIDs)).GetMulti(ctx)
+		if err != nil {
+			logger.Log().Error("infrastructure: buildSummaries 批量查询分类失败: %v", err)
+			return nil, err
+		}
+		for _, c := range list {
+			categories[c.ID] = &domain.CategoryBrief{ID: c.ID, Name: c.Name, Slug: c.Slug}
+		}
+	}
+	tags := make(map[int64][]*domain.TagBrief, len(articleIDs))
+	if len(articleIDs) > 0 {
+		query, args := inClause("at.article_id", articleIDs)
+		list, err := orm.RawQuery[articleTagRow](r.sess,
+			"SELECT at.article_id, t.id AS tag_id, t.name, t.slug, t.color FROM blog_article_tags at "+
+				"JOIN blog_tag t ON t.id = at.tag_id WHERE "+query+" ORDER BY at.article_id, t.id", args...).
+			GetMulti(ctx)
+		if err != nil {
+			logger.Log().Error("infrastructure: buildSummaries 批量查询标签失败: %v", err)
+			return nil, err
+		}
+		for _, t := range list {
+			color := ""
+			if t.Color != nil && t.Color.Valid {
+				color = t.Color.String
+			}
+			tags[t.ArticleID] = append(tags[t.ArticleID], &domain.TagBrief{ID: t.TagID, Name: t.Name, Slug: t.Slug, Color: color})
+		}
 	}
 	summaries := make([]*domain.ArticleSummary, 0, len(rows))
 	for _, a := range rows {
@@ -136,81 +309,177 @@ func (r *ContentRepository) SearchArticleSummaries(ctx context.Context, keyword
 			continue
 		}
 		s := &domain.ArticleSummary{ID: a.ID, Title: a.Title, AuthorID: a.AuthorID}
+		// summary：若空则从正文提取纯文本摘要（去除 Markdown 标记）
 		if a.Summary != nil && a.Summary.Valid && a.Summary.String != "" {
 			s.Summary = a.Summary.String
 		} else {
-			runes := []rune(a.Content)
-			if len(runes) > 120 {
-				s.Summary = string(runes[:120])
-			} else {
-				s.Summary = string(runes)
-			}
+			s.Summary = r.summarizer.Summarize(a.Content, domain.SummaryLength)
 		}
+		// cover_url：取 Cover 字段
 		if a.Cover != nil && a.Cover.Valid {
 			s.CoverURL = a.Cover.String
 		}
-		if a.CategoryID > 0 {
-			c, er := orm.NewSelector[domain.Category](r.db).Where(orm.C("ID").Eq(a.CategoryID)).Get(ctx)
-			if er == nil && c != nil {
-				s.Category = &domain.CategoryBrief{ID: c.ID, Name: c.Name, Slug: c.Slug}
-			}
-		}
-		ts, er2 := r.ListArticleTags(ctx, a.ID)
-		if er2 == nil && len(ts) > 0 {
-			for _, t := range ts {
-				color := ""
-				if t.Color != nil && t.Color.Valid {
-					color = t.Color.String
-				}
-				s.Tags = append(s.Tags, &domain.TagBrief{ID: t.ID, Name: t.Name, Slug: t.Slug, Color: color})
-			}
-		}
+		s.Category = categories[a.CategoryID]
+		s.Tags = tags[a.ID]
 		summaries = append(summaries, s)
 	}
-	allCnt, err := orm.NewSelector[aggregate.Result](r.db).From(orm.TableOf(&domain.Article{})).
-		Where(orm.Raw("Title LIKE ? OR Summary LIKE ?", keyword, keyword).AsPredicate()).
-		Select(orm.Count("ID").As("count")).
-		Get(ctx)
-	if err != nil {
-		logger.Log().Error("infrastructure: SearchArticleSummaries 获取总数失败: %v", err)
-		return nil, 0, err
+	return summaries, nil
+}
+
+// inClause 生成 col IN (?, ?, ...) 及参数
+func inClause(col string, ids []int64) (string, []any) {
+	marks := make([]string, 0, len(ids))
+	args := make([]any, 0, len(ids))
+	for _, id := range ids {
+		marks = append(marks, "?")
+		args = append(args, id)
 	}
-	return summaries, allCnt.Count, nil
+	return col + " IN (" + strings.Join(marks, ", ") + ")", args
+}
+
+// inInt64 IN 查询谓词
+func inInt64(col string, ids []int64) orm.Predicate {
+	query, args := inClause(col, ids)
+	return orm.Raw(query, args...).AsPredicate()
 }
 
-// UpdateArticle 更新文章
+// UpdateArticle 条件更新：WHERE 带上期望版本，未命中时区分文章不存在与版本冲突
 func (r *ContentRepository) UpdateArticle(ctx context.Context, a *domain.Article) error {
-	return orm.NewUpdater[domain.Article](r.db).
-		Set(orm.C("Title"), a.Title).
-		Set(orm.C("Slug"), a.Slug).
-		Set(orm.C("Content"), a.Content).
-		Set(orm.C("Summary"), a.Summary).
-		Set(orm.C("Cover"), a.Cover).
-		Set(orm.C("CategoryID"), a.CategoryID).
-		Set(orm.C("Status"), a.Status).
-		Set(orm.C("IsTop"), a.IsTop).
-		Set(orm.C("IsRecommend"), a.IsRecommend).
-		Set(orm.C("MetaTitle"), a.MetaTitle).
-		Set(orm.C("MetaDesc"), a.MetaDesc).
-		Set(orm.C("MetaKeywords"), a.MetaKeywords).
-		Set(orm.C("PublishedAt"), a.PublishedAt).
-		Set(orm.C("UpdatedAt"), a.UpdatedAt).
-		Where(orm.C("ID").Eq(a.ID)).
-		Exec(ctx).Err()
+	res := orm.RawQuery[domain.Article](r.sess,
+		"UPDATE blog_article SET title = ?, slug = ?, content = ?, summary = ?, cover = ?, category_id = ?, status = ?, "+
+			"is_top = ?, is_recommend = ?, meta_title = ?, meta_desc = ?, meta_keywords = ?, published_at = ?, updated_at = ?, "+
+			"version = version + 1 WHERE id = ? AND version = ?",
+		a.Title, a.Slug, a.Content, a.Summary, a.Cover, a.CategoryID, a.Status,
+		a.IsTop, a.IsRecommend, a.MetaTitle, a.MetaDesc, a.MetaKeywords, a.PublishedAt, a.UpdatedAt,
+		a.ID, a.Version).
+		Exec(ctx)
+	if err := res.Err(); err != nil {
+		logger.Log().Error("infrastructure: UpdateArticle 更新失败: id=%d err=%v", a.ID, err)
+		return slugConflict(err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		if _, err := r.GetArticleByID(ctx, a.ID); err != nil {
+			return err
+		}
+		return domain.ErrVersionConflict
+	}
+	a.Version++
+	return nil
+}
+
+// ListScheduledArticles 定时发布的文章（按发布时间升序），dueBefore 非零时仅返回已到期的
+func (r *ContentRepository) ListScheduledArticles(ctx context.Context, dueBefore time.Time) ([]*domain.Article, error) {
+	preds := []orm.Predicate{orm.C("Status").Eq(domain.ArticleStatusScheduled)}
+	if !dueBefore.IsZero() {
+		preds = append(preds, orm.Raw("published_at <= ?", dueBefore).AsPredicate())
+	}
+	list, err := orm.NewSelector[domain.Article](r.sess).
+		Where(preds...).
+		OrderBy(orm.Asc("PublishedAt")).
+		GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListScheduledArticles 查询失败: %v", err)
+		return nil, err
+	}
+	return list, nil
+}
+
+// TransitArticleStatus 条件更新状态，多副本并发执行时仅一方成功
+func (r *ContentRepository) TransitArticleStatus(ctx context.Context, id int64, from, to int) (bool, error) {
+	res := orm.RawQuery[domain.Article](r.sess,
+		"UPDATE blog_article SET status = ?, updated_at = ?, version = version + 1 WHERE id = ? AND status = ?",
+		to, time.Now(), id, from).
+		Exec(ctx)
+	if err := res.Err(); err != nil {
+		logger.Log().Error("infrastructure: TransitArticleStatus 更新失败: id=%d err=%v", id, err)
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
 }
 
 func (r *ContentRepository) DeleteArticle(ctx context.Context, id int64) error {
-	// 物理删除文章，并删除文章标签关联
-	if err := orm.NewDeleter[domain.Article](r.db).Where(orm.C("ID").Eq(id)).Exec(ctx).Err(); err != nil {
-		logger.Log().Error("infrastructure: DeleteArticle 删除文章失败: %v", err)
-		return err
+	// 物理删除文章，并删除文章标签关联、历史版本与旧别名，全部在同一事务中完成
+	return r.inTx(ctx, func(ctx context.Context, tx orm.Session) error {
+		if err := orm.NewDeleter[domain.ArticleTag](tx).Where(orm.C("ArticleID").Eq(id)).Exec(ctx).Err(); err != nil {
+			logger.Log().Error("infrastructure: DeleteArticle 删除标签关联失败: %v", err)
+			return err
+		}
+		if err := orm.NewDeleter[domain.ArticleRevision](tx).Where(orm.C("ArticleID").Eq(id)).Exec(ctx).Err(); err != nil {
+			logger.Log().Error("infrastructure: DeleteArticle 删除历史版本失败: %v", err)
+			return err
+		}
+		if err := deleteSlugRedirects(ctx, tx, domain.SlugKindArticle, id); err != nil {
+			logger.Log().Error("infrastructure: DeleteArticle 删除旧别名失败: %v", err)
+			return err
+		}
+		if err := orm.NewDeleter[domain.Article](tx).Where(orm.C("ID").Eq(id)).Exec(ctx).Err(); err != nil {
+			logger.Log().Error("infrastructure: DeleteArticle 删除文章失败: %v", err)
+			return err
+		}
+		return nil
+	})
+}
+
+// Revision
+func (r *ContentRepository) CreateRevision(ctx context.Context, rev *domain.ArticleRevision) error {
+	return orm.NewInserter[domain.ArticleRevision](r.sess).Values(rev).Exec(ctx).Err()
+}
+
+// ListRevisions 文章的历史版本（新到旧）
+func (r *ContentRepository) ListRevisions(ctx context.Context, articleID int64) ([]*domain.ArticleRevision, error) {
+	list, err := orm.NewSelector[domain.ArticleRevision](r.sess).
+		Where(orm.C("ArticleID").Eq(articleID)).
+		OrderBy(orm.Desc("ID")).
+		GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListRevisions 查询失败: article_id=%d err=%v", articleID, err)
+		return nil, err
 	}
-	return orm.NewDeleter[domain.ArticleTag](r.db).Where(orm.C("ArticleID").Eq(id)).Exec(ctx).Err()
+	return list, nil
+}
+
+func (r *ContentRepository) GetRevision(ctx context.Context, id int64) (*domain.ArticleRevision, error) {
+	return orm.NewSelector[domain.ArticleRevision](r.sess).Where(orm.C("ID").Eq(id)).Get(ctx)
+}
+
+// PruneRevisions 以第 keep 新的版本 ID 为界删除更早的版本
+func (r *ContentRepository) PruneRevisions(ctx context.Context, articleID int64, keep int) (int64, error) {
+	if keep <= 0 {
+		return 0, nil
+	}
+	edge, err := orm.NewSelector[domain.ArticleRevision](r.sess).
+		Where(orm.C("ArticleID").Eq(articleID)).
+		OrderBy(orm.Desc("ID")).
+		Limit(1).Offset(keep - 1).
+		Get(ctx)
+	if errors.Is(err, orm.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		logger.Log().Error("infrastructure: PruneRevisions 查询失败: article_id=%d err=%v", articleID, err)
+		return 0, err
+	}
+	res := orm.NewDeleter[domain.ArticleRevision](r.sess).
+		Where(orm.C("ArticleID").Eq(articleID), orm.C("ID").Lt(edge.ID)).
+		Exec(ctx)
+	if err := res.Err(); err != nil {
+		logger.Log().Error("infrastructure: PruneRevisions 删除失败: article_id=%d err=%v", articleID, err)
+		return 0, err
+	}
+	return res.RowsAffected()
 }
 
 // CountArticles 数量
 func (r *ContentRepository) CountArticles(ctx context.Context) (int64, error) {
-	cnt, err := orm.NewSelector[aggregate.Result](r.db).
+	cnt, err := orm.NewSelector[aggregate.Result](r.sess).
 		From(orm.TableOf(&domain.Article{})).
 		Select(orm.Count("ID").As("count")).
 		Get(ctx)
@@ -221,9 +490,9 @@ func (r *ContentRepository) CountArticles(ctx context.Context) (int64, error) {
 	return cnt.Count, nil
 }
 
-// Category（单级）
+// Category（树形，物化路径）
 func (r *ContentRepository) ListAllCategories(ctx context.Context) ([]*domain.Category, error) {
-	list, err := orm.NewSelector[domain.Category](r.db).OrderBy(orm.Asc("Sort")).GetMulti(ctx)
+	list, err := orm.NewSelector[domain.Category](r.sess).OrderBy(orm.Asc("Sort")).GetMulti(ctx)
 	if err != nil {
 		logger.Log().Error("infrastructure: ListAllCategories 查询失败: %v", err)
 		return nil, err
@@ -233,12 +502,12 @@ func (r *ContentRepository) ListAllCategories(ctx context.Context) ([]*domain.Ca
 
 func (r *ContentRepository) ListCategories(ctx context.Context, page, pageSize int) ([]*domain.Category, int64, error) {
 	offset := (page - 1) * pageSize
-	list, err := orm.NewSelector[domain.Category](r.db).OrderBy(orm.Asc("Sort")).Limit(pageSize).Offset(offset).GetMulti(ctx)
+	list, err := orm.NewSelector[domain.Category](r.sess).OrderBy(orm.Asc("Sort")).Limit(pageSize).Offset(offset).GetMulti(ctx)
 	if err != nil {
 		logger.Log().Error("infrastructure: ListCategories 查询失败: %v", err)
 		return nil, 0, err
 	}
-	cnt, err := orm.NewSelector[aggregate.Result](r.db).
+	cnt, err := orm.NewSelector[aggregate.Result](r.sess).
 		From(orm.TableOf(&domain.Category{})).
 		Select(orm.Count("ID").As("count")).
 		Get(ctx)
@@ -250,7 +519,7 @@ func (r *ContentRepository) ListCategories(ctx context.Context, page, pageSize i
 }
 
 func (r *ContentRepository) CountCategories(ctx context.Context) (int64, error) {
-	cnt, err := orm.NewSelector[aggregate.Result](r.db).
+	cnt, err := orm.NewSelector[aggregate.Result](r.sess).
 		From(orm.TableOf(&domain.Category{})).
 		Select(orm.Count("ID").As("count")).
 		Get(ctx)
@@ -261,57 +530,220 @@ func (r *ContentRepository) CountCategories(ctx context.Context) (int64, error)
 	return cnt.Count, nil
 }
 
+func (r *ContentRepository) GetCategoryByID(ctx context.Context, id int64) (*domain.Category, error) {
+	return orm.NewSelector[domain.Category](r.sess).Where(orm.C("ID").Eq(id)).Get(ctx)
+}
+
+// CreateCategory 插入后以自增 ID 补全物化路径，两步在同一事务中完成
 func (r *ContentRepository) CreateCategory(ctx context.Context, c *domain.Category) error {
-	return orm.NewInserter[domain.Category](r.db).Values(c).Exec(ctx).Err()
+	parentPath := c.Path
+	return slugConflict(r.inTx(ctx, func(ctx context.Context, tx orm.Session) error {
+		res := orm.NewInserter[domain.Category](tx).Values(c).Exec(ctx)
+		if err := res.Err(); err != nil {
+			return err
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return err
+		}
+		c.ID, c.Path = id, domain.ChildPath(parentPath, id)
+		return orm.RawQuery[domain.Category](tx, "UPDATE blog_category SET path = ? WHERE id = ?", c.Path, c.ID).Exec(ctx).Err()
+	}))
+}
+
+// MoveCategory 修改上级并重写子树（含自身）的路径前缀
+func (r *ContentRepository) MoveCategory(ctx context.Context, c *domain.Category, parent *domain.Category) error {
+	var parentID int64
+	parentPath := ""
+	if parent != nil {
+		parentID, parentPath = parent.ID, parent.Path
+	}
+	oldPath, newPath := c.Path, domain.ChildPath(parentPath, c.ID)
+	err := r.inTx(ctx, func(ctx context.Context, tx orm.Session) error {
+		if err := orm.RawQuery[domain.Category](tx,
+			"UPDATE blog_category SET parent_id = ?, updated_at = ? WHERE id = ?", parentID, time.Now(), c.ID).
+			Exec(ctx).Err(); err != nil {
+			return err
+		}
+		return orm.RawQuery[domain.Category](tx,
+			"UPDATE blog_category SET path = CONCAT(?, SUBSTRING(path, ?)) WHERE path LIKE ?",
+			newPath, len(oldPath)+1, oldPath+"%").Exec(ctx).Err()
+	})
+	if err != nil {
+		logger.Log().Error("infrastructure: MoveCategory 移动失败: id=%d err=%v", c.ID, err)
+		return err
+	}
+	c.ParentID, c.Path = parentID, newPath
+	return nil
+}
+
+func (r *ContentRepository) CountChildCategories(ctx context.Context, id int64) (int64, error) {
+	cnt, err := orm.NewSelector[aggregate.Result](r.sess).
+		From(orm.TableOf(&domain.Category{})).
+		Select(orm.Count("ID").As("count")).
+		Where(orm.C("ParentID").Eq(id)).
+		Get(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: CountChildCategories 统计失败: %v", err)
+		return 0, err
+	}
+	return cnt.Count, nil
 }
 
 func (r *ContentRepository) UpdateCategory(ctx context.Context, c *domain.Category) error {
-	return orm.NewUpdater[domain.Category](r.db).
-		Set(orm.C("Name"), c.Name).
-		Set(orm.C("Slug"), c.Slug).
-		Set(orm.C("Description"), c.Description).
-		Set(orm.C("Sort"), c.Sort).
-		Set(orm.C("UpdatedAt"), c.UpdatedAt).
-		Where(orm.C("ID").Eq(c.ID)).
-		Exec(ctx).Err()
+	err := orm.RawQuery[domain.Category](r.sess,
+		"UPDATE blog_category SET name = ?, slug = ?, description = ?, sort = ?, updated_at = ? WHERE id = ?",
+		c.Name, c.Slug, c.Description, c.Sort, c.UpdatedAt, c.ID).Exec(ctx).Err()
+	return slugConflict(err)
 }
 
-func (r *ContentRepository) DeleteCategory(ctx context.Context, id int64) error {
-	return orm.NewDeleter[domain.Category](r.db).Where(orm.C("ID").Eq(id)).Exec(ctx).Err()
+func (r *ContentRepository) ListArticleIDsByCategory(ctx context.Context, categoryID int64) ([]int64, error) {
+	rows, err := orm.RawQuery[aggregate.Result](r.sess,
+		"SELECT id FROM blog_article WHERE category_id = ? ORDER BY id", categoryID).GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListArticleIDsByCategory 查询失败: %v", err)
+		return nil, err
+	}
+	return resultIDs(rows), nil
+}
+
+// DeleteCategory 转移文章（版本号递增）、删除旧别名与分类在同一事务中完成；
+// 不依赖外键级联，避免误删文章
+func (r *ContentRepository) DeleteCategory(ctx context.Context, id, targetID int64) error {
+	err := r.inTx(ctx, func(ctx context.Context, tx orm.Session) error {
+		if targetID > 0 {
+			if err := orm.RawQuery[domain.Article](tx,
+				"UPDATE blog_article SET category_id = ?, version = version + 1, updated_at = ? WHERE category_id = ?",
+				targetID, time.Now(), id).Exec(ctx).Err(); err != nil {
+				return err
+			}
+		} else {
+			cnt, err := orm.RawQuery[aggregate.Result](tx,
+				"SELECT COUNT(*) AS count FROM blog_article WHERE category_id = ? FOR UPDATE", id).Get(ctx)
+			if err != nil {
+				return err
+			}
+			if cnt.Count > 0 {
+				return domain.ErrCategoryNotEmpty
+			}
+		}
+		if err := deleteSlugRedirects(ctx, tx, domain.SlugKindCategory, id); err != nil {
+			return err
+		}
+		return orm.RawQuery[domain.Category](tx, "DELETE FROM blog_category WHERE id = ?", id).Exec(ctx).Err()
+	})
+	if err != nil && !errors.Is(err, domain.ErrCategoryNotEmpty) {
+		logger.Log().Error("infrastructure: DeleteCategory 删除失败: id=%d err=%v", id, err)
+	}
+	return err
 }
 
 // Tag
+func (r *ContentRepository) GetTagByID(ctx context.Context, id int64) (*domain.Tag, error) {
+	return orm.NewSelector[domain.Tag](r.sess).Where(orm.C("ID").Eq(id)).Get(ctx)
+}
+
 func (r *ContentRepository) CreateTag(ctx context.Context, t *domain.Tag) error {
-	return orm.NewInserter[domain.Tag](r.db).Values(t).Exec(ctx).Err()
+	return slugConflict(orm.NewInserter[domain.Tag](r.sess).Values(t).Exec(ctx).Err())
 }
 
 func (r *ContentRepository) UpdateTag(ctx context.Context, t *domain.Tag) error {
-	return orm.NewUpdater[domain.Tag](r.db).
-		Set(orm.C("Name"), t.Name).
-		Set(orm.C("Slug"), t.Slug).
-		Set(orm.C("Color"), t.Color).
-		Set(orm.C("UpdatedAt"), t.UpdatedAt).
-		Where(orm.C("ID").Eq(t.ID)).
-		Exec(ctx).Err()
+	err := orm.RawQuery[domain.Tag](r.sess,
+		"UPDATE blog_tag SET name = ?, slug = ?, color = ?, updated_at = ? WHERE id = ?",
+		t.Name, t.Slug, t.Color, t.UpdatedAt, t.ID).Exec(ctx).Err()
+	return slugConflict(err)
 }
 
 func (r *ContentRepository) DeleteTag(ctx context.Context, id int64) error {
-	// 删除标签与文章关联，再删除标签
-	if err := orm.NewDeleter[domain.ArticleTag](r.db).Where(orm.C("TagID").Eq(id)).Exec(ctx).Err(); err != nil {
-		logger.Log().Error("infrastructure: DeleteTag 删除关联失败: %v", err)
-		return err
+	// 删除标签与文章关联、旧别名，再删除标签
+	err := r.inTx(ctx, func(ctx context.Context, tx orm.Session) error {
+		if err := orm.RawQuery[domain.ArticleTag](tx,
+			"DELETE FROM blog_article_tags WHERE tag_id = ?", id).Exec(ctx).Err(); err != nil {
+			return err
+		}
+		if err := deleteSlugRedirects(ctx, tx, domain.SlugKindTag, id); err != nil {
+			return err
+		}
+		return orm.RawQuery[domain.Tag](tx, "DELETE FROM blog_tag WHERE id = ?", id).Exec(ctx).Err()
+	})
+	if err != nil {
+		logger.Log().Error("infrastructure: DeleteTag 删除失败: id=%d err=%v", id, err)
 	}
-	return orm.NewDeleter[domain.Tag](r.db).Where(orm.C("ID").Eq(id)).Exec(ctx).Err()
+	return err
+}
+
+func (r *ContentRepository) MergeTag(ctx context.Context, source *domain.Tag, targetID int64) error {
+	now := time.Now()
+	err := r.inTx(ctx, func(ctx context.Context, tx orm.Session) error {
+		// 已带有目标标签的文章由唯一键跳过
+		if err := orm.RawQuery[domain.ArticleTag](tx,
+			"INSERT IGNORE INTO blog_article_tags (article_id, tag_id, created_at) "+
+				"SELECT article_id, ?, ? FROM blog_article_tags WHERE tag_id = ?",
+			targetID, now, source.ID).Exec(ctx).Err(); err != nil {
+			return err
+		}
+		if err := orm.RawQuery[domain.ArticleTag](tx,
+			"DELETE FROM blog_article_tags WHERE tag_id = ?", source.ID).Exec(ctx).Err(); err != nil {
+			return err
+		}
+		if err := orm.RawQuery[domain.SlugRedirect](tx,
+			"UPDATE blog_slug_redirect SET target_id = ? WHERE kind = ? AND target_id = ?",
+			targetID, domain.SlugKindTag, source.ID).Exec(ctx).Err(); err != nil {
+			return err
+		}
+		if err := orm.RawQuery[domain.Tag](tx, "DELETE FROM blog_tag WHERE id = ?", source.ID).Exec(ctx).Err(); err != nil {
+			return err
+		}
+		// 标签删除后其别名才可作为旧别名记录
+		return orm.RawQuery[domain.SlugRedirect](tx,
+			"INSERT INTO blog_slug_redirect (kind, slug, target_id, created_at) VALUES (?, ?, ?, ?) "+
+				"ON DUPLICATE KEY UPDATE target_id = VALUES(target_id), created_at = VALUES(created_at)",
+			domain.SlugKindTag, source.Slug, targetID, now).Exec(ctx).Err()
+	})
+	if err != nil {
+		logger.Log().Error("infrastructure: MergeTag 合并失败: source=%d target=%d err=%v", source.ID, targetID, err)
+	}
+	return err
+}
+
+func (r *ContentRepository) ListArticleIDsByTag(ctx context.Context, tagID int64) ([]int64, error) {
+	rows, err := orm.RawQuery[aggregate.Result](r.sess,
+		"SELECT article_id AS id FROM blog_article_tags WHERE tag_id = ? ORDER BY article_id", tagID).GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListArticleIDsByTag 查询失败: %v", err)
+		return nil, err
+	}
+	return resultIDs(rows), nil
+}
+
+func (r *ContentRepository) CountArticlesWithBothTags(ctx context.Context, a, b int64) (int64, error) {
+	cnt, err := orm.RawQuery[aggregate.Result](r.sess,
+		"SELECT COUNT(*) AS count FROM blog_article_tags x "+
+			"JOIN blog_article_tags y ON y.article_id = x.article_id WHERE x.tag_id = ? AND y.tag_id = ?", a, b).Get(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: CountArticlesWithBothTags 统计失败: %v", err)
+		return 0, err
+	}
+	return cnt.Count, nil
+}
+
+// resultIDs 取出查询结果中的 ID
+func resultIDs(rows []*aggregate.Result) []int64 {
+	ids := make([]int64, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	return ids
 }
 
 func (r *ContentRepository) ListTags(ctx context.Context, page, pageSize int) ([]*domain.Tag, int64, error) {
 	offset := (page - 1) * pageSize
-	list, err := orm.NewSelector[domain.Tag](r.db).OrderBy(orm.Asc("ID")).Limit(pageSize).Offset(offset).GetMulti(ctx)
+	list, err := orm.NewSelector[domain.Tag](r.sess).OrderBy(orm.Asc("ID")).Limit(pageSize).Offset(offset).GetMulti(ctx)
 	if err != nil {
 		logger.Log().Error("infrastructure: ListTags 查询失败: %v", err)
 		return nil, 0, err
 	}
-	cnt, err := orm.NewSelector[aggregate.Result](r.db).
+	cnt, err := orm.NewSelector[aggregate.Result](r.sess).
 		From(orm.TableOf(&domain.Tag{})).
 		Select(orm.Count("ID").As("count")).
 		Get(ctx)
@@ -323,7 +755,7 @@ func (r *ContentRepository) ListTags(ctx context.Context, page, pageSize int) ([
 }
 
 func (r *ContentRepository) CountTags(ctx context.Context) (int64, error) {
-	cnt, err := orm.NewSelector[aggregate.Result](r.db).
+	cnt, err := orm.NewSelector[aggregate.Result](r.sess).
 		From(orm.TableOf(&domain.Tag{})).
 		Select(orm.Count("ID").As("count")).
 		Get(ctx)
@@ -335,7 +767,7 @@ func (r *ContentRepository) CountTags(ctx context.Context) (int64, error) {
 }
 
 func (r *ContentRepository) ListArticleTags(ctx context.Context, articleID int64) ([]*domain.Tag, error) {
-	rows, err := orm.NewSelector[domain.Tag](r.db).
+	rows, err := orm.NewSelector[domain.Tag](r.sess).
 		Where(
 			orm.Raw("EXISTS (SELECT 1 FROM blog_article_tags at WHERE at.tag_id = blog_tag.id AND at.article_id = ?)", articleID).AsPredicate(),
 		).
@@ -347,81 +779,270 @@ func (r *ContentRepository) ListArticleTags(ctx context.Context, articleID int64
 	return rows, nil
 }
 
+// UpdateArticleTags 以 tagIDs 覆盖文章标签：删除旧关联与批量插入在同一事务中完成
 func (r *ContentRepository) UpdateArticleTags(ctx context.Context, articleID int64, tagIDs []int64) error {
-	// 先删除旧关联
-	if err := orm.NewDeleter[domain.ArticleTag](r.db).Where(orm.C("ArticleID").Eq(articleID)).Exec(ctx).Err(); err != nil {
-		logger.Log().Error("infrastructure: UpdateArticleTags 删除旧关联失败: %v", err)
-		return err
-	}
-	// 批量插入新关联
+	now := time.Now()
 	batch := make([]*domain.ArticleTag, 0, len(tagIDs))
 	for _, tid := range tagIDs {
-		batch = append(batch, &domain.ArticleTag{ArticleID: articleID, TagID: tid, CreatedAt: time.Now()})
+		batch = append(batch, &domain.ArticleTag{ArticleID: articleID, TagID: tid, CreatedAt: now})
 	}
-	if len(batch) == 0 {
+	return r.inTx(ctx, func(ctx context.Context, tx orm.Session) error {
+		if err := orm.NewDeleter[domain.ArticleTag](tx).Where(orm.C("ArticleID").Eq(articleID)).Exec(ctx).Err(); err != nil {
+			logger.Log().Error("infrastructure: UpdateArticleTags 删除旧关联失败: %v", err)
+			return err
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := orm.NewInserter[domain.ArticleTag](tx).Values(batch...).Exec(ctx).Err(); err != nil {
+			logger.Log().Error("infrastructure: UpdateArticleTags 写入新关联失败: %v", err)
+			return err
+		}
 		return nil
+	})
+}
+
+func (r *ContentRepository) ListArticleTagLinks(ctx context.Context) ([]*domain.ArticleTag, error) {
+	list, err := orm.NewSelector[domain.ArticleTag](r.sess).OrderBy(orm.Asc("ArticleID"), orm.Asc("TagID")).GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListArticleTagLinks 查询失败: %v", err)
+		return nil, err
 	}
-	return orm.NewInserter[domain.ArticleTag](r.db).Values(batch...).Exec(ctx).Err()
+	return list, nil
 }
 
-// ListArticleSummariesFiltered 支持按分类与标签过滤的摘要列表
-func (r *ContentRepository) ListArticleSummariesFiltered(ctx context.Context, categoryID *int64, tagIDs []int64, page, pageSize int) ([]*domain.ArticleSummary, int64, error) {
-	offset := (page - 1) * pageSize
-	base := orm.NewSelector[domain.Article](r.db).OrderBy(orm.Desc("PublishedAt"))
-	if categoryID != nil && *categoryID > 0 {
-		base = base.Where(orm.C("CategoryID").Eq(*categoryID))
+func (r *ContentRepository) ListTagsByIDs(ctx context.Context, ids []int64) ([]*domain.Tag, error) {
+	if len(ids) == 0 {
+		return []*domain.Tag{}, nil
 	}
-	if len(tagIDs) > 0 {
-		conds := make([]string, 0, len(tagIDs))
-		args := make([]any, 0, len(tagIDs))
-		for range tagIDs {
-			conds = append(conds, "at.tag_id = ?")
-		}
-		for _, id := range tagIDs {
-			args = append(args, id)
-		}
-		clause := "EXISTS (SELECT 1 FROM blog_article_tags at WHERE at.article_id = blog_article.id AND (" + strings.Join(conds, " OR ") + "))"
-		base = base.Where(orm.Raw(clause, args...).AsPredicate())
+	list, err := orm.NewSelector[domain.Tag](r.sess).Where(inInt64("id", ids)).GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListTagsByIDs 查询失败: %v", err)
+		return nil, err
 	}
-	rows, err := base.Limit(pageSize).Offset(offset).GetMulti(ctx)
+	return list, nil
+}
+
+// ListArticleSummariesFiltered 支持按分类与标签过滤的摘要列表（按可见性过滤）
+func (r *ContentRepository) ListArticleSummariesFiltered(ctx context.Context, v domain.Viewer, categoryID *int64, tagIDs []int64, page, pageSize int) ([]*domain.ArticleSummary, int64, error) {
+	return r.listSummaries(ctx, "ListArticleSummariesFiltered", filterPreds(v, categoryID, tagIDs), page, pageSize)
+}
+
+// listSummaries 按条件分页查询文章摘要：置顶文章在前，其余按发布时间新到旧
+func (r *ContentRepository) listSummaries(ctx context.Context, op string, preds []orm.Predicate, page, pageSize int) ([]*domain.ArticleSummary, int64, error) {
+	offset := (page - 1) * pageSize
+	rows, err := orm.NewSelector[domain.Article](r.sess).
+		Where(preds...).
+		OrderBy(orm.Desc("IsTop"), orm.Desc("PublishedAt"), orm.Desc("ID")).
+		Limit(pageSize).Offset(offset).
+		GetMulti(ctx)
 	if err != nil {
-		logger.Log().Error("infrastructure: ListArticleSummariesFiltered 查询失败: %v", err)
+		logger.Log().Error("infrastructure: %s 查询失败: %v", op, err)
 		return nil, 0, err
 	}
-	summaries := make([]*domain.ArticleSummary, 0, len(rows))
-	for _, a := range rows {
-		if a == nil {
-			continue
-		}
-		summaries = append(summaries, &domain.ArticleSummary{ID: a.ID, Title: a.Title})
+	summaries, err := r.buildSummaries(ctx, rows)
+	if err != nil {
+		return nil, 0, err
 	}
 	// 统计总数
-	cntSel := orm.NewSelector[aggregate.Result](r.db).
+	cnt, err := orm.NewSelector[aggregate.Result](r.sess).
 		From(orm.TableOf(&domain.Article{})).
-		Select(orm.Count("ID").As("count"))
-	if categoryID != nil && *categoryID > 0 {
-		cntSel = cntSel.Where(orm.C("CategoryID").Eq(*categoryID))
-	}
-	if len(tagIDs) > 0 {
-		conds := make([]string, 0, len(tagIDs))
-		args := make([]any, 0, len(tagIDs))
-		for range tagIDs {
-			conds = append(conds, "at.tag_id = ?")
-		}
-		for _, id := range tagIDs {
-			args = append(args, id)
-		}
-		clause := "EXISTS (SELECT 1 FROM blog_article_tags at WHERE at.article_id = blog_article.id AND (" + strings.Join(conds, " OR ") + "))"
-		cntSel = cntSel.Where(orm.Raw(clause, args...).AsPredicate())
-	}
-	cnt, err := cntSel.Get(ctx)
+		Select(orm.Count("ID").As("count")).
+		Where(preds...).
+		Get(ctx)
 	if err != nil {
-		logger.Log().Error("infrastructure: ListArticleSummariesFiltered 统计失败: %v", err)
+		logger.Log().Error("infrastructure: %s 统计失败: %v", op, err)
 		return nil, 0, err
 	}
 	return summaries, cnt.Count, nil
 }
 
+func (r *ContentRepository) ListArticleSummariesAfter(ctx context.Context, v domain.Viewer, categoryID *int64, tagIDs []int64, after *pagination.Cursor, limit int) ([]*domain.ArticleSummary, *pagination.Cursor, error) {
+	rows, next, err := r.selectAfter(ctx, "ListArticleSummariesAfter", filterPreds(v, categoryID, tagIDs), after, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+	summaries, err := r.buildSummaries(ctx, rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	return summaries, next, nil
+}
+
+// selectAfter 键集分页：按 (is_top, published_at, id) 降序取游标之后的 limit 条，多取一条判断是否还有下一页
+func (r *ContentRepository) selectAfter(ctx context.Context, op string, preds []orm.Predicate, after *pagination.Cursor, limit int) ([]*domain.Article, *pagination.Cursor, error) {
+	if after != nil {
+		preds = append(preds, keysetAfter(after))
+	}
+	rows, err := orm.NewSelector[domain.Article](r.sess).
+		Where(preds...).
+		OrderBy(orm.Desc("IsTop"), orm.Desc("PublishedAt"), orm.Desc("ID")).
+		Limit(limit + 1).
+		GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: %s 查询失败: %v", op, err)
+		return nil, nil, err
+	}
+	if len(rows) <= limit {
+		return rows, nil, nil
+	}
+	rows = rows[:limit]
+	last := rows[limit-1]
+	return rows, &pagination.Cursor{Top: last.IsTop, Time: last.PublishedAt, ID: last.ID}, nil
+}
+
+// keysetAfter 排在游标之后的谓词，与 is_top DESC, published_at DESC, id DESC 一致（MySQL 降序时 NULL 排在最后）
+func keysetAfter(c *pagination.Cursor) orm.Predicate {
+	top := 0
+	if c.Top {
+		top = 1
+	}
+	if c.Time == nil {
+		return orm.Raw("(is_top < ? OR (is_top = ? AND published_at IS NULL AND id < ?))", top, top, c.ID).AsPredicate()
+	}
+	return orm.Raw("(is_top < ? OR (is_top = ? AND (published_at < ? OR published_at IS NULL OR (published_at = ? AND id < ?))))",
+		top, top, *c.Time, *c.Time, c.ID).AsPredicate()
+}
+
+// ListFeedArticles 已公开文章（发布时间新到旧，至多 limit 篇），过滤条件同 ListArticleSummariesFiltered
+func (r *ContentRepository) ListFeedArticles(ctx context.Context, categoryID *int64, tagIDs []int64, limit int) ([]*domain.Article, error) {
+	list, err := orm.NewSelector[domain.Article](r.sess).
+		Where(filterPreds(domain.Viewer{}, categoryID, tagIDs)...).
+		OrderBy(orm.Desc("PublishedAt")).
+		Limit(limit).
+		GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListFeedArticles 查询失败: %v", err)
+		return nil, err
+	}
+	return list, nil
+}
+
+// ListAuthorNames 作者 ID 到用户名（用户表与内容表同库）
+func (r *ContentRepository) ListAuthorNames(ctx context.Context, ids []int64) (map[int64]string, error) {
+	out := make(map[int64]string, len(ids))
+	if len(ids) == 0 {
+		return out, nil
+	}
+	query, args := inClause("id", ids)
+	rows, err := orm.RawQuery[authorRow](r.sess, "SELECT id, username FROM blog_user WHERE "+query, args...).GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListAuthorNames 查询失败: %v", err)
+		return nil, err
+	}
+	for _, row := range rows {
+		out[row.ID] = row.Username
+	}
+	return out, nil
+}
+
+// authorRow 作者 ID 与用户名
+type authorRow struct {
+	ID       int64
+	Username string
+}
+
+// sitemapQueries 各类对象的 sitemap 查询：分类、标签的最后修改时间计入其下已公开文章
+var sitemapQueries = map[string]string{
+	domain.SlugKindArticle: "SELECT slug, GREATEST(updated_at, published_at) AS lastmod FROM blog_article " +
+		"WHERE status = ? AND published_at <= ? ORDER BY id",
+	domain.SlugKindCategory: "SELECT c.slug, GREATEST(c.updated_at, COALESCE(MAX(a.updated_at), c.updated_at)) AS lastmod " +
+		"FROM blog_category c LEFT JOIN blog_article a ON a.category_id = c.id AND a.status = ? AND a.published_at <= ? " +
+		"GROUP BY c.id, c.slug, c.updated_at ORDER BY c.id",
+	domain.SlugKindTag: "SELECT t.slug, GREATEST(t.updated_at, COALESCE(MAX(a.updated_at), t.updated_at)) AS lastmod " +
+		"FROM blog_tag t LEFT JOIN blog_article_tags at ON at.tag_id = t.id " +
+		"LEFT JOIN blog_article a ON a.id = at.article_id AND a.status = ? AND a.published_at <= ? " +
+		"GROUP BY t.id, t.slug, t.updated_at ORDER BY t.id",
+}
+
+func (r *ContentRepository) ListSitemapEntries(ctx context.Context, kind string) ([]*domain.SitemapEntry, error) {
+	query, ok := sitemapQueries[kind]
+	if !ok {
+		return nil, domain.ErrSitemapNotFound
+	}
+	list, err := orm.RawQuery[domain.SitemapEntry](r.sess, query, domain.ArticleStatusPublished, time.Now()).GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: ListSitemapEntries 查询失败: kind=%s err=%v", kind, err)
+		return nil, err
+	}
+	return list, nil
+}
+
+// CountArchive 已公开文章按发布年月统计（新到旧）
+func (r *ContentRepository) CountArchive(ctx context.Context) ([]*domain.ArchiveCount, error) {
+	list, err := orm.RawQuery[domain.ArchiveCount](r.sess,
+		"SELECT YEAR(published_at) AS year, MONTH(published_at) AS month, COUNT(*) AS count FROM blog_article "+
+			"WHERE status = ? AND published_at <= ? GROUP BY year, month ORDER BY year DESC, month DESC",
+		domain.ArticleStatusPublished, time.Now()).GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: CountArchive 查询失败: %v", err)
+		return nil, err
+	}
+	return list, nil
+}
+
+// CountArchiveDays 发布时间在 [from, to) 内的已公开文章按日统计（按日期升序）
+func (r *ContentRepository) CountArchiveDays(ctx context.Context, from, to time.Time) ([]*domain.ArchiveCount, error) {
+	list, err := orm.RawQuery[domain.ArchiveCount](r.sess,
+		"SELECT YEAR(published_at) AS year, MONTH(published_at) AS month, DAY(published_at) AS day, COUNT(*) AS count "+
+			"FROM blog_article WHERE status = ? AND published_at <= ? AND published_at >= ? AND published_at < ? "+
+			"GROUP BY year, month, day ORDER BY year, month, day",
+		domain.ArticleStatusPublished, time.Now(), from, to).GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: CountArchiveDays 查询失败: %v", err)
+		return nil, err
+	}
+	return list, nil
+}
+
+// ListArchiveSummaries 发布时间在 [from, to) 内的已公开文章摘要
+func (r *ContentRepository) ListArchiveSummaries(ctx context.Context, from, to time.Time, page, pageSize int) ([]*domain.ArticleSummary, int64, error) {
+	preds := filterPreds(domain.Viewer{}, nil, nil)
+	preds = append(preds, orm.Raw("(published_at >= ? AND published_at < ?)", from, to).AsPredicate())
+	return r.listSummaries(ctx, "ListArchiveSummaries", preds, page, pageSize)
+}
+
+// GetAdjacentArticle 按 (发布时间, ID) 排序与 a 相邻的已公开文章：next 为 true 时取更晚的一篇，否则取更早的一篇；
+// sameCategory 时限定与 a 同一分类，不存在时返回 orm.ErrNoRows
+func (r *ContentRepository) GetAdjacentArticle(ctx context.Context, a *domain.Article, sameCategory, next bool) (*domain.ArticleNavItem, error) {
+	cmp, order := "<", "DESC"
+	if next {
+		cmp, order = ">", "ASC"
+	}
+	query := "SELECT id, title, slug, published_at FROM blog_article WHERE status = ? AND published_at <= ? " +
+		"AND (published_at " + cmp + " ? OR (published_at = ? AND id " + cmp + " ?))"
+	args := []any{domain.ArticleStatusPublished, time.Now(), *a.PublishedAt, *a.PublishedAt, a.ID}
+	if sameCategory {
+		query += " AND category_id = ?"
+		args = append(args, a.CategoryID)
+	}
+	query += " ORDER BY published_at " + order + ", id " + order + " LIMIT 1"
+	item, err := orm.RawQuery[domain.ArticleNavItem](r.sess, query, args...).Get(ctx)
+	if err != nil && !errors.Is(err, orm.ErrNoRows) {
+		logger.Log().Error("infrastructure: GetAdjacentArticle 查询失败: id=%d err=%v", a.ID, err)
+	}
+	return item, err
+}
+
+// filterPreds 可见性、分类（含全部子分类）与标签过滤谓词
+func filterPreds(v domain.Viewer, categoryID *int64, tagIDs []int64) []orm.Predicate {
+	var preds []orm.Predicate
+	if p, ok := visibleTo(v, time.Now()); ok {
+		preds = append(preds, p)
+	}
+	if categoryID != nil && *categoryID > 0 {
+		preds = append(preds, orm.Raw("(category_id = ? OR category_id IN (SELECT c.id FROM blog_category c "+
+			"JOIN blog_category r ON c.path LIKE CONCAT(r.path, '%') WHERE r.id = ? AND r.path <> ''))",
+			*categoryID, *categoryID).AsPredicate())
+	}
+	if len(tagIDs) > 0 {
+		query, args := inClause("at.tag_id", tagIDs)
+		clause := "EXISTS (SELECT 1 FROM blog_article_tags at WHERE at.article_id = blog_article.id AND " + query + ")"
+		preds = append(preds, orm.Raw(clause, args...).AsPredicate())
+	}
+	return preds
+}
+
 // containsFold 简单不区分大小写包含
 func containsFold(s, sub string) bool {
 	if sub == "" {
@@ -442,7 +1063,7 @@ func indexFold(s, sub string) int {
 func index(s, sub string) int { return strings.Index(s, sub) }
 
 func (r *ContentRepository) ListAllTags(ctx context.Context) ([]*domain.Tag, error) {
-	list, err := orm.NewSelector[domain.Tag](r.db).OrderBy(orm.Asc("ID")).GetMulti(ctx)
+	list, err := orm.NewSelector[domain.Tag](r.sess).OrderBy(orm.Asc("ID")).GetMulti(ctx)
 	if err != nil {
 		logger.Log().Error("infrastructure: ListAllTags 查询失败: %v", err)
 		return nil, err
@@ -451,7 +1072,7 @@ func (r *ContentRepository) ListAllTags(ctx context.Context) ([]*domain.Tag, err
 }
 
 func (r *ContentRepository) CountArticlesByTag(ctx context.Context, tagID int64) (int64, error) {
-	cnt, err := orm.NewSelector[aggregate.Result](r.db).
+	cnt, err := orm.NewSelector[aggregate.Result](r.sess).
 		From(orm.TableOf(&domain.ArticleTag{})).
 		Select(orm.Count("ID").As("count")).
 		Where(orm.Raw("tag_id = ?", tagID).AsPredicate()).
@@ -462,3 +1083,21 @@ func (r *ContentRepository) CountArticlesByTag(ctx context.Context, tagID int64)
 	}
 	return cnt.Count, nil
 }
+
+// CountArticlesGroupByTag 一次 GROUP BY 统计各标签已公开文章数（ID 为 tag_id）
+func (r *ContentRepository) CountArticlesGroupByTag(ctx context.Context) (map[int64]int64, error) {
+	rows, err := orm.RawQuery[aggregate.Result](r.sess,
+		"SELECT at.tag_id AS id, COUNT(*) AS count FROM blog_article_tags at "+
+			"JOIN blog_article a ON a.id = at.article_id WHERE a.status = ? AND a.published_at <= ? GROUP BY at.tag_id",
+		domain.ArticleStatusPublished, time.Now()).
+		GetMulti(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: CountArticlesGroupByTag 统计失败: %v", err)
+		return nil, err
+	}
+	out := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		out[row.ID] = row.Count
+	}
+	return out, nil
+}
@@ -0,0 +1,79 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"blog-system/common/pkg/aggregate"
+	"blog-system/common/pkg/logger"
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/orm"
+	"github.com/go-sql-driver/mysql"
+)
+
+// slugTables 别名所属对象的表名
+var slugTables = map[string]string{
+	domain.SlugKindArticle:  "blog_article",
+	domain.SlugKindCategory: "blog_category",
+	domain.SlugKindTag:      "blog_tag",
+}
+
+func (r *ContentRepository) GetArticleBySlug(ctx context.Context, slug string) (*domain.Article, error) {
+	return orm.NewSelector[domain.Article](r.sess).Where(orm.C("Slug").Eq(slug)).Get(ctx)
+}
+
+// SlugExists 别名是否已被同类对象中 excludeID 以外的对象占用
+func (r *ContentRepository) SlugExists(ctx context.Context, kind, slug string, excludeID int64) (bool, error) {
+	table, ok := slugTables[kind]
+	if !ok {
+		return false, fmt.Errorf("unknown slug kind: %s", kind)
+	}
+	cnt, err := orm.RawQuery[aggregate.Result](r.sess,
+		"SELECT COUNT(*) AS count FROM "+table+" WHERE slug = ? AND id <> ?", slug, excludeID).Get(ctx)
+	if err != nil {
+		logger.Log().Error("infrastructure: SlugExists 查询失败: %v", err)
+		return false, err
+	}
+	return cnt.Count > 0, nil
+}
+
+// SaveSlugRedirect 记录旧别名，已存在时改为指向 targetID
+func (r *ContentRepository) SaveSlugRedirect(ctx context.Context, kind, slug string, targetID int64) error {
+	err := orm.RawQuery[domain.SlugRedirect](r.sess,
+		"INSERT INTO blog_slug_redirect (kind, slug, target_id, created_at) VALUES (?, ?, ?, ?) "+
+			"ON DUPLICATE KEY UPDATE target_id = VALUES(target_id), created_at = VALUES(created_at)",
+		kind, slug, targetID, time.Now()).Exec(ctx).Err()
+	if err != nil {
+		logger.Log().Error("infrastructure: SaveSlugRedirect 写入失败: %v", err)
+	}
+	return err
+}
+
+// FindSlugRedirect 旧别名指向的对象 ID
+func (r *ContentRepository) FindSlugRedirect(ctx context.Context, kind, slug string) (int64, error) {
+	rd, err := orm.NewSelector[domain.SlugRedirect](r.sess).
+		Where(orm.C("Kind").Eq(kind), orm.C("Slug").Eq(slug)).Get(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return rd.TargetID, nil
+}
+
+// deleteSlugRedirects 删除指向对象的全部旧别名，sess 可为事务
+func deleteSlugRedirects(ctx context.Context, sess orm.Session, kind string, targetID int64) error {
+	return orm.NewDeleter[domain.SlugRedirect](sess).
+		Where(orm.C("Kind").Eq(kind), orm.C("TargetID").Eq(targetID)).Exec(ctx).Err()
+}
+
+// slugConflict 并发写入同一别名时由唯一索引兜底，将重复键错误转换为 ErrSlugTaken
+func slugConflict(err error) error {
+	var me *mysql.MySQLError
+	if errors.As(err, &me) && me.Number == 1062 && strings.Contains(me.Message, "slug") {
+		return domain.ErrSlugTaken
+	}
+	return err
+}
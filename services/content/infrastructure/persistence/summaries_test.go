@@ -0,0 +1,148 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/orm"
+)
+
+// countingDriver 只读内存驱动：统计查询次数，按 SQL 中的表名返回分类或文章标签结果
+type countingDriver struct {
+	queries atomic.Int64
+}
+
+var (
+	registerOnce sync.Once
+	counting     = &countingDriver{}
+)
+
+func (d *countingDriver) Open(string) (driver.Conn, error) { return &countingConn{d: d}, nil }
+
+type countingConn struct{ d *countingDriver }
+
+func (c *countingConn) Prepare(string) (driver.Stmt, error) {
+	return nil, errors.New("countingConn: prepare not supported")
+}
+func (c *countingConn) Close() error { return nil }
+func (c *countingConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("countingConn: tx not supported")
+}
+
+func (c *countingConn) QueryContext(_ context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	c.d.queries.Add(1)
+	rows := &memRows{}
+	switch {
+	case strings.Contains(query, "blog_article_tags"):
+		rows.cols = []string{"article_id", "tag_id", "name", "slug", "color"}
+		for _, a := range args {
+			id := a.Value.(int64)
+			for t := int64(1); t <= 2; t++ {
+				rows.data = append(rows.data, []driver.Value{id, t, fmt.Sprintf("tag%d", t), fmt.Sprintf("tag-%d", t), nil})
+			}
+		}
+	case strings.Contains(query, "blog_category"):
+		rows.cols = []string{"id", "name", "slug"}
+		for _, a := range args {
+			id := a.Value.(int64)
+			rows.data = append(rows.data, []driver.Value{id, fmt.Sprintf("cat%d", id), fmt.Sprintf("cat-%d", id)})
+		}
+	default:
+		return nil, fmt.Errorf("countingConn: unexpected query %q", query)
+	}
+	return rows, nil
+}
+
+type memRows struct {
+	cols []string
+	data [][]driver.Value
+	pos  int
+}
+
+func (r *memRows) Columns() []string { return r.cols }
+func (r *memRows) Close() error      { return nil }
+func (r *memRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.data) {
+		return io.EOF
+	}
+	copy(dest, r.data[r.pos])
+	r.pos++
+	return nil
+}
+
+type plainSummarizer struct{}
+
+func (plainSummarizer) Summarize(content string, _ int) string { return content }
+
+func newCountingRepo(t testing.TB) *ContentRepository {
+	registerOnce.Do(func() { sql.Register("counting", counting) })
+	sqlDB, err := sql.Open("counting", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	db, err := orm.OpenDB(sqlDB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewContentRepository(db, plainSummarizer{})
+}
+
+// summaryRows 构造一页文章，分类分布在 5 个分类上
+func summaryRows(n int) []*domain.Article {
+	rows := make([]*domain.Article, 0, n)
+	for i := 1; i <= n; i++ {
+		rows = append(rows, &domain.Article{ID: int64(i), Title: fmt.Sprintf("t%d", i), Content: "body", CategoryID: int64(i%5 + 1)})
+	}
+	return rows
+}
+
+func TestBuildSummariesQueryCount(t *testing.T) {
+	repo := newCountingRepo(t)
+	for _, n := range []int{10, 100} {
+		before := counting.queries.Load()
+		list, err := repo.buildSummaries(context.Background(), summaryRows(n))
+		if err != nil {
+			t.Fatalf("n=%d: %v", n, err)
+		}
+		if got := counting.queries.Load() - before; got != 2 {
+			t.Fatalf("n=%d: %d queries, want 2", n, got)
+		}
+		if len(list) != n || list[n-1].Category == nil || len(list[n-1].Tags) != 2 {
+			t.Fatalf("n=%d: unexpected summaries %+v", n, list[n-1])
+		}
+	}
+}
+
+func BenchmarkBuildSummaries(b *testing.B) {
+	repo := newCountingRepo(b)
+	for _, n := range []int{10, 100} {
+		b.Run(fmt.Sprintf("page=%d", n), func(b *testing.B) {
+			rows := summaryRows(n)
+			before := counting.queries.Load()
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := repo.buildSummaries(context.Background(), rows); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+			perOp := float64(counting.queries.Load()-before) / float64(b.N)
+			b.ReportMetric(perOp, "queries/op")
+			if perOp != 2 {
+				b.Fatalf("page=%d: %.2f queries/op, want 2", n, perOp)
+			}
+		})
+	}
+}
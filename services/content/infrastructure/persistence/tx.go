@@ -0,0 +1,37 @@
+package infrastructure
+
+import (
+	"context"
+
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/orm"
+)
+
+// Transaction 在同一事务中执行 fn：fn 经 repo 的读写共享该事务，返回错误或 panic 时回滚；
+// 已处于事务中时直接复用当前事务
+func (r *ContentRepository) Transaction(ctx context.Context, fn func(ctx context.Context, repo domain.ContentRepository) error) error {
+	if r.tx != nil {
+		return fn(ctx, r)
+	}
+	return r.db.DoTx(ctx, func(ctx context.Context, tx *orm.Tx) error {
+		return fn(ctx, r.withTx(tx))
+	}, nil)
+}
+
+// inTx 多条语句的写操作在事务中执行，已处于事务中时复用
+func (r *ContentRepository) inTx(ctx context.Context, fn func(ctx context.Context, tx orm.Session) error) error {
+	if r.tx != nil {
+		return fn(ctx, r.tx)
+	}
+	return r.db.DoTx(ctx, func(ctx context.Context, tx *orm.Tx) error {
+		return fn(ctx, tx)
+	}, nil)
+}
+
+// withTx 绑定到事务的仓储副本
+func (r *ContentRepository) withTx(tx *orm.Tx) *ContentRepository {
+	cp := *r
+	cp.sess, cp.tx = tx, tx
+	return &cp
+}
@@ -0,0 +1,81 @@
+package infrastructure
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/orm"
+)
+
+// evalVisible 按 visibleTo 生成的两种 SQL 形态在内存中求值，用于与 Viewer.CanSee 对照
+func evalVisible(t *testing.T, q *orm.Query, a *domain.Article) bool {
+	t.Helper()
+	status, before := q.Args[0].(int), q.Args[1].(time.Time)
+	public := a.Status == status && a.PublishedAt != nil && !a.PublishedAt.After(before)
+	switch len(q.Args) {
+	case 2:
+		return public
+	case 3:
+		return public || a.AuthorID == q.Args[2].(int64)
+	}
+	t.Fatalf("unexpected args %v", q.Args)
+	return false
+}
+
+func TestVisibleTo(t *testing.T) {
+	repo := newCountingRepo(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	const author, other = 1, 2
+	articles := map[string]*domain.Article{
+		"published":        {AuthorID: author, Status: domain.ArticleStatusPublished, PublishedAt: &past},
+		"published-future": {AuthorID: author, Status: domain.ArticleStatusPublished, PublishedAt: &future},
+		"draft":            {AuthorID: author, Status: domain.ArticleStatusDraft},
+		"private":          {AuthorID: author, Status: domain.ArticleStatusPrivate, PublishedAt: &past},
+		"scheduled":        {AuthorID: author, Status: domain.ArticleStatusScheduled, PublishedAt: &future},
+		"others-draft":     {AuthorID: other, Status: domain.ArticleStatusDraft},
+		"others-scheduled": {AuthorID: other, Status: domain.ArticleStatusScheduled, PublishedAt: &future},
+	}
+	tests := []struct {
+		name   string
+		viewer domain.Viewer
+		where  string // 为空表示不加可见性条件
+	}{
+		{"anonymous", domain.Viewer{}, "WHERE (status = ? AND published_at <= ?)"},
+		{"reader", domain.Viewer{UserID: 3}, "WHERE ((status = ? AND published_at <= ?) OR author_id = ?)"},
+		{"author", domain.Viewer{UserID: author}, "WHERE ((status = ? AND published_at <= ?) OR author_id = ?)"},
+		{"editor", domain.Viewer{UserID: 4, Admin: true}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pred, ok := visibleTo(tt.viewer, now)
+			if ok != (tt.where != "") {
+				t.Fatalf("ok = %t", ok)
+			}
+			if !ok {
+				// 不加条件即全部可见，须与 CanSee 一致
+				for name, a := range articles {
+					if !tt.viewer.CanSee(a, now) {
+						t.Errorf("CanSee(%s) = false for unrestricted viewer", name)
+					}
+				}
+				return
+			}
+			q, err := orm.NewSelector[domain.Article](repo.db).Where(pred).Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasSuffix(q.SQL, tt.where+";") {
+				t.Fatalf("sql = %s, want suffix %s", q.SQL, tt.where)
+			}
+			for name, a := range articles {
+				if got, want := evalVisible(t, q, a), tt.viewer.CanSee(a, now); got != want {
+					t.Errorf("%s: sql visible = %t, CanSee = %t", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+// allowScript 计数加一，窗口内首次计数时设置过期时间；脚本原子执行，避免计数成功而过期设置失败导致键永不过期
+var allowScript = redis.NewScript(`
+local n = redis.call('INCR', KEYS[1])
+if n == 1 then
+	redis.call('PEXPIRE', KEYS[1], ARGV[1])
+end
+return n`)
+
+// RedisRateLimiter 基于 Redis INCR 的固定窗口限流：窗口内首次计数时设置过期时间
+type RedisRateLimiter struct {
+	rdb redis.Cmdable
+}
+
+func NewRedisRateLimiter(rdb redis.Cmdable) *RedisRateLimiter {
+	return &RedisRateLimiter{rdb: rdb}
+}
+
+func (l *RedisRateLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, error) {
+	n, err := allowScript.Run(ctx, l.rdb, []string{key}, window.Milliseconds()).Int64()
+	if err != nil {
+		return false, err
+	}
+	return n <= int64(limit), nil
+}
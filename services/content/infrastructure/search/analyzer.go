@@ -0,0 +1,112 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// token 分词结果，Start/End 为原文字节偏移
+type token struct {
+	Term  string
+	Start int
+	End   int
+}
+
+// isCJK 中日韩字符（按二元组切分）
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) ||
+		unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) ||
+		unicode.Is(unicode.Hangul, r)
+}
+
+// isWord 非 CJK 的词字符（字母、数字）
+func isWord(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// analyze 索引与高亮分词：在 tokenize 的基础上为多字 CJK 片段同时输出单字，
+// 使单字查询（如 "检"）也能命中 "全文检索"
+func analyze(text string) []token {
+	return tokenize(text, true)
+}
+
+// tokenize CJK 二元组分词：
+//   - 连续 CJK 字符按相邻两字切分（"全文检索" -> 全文/文检/检索），单字成词；
+//     unigrams 为 true 时多字片段的每个字也单独成词（按起始偏移排在同位置的二元组之前）
+//   - 字母数字按连续片段成词并转小写
+//   - 其余字符（空白、标点）作为分隔符
+func tokenize(text string, unigrams bool) []token {
+	var (
+		out []token
+		run []int // 当前 CJK 片段中各字符的起始偏移
+	)
+	flushCJK := func(end int) {
+		switch len(run) {
+		case 0:
+		case 1:
+			out = append(out, token{Term: text[run[0]:end], Start: run[0], End: end})
+		default:
+			for i := range run {
+				next := end
+				if i+1 < len(run) {
+					next = run[i+1]
+				}
+				if unigrams {
+					out = append(out, token{Term: text[run[i]:next], Start: run[i], End: next})
+				}
+				if i+1 < len(run) {
+					e := end
+					if i+2 < len(run) {
+						e = run[i+2]
+					}
+					out = append(out, token{Term: text[run[i]:e], Start: run[i], End: e})
+				}
+			}
+		}
+		run = run[:0]
+	}
+	wordStart := -1
+	flushWord := func(end int) {
+		if wordStart >= 0 {
+			out = append(out, token{Term: strings.ToLower(text[wordStart:end]), Start: wordStart, End: end})
+			wordStart = -1
+		}
+	}
+	for i := 0; i < len(text); {
+		r, size := utf8.DecodeRuneInString(text[i:])
+		switch {
+		case isCJK(r):
+			flushWord(i)
+			run = append(run, i)
+		case isWord(r):
+			flushCJK(i)
+			if wordStart < 0 {
+				wordStart = i
+			}
+		default:
+			flushCJK(i)
+			flushWord(i)
+		}
+		i += size
+	}
+	flushCJK(len(text))
+	flushWord(len(text))
+	return out
+}
+
+// queryTerms 查询词去重（保持顺序）；多字 CJK 片段只取二元组，保证多字查询的精度
+func queryTerms(q string) []string {
+	toks := tokenize(q, false)
+	seen := make(map[string]struct{}, len(toks))
+	out := make([]string, 0, len(toks))
+	for _, t := range toks {
+		if _, ok := seen[t.Term]; ok {
+			continue
+		}
+		seen[t.Term] = struct{}{}
+		out = append(out, t.Term)
+	}
+	return out
+}
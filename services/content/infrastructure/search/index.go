@@ -0,0 +1,437 @@
+package search
+
+import (
+	"context"
+	"html"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"blog-system/common/pkg/pagination"
+	"blog-system/services/content/domain"
+)
+
+// 索引字段
+const (
+	fieldTitle = iota
+	fieldSummary
+	fieldContent
+	numFields
+)
+
+var fieldNames = [numFields]string{"title", "summary", "content"}
+
+// fieldBoost 字段权重：标题 > 摘要 > 正文
+var fieldBoost = [numFields]float64{3, 2, 1}
+
+// BM25 参数
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// 高亮片段长度（字符数）及命中词之前保留的上下文长度
+const (
+	snippetRunes = 120
+	snippetLead  = 30
+)
+
+// docEntry 已索引文档
+type docEntry struct {
+	doc   *domain.SearchDoc
+	lens  [numFields]int
+	terms []string
+}
+
+// MemoryIndex 内嵌倒排索引（CJK 二元组与单字分词 + BM25 打分），进程内存储，启动时由仓储全量重建
+type MemoryIndex struct {
+	mu       sync.RWMutex
+	docs     map[int64]*docEntry
+	postings map[string]map[int64]*[numFields]int // term -> 文档 -> 各字段词频
+	totalLen [numFields]int
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		docs:     make(map[int64]*docEntry),
+		postings: make(map[string]map[int64]*[numFields]int),
+	}
+}
+
+// Index 写入或覆盖文档
+func (m *MemoryIndex) Index(_ context.Context, doc *domain.SearchDoc) error {
+	if doc == nil || doc.ID == 0 {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.remove(doc.ID)
+	m.add(doc)
+	return nil
+}
+
+// Delete 删除文档（不存在时忽略）
+func (m *MemoryIndex) Delete(_ context.Context, id int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.remove(id)
+	return nil
+}
+
+// Rebuild 在新索引上写入全部文档后整体替换，重建期间检索不受影响
+func (m *MemoryIndex) Rebuild(ctx context.Context, docs []*domain.SearchDoc) error {
+	fresh := NewMemoryIndex()
+	for _, d := range docs {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if d != nil && d.ID != 0 {
+			fresh.remove(d.ID)
+			fresh.add(d)
+		}
+	}
+	m.mu.Lock()
+	m.docs, m.postings, m.totalLen = fresh.docs, fresh.postings, fresh.totalLen
+	m.mu.Unlock()
+	return nil
+}
+
+// Count 已索引文档数
+func (m *MemoryIndex) Count() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return len(m.docs)
+}
+
+// IDs 已索引的全部文档 ID（无序）
+func (m *MemoryIndex) IDs() []int64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	ids := make([]int64, 0, len(m.docs))
+	for id := range m.docs {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Search 所有查询词均需命中（任一字段），分类精确过滤，标签任一命中
+func (m *MemoryIndex) Search(_ context.Context, q domain.SearchQuery) (*domain.SearchResult, error) {
+	res := &domain.SearchResult{Facets: domain.SearchFacets{Categories: []*domain.FacetCount{}, Tags: []*domain.FacetCount{}}}
+	terms := queryTerms(q.Keyword)
+	if len(terms) == 0 {
+		return res, nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	// 从文档频率最小的词开始求交集
+	lists := make([]map[int64]*[numFields]int, 0, len(terms))
+	for _, t := range terms {
+		p, ok := m.postings[t]
+		if !ok {
+			return res, nil
+		}
+		lists = append(lists, p)
+	}
+	sort.Slice(lists, func(i, j int) bool { return len(lists[i]) < len(lists[j]) })
+
+	tagSet := make(map[int64]struct{}, len(q.TagIDs))
+	for _, id := range q.TagIDs {
+		tagSet[id] = struct{}{}
+	}
+	catSet := make(map[int64]struct{}, len(q.CategoryIDs))
+	for _, id := range q.CategoryIDs {
+		catSet[id] = struct{}{}
+	}
+	n := float64(len(m.docs))
+	var avg [numFields]float64
+	for f := 0; f < numFields; f++ {
+		avg[f] = math.Max(float64(m.totalLen[f])/math.Max(n, 1), 1)
+	}
+	idf := make(map[string]float64, len(terms))
+	for _, t := range terms {
+		df := float64(len(m.postings[t]))
+		idf[t] = math.Log(1 + (n-df+0.5)/(df+0.5))
+	}
+
+	catCount := make(map[int64]int64)
+	tagCount := make(map[int64]int64)
+	hits := make([]*domain.SearchHit, 0, len(lists[0]))
+next:
+	for id := range lists[0] {
+		for _, l := range lists[1:] {
+			if _, ok := l[id]; !ok {
+				continue next
+			}
+		}
+		e := m.docs[id]
+		if len(catSet) > 0 {
+			if _, ok := catSet[e.doc.CategoryID]; !ok {
+				continue
+			}
+		} else if q.CategoryID != nil && *q.CategoryID > 0 && e.doc.CategoryID != *q.CategoryID {
+			continue
+		}
+		if len(tagSet) > 0 && !hasAnyTag(e.doc.TagIDs, tagSet) {
+			continue
+		}
+		if !q.PublishedBefore.IsZero() && (e.doc.PublishedAt == nil || e.doc.PublishedAt.After(q.PublishedBefore)) {
+			continue
+		}
+		var score float64
+		for _, t := range terms {
+			tf := m.postings[t][id]
+			for f := 0; f < numFields; f++ {
+				if tf[f] == 0 {
+					continue
+				}
+				norm := bm25K1 * (1 - bm25B + bm25B*float64(e.lens[f])/avg[f])
+				score += fieldBoost[f] * idf[t] * float64(tf[f]) * (bm25K1 + 1) / (float64(tf[f]) + norm)
+			}
+		}
+		hits = append(hits, &domain.SearchHit{ID: id, Score: score})
+		if e.doc.CategoryID > 0 {
+			catCount[e.doc.CategoryID]++
+		}
+		for _, tid := range e.doc.TagIDs {
+			tagCount[tid]++
+		}
+	}
+	// 相关度降序，同分按发布时间、ID 降序
+	keyOf := func(h *domain.SearchHit) hitKey {
+		return hitKey{score: h.Score, published: m.docs[h.ID].doc.PublishedAt, id: h.ID}
+	}
+	sort.Slice(hits, func(i, j int) bool { return keyOf(hits[i]).before(keyOf(hits[j])) })
+
+	res.Total = int64(len(hits))
+	res.Facets.Categories = facetList(catCount)
+	res.Facets.Tags = facetList(tagCount)
+	page, pageSize := pagination.Normalize(q.Page, q.PageSize)
+	start := (page - 1) * pageSize
+	if q.After != nil {
+		after := hitKey{score: q.After.Score, published: q.After.Time, id: q.After.ID}
+		start = sort.Search(len(hits), func(i int) bool { return after.before(keyOf(hits[i])) })
+	}
+	if start >= len(hits) {
+		res.Hits = []*domain.SearchHit{}
+		return res, nil
+	}
+	end := min(start+pageSize, len(hits))
+	res.Hits = hits[start:end]
+	if end < len(hits) {
+		last := keyOf(hits[end-1])
+		res.Next = &pagination.Cursor{Score: last.score, Time: last.published, ID: last.id}
+	}
+
+	qset := make(map[string]struct{}, len(terms))
+	for _, t := range terms {
+		qset[t] = struct{}{}
+	}
+	for _, h := range res.Hits {
+		d := m.docs[h.ID].doc
+		h.Highlights = make(map[string]string, numFields)
+		for f, text := range [numFields]string{d.Title, d.Summary, d.Content} {
+			if frag, ok := highlight(text, qset, f == fieldTitle); ok {
+				h.Highlights[fieldNames[f]] = frag
+			}
+		}
+	}
+	return res, nil
+}
+
+// Similar 标题、摘要按字段权重合并词频，词权重为 tf * ln(1 + N/df)，文档频率按全文统计
+func (m *MemoryIndex) Similar(_ context.Context, id int64) (map[int64]float64, error) {
+	out := make(map[int64]float64)
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	src, ok := m.docs[id]
+	if !ok {
+		return out, nil
+	}
+	n := float64(len(m.docs))
+	idf := func(t string) float64 { return math.Log(1 + n/float64(len(m.postings[t]))) }
+
+	dot := make(map[int64]float64)
+	var srcNorm float64
+	for _, t := range src.terms {
+		ws := textWeight(m.postings[t][id]) * idf(t)
+		if ws == 0 {
+			continue
+		}
+		srcNorm += ws * ws
+		for did, tf := range m.postings[t] {
+			if did == id {
+				continue
+			}
+			if wd := textWeight(tf) * idf(t); wd > 0 {
+				dot[did] += ws * wd
+			}
+		}
+	}
+	if srcNorm == 0 {
+		return out, nil
+	}
+	for did, d := range dot {
+		var norm float64
+		for _, t := range m.docs[did].terms {
+			w := textWeight(m.postings[t][did]) * idf(t)
+			norm += w * w
+		}
+		out[did] = d / math.Sqrt(srcNorm*norm)
+	}
+	return out, nil
+}
+
+// textWeight 标题、摘要的加权词频
+func textWeight(tf *[numFields]int) float64 {
+	return fieldBoost[fieldTitle]*float64(tf[fieldTitle]) + fieldBoost[fieldSummary]*float64(tf[fieldSummary])
+}
+
+// hitKey 命中结果的排序键
+type hitKey struct {
+	score     float64
+	published *time.Time
+	id        int64
+}
+
+// before 排序在 o 之前：相关度降序，同分按发布时间、ID 降序
+func (k hitKey) before(o hitKey) bool {
+	if k.score != o.score {
+		return k.score > o.score
+	}
+	if k.published != nil && o.published != nil && !k.published.Equal(*o.published) {
+		return k.published.After(*o.published)
+	}
+	return k.id > o.id
+}
+
+// add 写入文档（调用方持有写锁）
+func (m *MemoryIndex) add(doc *domain.SearchDoc) {
+	e := &docEntry{doc: doc}
+	seen := make(map[string]struct{})
+	for f, text := range [numFields]string{doc.Title, doc.Summary, doc.Content} {
+		toks := analyze(text)
+		e.lens[f] = len(toks)
+		m.totalLen[f] += len(toks)
+		for _, t := range toks {
+			p, ok := m.postings[t.Term]
+			if !ok {
+				p = make(map[int64]*[numFields]int)
+				m.postings[t.Term] = p
+			}
+			tf, ok := p[doc.ID]
+			if !ok {
+				tf = new([numFields]int)
+				p[doc.ID] = tf
+			}
+			tf[f]++
+			if _, ok := seen[t.Term]; !ok {
+				seen[t.Term] = struct{}{}
+				e.terms = append(e.terms, t.Term)
+			}
+		}
+	}
+	m.docs[doc.ID] = e
+}
+
+// remove 移除文档及其倒排项（调用方持有写锁）
+func (m *MemoryIndex) remove(id int64) {
+	e, ok := m.docs[id]
+	if !ok {
+		return
+	}
+	for _, t := range e.terms {
+		if p, ok := m.postings[t]; ok {
+			delete(p, id)
+			if len(p) == 0 {
+				delete(m.postings, t)
+			}
+		}
+	}
+	for f := 0; f < numFields; f++ {
+		m.totalLen[f] -= e.lens[f]
+	}
+	delete(m.docs, id)
+}
+
+func hasAnyTag(ids []int64, set map[int64]struct{}) bool {
+	for _, id := range ids {
+		if _, ok := set[id]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// facetList 分面按计数降序、ID 升序
+func facetList(counts map[int64]int64) []*domain.FacetCount {
+	out := make([]*domain.FacetCount, 0, len(counts))
+	for id, c := range counts {
+		out = append(out, &domain.FacetCount{ID: id, Count: c})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Count != out[j].Count {
+			return out[i].Count > out[j].Count
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// highlight 以 <em> 包裹命中词并转义其余文本；full 为 false 时截取首个命中附近的片段
+func highlight(text string, qset map[string]struct{}, full bool) (string, bool) {
+	// 合并命中区间（二元组、单字相互重叠）
+	var spans [][2]int
+	for _, t := range analyze(text) {
+		if _, ok := qset[t.Term]; !ok {
+			continue
+		}
+		if n := len(spans); n > 0 && t.Start <= spans[n-1][1] {
+			spans[n-1][1] = max(spans[n-1][1], t.End)
+			continue
+		}
+		spans = append(spans, [2]int{t.Start, t.End})
+	}
+	if len(spans) == 0 {
+		return "", false
+	}
+	from, to := 0, len(text)
+	if !full {
+		from = spans[0][0]
+		for i := 0; i < snippetLead && from > 0; i++ {
+			_, size := utf8.DecodeLastRuneInString(text[:from])
+			from -= size
+		}
+		to = from
+		for i := 0; i < snippetRunes && to < len(text); i++ {
+			_, size := utf8.DecodeRuneInString(text[to:])
+			to += size
+		}
+	}
+	var b strings.Builder
+	if from > 0 {
+		b.WriteString("…")
+	}
+	pos := from
+	for _, sp := range spans {
+		s, e := max(sp[0], from), min(sp[1], to)
+		if s >= e {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:s]))
+		b.WriteString("<em>")
+		b.WriteString(html.EscapeString(text[s:e]))
+		b.WriteString("</em>")
+		pos = e
+	}
+	b.WriteString(html.EscapeString(text[pos:to]))
+	if to < len(text) {
+		b.WriteString("…")
+	}
+	return b.String(), true
+}
+
+var _ domain.SearchIndex = (*MemoryIndex)(nil)
@@ -0,0 +1,175 @@
+package search
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"blog-system/services/content/domain"
+)
+
+func terms(toks []token) []string {
+	out := make([]string, len(toks))
+	for i, t := range toks {
+		out[i] = t.Term
+	}
+	return out
+}
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		text  string
+		index []string // analyze：索引与高亮
+		query []string // queryTerms：查询
+	}{
+		{"全文检索", []string{"全", "全文", "文", "文检", "检", "检索", "索"}, []string{"全文", "文检", "检索"}},
+		{"检", []string{"检"}, []string{"检"}},
+		{"Go语言 v1.2", []string{"go", "语", "语言", "言", "v1", "2"}, []string{"go", "语言", "v1", "2"}},
+		{"检索，检索 GO go", []string{"检", "检索", "索", "检", "检索", "索", "go", "go"}, []string{"检索", "go"}},
+		{"  ，。", []string{}, []string{}},
+	}
+	for _, tt := range tests {
+		if got := terms(analyze(tt.text)); !slices.Equal(got, tt.index) {
+			t.Errorf("analyze(%q) = %q, want %q", tt.text, got, tt.index)
+		}
+		if got := queryTerms(tt.text); !slices.Equal(got, tt.query) {
+			t.Errorf("queryTerms(%q) = %q, want %q", tt.text, got, tt.query)
+		}
+	}
+	// 偏移指向原文
+	for _, tok := range analyze("Go语言") {
+		if !strings.EqualFold("Go语言"[tok.Start:tok.End], tok.Term) {
+			t.Errorf("token %q: offsets [%d,%d) do not match source", tok.Term, tok.Start, tok.End)
+		}
+	}
+}
+
+func TestSearchScoring(t *testing.T) {
+	idx := NewMemoryIndex()
+	ctx := context.Background()
+	for _, d := range []*domain.SearchDoc{
+		{ID: 1, Title: "全文检索", Content: "倒排索引与 BM25"},
+		{ID: 2, Title: "Go 并发", Content: "goroutine 与 channel 的全文检索示例"},
+		{ID: 3, Title: "数据库", Content: "MySQL 索引"},
+	} {
+		if err := idx.Index(ctx, d); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		q    string
+		want []int64
+	}{
+		// 标题权重高于正文
+		{"全文检索", []int64{1, 2}},
+		// 单字查询命中多字词
+		{"检", []int64{1, 2}},
+		{"GO", []int64{2}},
+		{"mysql 索引", []int64{3}},
+		// 所有查询词均需命中
+		{"全文 mysql", nil},
+		{"不存在", nil},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		res, err := idx.Search(ctx, domain.SearchQuery{Keyword: tt.q})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []int64
+		for i, h := range res.Hits {
+			got = append(got, h.ID)
+			if h.Score <= 0 || (i > 0 && h.Score > res.Hits[i-1].Score) {
+				t.Errorf("%q: hit %d score %v not positive and descending", tt.q, h.ID, h.Score)
+			}
+		}
+		if !slices.Equal(got, tt.want) || res.Total != int64(len(tt.want)) {
+			t.Errorf("%q: hits = %v total = %d, want %v", tt.q, got, res.Total, tt.want)
+		}
+	}
+
+	res, _ := idx.Search(ctx, domain.SearchQuery{Keyword: "检"})
+	if got := res.Hits[0].Highlights["title"]; got != "全文<em>检</em>索" {
+		t.Errorf("title highlight = %q", got)
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	set := func(ts ...string) map[string]struct{} {
+		m := make(map[string]struct{}, len(ts))
+		for _, t := range ts {
+			m[t] = struct{}{}
+		}
+		return m
+	}
+	long := strings.Repeat("啊", 100) + "检索" + strings.Repeat("啊", 200)
+	tests := []struct {
+		name string
+		text string
+		q    map[string]struct{}
+		full bool
+		want string
+		ok   bool
+	}{
+		{"unigram", "全文检索入门", set("检"), true, "全文<em>检</em>索入门", true},
+		{"bigram", "全文检索入门", set("检索"), true, "全文<em>检索</em>入门", true},
+		{"adjacent spans merged", "全文检索入门", set("全文", "检索"), true, "<em>全文检索</em>入门", true},
+		{"escaped, original case", "<b>Go</b> 语言", set("go"), true, "&lt;b&gt;<em>Go</em>&lt;/b&gt; 语言", true},
+		{"no hit", "全文检索", set("数据"), true, "", false},
+		{"snippet", long, set("检索"), false,
+			"…" + strings.Repeat("啊", snippetLead) + "<em>检索</em>" + strings.Repeat("啊", snippetRunes-snippetLead-2) + "…", true},
+	}
+	for _, tt := range tests {
+		got, ok := highlight(tt.text, tt.q, tt.full)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("%s: highlight = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSearchCursor(t *testing.T) {
+	idx := NewMemoryIndex()
+	ctx := context.Background()
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(m int) *time.Time { p := base.Add(time.Duration(m) * time.Minute); return &p }
+	// 内容相同则得分相同，按发布时间、ID 降序
+	published := map[int64]*time.Time{11: at(1), 12: at(3), 13: at(2), 14: at(3), 15: at(0)}
+	for id, p := range published {
+		if err := idx.Index(ctx, &domain.SearchDoc{ID: id, Title: "分页", PublishedAt: p}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want := []int64{14, 12, 13, 11, 15}
+
+	q := domain.SearchQuery{Keyword: "分页", PageSize: 2}
+	var got []int64
+	for pages := 0; ; pages++ {
+		if pages > len(want) {
+			t.Fatal("cursor did not terminate")
+		}
+		res, err := idx.Search(ctx, q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, h := range res.Hits {
+			got = append(got, h.ID)
+		}
+		if res.Next == nil {
+			break
+		}
+		q.After = res.Next
+	}
+	if !slices.Equal(got, want) {
+		t.Fatalf("cursor pages = %v, want %v", got, want)
+	}
+
+	res, err := idx.Search(ctx, domain.SearchQuery{Keyword: "分页", Page: 2, PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Hits) != 2 || res.Hits[0].ID != 13 || res.Hits[1].ID != 11 || res.Next == nil {
+		t.Fatalf("page 2 = %+v next = %v", res.Hits, res.Next)
+	}
+}
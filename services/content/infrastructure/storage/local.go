@@ -0,0 +1,86 @@
+// Package storage 文件存储：本地文件系统与 S3 兼容对象存储
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"blog-system/services/content/domain"
+)
+
+// LocalStorage 本地文件系统存储，文件经 PublicURL 对外访问（由内容服务或前置 Web 服务器提供）
+type LocalStorage struct {
+	root      string
+	publicURL string
+}
+
+func NewLocalStorage(root, publicURL string) (*LocalStorage, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStorage{root: root, publicURL: strings.TrimRight(publicURL, "/")}, nil
+}
+
+// Put 先写临时文件再重命名，避免读到写了一半的文件
+func (s *LocalStorage) Put(_ context.Context, key string, r io.Reader, _ int64, _ string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+	if _, err := io.Copy(tmp, r); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *LocalStorage) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, domain.ErrMediaNotFound
+	}
+	return f, err
+}
+
+// Delete 文件不存在时视为成功
+func (s *LocalStorage) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStorage) URL(key string) string {
+	return s.publicURL + "/" + key
+}
+
+// path 存储键对应的本地路径，拒绝越出根目录的键
+func (s *LocalStorage) path(key string) (string, error) {
+	if !filepath.IsLocal(key) {
+		return "", domain.ErrMediaNotFound
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,246 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"blog-system/services/content/domain"
+)
+
+// S3Config S3 兼容对象存储配置；PathStyle 为 true 时使用 endpoint/bucket/key 寻址（MinIO 等本地替身需开启）
+type S3Config struct {
+	Endpoint  string // 如 https://s3.us-east-1.amazonaws.com、http://127.0.0.1:9000
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	PathStyle bool
+	PublicURL string // 对外访问前缀（如 CDN），为空时取对象地址
+}
+
+// S3Storage 以 AWS Signature V4 直接调用 S3 REST 接口（PUT / GET / DELETE Object）
+type S3Storage struct {
+	cfg    S3Config
+	base   *url.URL
+	client *http.Client
+}
+
+func NewS3Storage(cfg S3Config) (*S3Storage, error) {
+	base, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("s3 endpoint 不合法: %q", cfg.Endpoint)
+	}
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 bucket 不能为空")
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+	cfg.PublicURL = strings.TrimRight(cfg.PublicURL, "/")
+	return &S3Storage{cfg: cfg, base: base, client: &http.Client{Timeout: time.Minute}}, nil
+}
+
+// Put 上传对象；正文读入内存以计算签名所需的负载哈希（单文件受上传大小上限约束）
+func (s *S3Storage) Put(ctx context.Context, key string, r io.Reader, _ int64, contentType string) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	req, err := s.newRequest(ctx, http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := s.do(req, body)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return s3Error(resp)
+	}
+	return nil
+}
+
+func (s *S3Storage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	req, err := s.newRequest(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req, nil)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		_ = resp.Body.Close()
+		return nil, domain.ErrMediaNotFound
+	default:
+		defer func() { _ = resp.Body.Close() }()
+		return nil, s3Error(resp)
+	}
+}
+
+// Delete 对象不存在时视为成功
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	req, err := s.newRequest(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.do(req, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return s3Error(resp)
+	}
+	return nil
+}
+
+func (s *S3Storage) URL(key string) string {
+	if s.cfg.PublicURL != "" {
+		return s.cfg.PublicURL + "/" + key
+	}
+	return s.objectURL(key).String()
+}
+
+// objectURL 对象地址：路径风格为 endpoint/bucket/key，否则为 bucket.endpoint/key
+func (s *S3Storage) objectURL(key string) *url.URL {
+	u := *s.base
+	path := strings.TrimRight(s.base.EscapedPath(), "/")
+	if s.cfg.PathStyle {
+		path += "/" + s3Escape(s.cfg.Bucket)
+	} else {
+		u.Host = s.cfg.Bucket + "." + u.Host
+	}
+	u.RawPath = path + "/" + s3EscapePath(key)
+	u.Path, _ = url.PathUnescape(u.RawPath)
+	return &u
+}
+
+func (s *S3Storage) newRequest(ctx context.Context, method, key string, body []byte) (*http.Request, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	return http.NewRequestWithContext(ctx, method, s.objectURL(key).String(), r)
+}
+
+func (s *S3Storage) do(req *http.Request, body []byte) (*http.Response, error) {
+	signV4(req, body, s.cfg.AccessKey, s.cfg.SecretKey, s.cfg.Region, "s3", time.Now())
+	return s.client.Do(req)
+}
+
+// s3Error 读取 S3 错误响应（XML）的前一段作为错误信息
+func s3Error(resp *http.Response) error {
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	return fmt.Errorf("s3: %s: %s", resp.Status, strings.TrimSpace(string(msg)))
+}
+
+// signV4 为请求添加 AWS Signature V4 头：签名覆盖 host 与请求上已设置的全部头
+func signV4(req *http.Request, body []byte, accessKey, secretKey, region, service string, now time.Time) {
+	now = now.UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payload := sha256.Sum256(body)
+	payloadHash := hex.EncodeToString(payload[:])
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k, v := range req.Header {
+		headers[strings.ToLower(k)] = strings.TrimSpace(strings.Join(v, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, k := range names {
+		canonicalHeaders.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	sum := sha256.Sum256([]byte(canonical))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(sum[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKey+"/"+scope+
+		", SignedHeaders="+signedHeaders+", Signature="+signature)
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// canonicalQuery 按键排序并按 RFC 3986 编码的查询串
+func canonicalQuery(q url.Values) string {
+	keys := make([]string, 0, len(q))
+	for k := range q {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		vals := append([]string(nil), q[k]...)
+		sort.Strings(vals)
+		for _, v := range vals {
+			parts = append(parts, s3Escape(k)+"="+s3Escape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// s3Escape RFC 3986 编码：仅保留字母、数字与 -_.~
+func s3Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// s3EscapePath 逐段编码，保留分隔符 /
+func s3EscapePath(key string) string {
+	segs := strings.Split(key, "/")
+	for i, seg := range segs {
+		segs[i] = s3Escape(seg)
+	}
+	return strings.Join(segs, "/")
+}
@@ -0,0 +1,47 @@
+package grpcserver
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+
+	"blog-system/services/content/domain"
+	"blog-system/services/content/infrastructure/importer"
+	pb "blog-system/services/content/proto"
+)
+
+// ExportSite 整站导出为 zip（管理端下载）
+func (s *AdminGRPCServer) ExportSite(ctx context.Context, req *pb.ExportRequest) (*pb.ExportResponse, error) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	if _, err := s.backups.Export(ctx, zw, req.Media); err != nil {
+		return nil, errStatus(err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return &pb.ExportResponse{Archive: buf.Bytes()}, nil
+}
+
+// RestoreSite 由 zip 恢复站点（管理端上传）
+func (s *AdminGRPCServer) RestoreSite(ctx context.Context, req *pb.RestoreRequest) (*pb.RestoreReport, error) {
+	src, err := importer.FromZip(req.Archive)
+	if err != nil {
+		return nil, errStatus(err)
+	}
+	opts := domain.RestoreOptions{AuthorMap: make(map[int64]int64, len(req.Authors)), DefaultAuthor: req.DefaultAuthor}
+	for _, m := range req.Authors {
+		opts.AuthorMap[m.OldId] = m.NewId
+	}
+	r, err := s.backups.Restore(ctx, src, opts)
+	if err != nil {
+		return nil, errStatus(err)
+	}
+	count := func(c domain.RestoreCount) *pb.RestoreCount {
+		return &pb.RestoreCount{Created: int32(c.Created), Existing: int32(c.Existing), Failed: int32(c.Failed)}
+	}
+	return &pb.RestoreReport{
+		SchemaVersion: int32(r.SchemaVersion), Categories: count(r.Categories), Tags: count(r.Tags),
+		Articles: count(r.Articles), Media: count(r.Media), Errors: r.Errors,
+	}, nil
+}
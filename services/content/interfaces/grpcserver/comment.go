@@ -0,0 +1,49 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"blog-system/common/pkg/pagination"
+	"blog-system/services/content/domain"
+	pb "blog-system/services/content/proto"
+)
+
+// Comment 评论审核
+func (s *AdminGRPCServer) ListComments(ctx context.Context, req *pb.ListCommentsRequest) (*pb.CommentListResponse, error) {
+	f := domain.CommentFilter{ArticleID: req.ArticleId, Page: int(req.Page), PageSize: int(req.PageSize)}
+	f.Page, f.PageSize = max(f.Page, 1), pagination.Size(f.PageSize, 20)
+	if req.Status >= 0 {
+		st := int(req.Status)
+		f.Status = &st
+	}
+	list, total, err := s.comments.List(ctx, f)
+	if err != nil {
+		return nil, errStatus(err)
+	}
+	out := &pb.CommentListResponse{Total: total}
+	for _, c := range list {
+		out.Data = append(out.Data, toPBComment(c))
+	}
+	return out, nil
+}
+
+func (s *AdminGRPCServer) ModerateComments(ctx context.Context, req *pb.ModerateCommentsRequest) (*pb.Count, error) {
+	n, err := s.comments.Moderate(ctx, req.Ids, int(req.Status))
+	if err != nil {
+		return nil, errStatus(err)
+	}
+	return &pb.Count{Value: n}, nil
+}
+
+func (s *AdminGRPCServer) DeleteComment(ctx context.Context, req *pb.Id) (*pb.Empty, error) {
+	return &pb.Empty{}, errStatus(s.comments.Delete(ctx, req.Id))
+}
+
+func toPBComment(c *domain.Comment) *pb.Comment {
+	return &pb.Comment{
+		Id: c.ID, ArticleId: c.ArticleID, ParentId: c.ParentID, RootId: c.RootID, UserId: c.UserID,
+		Content: c.Content, Status: int32(c.Status), Ip: c.IP,
+		CreatedAt: c.CreatedAt.Format(time.RFC3339), UpdatedAt: c.UpdatedAt.Format(time.RFC3339),
+	}
+}
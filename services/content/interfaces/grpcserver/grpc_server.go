@@ -3,58 +3,126 @@ package grpcserver
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"time"
 
+	"blog-system/common/pkg/pagination"
+	"blog-system/common/pkg/textdiff"
 	"blog-system/services/content/application"
 	"blog-system/services/content/domain"
 	pb "blog-system/services/content/proto"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type AdminGRPCServer struct {
 	pb.UnimplementedContentAdminServiceServer
-	app *application.ContentAppService
+	app      *application.ContentAppService
+	comments *application.CommentAppService
+	media    *application.MediaAppService
+	imports  *application.ImportAppService
+	backups  *application.BackupAppService
 }
 
-func NewAdminGRPCServer(app *application.ContentAppService) *AdminGRPCServer {
-	return &AdminGRPCServer{app: app}
+func NewAdminGRPCServer(app *application.ContentAppService, comments *application.CommentAppService, media *application.MediaAppService, imports *application.ImportAppService, backups *application.BackupAppService) *AdminGRPCServer {
+	return &AdminGRPCServer{app: app, comments: comments, media: media, imports: imports, backups: backups}
 }
 
 // Article
 func (s *AdminGRPCServer) CreateArticle(ctx context.Context, req *pb.Article) (*pb.Empty, error) {
-	var summary, cover *sql.NullString
-	if req.Summary != "" {
-		summary = &sql.NullString{String: req.Summary, Valid: true}
-	}
-	if req.Cover != "" {
-		cover = &sql.NullString{String: req.Cover, Valid: true}
-	}
-	a := &domain.Article{Title: req.Title, Slug: req.Slug, Content: req.Content, Summary: summary, Cover: cover, AuthorID: req.AuthorId, CategoryID: req.CategoryId, Status: int(req.Status), IsTop: req.IsTop, IsRecommend: req.IsRecommend}
-	_, err := s.app.Create(ctx, a)
-	return &pb.Empty{}, err
+	a := &domain.Article{Title: req.Title, Slug: req.Slug, Content: req.Content, Summary: nullable(req.Summary), Cover: nullable(req.Cover), AuthorID: req.AuthorId, CategoryID: req.CategoryId, Status: int(req.Status), IsTop: req.IsTop, IsRecommend: req.IsRecommend, PublishedAt: parseTime(req.PublishedAt),
+		MetaTitle: nullable(req.MetaTitle), MetaDesc: nullable(req.MetaDesc), MetaKeywords: nullable(req.MetaKeywords)}
+	_, err := s.app.Create(ctx, a, req.TagIds)
+	return &pb.Empty{}, errStatus(err)
 }
 func (s *AdminGRPCServer) UpdateArticle(ctx context.Context, req *pb.Article) (*pb.Empty, error) {
-	var summary, cover *sql.NullString
-	if req.Summary != "" {
-		summary = &sql.NullString{String: req.Summary, Valid: true}
+	a := &domain.Article{ID: req.Id, Title: req.Title, Slug: req.Slug, Content: req.Content, Summary: nullable(req.Summary), Cover: nullable(req.Cover), CategoryID: req.CategoryId, Status: int(req.Status), IsTop: req.IsTop, IsRecommend: req.IsRecommend, PublishedAt: parseTime(req.PublishedAt), Version: req.Version,
+		MetaTitle: nullable(req.MetaTitle), MetaDesc: nullable(req.MetaDesc), MetaKeywords: nullable(req.MetaKeywords)}
+	var tagIDs []int64
+	if req.SetTags {
+		tagIDs = append(make([]int64, 0, len(req.TagIds)), req.TagIds...)
 	}
-	if req.Cover != "" {
-		cover = &sql.NullString{String: req.Cover, Valid: true}
+	return &pb.Empty{}, errStatus(s.app.Update(ctx, a, tagIDs))
+}
+
+// errStatus 业务错误映射为 gRPC 状态码，便于调用方识别：
+// 版本冲突为 Aborted，别名已占用为 AlreadyExists，别名格式错误为 InvalidArgument
+func errStatus(err error) error {
+	switch {
+	case errors.Is(err, domain.ErrVersionConflict):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.Is(err, domain.ErrSlugTaken), errors.Is(err, domain.ErrTagNameTaken):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, domain.ErrSlugInvalid), errors.Is(err, domain.ErrCategoryParent),
+		errors.Is(err, domain.ErrCategoryCycle), errors.Is(err, domain.ErrCategoryTooDeep),
+		errors.Is(err, domain.ErrCategoryDeleteMode), errors.Is(err, domain.ErrCategoryTarget),
+		errors.Is(err, domain.ErrTagMergeSelf), errors.Is(err, domain.ErrTagInvalid),
+		errors.Is(err, domain.ErrCommentStatus), errors.Is(err, pagination.ErrInvalidCursor),
+		errors.Is(err, domain.ErrImportArchive), errors.Is(err, domain.ErrImportNoDocs), errors.Is(err, domain.ErrImportLogin),
+		errors.Is(err, domain.ErrBackupManifest), errors.Is(err, domain.ErrBackupData), errors.As(err, new(*domain.BackupVersionError)):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, domain.ErrCategoryNotFound), errors.Is(err, domain.ErrTagNotFound),
+		errors.Is(err, domain.ErrCommentNotFound), errors.Is(err, domain.ErrMediaNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, domain.ErrCategoryHasChildren), errors.Is(err, domain.ErrCategoryNotEmpty),
+		errors.Is(err, domain.ErrMediaInUse):
+		return status.Error(codes.FailedPrecondition, err.Error())
 	}
-	a := &domain.Article{ID: req.Id, Title: req.Title, Slug: req.Slug, Content: req.Content, Summary: summary, Cover: cover, CategoryID: req.CategoryId, Status: int(req.Status), IsTop: req.IsTop, IsRecommend: req.IsRecommend}
-	return &pb.Empty{}, s.app.Update(ctx, a)
+	return err
 }
 func (s *AdminGRPCServer) DeleteArticle(ctx context.Context, req *pb.Id) (*pb.Empty, error) {
 	return &pb.Empty{}, s.app.Delete(ctx, req.Id)
 }
-func (s *AdminGRPCServer) ListArticles(ctx context.Context, _ *pb.Empty) (*pb.ArticleListResponse, error) {
-	list, total, err := s.app.ListAllArticles(ctx)
+func (s *AdminGRPCServer) GetArticle(ctx context.Context, req *pb.Id) (*pb.Article, error) {
+	a, err := s.app.GetByID(ctx, req.Id)
 	if err != nil {
 		return nil, err
 	}
-	out := make([]*pb.Article, 0, len(list))
+	out := toPBArticle(a)
+	// 详情附带渲染结果，供后台预览
+	r, err := s.app.Render(ctx, a.Content)
+	if err != nil {
+		return nil, err
+	}
+	out.Html, out.WordCount, out.ReadingMinutes = r.HTML, int32(r.WordCount), int32(r.ReadingMinutes)
+	for _, h := range r.TOC {
+		out.Toc = append(out.Toc, &pb.Heading{Level: int32(h.Level), Id: h.ID, Text: h.Text})
+	}
+	tags, err := s.app.GetArticleTags(ctx, a.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range tags {
+		out.TagIds = append(out.TagIds, t.ID)
+	}
+	return out, nil
+}
+
+// ListArticles 游标分页的文章列表（不含正文）
+func (s *AdminGRPCServer) ListArticles(ctx context.Context, req *pb.ListArticlesRequest) (*pb.ArticleListResponse, error) {
+	list, next, err := s.app.ListArticlesPage(ctx, req.AuthorId, req.Cursor, int(req.PageSize))
+	if err != nil {
+		return nil, errStatus(err)
+	}
+	out := &pb.ArticleListResponse{Data: make([]*pb.Article, 0, len(list)), NextCursor: next}
 	for _, it := range list {
-		out = append(out, &pb.Article{Id: it.ID, Title: it.Title})
+		a := &pb.Article{
+			Id: it.ID, Title: it.Title, Slug: it.Slug, AuthorId: it.AuthorID, CategoryId: it.CategoryID,
+			Status: int32(it.Status), IsTop: it.IsTop, IsRecommend: it.IsRecommend, Version: it.Version,
+			CreatedAt: it.CreatedAt.Format(time.RFC3339), UpdatedAt: it.UpdatedAt.Format(time.RFC3339),
+		}
+		if it.PublishedAt != nil {
+			a.PublishedAt = it.PublishedAt.Format(time.RFC3339)
+		}
+		out.Data = append(out.Data, a)
+	}
+	if req.AuthorId == 0 {
+		if out.Total, err = s.app.CountArticles(ctx); err != nil {
+			return nil, err
+		}
 	}
-	return &pb.ArticleListResponse{Data: out, Total: total}, nil
+	return out, nil
 }
 func (s *AdminGRPCServer) CountArticles(ctx context.Context, _ *pb.Empty) (*pb.Count, error) {
 	val, err := s.app.CountArticles(ctx)
@@ -64,17 +132,74 @@ func (s *AdminGRPCServer) CountArticles(ctx context.Context, _ *pb.Empty) (*pb.C
 	return &pb.Count{Value: val}, nil
 }
 
+// nullable 空串视为 NULL
+func nullable(v string) *sql.NullString {
+	if v == "" {
+		return nil
+	}
+	return &sql.NullString{String: v, Valid: true}
+}
+
+// toPBArticle 领域文章转换为 pb
+func toPBArticle(a *domain.Article) *pb.Article {
+	out := &pb.Article{
+		Id: a.ID, Title: a.Title, Slug: a.Slug, Content: a.Content,
+		AuthorId: a.AuthorID, CategoryId: a.CategoryID, Status: int32(a.Status),
+		IsTop: a.IsTop, IsRecommend: a.IsRecommend, Version: a.Version,
+		CreatedAt: a.CreatedAt.Format(time.RFC3339), UpdatedAt: a.UpdatedAt.Format(time.RFC3339),
+	}
+	if a.Summary != nil && a.Summary.Valid {
+		out.Summary = a.Summary.String
+	}
+	if a.Cover != nil && a.Cover.Valid {
+		out.Cover = a.Cover.String
+	}
+	if a.MetaTitle != nil && a.MetaTitle.Valid {
+		out.MetaTitle = a.MetaTitle.String
+	}
+	if a.MetaDesc != nil && a.MetaDesc.Valid {
+		out.MetaDesc = a.MetaDesc.String
+	}
+	if a.MetaKeywords != nil && a.MetaKeywords.Valid {
+		out.MetaKeywords = a.MetaKeywords.String
+	}
+	if a.PublishedAt != nil {
+		out.PublishedAt = a.PublishedAt.Format(time.RFC3339)
+	}
+	return out
+}
+
+// parseTime 解析 RFC3339 时间，空串或非法返回 nil
+func parseTime(v string) *time.Time {
+	if v == "" {
+		return nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
 // Category（全量）
 func (s *AdminGRPCServer) CreateCategory(ctx context.Context, req *pb.Category) (*pb.Empty, error) {
-	c := &domain.Category{Name: req.Name, Slug: req.Slug, Sort: int(req.Sort)}
-	return &pb.Empty{}, s.app.UpdateCategory(ctx, c)
+	c := &domain.Category{Name: req.Name, Slug: req.Slug, Sort: int(req.Sort), ParentID: req.ParentId}
+	return &pb.Empty{}, errStatus(s.app.CreateCategory(ctx, c))
 }
 func (s *AdminGRPCServer) UpdateCategory(ctx context.Context, req *pb.Category) (*pb.Empty, error) {
 	c := &domain.Category{ID: req.Id, Name: req.Name, Slug: req.Slug, Sort: int(req.Sort)}
-	return &pb.Empty{}, s.app.UpdateCategory(ctx, c)
+	return &pb.Empty{}, errStatus(s.app.UpdateCategory(ctx, c))
 }
 func (s *AdminGRPCServer) DeleteCategory(ctx context.Context, req *pb.Id) (*pb.Empty, error) {
-	return &pb.Empty{}, s.app.DeleteCategory(ctx, req.Id)
+	_, err := s.app.DeleteCategory(ctx, req.Id, domain.CategoryDeleteRefuse, 0, false)
+	return &pb.Empty{}, errStatus(err)
+}
+func (s *AdminGRPCServer) DeleteCategoryWithPlan(ctx context.Context, req *pb.DeleteCategoryRequest) (*pb.CategoryDeletePlan, error) {
+	p, err := s.app.DeleteCategory(ctx, req.Id, req.Mode, req.TargetId, req.DryRun)
+	if err != nil {
+		return nil, errStatus(err)
+	}
+	return &pb.CategoryDeletePlan{Id: p.ID, Mode: p.Mode, TargetId: p.TargetID, Articles: p.Articles, DryRun: p.DryRun}, nil
 }
 func (s *AdminGRPCServer) ListCategories(ctx context.Context, _ *pb.Empty) (*pb.CategoryListResponse, error) {
 	list, err := s.app.ListAllCategories(ctx)
@@ -83,10 +208,32 @@ func (s *AdminGRPCServer) ListCategories(ctx context.Context, _ *pb.Empty) (*pb.
 	}
 	out := make([]*pb.Category, 0, len(list))
 	for _, c := range list {
-		out = append(out, &pb.Category{Id: c.ID, Name: c.Name, Slug: c.Slug, Sort: int32(c.Sort)})
+		out = append(out, toPBCategory(c))
 	}
 	return &pb.CategoryListResponse{Data: out, Total: int64(len(out))}, nil
 }
+func (s *AdminGRPCServer) MoveCategory(ctx context.Context, req *pb.MoveCategoryRequest) (*pb.Empty, error) {
+	return &pb.Empty{}, errStatus(s.app.MoveCategory(ctx, req.Id, req.ParentId))
+}
+func (s *AdminGRPCServer) GetCategoryTree(ctx context.Context, _ *pb.Empty) (*pb.CategoryTreeResponse, error) {
+	tree, err := s.app.CategoryTree(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.CategoryTreeResponse{Data: toPBCategoryNodes(tree)}, nil
+}
+
+func toPBCategory(c *domain.Category) *pb.Category {
+	return &pb.Category{Id: c.ID, Name: c.Name, Slug: c.Slug, Sort: int32(c.Sort), ParentId: c.ParentID, Path: c.Path}
+}
+
+func toPBCategoryNodes(nodes []*domain.CategoryNode) []*pb.CategoryNode {
+	out := make([]*pb.CategoryNode, 0, len(nodes))
+	for _, n := range nodes {
+		out = append(out, &pb.CategoryNode{Category: toPBCategory(n.Category), Children: toPBCategoryNodes(n.Children)})
+	}
+	return out
+}
 func (s *AdminGRPCServer) CountCategories(ctx context.Context, _ *pb.Empty) (*pb.Count, error) {
 	val, err := s.app.CountCategories(ctx)
 	if err != nil {
@@ -102,7 +249,7 @@ func (s *AdminGRPCServer) CreateTag(ctx context.Context, req *pb.Tag) (*pb.Empty
 		color = &sql.NullString{String: req.Color, Valid: true}
 	}
 	t := &domain.Tag{Name: req.Name, Slug: req.Slug, Color: color}
-	return &pb.Empty{}, s.app.CreateTag(ctx, t)
+	return &pb.Empty{}, errStatus(s.app.CreateTag(ctx, t))
 }
 func (s *AdminGRPCServer) UpdateTag(ctx context.Context, req *pb.Tag) (*pb.Empty, error) {
 	var color *sql.NullString
@@ -110,10 +257,29 @@ func (s *AdminGRPCServer) UpdateTag(ctx context.Context, req *pb.Tag) (*pb.Empty
 		color = &sql.NullString{String: req.Color, Valid: true}
 	}
 	t := &domain.Tag{ID: req.Id, Name: req.Name, Slug: req.Slug, Color: color}
-	return &pb.Empty{}, s.app.UpdateTag(ctx, t)
+	return &pb.Empty{}, errStatus(s.app.UpdateTag(ctx, t))
 }
 func (s *AdminGRPCServer) DeleteTag(ctx context.Context, req *pb.Id) (*pb.Empty, error) {
-	return &pb.Empty{}, s.app.DeleteTag(ctx, req.Id)
+	_, err := s.app.DeleteTag(ctx, req.Id, false)
+	return &pb.Empty{}, errStatus(err)
+}
+func (s *AdminGRPCServer) DeleteTagWithPlan(ctx context.Context, req *pb.DeleteTagRequest) (*pb.TagChangePlan, error) {
+	p, err := s.app.DeleteTag(ctx, req.Id, req.DryRun)
+	if err != nil {
+		return nil, errStatus(err)
+	}
+	return toPBTagChangePlan(p), nil
+}
+func (s *AdminGRPCServer) MergeTag(ctx context.Context, req *pb.MergeTagRequest) (*pb.TagChangePlan, error) {
+	p, err := s.app.MergeTag(ctx, req.SourceId, req.TargetId, req.DryRun)
+	if err != nil {
+		return nil, errStatus(err)
+	}
+	return toPBTagChangePlan(p), nil
+}
+
+func toPBTagChangePlan(p *domain.TagChangePlan) *pb.TagChangePlan {
+	return &pb.TagChangePlan{SourceId: p.SourceID, TargetId: p.TargetID, Articles: p.Articles, Overlap: p.Overlap, DryRun: p.DryRun}
 }
 func (s *AdminGRPCServer) ListTags(ctx context.Context, _ *pb.Empty) (*pb.TagListResponse, error) {
 	tags, _, err := s.app.ListAllTags(ctx)
@@ -137,3 +303,88 @@ func (s *AdminGRPCServer) CountTags(ctx context.Context, _ *pb.Empty) (*pb.Count
 	}
 	return &pb.Count{Value: val}, nil
 }
+
+// Search
+func (s *AdminGRPCServer) RebuildSearchIndex(ctx context.Context, _ *pb.Empty) (*pb.Count, error) {
+	n, err := s.app.RebuildSearchIndex(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.Count{Value: int64(n)}, nil
+}
+
+// Schedule
+func (s *AdminGRPCServer) ListScheduledArticles(ctx context.Context, _ *pb.Empty) (*pb.ArticleListResponse, error) {
+	list, err := s.app.ListScheduled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*pb.Article, 0, len(list))
+	for _, a := range list {
+		pa := toPBArticle(a)
+		pa.Content = ""
+		out = append(out, pa)
+	}
+	return &pb.ArticleListResponse{Data: out, Total: int64(len(out))}, nil
+}
+func (s *AdminGRPCServer) CancelScheduledArticle(ctx context.Context, req *pb.Id) (*pb.Empty, error) {
+	return &pb.Empty{}, s.app.CancelSchedule(ctx, req.Id)
+}
+
+// Revision
+func (s *AdminGRPCServer) ListArticleRevisions(ctx context.Context, req *pb.Id) (*pb.RevisionListResponse, error) {
+	list, err := s.app.ListRevisions(ctx, req.Id)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*pb.ArticleRevision, 0, len(list))
+	for _, r := range list {
+		pr := toPBRevision(r)
+		pr.Content = ""
+		out = append(out, pr)
+	}
+	return &pb.RevisionListResponse{Data: out, Total: int64(len(out))}, nil
+}
+func (s *AdminGRPCServer) GetArticleRevision(ctx context.Context, req *pb.RevisionRequest) (*pb.ArticleRevision, error) {
+	r, err := s.app.GetRevision(ctx, req.ArticleId, req.RevisionId)
+	if err != nil {
+		return nil, err
+	}
+	return toPBRevision(r), nil
+}
+func (s *AdminGRPCServer) DiffArticleRevisions(ctx context.Context, req *pb.RevisionDiffRequest) (*pb.RevisionDiff, error) {
+	d, err := s.app.DiffRevisions(ctx, req.ArticleId, req.FromId, req.ToId, req.Mode)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RevisionDiff{
+		FromId: d.FromID, ToId: d.ToID, Mode: d.Mode, Unified: d.Unified,
+		Title: toPBSegments(d.Title), Summary: toPBSegments(d.Summary), Content: toPBSegments(d.Content),
+	}, nil
+}
+func (s *AdminGRPCServer) RestoreArticleRevision(ctx context.Context, req *pb.RevisionRequest) (*pb.Empty, error) {
+	return &pb.Empty{}, errStatus(s.app.RestoreRevision(ctx, req.ArticleId, req.RevisionId))
+}
+
+// toPBRevision 历史版本转换为 pb
+func toPBRevision(r *domain.ArticleRevision) *pb.ArticleRevision {
+	out := &pb.ArticleRevision{
+		Id: r.ID, ArticleId: r.ArticleID, Title: r.Title, Slug: r.Slug, Content: r.Content,
+		CategoryId: r.CategoryID, CreatedAt: r.CreatedAt.Format(time.RFC3339),
+	}
+	if r.Summary != nil && r.Summary.Valid {
+		out.Summary = r.Summary.String
+	}
+	if r.Cover != nil && r.Cover.Valid {
+		out.Cover = r.Cover.String
+	}
+	return out
+}
+
+func toPBSegments(list []textdiff.Segment) []*pb.DiffSegment {
+	out := make([]*pb.DiffSegment, 0, len(list))
+	for _, seg := range list {
+		out = append(out, &pb.DiffSegment{Op: string(seg.Op), Text: seg.Text})
+	}
+	return out
+}
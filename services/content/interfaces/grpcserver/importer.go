@@ -0,0 +1,31 @@
+package grpcserver
+
+import (
+	"context"
+
+	"blog-system/services/content/infrastructure/importer"
+	pb "blog-system/services/content/proto"
+)
+
+// ImportArticles 导入 zip 中的 Markdown 文章（管理端上传）
+func (s *AdminGRPCServer) ImportArticles(ctx context.Context, req *pb.ImportRequest) (*pb.ImportReport, error) {
+	src, err := importer.FromZip(req.Archive)
+	if err != nil {
+		return nil, errStatus(err)
+	}
+	r, err := s.imports.Import(ctx, src, req.AuthorId, req.DryRun)
+	if err != nil {
+		return nil, errStatus(err)
+	}
+	out := &pb.ImportReport{
+		DryRun: r.DryRun, Total: int32(r.Total), Created: int32(r.Created), Updated: int32(r.Updated),
+		Unchanged: int32(r.Unchanged), Failed: int32(r.Failed), NewTags: r.NewTags, NewCategories: r.NewCategories,
+	}
+	for _, it := range r.Items {
+		out.Items = append(out.Items, &pb.ImportItem{
+			File: it.File, Slug: it.Slug, Title: it.Title, Action: it.Action, ArticleId: it.ArticleID,
+			Images: int32(it.Images), MissingImages: it.Missing, Error: it.Error,
+		})
+	}
+	return out, nil
+}
@@ -0,0 +1,53 @@
+package grpcserver
+
+import (
+	"context"
+	"time"
+
+	"blog-system/common/pkg/pagination"
+	"blog-system/services/content/domain"
+	pb "blog-system/services/content/proto"
+)
+
+// Media 文件管理
+func (s *AdminGRPCServer) ListMedia(ctx context.Context, req *pb.ListMediaRequest) (*pb.MediaListResponse, error) {
+	page, pageSize := max(int(req.Page), 1), pagination.Size(int(req.PageSize), 20)
+	var (
+		list  []*domain.Media
+		total int64
+		err   error
+	)
+	if req.Orphan {
+		list, total, err = s.media.Orphans(ctx, time.Duration(req.OlderThanSeconds)*time.Second, page, pageSize)
+	} else {
+		list, total, err = s.media.List(ctx, domain.MediaFilter{
+			MimePrefix: req.MimePrefix, UploaderID: req.UploaderId, Page: page, PageSize: pageSize,
+		})
+	}
+	if err != nil {
+		return nil, errStatus(err)
+	}
+	out := &pb.MediaListResponse{Total: total}
+	for _, m := range list {
+		out.Data = append(out.Data, toPBMedia(m))
+	}
+	return out, nil
+}
+
+func (s *AdminGRPCServer) DeleteMedia(ctx context.Context, req *pb.DeleteMediaRequest) (*pb.Empty, error) {
+	return &pb.Empty{}, errStatus(s.media.Delete(ctx, req.Id, req.Force))
+}
+
+func toPBMedia(m *domain.Media) *pb.Media {
+	out := &pb.Media{
+		Id: m.ID, Hash: m.Hash, StorageKey: m.StorageKey, Url: m.URL, Name: m.Name, MimeType: m.MimeType,
+		Size: m.Size, Width: int32(m.Width), Height: int32(m.Height), UploaderId: m.UploaderID,
+		CreatedAt: m.CreatedAt.Format(time.RFC3339),
+	}
+	for _, v := range m.Variants {
+		out.Variants = append(out.Variants, &pb.MediaVariant{
+			Name: v.Name, StorageKey: v.StorageKey, Url: v.URL, Width: int32(v.Width), Height: int32(v.Height), Size: v.Size,
+		})
+	}
+	return out
+}
@@ -0,0 +1,91 @@
+package httpserver
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"blog-system/common/pkg/dto"
+	"blog-system/common/pkg/errcode"
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/web"
+)
+
+// Archive 归档树：年 -> 月及各自的已公开文章数
+func (s *HTTPServer) Archive(ctx *web.Context) {
+	tree, err := s.contentService.Archive(ctx.Req.Context())
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(tree))
+}
+
+// ArchiveMonth 某年某月发布的文章列表
+func (s *HTTPServer) ArchiveMonth(ctx *web.Context) {
+	year, month, ok := pathYearMonth(ctx)
+	if !ok {
+		return
+	}
+	page, pageSize := parsePagination(ctx)
+	list, total, err := s.contentService.ArchiveMonth(ctx.Req.Context(), year, month, page, pageSize)
+	if err != nil {
+		respArchiveErr(ctx, err)
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(dto.PageResponse[*domain.ArticleSummary]{
+		List: list, Total: total, Page: page, PageSize: pageSize,
+	}))
+}
+
+// ArchiveCalendar 某年某月的日历视图：每日发布的文章数
+func (s *HTTPServer) ArchiveCalendar(ctx *web.Context) {
+	year, month, ok := pathYearMonth(ctx)
+	if !ok {
+		return
+	}
+	days, err := s.contentService.ArchiveCalendar(ctx.Req.Context(), year, month)
+	if err != nil {
+		respArchiveErr(ctx, err)
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(days))
+}
+
+// GetArticleNav 上一篇 / 下一篇，same_category=true 时限定同一分类
+func (s *HTTPServer) GetArticleNav(ctx *web.Context) {
+	id, err := ctx.PathValue("article_id").AsInt64()
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	sameCategory, _ := strconv.ParseBool(ctx.Req.URL.Query().Get("same_category"))
+	nav, err := s.contentService.Nav(ctx.Req.Context(), id, viewerOf(ctx), sameCategory)
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusNotFound, dto.Error(errcode.ErrArticleNotFound, err.Error()))
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(nav))
+}
+
+// pathYearMonth 解析路径中的 :year/:month，失败时已写入 400 响应
+func pathYearMonth(ctx *web.Context) (int, int, bool) {
+	year, err := ctx.PathValue("year").AsInt64()
+	if err == nil {
+		var month int64
+		if month, err = ctx.PathValue("month").AsInt64(); err == nil {
+			return int(year), int(month), true
+		}
+	}
+	_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+	return 0, 0, false
+}
+
+func respArchiveErr(ctx *web.Context, err error) {
+	if errors.Is(err, domain.ErrArchiveMonth) {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+}
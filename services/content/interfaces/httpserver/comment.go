@@ -0,0 +1,119 @@
+package httpserver
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"blog-system/common/pkg/dto"
+	"blog-system/common/pkg/errcode"
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/web"
+)
+
+// ListComments 文章评论楼层分页：顶层评论新到旧，每层附带全部回复
+func (s *HTTPServer) ListComments(ctx *web.Context) {
+	articleID, err := strconv.ParseInt(ctx.Req.URL.Query().Get("article_id"), 10, 64)
+	if err != nil || articleID <= 0 {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "缺少 article_id"))
+		return
+	}
+	page, pageSize := parsePagination(ctx)
+	list, total, err := s.commentService.Threads(ctx.Req.Context(), articleID, viewerOf(ctx), page, pageSize)
+	if err != nil {
+		respCommentErr(ctx, err)
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(dto.PageResponse[*domain.CommentThread]{
+		List: list, Total: total, Page: page, PageSize: pageSize,
+	}))
+}
+
+// CountComments 逗号分隔的 article_ids 各自的可见评论数
+func (s *HTTPServer) CountComments(ctx *web.Context) {
+	var ids []int64
+	for _, p := range strings.Split(ctx.Req.URL.Query().Get("article_ids"), ",") {
+		if id, err := strconv.ParseInt(strings.TrimSpace(p), 10, 64); err == nil && id > 0 {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "缺少 article_ids"))
+		return
+	}
+	counts, err := s.commentService.Counts(ctx.Req.Context(), ids)
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(counts))
+}
+
+// PostComment 发表评论或回复（parent_id 为被回复的评论），作者取自 X-User-ID
+func (s *HTTPServer) PostComment(ctx *web.Context) {
+	var req struct {
+		ArticleID int64  `json:"article_id"`
+		ParentID  int64  `json:"parent_id"`
+		Content   string `json:"content"`
+	}
+	if err := ctx.BindJSON(&req); err != nil || req.ArticleID <= 0 {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "参数错误"))
+		return
+	}
+	c := &domain.Comment{ArticleID: req.ArticleID, ParentID: req.ParentID, Content: req.Content, IP: clientIP(ctx)}
+	if err := s.commentService.Post(ctx.Req.Context(), c, viewerOf(ctx)); err != nil {
+		respCommentErr(ctx, err)
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(c))
+}
+
+// DeleteComment 删除自己的评论（软删除）
+func (s *HTTPServer) DeleteComment(ctx *web.Context) {
+	id, err := ctx.PathValue("id").AsInt64()
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	if err := s.commentService.DeleteOwn(ctx.Req.Context(), id, viewerOf(ctx).UserID); err != nil {
+		respCommentErr(ctx, err)
+		return
+	}
+	_ = ctx.RespJSONOK(dto.SuccessNil())
+}
+
+// respCommentErr 评论错误映射为 HTTP 状态码
+func respCommentErr(ctx *web.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrCommentLogin):
+		_ = ctx.RespJSON(http.StatusUnauthorized, dto.Error(errcode.ErrUnauthorized, err.Error()))
+	case errors.Is(err, domain.ErrCommentForbidden):
+		_ = ctx.RespJSON(http.StatusForbidden, dto.Error(errcode.ErrForbidden, err.Error()))
+	case errors.Is(err, domain.ErrCommentNotFound):
+		_ = ctx.RespJSON(http.StatusNotFound, dto.Error(errcode.ErrCommentNotFound, err.Error()))
+	case errors.Is(err, domain.ErrCommentArticle):
+		_ = ctx.RespJSON(http.StatusNotFound, dto.Error(errcode.ErrArticleNotFound, err.Error()))
+	case errors.Is(err, domain.ErrCommentRateLimited):
+		_ = ctx.RespJSON(http.StatusTooManyRequests, dto.Error(errcode.ErrTooManyRequests, err.Error()))
+	case errors.Is(err, domain.ErrCommentEmpty), errors.Is(err, domain.ErrCommentTooLong),
+		errors.Is(err, domain.ErrCommentParent), errors.Is(err, domain.ErrCommentStatus):
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+	default:
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+	}
+}
+
+// clientIP 客户端地址：优先取网关追加的 X-Forwarded-For 首个地址，去掉端口
+func clientIP(ctx *web.Context) string {
+	addr := ctx.Req.RemoteAddr
+	if xff := ctx.Req.Header.Get("X-Forwarded-For"); xff != "" {
+		addr = strings.TrimSpace(strings.Split(xff, ",")[0])
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		return host
+	}
+	return addr
+}
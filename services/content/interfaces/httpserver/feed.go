@@ -0,0 +1,324 @@
+package httpserver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"blog-system/common/pkg/dto"
+	"blog-system/common/pkg/errcode"
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/web"
+)
+
+// feedContentTypes 各订阅格式的 Content-Type
+var feedContentTypes = map[string]string{
+	domain.FeedRSS:  "application/rss+xml; charset=utf-8",
+	domain.FeedAtom: "application/atom+xml; charset=utf-8",
+	domain.FeedJSON: "application/feed+json; charset=utf-8",
+}
+
+// registerFeedRoutes 全站、分类（含子分类）与标签订阅源：/api/feed/<format>[/category|tag/:slug]
+func (s *HTTPServer) registerFeedRoutes() {
+	for _, f := range domain.FeedFormats {
+		s.server.Get("/api/feed/"+f, s.feedHandler(f, ""))
+		s.server.Get("/api/feed/"+f+"/category/:slug", s.feedHandler(f, domain.SlugKindCategory))
+		s.server.Get("/api/feed/"+f+"/tag/:slug", s.feedHandler(f, domain.SlugKindTag))
+	}
+}
+
+// feedHandler 输出订阅源，支持 If-None-Match / If-Modified-Since 条件请求；旧别名 301 到新别名
+func (s *HTTPServer) feedHandler(format, kind string) web.Handler {
+	return func(ctx *web.Context) {
+		scope := domain.FeedScope{Kind: kind}
+		if kind != "" {
+			slug, err := ctx.PathValue("slug").String()
+			if err != nil {
+				_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+				return
+			}
+			if kind == domain.SlugKindCategory {
+				c, err := s.contentService.GetCategoryBySlug(ctx.Req.Context(), slug)
+				if err != nil {
+					if !redirectSlug(ctx, err) {
+						_ = ctx.RespJSON(http.StatusNotFound, dto.Error(errcode.ErrCategoryNotFound, err.Error()))
+					}
+					return
+				}
+				scope.ID, scope.Name, scope.Slug = c.ID, c.Name, c.Slug
+			} else {
+				t, err := s.contentService.GetTagBySlug(ctx.Req.Context(), slug)
+				if err != nil {
+					if !redirectSlug(ctx, err) {
+						_ = ctx.RespJSON(http.StatusNotFound, dto.Error(errcode.ErrTagNotFound, err.Error()))
+					}
+					return
+				}
+				scope.ID, scope.Name, scope.Slug = t.ID, t.Name, t.Slug
+			}
+		}
+		feed, err := s.contentService.Feed(ctx.Req.Context(), scope)
+		if err != nil {
+			_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+			return
+		}
+		body, err := encodeFeed(format, feed)
+		if err != nil {
+			_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+			return
+		}
+		respConditional(ctx, body, feedContentTypes[format], feed.Updated)
+	}
+}
+
+// respConditional 输出可缓存的原始响应体：以内容哈希为 ETag、updated 为 Last-Modified，命中条件请求时返回 304
+func respConditional(ctx *web.Context, body []byte, contentType string, updated time.Time) {
+	sum := sha256.Sum256(body)
+	etag := strconv.Quote(hex.EncodeToString(sum[:16]))
+	h := ctx.Resp.Header()
+	h.Set("ETag", etag)
+	if !updated.IsZero() {
+		h.Set("Last-Modified", updated.UTC().Format(http.TimeFormat))
+	}
+	h.Set("Cache-Control", "public, max-age=300")
+	if notModified(ctx.Req, etag, updated) {
+		ctx.RespCode = http.StatusNotModified
+		return
+	}
+	h.Set("Content-Type", contentType)
+	ctx.RespCode = http.StatusOK
+	ctx.RespData = body
+}
+
+// notModified 条件请求判断：携带 If-None-Match 时只比较 ETag，否则比较 If-Modified-Since（秒级）
+func notModified(req *http.Request, etag string, updated time.Time) bool {
+	if inm := req.Header.Get("If-None-Match"); inm != "" {
+		for _, t := range strings.Split(inm, ",") {
+			t = strings.TrimPrefix(strings.TrimSpace(t), "W/")
+			if t == etag || t == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := req.Header.Get("If-Modified-Since"); ims != "" && !updated.IsZero() {
+		if t, err := http.ParseTime(ims); err == nil {
+			return !updated.Truncate(time.Second).After(t)
+		}
+	}
+	return false
+}
+
+// encodeFeed 按格式序列化订阅源
+func encodeFeed(format string, f *domain.Feed) ([]byte, error) {
+	switch format {
+	case domain.FeedAtom:
+		return marshalXML(toAtom(f))
+	case domain.FeedJSON:
+		return json.MarshalIndent(toJSONFeed(f), "", "  ")
+	default:
+		return marshalXML(toRSS(f))
+	}
+}
+
+func marshalXML(v any) ([]byte, error) {
+	b, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
+
+// RSS 2.0
+type rssFeed struct {
+	XMLName   xml.Name   `xml:"rss"`
+	Version   string     `xml:"version,attr"`
+	ContentNS string     `xml:"xmlns:content,attr"`
+	DCNS      string     `xml:"xmlns:dc,attr"`
+	Channel   rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string    `xml:"title"`
+	Link        string    `xml:"link"`
+	GUID        rssGUID   `xml:"guid"`
+	Description string    `xml:"description"`
+	Content     *xmlCDATA `xml:"content:encoded,omitempty"`
+	Creator     string    `xml:"dc:creator,omitempty"`
+	Categories  []string  `xml:"category"`
+	PubDate     string    `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type xmlCDATA struct {
+	Text string `xml:",cdata"`
+}
+
+func toRSS(f *domain.Feed) *rssFeed {
+	ch := rssChannel{Title: f.Title, Link: f.Link, Description: f.Description}
+	if !f.Updated.IsZero() {
+		ch.LastBuildDate = f.Updated.Format(time.RFC1123Z)
+	}
+	for _, it := range f.Items {
+		item := rssItem{
+			Title: it.Title, Link: it.Link, GUID: rssGUID{IsPermaLink: true, Value: it.Link},
+			Description: it.Summary, Creator: it.Author, PubDate: it.PublishedAt.Format(time.RFC1123Z),
+		}
+		if it.ContentHTML != "" {
+			item.Content = &xmlCDATA{Text: it.ContentHTML}
+		}
+		if it.Category != "" {
+			item.Categories = append(item.Categories, it.Category)
+		}
+		item.Categories = append(item.Categories, it.Tags...)
+		ch.Items = append(ch.Items, item)
+	}
+	return &rssFeed{
+		Version: "2.0", ContentNS: "http://purl.org/rss/1.0/modules/content/",
+		DCNS: "http://purl.org/dc/elements/1.1/", Channel: ch,
+	}
+}
+
+// Atom 1.0
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	ID       string      `xml:"id"`
+	Link     atomLink    `xml:"link"`
+	Updated  string      `xml:"updated"`
+	Author   atomPerson  `xml:"author"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Author     *atomPerson    `xml:"author,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Summary    *atomText      `xml:"summary,omitempty"`
+	Content    *atomText      `xml:"content,omitempty"`
+}
+
+func toAtom(f *domain.Feed) *atomFeed {
+	out := &atomFeed{
+		Title: f.Title, Subtitle: f.Description, ID: f.Link,
+		Link: atomLink{Href: f.Link, Rel: "alternate"}, Updated: f.Updated.UTC().Format(time.RFC3339),
+		Author: atomPerson{Name: f.Title},
+	}
+	for _, it := range f.Items {
+		e := atomEntry{
+			Title: it.Title, ID: it.Link, Link: atomLink{Href: it.Link, Rel: "alternate"},
+			Published: it.PublishedAt.UTC().Format(time.RFC3339), Updated: it.UpdatedAt.UTC().Format(time.RFC3339),
+		}
+		if it.Author != "" {
+			e.Author = &atomPerson{Name: it.Author}
+		}
+		if it.Category != "" {
+			e.Categories = append(e.Categories, atomCategory{Term: it.Category})
+		}
+		for _, t := range it.Tags {
+			e.Categories = append(e.Categories, atomCategory{Term: t})
+		}
+		if it.Summary != "" {
+			e.Summary = &atomText{Type: "text", Body: it.Summary}
+		}
+		if it.ContentHTML != "" {
+			e.Content = &atomText{Type: "html", Body: it.ContentHTML}
+		}
+		out.Entries = append(out.Entries, e)
+	}
+	return out
+}
+
+// JSON Feed 1.1
+type jsonFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url,omitempty"`
+	Description string         `json:"description,omitempty"`
+	Items       []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string           `json:"id"`
+	URL           string           `json:"url"`
+	Title         string           `json:"title"`
+	Summary       string           `json:"summary,omitempty"`
+	ContentHTML   string           `json:"content_html,omitempty"`
+	ContentText   string           `json:"content_text,omitempty"`
+	DatePublished string           `json:"date_published"`
+	DateModified  string           `json:"date_modified"`
+	Authors       []jsonFeedAuthor `json:"authors,omitempty"`
+	Tags          []string         `json:"tags,omitempty"`
+}
+
+type jsonFeedAuthor struct {
+	Name string `json:"name"`
+}
+
+func toJSONFeed(f *domain.Feed) *jsonFeed {
+	out := &jsonFeed{
+		Version: "https://jsonfeed.org/version/1.1", Title: f.Title, HomePageURL: f.Link,
+		Description: f.Description, Items: make([]jsonFeedItem, 0, len(f.Items)),
+	}
+	for _, it := range f.Items {
+		item := jsonFeedItem{
+			ID: strconv.FormatInt(it.ID, 10), URL: it.Link, Title: it.Title, Summary: it.Summary,
+			ContentHTML:   it.ContentHTML,
+			DatePublished: it.PublishedAt.Format(time.RFC3339), DateModified: it.UpdatedAt.Format(time.RFC3339),
+		}
+		// 条目须带 content_html 或 content_text 之一
+		if item.ContentHTML == "" {
+			item.ContentText = it.Summary
+		}
+		if it.Author != "" {
+			item.Authors = []jsonFeedAuthor{{Name: it.Author}}
+		}
+		if it.Category != "" {
+			item.Tags = append(item.Tags, it.Category)
+		}
+		item.Tags = append(item.Tags, it.Tags...)
+		out.Items = append(out.Items, item)
+	}
+	return out
+}
@@ -4,9 +4,14 @@ import (
 	"blog-system/common/pkg/dto"
 	"blog-system/common/pkg/errcode"
 	"blog-system/common/pkg/logger"
+	"blog-system/common/pkg/pagination"
+	"blog-system/common/pkg/perm"
+	"blog-system/common/pkg/util"
 	"blog-system/services/content/application"
 	"blog-system/services/content/domain"
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -19,10 +24,12 @@ import (
 
 type HTTPServer struct {
 	contentService *application.ContentAppService
+	commentService *application.CommentAppService
+	mediaService   *application.MediaAppService
 	server         *web.HTTPServer
 }
 
-func NewHTTPServer(contentService *application.ContentAppService) *HTTPServer {
+func NewHTTPServer(contentService *application.ContentAppService, commentService *application.CommentAppService, mediaService *application.MediaAppService) *HTTPServer {
 	// Request ID 中间件
 	requestIDMiddleware := func(next web.Handler) web.Handler {
 		return func(ctx *web.Context) {
@@ -41,7 +48,7 @@ func NewHTTPServer(contentService *application.ContentAppService) *HTTPServer {
 		),
 	)
 
-	s := &HTTPServer{contentService: contentService, server: server}
+	s := &HTTPServer{contentService: contentService, commentService: commentService, mediaService: mediaService, server: server}
 	s.registerRoutes()
 	return s
 }
@@ -54,17 +61,37 @@ func (s *HTTPServer) registerRoutes() {
 	s.server.Get("/api/article/:article_id", s.GetArticle)
 	s.server.Get("/api/article/list", s.ListArticleSummaries)
 	s.server.Get("/api/article/search", s.SearchArticles)
+	s.server.Get("/api/article/slug/:slug", s.GetArticleBySlug)
 	s.server.Get("/api/category/list", s.ListCategories)
+	s.server.Get("/api/category/tree", s.CategoryTree)
+	s.server.Get("/api/category/slug/:slug", s.GetCategoryBySlug)
 	s.server.Get("/api/tag/list", s.ListTags)
+	s.server.Get("/api/tag/slug/:slug", s.GetTagBySlug)
+	s.server.Get("/api/comment/list", s.ListComments)
+	s.server.Get("/api/comment/count", s.CountComments)
+	s.server.Post("/api/comment", s.PostComment)
+	s.server.Post("/api/comment/delete/:id", s.DeleteComment)
+	s.registerFeedRoutes()
+	s.server.Get("/api/sitemap.xml", s.SitemapIndex)
+	s.server.Get("/api/sitemap/:file", s.Sitemap)
+	s.server.Get("/api/article/:article_id/seo", s.GetArticleSEO)
+	s.server.Get("/api/article/:article_id/related", s.GetArticleRelated)
+	s.server.Get("/api/article/:article_id/nav", s.GetArticleNav)
+	s.server.Get("/api/archive", s.Archive)
+	s.server.Get("/api/archive/:year/:month", s.ArchiveMonth)
+	s.server.Get("/api/archive/:year/:month/calendar", s.ArchiveCalendar)
+	s.server.Post("/api/media/upload", s.UploadMedia)
+	s.server.Get(mediaFilePrefix+"*", s.ServeMedia)
 }
 
+// GetArticle 文章详情：原文及渲染后的 HTML、目录、字数与阅读时长
 func (s *HTTPServer) GetArticle(ctx *web.Context) {
 	id, err := ctx.PathValue("article_id").AsInt64()
 	if err != nil {
 		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
 		return
 	}
-	art, err := s.contentService.GetByID(ctx.Req.Context(), id)
+	art, err := s.contentService.GetVisibleDetail(ctx.Req.Context(), id, viewerOf(ctx))
 	if err != nil {
 		_ = ctx.RespJSON(http.StatusNotFound, dto.Error(errcode.ErrInternal, err.Error()))
 		return
@@ -72,32 +99,56 @@ func (s *HTTPServer) GetArticle(ctx *web.Context) {
 	_ = ctx.RespJSONOK(dto.Success(art))
 }
 
-// ListArticleSummaries 文章列表（ID+Title），支持分类与标签过滤
-func (s *HTTPServer) ListArticleSummaries(ctx *web.Context) {
-	q := ctx.Req.URL.Query()
-	var (
-		categoryID *int64
-		tagIDs     []int64
-	)
-	if v := q.Get("category_id"); v != "" {
-		if id, err := strconv.ParseInt(v, 10, 64); err == nil && id > 0 {
-			categoryID = &id
-		}
+// GetArticleRelated 相关文章推荐：共有标签（按稀有度加权）、同分类与标题/摘要相似度综合打分，编辑推荐置顶
+func (s *HTTPServer) GetArticleRelated(ctx *web.Context) {
+	id, err := ctx.PathValue("article_id").AsInt64()
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
 	}
-	if v := q.Get("tag_ids"); v != "" {
-		parts := strings.Split(v, ",")
-		for _, p := range parts {
-			p = strings.TrimSpace(p)
-			if p == "" {
-				continue
-			}
-			if id, err := strconv.ParseInt(p, 10, 64); err == nil && id > 0 {
-				tagIDs = append(tagIDs, id)
-			}
+	limit, _ := strconv.Atoi(ctx.Req.URL.Query().Get("limit"))
+	list, err := s.contentService.Related(ctx.Req.Context(), id, viewerOf(ctx), limit)
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusNotFound, dto.Error(errcode.ErrArticleNotFound, err.Error()))
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(list))
+}
+
+// GetArticleBySlug 按别名获取文章详情，旧别名 301 重定向到新别名
+func (s *HTTPServer) GetArticleBySlug(ctx *web.Context) {
+	slug, err := ctx.PathValue("slug").String()
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	art, err := s.contentService.GetVisibleDetailBySlug(ctx.Req.Context(), slug, viewerOf(ctx))
+	if err != nil {
+		if !redirectSlug(ctx, err) {
+			_ = ctx.RespJSON(http.StatusNotFound, dto.Error(errcode.ErrInternal, err.Error()))
 		}
+		return
 	}
+	_ = ctx.RespJSONOK(dto.Success(art))
+}
+
+// ListArticleSummaries 文章列表（ID+Title），支持分类与标签过滤，按读取方可见性返回；
+// 带 cursor 参数（首页为空值）时使用游标分页
+func (s *HTTPServer) ListArticleSummaries(ctx *web.Context) {
+	categoryID, tagIDs := parseFilters(ctx)
 	page, pageSize := parsePagination(ctx)
-	list, total, err := s.contentService.ListSummariesFiltered(ctx.Req.Context(), categoryID, tagIDs, page, pageSize)
+	if q := ctx.Req.URL.Query(); q.Has("cursor") {
+		list, next, err := s.contentService.ListSummariesAfter(ctx.Req.Context(), viewerOf(ctx), categoryID, tagIDs, q.Get("cursor"), pageSize)
+		if err != nil {
+			respListErr(ctx, err)
+			return
+		}
+		_ = ctx.RespJSONOK(dto.Success(dto.CursorResponse[*domain.ArticleSummary]{
+			List: list, NextCursor: next, HasMore: next != "", PageSize: pageSize,
+		}))
+		return
+	}
+	list, total, err := s.contentService.ListSummariesFiltered(ctx.Req.Context(), viewerOf(ctx), categoryID, tagIDs, page, pageSize)
 	if err != nil {
 		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
 		return
@@ -107,20 +158,47 @@ func (s *HTTPServer) ListArticleSummaries(ctx *web.Context) {
 	}))
 }
 
-// SearchArticles 根据内容全文搜索文章（返回摘要列表）
+// SearchArticles 全文检索（标题/摘要/正文），按相关度排序，返回高亮片段与分类/标签分面；
+// 带 cursor 时忽略 page，返回游标之后的一页
 func (s *HTTPServer) SearchArticles(ctx *web.Context) {
 	q := ctx.Req.URL.Query().Get("q")
-	if q == "" {
+	if strings.TrimSpace(q) == "" {
 		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "缺少查询关键词 q"))
 		return
 	}
+	after, err := pagination.Decode(ctx.Req.URL.Query().Get("cursor"))
+	if err != nil {
+		respListErr(ctx, err)
+		return
+	}
+	categoryID, tagIDs := parseFilters(ctx)
 	page, pageSize := parsePagination(ctx)
-	list, total, err := s.contentService.SearchSummaries(ctx.Req.Context(), q, page, pageSize)
+	res, err := s.contentService.Search(ctx.Req.Context(), domain.SearchQuery{
+		Keyword: q, CategoryID: categoryID, TagIDs: tagIDs, Page: page, PageSize: pageSize, After: after,
+	})
 	if err != nil {
 		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
 		return
 	}
-	_ = ctx.RespJSONOK(dto.Success(dto.PageResponse[*domain.ArticleSummary]{List: list, Total: total, Page: page, PageSize: pageSize}))
+	type SearchVO struct {
+		dto.PageResponse[*domain.SearchItem]
+		Facets     *domain.SearchFacets `json:"facets"`
+		NextCursor string               `json:"next_cursor"`
+	}
+	_ = ctx.RespJSONOK(dto.Success(SearchVO{
+		PageResponse: dto.PageResponse[*domain.SearchItem]{List: res.Items, Total: res.Total, Page: page, PageSize: pageSize},
+		Facets:       res.Facets,
+		NextCursor:   res.NextCursor,
+	}))
+}
+
+// respListErr 游标不合法返回 400，其余为 500
+func respListErr(ctx *web.Context, err error) {
+	if errors.Is(err, pagination.ErrInvalidCursor) {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
 }
 
 // ListCategories 返回分类全量列表
@@ -133,6 +211,54 @@ func (s *HTTPServer) ListCategories(ctx *web.Context) {
 	_ = ctx.RespJSONOK(dto.Success(list))
 }
 
+// CategoryTree 分类树
+func (s *HTTPServer) CategoryTree(ctx *web.Context) {
+	tree, err := s.contentService.CategoryTree(ctx.Req.Context())
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(tree))
+}
+
+// GetCategoryBySlug 按别名获取分类，旧别名 301 重定向到新别名
+func (s *HTTPServer) GetCategoryBySlug(ctx *web.Context) {
+	slug, err := ctx.PathValue("slug").String()
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	c, err := s.contentService.GetCategoryBySlug(ctx.Req.Context(), slug)
+	if err != nil {
+		if !redirectSlug(ctx, err) {
+			_ = ctx.RespJSON(http.StatusNotFound, dto.Error(errcode.ErrInternal, err.Error()))
+		}
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(c))
+}
+
+// GetTagBySlug 按别名获取标签，旧别名 301 重定向到新别名
+func (s *HTTPServer) GetTagBySlug(ctx *web.Context) {
+	slug, err := ctx.PathValue("slug").String()
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	t, err := s.contentService.GetTagBySlug(ctx.Req.Context(), slug)
+	if err != nil {
+		if !redirectSlug(ctx, err) {
+			_ = ctx.RespJSON(http.StatusNotFound, dto.Error(errcode.ErrInternal, err.Error()))
+		}
+		return
+	}
+	color := ""
+	if t.Color != nil && t.Color.Valid {
+		color = t.Color.String
+	}
+	_ = ctx.RespJSONOK(dto.Success(domain.TagBrief{ID: t.ID, Name: t.Name, Slug: t.Slug, Color: color}))
+}
+
 // ListTags 标签列表，返回每个标签及 count（文章数）
 func (s *HTTPServer) ListTags(ctx *web.Context) {
 	tagsWithCount, err := s.contentService.ListAllTagsWithCount(ctx.Req.Context())
@@ -158,21 +284,75 @@ func (s *HTTPServer) ListTags(ctx *web.Context) {
 	_ = ctx.RespJSONOK(dto.Success(out))
 }
 
-// parsePagination 统一分页解析
-func parsePagination(ctx *web.Context) (int, int) {
-	page := 1
-	pageSize := 10
-	if v := ctx.Req.URL.Query().Get("page"); v != "" {
-		if p, err := strconv.Atoi(v); err == nil && p > 0 {
-			page = p
+// redirectSlug 按旧别名访问时 301 重定向到新别名：Location 为相对地址（兼容网关路径前缀），保留查询参数
+func redirectSlug(ctx *web.Context, err error) bool {
+	var moved *domain.SlugMovedError
+	if !errors.As(err, &moved) {
+		return false
+	}
+	loc := url.PathEscape(moved.Slug)
+	if q := ctx.Req.URL.RawQuery; q != "" {
+		loc += "?" + q
+	}
+	ctx.Resp.Header().Set("Location", loc)
+	_ = ctx.RespJSON(http.StatusMovedPermanently, dto.Success(map[string]string{"slug": moved.Slug}))
+	return true
+}
+
+// viewerOf 读取方身份：X-User-ID 为网关透传的登录用户；携带 article:edit 权限令牌的视为管理员
+func viewerOf(ctx *web.Context) domain.Viewer {
+	var v domain.Viewer
+	v.UserID, _ = strconv.ParseInt(ctx.Req.Header.Get("X-User-ID"), 10, 64)
+	if claims := claimsOf(ctx, v.UserID); claims != nil {
+		v.UserID = claims.UserID
+		v.Admin = claims.HasPermission(perm.ArticleEdit)
+	}
+	return v
+}
+
+// claimsOf 解析 Authorization 中的令牌；无令牌、令牌无效或与网关透传的 uid（非零时）不一致时返回 nil
+func claimsOf(ctx *web.Context, uid int64) *util.Claims {
+	token := strings.TrimPrefix(ctx.Req.Header.Get("Authorization"), "Bearer ")
+	if token == "" {
+		return nil
+	}
+	claims, err := util.ParseToken(token)
+	if err != nil || (uid != 0 && claims.UserID != uid) {
+		return nil
+	}
+	return claims
+}
+
+// parseFilters 解析 category_id 与逗号分隔的 tag_ids
+func parseFilters(ctx *web.Context) (*int64, []int64) {
+	q := ctx.Req.URL.Query()
+	var (
+		categoryID *int64
+		tagIDs     []int64
+	)
+	if v := q.Get("category_id"); v != "" {
+		if id, err := strconv.ParseInt(v, 10, 64); err == nil && id > 0 {
+			categoryID = &id
 		}
 	}
-	if v := ctx.Req.URL.Query().Get("page_size"); v != "" {
-		if ps, err := strconv.Atoi(v); err == nil && ps > 0 {
-			pageSize = ps
+	if v := q.Get("tag_ids"); v != "" {
+		parts := strings.Split(v, ",")
+		for _, p := range parts {
+			p = strings.TrimSpace(p)
+			if p == "" {
+				continue
+			}
+			if id, err := strconv.ParseInt(p, 10, 64); err == nil && id > 0 {
+				tagIDs = append(tagIDs, id)
+			}
 		}
 	}
-	return page, pageSize
+	return categoryID, tagIDs
+}
+
+// parsePagination 统一分页解析（每页条数上限见 pagination.MaxPageSize）
+func parsePagination(ctx *web.Context) (int, int) {
+	return pagination.FromQuery(ctx.Req.URL.Query())
 }
 
 func (s *HTTPServer) Run(addr string) error { return s.server.Start(addr) }
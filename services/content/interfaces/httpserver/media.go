@@ -0,0 +1,107 @@
+package httpserver
+
+import (
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+
+	"blog-system/common/pkg/dto"
+	"blog-system/common/pkg/errcode"
+	"blog-system/common/pkg/perm"
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/web"
+)
+
+// mediaFilePrefix 文件访问路径前缀，其后为存储键
+const mediaFilePrefix = "/api/media/file/"
+
+// multipartOverhead 表单边界与其他字段的余量
+const multipartOverhead = 1 << 20
+
+// UploadMedia multipart 上传（字段名 file），上传者取自 X-User-ID，需 media:upload 权限并按用户限流；相同内容返回已有记录
+func (s *HTTPServer) UploadMedia(ctx *web.Context) {
+	uid := viewerOf(ctx).UserID
+	if uid <= 0 {
+		respMediaErr(ctx, domain.ErrMediaLogin)
+		return
+	}
+	if claims := claimsOf(ctx, uid); claims == nil || !claims.HasPermission(perm.MediaUpload) {
+		respMediaErr(ctx, domain.ErrMediaDenied)
+		return
+	}
+	if err := s.mediaService.AllowUpload(ctx.Req.Context(), uid); err != nil {
+		respMediaErr(ctx, err)
+		return
+	}
+	ctx.Req.Body = http.MaxBytesReader(ctx.Resp, ctx.Req.Body, s.mediaService.MaxSize()+multipartOverhead)
+	file, header, err := ctx.Req.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respMediaErr(ctx, domain.ErrMediaTooLarge)
+			return
+		}
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "缺少文件字段 file"))
+		return
+	}
+	defer func() { _ = file.Close() }()
+	if ctx.Req.MultipartForm != nil {
+		defer func() { _ = ctx.Req.MultipartForm.RemoveAll() }()
+	}
+	m, err := s.mediaService.Upload(ctx.Req.Context(), path.Base(header.Filename), file, uid)
+	if err != nil {
+		respMediaErr(ctx, err)
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(m))
+}
+
+// ServeMedia 输出存储中的文件；存储键含内容哈希，内容不会变化，允许长期缓存
+func (s *HTTPServer) ServeMedia(ctx *web.Context) {
+	key := strings.TrimPrefix(ctx.Req.URL.Path, mediaFilePrefix)
+	rc, err := s.mediaService.Open(ctx.Req.Context(), key)
+	if err != nil {
+		respMediaErr(ctx, err)
+		return
+	}
+	defer func() { _ = rc.Close() }()
+	body, err := io.ReadAll(rc)
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		return
+	}
+	h := ctx.Resp.Header()
+	if ct := mime.TypeByExtension(path.Ext(key)); ct != "" {
+		h.Set("Content-Type", ct)
+	}
+	h.Set("Cache-Control", "public, max-age=31536000, immutable")
+	h.Set("X-Content-Type-Options", "nosniff")
+	ctx.RespCode = http.StatusOK
+	ctx.RespData = body
+}
+
+// respMediaErr 文件错误映射为 HTTP 状态码
+func respMediaErr(ctx *web.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrMediaLogin):
+		_ = ctx.RespJSON(http.StatusUnauthorized, dto.Error(errcode.ErrUnauthorized, err.Error()))
+	case errors.Is(err, domain.ErrMediaNotFound):
+		_ = ctx.RespJSON(http.StatusNotFound, dto.Error(errcode.ErrMediaNotFound, err.Error()))
+	case errors.Is(err, domain.ErrMediaDenied):
+		_ = ctx.RespJSON(http.StatusForbidden, dto.Error(errcode.ErrForbidden, err.Error()))
+	case errors.Is(err, domain.ErrMediaLimited):
+		_ = ctx.RespJSON(http.StatusTooManyRequests, dto.Error(errcode.ErrTooManyRequests, err.Error()))
+	case errors.Is(err, domain.ErrMediaTooLarge), errors.Is(err, domain.ErrMediaPixels):
+		_ = ctx.RespJSON(http.StatusRequestEntityTooLarge, dto.Error(errcode.ErrMediaTooLarge, err.Error()))
+	case errors.Is(err, domain.ErrMediaType):
+		_ = ctx.RespJSON(http.StatusUnsupportedMediaType, dto.Error(errcode.ErrMediaType, err.Error()))
+	case errors.Is(err, domain.ErrMediaEmpty):
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+	default:
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+	}
+}
@@ -0,0 +1,64 @@
+package httpserver
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"blog-system/common/pkg/logger"
+	"blog-system/common/pkg/perm"
+	"blog-system/common/pkg/util"
+	"blog-system/services/content/application"
+
+	"github.com/CoucouMonEcho/go-framework/web"
+)
+
+// denyAfter 前 n 次放行
+type denyAfter struct{ n int }
+
+func (l *denyAfter) Allow(context.Context, string, int, time.Duration) (bool, error) {
+	l.n--
+	return l.n >= 0, nil
+}
+
+func TestUploadMediaGate(t *testing.T) {
+	token := func(perms ...string) string {
+		tok, err := util.GenerateToken(7, "user", perms)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "Bearer " + tok
+	}
+	s := &HTTPServer{mediaService: application.NewMediaService(nil, nil, nil, &denyAfter{n: 1}, logger.Log())}
+	tests := []struct {
+		name string
+		uid  string
+		auth string
+		want int
+		body string
+	}{
+		{"anonymous", "", "", http.StatusUnauthorized, ""},
+		{"no permission", "7", token(perm.ArticleEdit), http.StatusForbidden, ""},
+		{"token of another user", "8", token(perm.MediaUpload), http.StatusForbidden, ""},
+		// 通过鉴权与限流后才读取表单
+		{"allowed", "7", token(perm.MediaUpload), http.StatusBadRequest, "file"},
+		{"rate limited", "7", token(perm.All), http.StatusTooManyRequests, ""},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/api/media/upload", strings.NewReader(""))
+		if tt.uid != "" {
+			req.Header.Set("X-User-ID", tt.uid)
+		}
+		if tt.auth != "" {
+			req.Header.Set("Authorization", tt.auth)
+		}
+		ctx := &web.Context{Req: req, Resp: httptest.NewRecorder()}
+		s.UploadMedia(ctx)
+		if ctx.RespCode != tt.want || !strings.Contains(string(ctx.RespData), tt.body) {
+			t.Errorf("%s: code=%d body=%s, want %d", tt.name, ctx.RespCode, ctx.RespData, tt.want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package httpserver
+
+import (
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"blog-system/common/pkg/dto"
+	"blog-system/common/pkg/errcode"
+	"blog-system/services/content/domain"
+
+	"github.com/CoucouMonEcho/go-framework/web"
+)
+
+// sitemapNS sitemap 协议命名空间
+const sitemapNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+const sitemapContentType = "application/xml; charset=utf-8"
+
+// SitemapIndex sitemap 索引，列出各类对象的分页 sitemap 文件
+func (s *HTTPServer) SitemapIndex(ctx *web.Context) {
+	files, err := s.contentService.SitemapIndex(ctx.Req.Context())
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		return
+	}
+	out := &sitemapIndex{XMLNS: sitemapNS}
+	var updated time.Time
+	for _, f := range files {
+		out.Sitemaps = append(out.Sitemaps, sitemapLoc{Loc: f.Loc, Lastmod: f.Lastmod.UTC().Format(time.RFC3339)})
+		if f.Lastmod.After(updated) {
+			updated = f.Lastmod
+		}
+	}
+	body, err := marshalXML(out)
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		return
+	}
+	respConditional(ctx, body, sitemapContentType, updated)
+}
+
+// Sitemap 分页 sitemap 文件，文件名形如 article-1.xml
+func (s *HTTPServer) Sitemap(ctx *web.Context) {
+	name, err := ctx.PathValue("file").String()
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	kind, page := parseSitemapName(name)
+	entries, err := s.contentService.Sitemap(ctx.Req.Context(), kind, page)
+	if err != nil {
+		if errors.Is(err, domain.ErrSitemapNotFound) {
+			_ = ctx.RespJSON(http.StatusNotFound, dto.Error(errcode.ErrParam, err.Error()))
+			return
+		}
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		return
+	}
+	out := &sitemapURLSet{XMLNS: sitemapNS}
+	var updated time.Time
+	for _, e := range entries {
+		out.URLs = append(out.URLs, sitemapLoc{Loc: e.Loc, Lastmod: e.Lastmod.UTC().Format(time.RFC3339)})
+		if e.Lastmod.After(updated) {
+			updated = e.Lastmod
+		}
+	}
+	body, err := marshalXML(out)
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		return
+	}
+	respConditional(ctx, body, sitemapContentType, updated)
+}
+
+// GetArticleSEO 文章页 SEO 信息：OpenGraph、Twitter Card 与 JSON-LD
+func (s *HTTPServer) GetArticleSEO(ctx *web.Context) {
+	id, err := ctx.PathValue("article_id").AsInt64()
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	seo, err := s.contentService.ArticleSEO(ctx.Req.Context(), id, viewerOf(ctx))
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusNotFound, dto.Error(errcode.ErrArticleNotFound, err.Error()))
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(seo))
+}
+
+// parseSitemapName 解析 <kind>-<page>.xml，格式不符时页码为 0
+func parseSitemapName(name string) (string, int) {
+	base, ok := strings.CutSuffix(name, ".xml")
+	if !ok {
+		return "", 0
+	}
+	i := strings.LastIndexByte(base, '-')
+	if i < 0 {
+		return "", 0
+	}
+	page, err := strconv.Atoi(base[i+1:])
+	if err != nil {
+		return "", 0
+	}
+	return base[:i], page
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapLoc `xml:"url"`
+}
+
+type sitemapLoc struct {
+	Loc     string `xml:"loc"`
+	Lastmod string `xml:"lastmod,omitempty"`
+}
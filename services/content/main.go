@@ -1,23 +1,36 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
 	"strconv"
+	"time"
 
 	conf "blog-system/common/pkg/config"
 	"blog-system/common/pkg/logger"
 	"blog-system/services/content/application"
+	"blog-system/services/content/domain"
 	infra "blog-system/services/content/infrastructure"
+	"blog-system/services/content/infrastructure/imaging"
+	"blog-system/services/content/infrastructure/importer"
+	"blog-system/services/content/infrastructure/markdown"
 	persistence "blog-system/services/content/infrastructure/persistence"
+	"blog-system/services/content/infrastructure/search"
 	grpcapi "blog-system/services/content/interfaces/grpcserver"
 	httpapi "blog-system/services/content/interfaces/httpserver"
 	pb "blog-system/services/content/proto"
 
+	"github.com/CoucouMonEcho/go-framework/cache"
 	"github.com/CoucouMonEcho/go-framework/micro"
 	regEtcd "github.com/CoucouMonEcho/go-framework/micro/registry/etcd"
 	clientv3 "go.etcd.io/etcd/client/v3"
+	"google.golang.org/grpc"
 )
 
+// grpcOverhead 导入请求中压缩包以外字段的余量
+const grpcOverhead = 1 << 20
+
 func main() {
 
 	cfg, err := conf.Load("content")
@@ -31,23 +44,86 @@ func main() {
 		logger.Log().Error("database: 数据库连接失败: %v", err)
 		return
 	}
-	cache, _ := infra.InitCache(cfg)
+	var (
+		c       cache.Cache
+		locker  application.Locker
+		limiter application.RateLimiter
+	)
+	if rdb, err := infra.InitRedis(cfg); err == nil {
+		c = cache.NewRedisCache(rdb)
+		locker = infra.NewRedisLocker(rdb)
+		limiter = infra.NewRedisRateLimiter(rdb)
+	} else {
+		logger.Log().Error("main: 初始化 Redis 失败: %v", err)
+	}
+
+	renderer := markdown.NewRenderer()
+	repo := persistence.NewContentRepository(db, renderer)
+	app := application.NewContentService(repo, search.NewMemoryIndex(), renderer, logger.Log(), c)
+	app.SetRevisionRetain(cfg.Revision.Retain)
+	app.SetFeedSite(domain.FeedSite{
+		Title: cfg.Feed.Title, Description: cfg.Feed.Description, Link: cfg.Feed.Link,
+		Limit: cfg.Feed.Limit, FullContent: cfg.Feed.FullContent,
+	})
+	app.SetSitemapBase(cfg.Sitemap.BaseURL)
+	// 全文索引驻留内存，启动时全量构建，之后按周期增量同步其他副本的写入
+	indexedAt := time.Now()
+	if _, err := app.RebuildSearchIndex(context.Background()); err != nil {
+		logger.Log().Error("main: 构建全文索引失败: %v", err)
+	}
+
+	comments := application.NewCommentService(repo, app, limiter, logger.Log())
+	comments.SetRateLimit(cfg.Comment.RateLimit, infra.ParseDurationOr(cfg.Comment.RateWindow, time.Minute))
+	comments.SetAutoApprove(cfg.Comment.AutoApprove)
+
+	store, err := infra.InitMediaStorage(cfg)
+	if err != nil {
+		logger.Log().Error("main: 初始化文件存储失败: %v", err)
+		return
+	}
+	media := application.NewMediaService(repo, store, imaging.NewProcessor(), limiter, logger.Log())
+	media.SetLimits(cfg.Media.MaxSize, cfg.Media.AllowedTypes)
+	media.SetMaxPixels(cfg.Media.MaxPixels)
+	media.SetRateLimit(cfg.Media.RateLimit, infra.ParseDurationOr(cfg.Media.RateWindow, time.Hour))
+	media.SetVariantWidths(cfg.Media.Variants)
+	codec := importer.NewParser()
+	imports := application.NewImportService(repo, app, media, codec, logger.Log())
+	backups := application.NewBackupService(repo, app, media, codec, codec, logger.Log())
 
-	repo := persistence.NewContentRepository(db)
-	app := application.NewContentService(repo, logger.Log(), cache)
+	// 子命令：content import [-dry-run] [-author=ID] <目录或 zip>、content export / restore
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "import":
+			os.Exit(runImport(context.Background(), imports, os.Args[2:]))
+		case "export":
+			os.Exit(runExport(context.Background(), backups, os.Args[2:]))
+		case "restore":
+			os.Exit(runRestore(context.Background(), backups, os.Args[2:]))
+		}
+	}
 
-	http := httpapi.NewHTTPServer(app)
+	// 定时发布调度（多副本通过 Redis 锁互斥）
+	go app.RunScheduler(context.Background(), locker, infra.ParseDurationOr(cfg.Scheduler.Interval, 30*time.Second))
 
-	// gRPC 服务
-	grpcSrv, _ := micro.NewServer("content-grpc")
+	go app.RunSearchSync(context.Background(), indexedAt, infra.ParseDurationOr(cfg.Search.SyncInterval, 30*time.Second))
+
+	http := httpapi.NewHTTPServer(app, comments, media)
+
+	// gRPC 服务：导入与恢复的压缩包随请求传入，单条消息上限放宽到导入大小上限
+	importMax := cfg.Import.MaxSize
+	if importMax <= 0 {
+		importMax = domain.ImportMaxSize
+	}
+	recvLimit := func(s *micro.Server) { s.Server = grpc.NewServer(grpc.MaxRecvMsgSize(int(importMax) + grpcOverhead)) }
+	grpcSrv, _ := micro.NewServer("content-grpc", recvLimit)
 	if len(cfg.Registry.Endpoints) > 0 {
 		if cli, er := clientv3.New(clientv3.Config{Endpoints: cfg.Registry.Endpoints}); er == nil {
 			if r, er2 := regEtcd.NewRegistry(cli); er2 == nil {
-				grpcSrv, _ = micro.NewServer("content-grpc", micro.ServerWithRegistry(r))
+				grpcSrv, _ = micro.NewServer("content-grpc", micro.ServerWithRegistry(r), recvLimit)
 			}
 		}
 	}
-	pb.RegisterContentAdminServiceServer(grpcSrv, grpcapi.NewAdminGRPCServer(app))
+	pb.RegisterContentAdminServiceServer(grpcSrv, grpcapi.NewAdminGRPCServer(app, comments, media, imports, backups))
 
 	// 注册到注册中心
 	if err := infra.RegisterService(cfg); err != nil {
@@ -19,21 +19,42 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	ContentAdminService_CreateArticle_FullMethodName   = "/content.ContentAdminService/CreateArticle"
-	ContentAdminService_UpdateArticle_FullMethodName   = "/content.ContentAdminService/UpdateArticle"
-	ContentAdminService_DeleteArticle_FullMethodName   = "/content.ContentAdminService/DeleteArticle"
-	ContentAdminService_ListArticles_FullMethodName    = "/content.ContentAdminService/ListArticles"
-	ContentAdminService_CountArticles_FullMethodName   = "/content.ContentAdminService/CountArticles"
-	ContentAdminService_CreateCategory_FullMethodName  = "/content.ContentAdminService/CreateCategory"
-	ContentAdminService_UpdateCategory_FullMethodName  = "/content.ContentAdminService/UpdateCategory"
-	ContentAdminService_DeleteCategory_FullMethodName  = "/content.ContentAdminService/DeleteCategory"
-	ContentAdminService_ListCategories_FullMethodName  = "/content.ContentAdminService/ListCategories"
-	ContentAdminService_CountCategories_FullMethodName = "/content.ContentAdminService/CountCategories"
-	ContentAdminService_CreateTag_FullMethodName       = "/content.ContentAdminService/CreateTag"
-	ContentAdminService_UpdateTag_FullMethodName       = "/content.ContentAdminService/UpdateTag"
-	ContentAdminService_DeleteTag_FullMethodName       = "/content.ContentAdminService/DeleteTag"
-	ContentAdminService_ListTags_FullMethodName        = "/content.ContentAdminService/ListTags"
-	ContentAdminService_CountTags_FullMethodName       = "/content.ContentAdminService/CountTags"
+	ContentAdminService_CreateArticle_FullMethodName          = "/content.ContentAdminService/CreateArticle"
+	ContentAdminService_UpdateArticle_FullMethodName          = "/content.ContentAdminService/UpdateArticle"
+	ContentAdminService_DeleteArticle_FullMethodName          = "/content.ContentAdminService/DeleteArticle"
+	ContentAdminService_GetArticle_FullMethodName             = "/content.ContentAdminService/GetArticle"
+	ContentAdminService_ListArticles_FullMethodName           = "/content.ContentAdminService/ListArticles"
+	ContentAdminService_CountArticles_FullMethodName          = "/content.ContentAdminService/CountArticles"
+	ContentAdminService_CreateCategory_FullMethodName         = "/content.ContentAdminService/CreateCategory"
+	ContentAdminService_UpdateCategory_FullMethodName         = "/content.ContentAdminService/UpdateCategory"
+	ContentAdminService_DeleteCategory_FullMethodName         = "/content.ContentAdminService/DeleteCategory"
+	ContentAdminService_ListCategories_FullMethodName         = "/content.ContentAdminService/ListCategories"
+	ContentAdminService_CountCategories_FullMethodName        = "/content.ContentAdminService/CountCategories"
+	ContentAdminService_CreateTag_FullMethodName              = "/content.ContentAdminService/CreateTag"
+	ContentAdminService_UpdateTag_FullMethodName              = "/content.ContentAdminService/UpdateTag"
+	ContentAdminService_DeleteTag_FullMethodName              = "/content.ContentAdminService/DeleteTag"
+	ContentAdminService_ListTags_FullMethodName               = "/content.ContentAdminService/ListTags"
+	ContentAdminService_CountTags_FullMethodName              = "/content.ContentAdminService/CountTags"
+	ContentAdminService_RebuildSearchIndex_FullMethodName     = "/content.ContentAdminService/RebuildSearchIndex"
+	ContentAdminService_ListScheduledArticles_FullMethodName  = "/content.ContentAdminService/ListScheduledArticles"
+	ContentAdminService_CancelScheduledArticle_FullMethodName = "/content.ContentAdminService/CancelScheduledArticle"
+	ContentAdminService_ListArticleRevisions_FullMethodName   = "/content.ContentAdminService/ListArticleRevisions"
+	ContentAdminService_GetArticleRevision_FullMethodName     = "/content.ContentAdminService/GetArticleRevision"
+	ContentAdminService_DiffArticleRevisions_FullMethodName   = "/content.ContentAdminService/DiffArticleRevisions"
+	ContentAdminService_RestoreArticleRevision_FullMethodName = "/content.ContentAdminService/RestoreArticleRevision"
+	ContentAdminService_MoveCategory_FullMethodName           = "/content.ContentAdminService/MoveCategory"
+	ContentAdminService_GetCategoryTree_FullMethodName        = "/content.ContentAdminService/GetCategoryTree"
+	ContentAdminService_DeleteCategoryWithPlan_FullMethodName = "/content.ContentAdminService/DeleteCategoryWithPlan"
+	ContentAdminService_DeleteTagWithPlan_FullMethodName      = "/content.ContentAdminService/DeleteTagWithPlan"
+	ContentAdminService_MergeTag_FullMethodName               = "/content.ContentAdminService/MergeTag"
+	ContentAdminService_ListComments_FullMethodName           = "/content.ContentAdminService/ListComments"
+	ContentAdminService_ModerateComments_FullMethodName       = "/content.ContentAdminService/ModerateComments"
+	ContentAdminService_DeleteComment_FullMethodName          = "/content.ContentAdminService/DeleteComment"
+	ContentAdminService_ListMedia_FullMethodName              = "/content.ContentAdminService/ListMedia"
+	ContentAdminService_DeleteMedia_FullMethodName            = "/content.ContentAdminService/DeleteMedia"
+	ContentAdminService_ImportArticles_FullMethodName         = "/content.ContentAdminService/ImportArticles"
+	ContentAdminService_ExportSite_FullMethodName             = "/content.ContentAdminService/ExportSite"
+	ContentAdminService_RestoreSite_FullMethodName            = "/content.ContentAdminService/RestoreSite"
 )
 
 // ContentAdminServiceClient is the client API for ContentAdminService service.
@@ -46,7 +67,8 @@ type ContentAdminServiceClient interface {
 	CreateArticle(ctx context.Context, in *Article, opts ...grpc.CallOption) (*Empty, error)
 	UpdateArticle(ctx context.Context, in *Article, opts ...grpc.CallOption) (*Empty, error)
 	DeleteArticle(ctx context.Context, in *Id, opts ...grpc.CallOption) (*Empty, error)
-	ListArticles(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ArticleListResponse, error)
+	GetArticle(ctx context.Context, in *Id, opts ...grpc.CallOption) (*Article, error)
+	ListArticles(ctx context.Context, in *ListArticlesRequest, opts ...grpc.CallOption) (*ArticleListResponse, error)
 	CountArticles(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error)
 	// 分类（全量）
 	CreateCategory(ctx context.Context, in *Category, opts ...grpc.CallOption) (*Empty, error)
@@ -60,6 +82,28 @@ type ContentAdminServiceClient interface {
 	DeleteTag(ctx context.Context, in *Id, opts ...grpc.CallOption) (*Empty, error)
 	ListTags(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*TagListResponse, error)
 	CountTags(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error)
+	// 检索
+	RebuildSearchIndex(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error)
+	// 定时发布
+	ListScheduledArticles(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ArticleListResponse, error)
+	CancelScheduledArticle(ctx context.Context, in *Id, opts ...grpc.CallOption) (*Empty, error)
+	ListArticleRevisions(ctx context.Context, in *Id, opts ...grpc.CallOption) (*RevisionListResponse, error)
+	GetArticleRevision(ctx context.Context, in *RevisionRequest, opts ...grpc.CallOption) (*ArticleRevision, error)
+	DiffArticleRevisions(ctx context.Context, in *RevisionDiffRequest, opts ...grpc.CallOption) (*RevisionDiff, error)
+	RestoreArticleRevision(ctx context.Context, in *RevisionRequest, opts ...grpc.CallOption) (*Empty, error)
+	MoveCategory(ctx context.Context, in *MoveCategoryRequest, opts ...grpc.CallOption) (*Empty, error)
+	GetCategoryTree(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*CategoryTreeResponse, error)
+	DeleteCategoryWithPlan(ctx context.Context, in *DeleteCategoryRequest, opts ...grpc.CallOption) (*CategoryDeletePlan, error)
+	DeleteTagWithPlan(ctx context.Context, in *DeleteTagRequest, opts ...grpc.CallOption) (*TagChangePlan, error)
+	MergeTag(ctx context.Context, in *MergeTagRequest, opts ...grpc.CallOption) (*TagChangePlan, error)
+	ListComments(ctx context.Context, in *ListCommentsRequest, opts ...grpc.CallOption) (*CommentListResponse, error)
+	ModerateComments(ctx context.Context, in *ModerateCommentsRequest, opts ...grpc.CallOption) (*Count, error)
+	DeleteComment(ctx context.Context, in *Id, opts ...grpc.CallOption) (*Empty, error)
+	ListMedia(ctx context.Context, in *ListMediaRequest, opts ...grpc.CallOption) (*MediaListResponse, error)
+	DeleteMedia(ctx context.Context, in *DeleteMediaRequest, opts ...grpc.CallOption) (*Empty, error)
+	ImportArticles(ctx context.Context, in *ImportRequest, opts ...grpc.CallOption) (*ImportReport, error)
+	ExportSite(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (*ExportResponse, error)
+	RestoreSite(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreReport, error)
 }
 
 type contentAdminServiceClient struct {
@@ -100,7 +144,17 @@ func (c *contentAdminServiceClient) DeleteArticle(ctx context.Context, in *Id, o
 	return out, nil
 }
 
-func (c *contentAdminServiceClient) ListArticles(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ArticleListResponse, error) {
+func (c *contentAdminServiceClient) GetArticle(ctx context.Context, in *Id, opts ...grpc.CallOption) (*Article, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Article)
+	err := c.cc.Invoke(ctx, ContentAdminService_GetArticle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) ListArticles(ctx context.Context, in *ListArticlesRequest, opts ...grpc.CallOption) (*ArticleListResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ArticleListResponse)
 	err := c.cc.Invoke(ctx, ContentAdminService_ListArticles_FullMethodName, in, out, cOpts...)
@@ -220,6 +274,206 @@ func (c *contentAdminServiceClient) CountTags(ctx context.Context, in *Empty, op
 	return out, nil
 }
 
+func (c *contentAdminServiceClient) RebuildSearchIndex(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Count, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Count)
+	err := c.cc.Invoke(ctx, ContentAdminService_RebuildSearchIndex_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) ListScheduledArticles(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*ArticleListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArticleListResponse)
+	err := c.cc.Invoke(ctx, ContentAdminService_ListScheduledArticles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) CancelScheduledArticle(ctx context.Context, in *Id, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ContentAdminService_CancelScheduledArticle_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) ListArticleRevisions(ctx context.Context, in *Id, opts ...grpc.CallOption) (*RevisionListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevisionListResponse)
+	err := c.cc.Invoke(ctx, ContentAdminService_ListArticleRevisions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) GetArticleRevision(ctx context.Context, in *RevisionRequest, opts ...grpc.CallOption) (*ArticleRevision, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ArticleRevision)
+	err := c.cc.Invoke(ctx, ContentAdminService_GetArticleRevision_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) DiffArticleRevisions(ctx context.Context, in *RevisionDiffRequest, opts ...grpc.CallOption) (*RevisionDiff, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevisionDiff)
+	err := c.cc.Invoke(ctx, ContentAdminService_DiffArticleRevisions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) RestoreArticleRevision(ctx context.Context, in *RevisionRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ContentAdminService_RestoreArticleRevision_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) MoveCategory(ctx context.Context, in *MoveCategoryRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ContentAdminService_MoveCategory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) GetCategoryTree(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*CategoryTreeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CategoryTreeResponse)
+	err := c.cc.Invoke(ctx, ContentAdminService_GetCategoryTree_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) DeleteCategoryWithPlan(ctx context.Context, in *DeleteCategoryRequest, opts ...grpc.CallOption) (*CategoryDeletePlan, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CategoryDeletePlan)
+	err := c.cc.Invoke(ctx, ContentAdminService_DeleteCategoryWithPlan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) DeleteTagWithPlan(ctx context.Context, in *DeleteTagRequest, opts ...grpc.CallOption) (*TagChangePlan, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TagChangePlan)
+	err := c.cc.Invoke(ctx, ContentAdminService_DeleteTagWithPlan_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) MergeTag(ctx context.Context, in *MergeTagRequest, opts ...grpc.CallOption) (*TagChangePlan, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TagChangePlan)
+	err := c.cc.Invoke(ctx, ContentAdminService_MergeTag_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) ListComments(ctx context.Context, in *ListCommentsRequest, opts ...grpc.CallOption) (*CommentListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CommentListResponse)
+	err := c.cc.Invoke(ctx, ContentAdminService_ListComments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) ModerateComments(ctx context.Context, in *ModerateCommentsRequest, opts ...grpc.CallOption) (*Count, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Count)
+	err := c.cc.Invoke(ctx, ContentAdminService_ModerateComments_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) DeleteComment(ctx context.Context, in *Id, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ContentAdminService_DeleteComment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) ListMedia(ctx context.Context, in *ListMediaRequest, opts ...grpc.CallOption) (*MediaListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MediaListResponse)
+	err := c.cc.Invoke(ctx, ContentAdminService_ListMedia_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) DeleteMedia(ctx context.Context, in *DeleteMediaRequest, opts ...grpc.CallOption) (*Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, ContentAdminService_DeleteMedia_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) ImportArticles(ctx context.Context, in *ImportRequest, opts ...grpc.CallOption) (*ImportReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportReport)
+	err := c.cc.Invoke(ctx, ContentAdminService_ImportArticles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) ExportSite(ctx context.Context, in *ExportRequest, opts ...grpc.CallOption) (*ExportResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ExportResponse)
+	err := c.cc.Invoke(ctx, ContentAdminService_ExportSite_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contentAdminServiceClient) RestoreSite(ctx context.Context, in *RestoreRequest, opts ...grpc.CallOption) (*RestoreReport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RestoreReport)
+	err := c.cc.Invoke(ctx, ContentAdminService_RestoreSite_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ContentAdminServiceServer is the server API for ContentAdminService service.
 // All implementations must embed UnimplementedContentAdminServiceServer
 // for forward compatibility.
@@ -230,7 +484,8 @@ type ContentAdminServiceServer interface {
 	CreateArticle(context.Context, *Article) (*Empty, error)
 	UpdateArticle(context.Context, *Article) (*Empty, error)
 	DeleteArticle(context.Context, *Id) (*Empty, error)
-	ListArticles(context.Context, *Empty) (*ArticleListResponse, error)
+	GetArticle(context.Context, *Id) (*Article, error)
+	ListArticles(context.Context, *ListArticlesRequest) (*ArticleListResponse, error)
 	CountArticles(context.Context, *Empty) (*Count, error)
 	// 分类（全量）
 	CreateCategory(context.Context, *Category) (*Empty, error)
@@ -244,6 +499,28 @@ type ContentAdminServiceServer interface {
 	DeleteTag(context.Context, *Id) (*Empty, error)
 	ListTags(context.Context, *Empty) (*TagListResponse, error)
 	CountTags(context.Context, *Empty) (*Count, error)
+	// 检索
+	RebuildSearchIndex(context.Context, *Empty) (*Count, error)
+	// 定时发布
+	ListScheduledArticles(context.Context, *Empty) (*ArticleListResponse, error)
+	CancelScheduledArticle(context.Context, *Id) (*Empty, error)
+	ListArticleRevisions(context.Context, *Id) (*RevisionListResponse, error)
+	GetArticleRevision(context.Context, *RevisionRequest) (*ArticleRevision, error)
+	DiffArticleRevisions(context.Context, *RevisionDiffRequest) (*RevisionDiff, error)
+	RestoreArticleRevision(context.Context, *RevisionRequest) (*Empty, error)
+	MoveCategory(context.Context, *MoveCategoryRequest) (*Empty, error)
+	GetCategoryTree(context.Context, *Empty) (*CategoryTreeResponse, error)
+	DeleteCategoryWithPlan(context.Context, *DeleteCategoryRequest) (*CategoryDeletePlan, error)
+	DeleteTagWithPlan(context.Context, *DeleteTagRequest) (*TagChangePlan, error)
+	MergeTag(context.Context, *MergeTagRequest) (*TagChangePlan, error)
+	ListComments(context.Context, *ListCommentsRequest) (*CommentListResponse, error)
+	ModerateComments(context.Context, *ModerateCommentsRequest) (*Count, error)
+	DeleteComment(context.Context, *Id) (*Empty, error)
+	ListMedia(context.Context, *ListMediaRequest) (*MediaListResponse, error)
+	DeleteMedia(context.Context, *DeleteMediaRequest) (*Empty, error)
+	ImportArticles(context.Context, *ImportRequest) (*ImportReport, error)
+	ExportSite(context.Context, *ExportRequest) (*ExportResponse, error)
+	RestoreSite(context.Context, *RestoreRequest) (*RestoreReport, error)
 	mustEmbedUnimplementedContentAdminServiceServer()
 }
 
@@ -263,7 +540,10 @@ func (UnimplementedContentAdminServiceServer) UpdateArticle(context.Context, *Ar
 func (UnimplementedContentAdminServiceServer) DeleteArticle(context.Context, *Id) (*Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteArticle not implemented")
 }
-func (UnimplementedContentAdminServiceServer) ListArticles(context.Context, *Empty) (*ArticleListResponse, error) {
+func (UnimplementedContentAdminServiceServer) GetArticle(context.Context, *Id) (*Article, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetArticle not implemented")
+}
+func (UnimplementedContentAdminServiceServer) ListArticles(context.Context, *ListArticlesRequest) (*ArticleListResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListArticles not implemented")
 }
 func (UnimplementedContentAdminServiceServer) CountArticles(context.Context, *Empty) (*Count, error) {
@@ -299,6 +579,66 @@ func (UnimplementedContentAdminServiceServer) ListTags(context.Context, *Empty)
 func (UnimplementedContentAdminServiceServer) CountTags(context.Context, *Empty) (*Count, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CountTags not implemented")
 }
+func (UnimplementedContentAdminServiceServer) RebuildSearchIndex(context.Context, *Empty) (*Count, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RebuildSearchIndex not implemented")
+}
+func (UnimplementedContentAdminServiceServer) ListScheduledArticles(context.Context, *Empty) (*ArticleListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListScheduledArticles not implemented")
+}
+func (UnimplementedContentAdminServiceServer) CancelScheduledArticle(context.Context, *Id) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CancelScheduledArticle not implemented")
+}
+func (UnimplementedContentAdminServiceServer) ListArticleRevisions(context.Context, *Id) (*RevisionListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListArticleRevisions not implemented")
+}
+func (UnimplementedContentAdminServiceServer) GetArticleRevision(context.Context, *RevisionRequest) (*ArticleRevision, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetArticleRevision not implemented")
+}
+func (UnimplementedContentAdminServiceServer) DiffArticleRevisions(context.Context, *RevisionDiffRequest) (*RevisionDiff, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DiffArticleRevisions not implemented")
+}
+func (UnimplementedContentAdminServiceServer) RestoreArticleRevision(context.Context, *RevisionRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreArticleRevision not implemented")
+}
+func (UnimplementedContentAdminServiceServer) MoveCategory(context.Context, *MoveCategoryRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveCategory not implemented")
+}
+func (UnimplementedContentAdminServiceServer) GetCategoryTree(context.Context, *Empty) (*CategoryTreeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCategoryTree not implemented")
+}
+func (UnimplementedContentAdminServiceServer) DeleteCategoryWithPlan(context.Context, *DeleteCategoryRequest) (*CategoryDeletePlan, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteCategoryWithPlan not implemented")
+}
+func (UnimplementedContentAdminServiceServer) DeleteTagWithPlan(context.Context, *DeleteTagRequest) (*TagChangePlan, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteTagWithPlan not implemented")
+}
+func (UnimplementedContentAdminServiceServer) MergeTag(context.Context, *MergeTagRequest) (*TagChangePlan, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeTag not implemented")
+}
+func (UnimplementedContentAdminServiceServer) ListComments(context.Context, *ListCommentsRequest) (*CommentListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListComments not implemented")
+}
+func (UnimplementedContentAdminServiceServer) ModerateComments(context.Context, *ModerateCommentsRequest) (*Count, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModerateComments not implemented")
+}
+func (UnimplementedContentAdminServiceServer) DeleteComment(context.Context, *Id) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteComment not implemented")
+}
+func (UnimplementedContentAdminServiceServer) ListMedia(context.Context, *ListMediaRequest) (*MediaListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMedia not implemented")
+}
+func (UnimplementedContentAdminServiceServer) DeleteMedia(context.Context, *DeleteMediaRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMedia not implemented")
+}
+func (UnimplementedContentAdminServiceServer) ImportArticles(context.Context, *ImportRequest) (*ImportReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportArticles not implemented")
+}
+func (UnimplementedContentAdminServiceServer) ExportSite(context.Context, *ExportRequest) (*ExportResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportSite not implemented")
+}
+func (UnimplementedContentAdminServiceServer) RestoreSite(context.Context, *RestoreRequest) (*RestoreReport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreSite not implemented")
+}
 func (UnimplementedContentAdminServiceServer) mustEmbedUnimplementedContentAdminServiceServer() {}
 func (UnimplementedContentAdminServiceServer) testEmbeddedByValue()                             {}
 
@@ -374,8 +714,26 @@ func _ContentAdminService_DeleteArticle_Handler(srv interface{}, ctx context.Con
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ContentAdminService_GetArticle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Id)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).GetArticle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_GetArticle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).GetArticle(ctx, req.(*Id))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ContentAdminService_ListArticles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(Empty)
+	in := new(ListArticlesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
@@ -387,7 +745,7 @@ func _ContentAdminService_ListArticles_Handler(srv interface{}, ctx context.Cont
 		FullMethod: ContentAdminService_ListArticles_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(ContentAdminServiceServer).ListArticles(ctx, req.(*Empty))
+		return srv.(ContentAdminServiceServer).ListArticles(ctx, req.(*ListArticlesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
@@ -590,6 +948,366 @@ func _ContentAdminService_CountTags_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ContentAdminService_RebuildSearchIndex_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).RebuildSearchIndex(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_RebuildSearchIndex_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).RebuildSearchIndex(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_ListScheduledArticles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).ListScheduledArticles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_ListScheduledArticles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).ListScheduledArticles(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_CancelScheduledArticle_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Id)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).CancelScheduledArticle(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_CancelScheduledArticle_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).CancelScheduledArticle(ctx, req.(*Id))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_ListArticleRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Id)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).ListArticleRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_ListArticleRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).ListArticleRevisions(ctx, req.(*Id))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_GetArticleRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).GetArticleRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_GetArticleRevision_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).GetArticleRevision(ctx, req.(*RevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_DiffArticleRevisions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevisionDiffRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).DiffArticleRevisions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_DiffArticleRevisions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).DiffArticleRevisions(ctx, req.(*RevisionDiffRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_RestoreArticleRevision_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevisionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).RestoreArticleRevision(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_RestoreArticleRevision_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).RestoreArticleRevision(ctx, req.(*RevisionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_MoveCategory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).MoveCategory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_MoveCategory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).MoveCategory(ctx, req.(*MoveCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_GetCategoryTree_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).GetCategoryTree(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_GetCategoryTree_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).GetCategoryTree(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_DeleteCategoryWithPlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteCategoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).DeleteCategoryWithPlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_DeleteCategoryWithPlan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).DeleteCategoryWithPlan(ctx, req.(*DeleteCategoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_DeleteTagWithPlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).DeleteTagWithPlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_DeleteTagWithPlan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).DeleteTagWithPlan(ctx, req.(*DeleteTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_MergeTag_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeTagRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).MergeTag(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_MergeTag_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).MergeTag(ctx, req.(*MergeTagRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_ListComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCommentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).ListComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_ListComments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).ListComments(ctx, req.(*ListCommentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_ModerateComments_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ModerateCommentsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).ModerateComments(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_ModerateComments_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).ModerateComments(ctx, req.(*ModerateCommentsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_DeleteComment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Id)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).DeleteComment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_DeleteComment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).DeleteComment(ctx, req.(*Id))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_ListMedia_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMediaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).ListMedia(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_ListMedia_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).ListMedia(ctx, req.(*ListMediaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_DeleteMedia_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMediaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).DeleteMedia(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_DeleteMedia_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).DeleteMedia(ctx, req.(*DeleteMediaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_ImportArticles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).ImportArticles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_ImportArticles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).ImportArticles(ctx, req.(*ImportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_ExportSite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).ExportSite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_ExportSite_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).ExportSite(ctx, req.(*ExportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContentAdminService_RestoreSite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContentAdminServiceServer).RestoreSite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContentAdminService_RestoreSite_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContentAdminServiceServer).RestoreSite(ctx, req.(*RestoreRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ContentAdminService_ServiceDesc is the grpc.ServiceDesc for ContentAdminService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -609,6 +1327,10 @@ var ContentAdminService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteArticle",
 			Handler:    _ContentAdminService_DeleteArticle_Handler,
 		},
+		{
+			MethodName: "GetArticle",
+			Handler:    _ContentAdminService_GetArticle_Handler,
+		},
 		{
 			MethodName: "ListArticles",
 			Handler:    _ContentAdminService_ListArticles_Handler,
@@ -657,6 +1379,86 @@ var ContentAdminService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CountTags",
 			Handler:    _ContentAdminService_CountTags_Handler,
 		},
+		{
+			MethodName: "RebuildSearchIndex",
+			Handler:    _ContentAdminService_RebuildSearchIndex_Handler,
+		},
+		{
+			MethodName: "ListScheduledArticles",
+			Handler:    _ContentAdminService_ListScheduledArticles_Handler,
+		},
+		{
+			MethodName: "CancelScheduledArticle",
+			Handler:    _ContentAdminService_CancelScheduledArticle_Handler,
+		},
+		{
+			MethodName: "ListArticleRevisions",
+			Handler:    _ContentAdminService_ListArticleRevisions_Handler,
+		},
+		{
+			MethodName: "GetArticleRevision",
+			Handler:    _ContentAdminService_GetArticleRevision_Handler,
+		},
+		{
+			MethodName: "DiffArticleRevisions",
+			Handler:    _ContentAdminService_DiffArticleRevisions_Handler,
+		},
+		{
+			MethodName: "RestoreArticleRevision",
+			Handler:    _ContentAdminService_RestoreArticleRevision_Handler,
+		},
+		{
+			MethodName: "MoveCategory",
+			Handler:    _ContentAdminService_MoveCategory_Handler,
+		},
+		{
+			MethodName: "GetCategoryTree",
+			Handler:    _ContentAdminService_GetCategoryTree_Handler,
+		},
+		{
+			MethodName: "DeleteCategoryWithPlan",
+			Handler:    _ContentAdminService_DeleteCategoryWithPlan_Handler,
+		},
+		{
+			MethodName: "DeleteTagWithPlan",
+			Handler:    _ContentAdminService_DeleteTagWithPlan_Handler,
+		},
+		{
+			MethodName: "MergeTag",
+			Handler:    _ContentAdminService_MergeTag_Handler,
+		},
+		{
+			MethodName: "ListComments",
+			Handler:    _ContentAdminService_ListComments_Handler,
+		},
+		{
+			MethodName: "ModerateComments",
+			Handler:    _ContentAdminService_ModerateComments_Handler,
+		},
+		{
+			MethodName: "DeleteComment",
+			Handler:    _ContentAdminService_DeleteComment_Handler,
+		},
+		{
+			MethodName: "ListMedia",
+			Handler:    _ContentAdminService_ListMedia_Handler,
+		},
+		{
+			MethodName: "DeleteMedia",
+			Handler:    _ContentAdminService_DeleteMedia_Handler,
+		},
+		{
+			MethodName: "ImportArticles",
+			Handler:    _ContentAdminService_ImportArticles_Handler,
+		},
+		{
+			MethodName: "ExportSite",
+			Handler:    _ContentAdminService_ExportSite_Handler,
+		},
+		{
+			MethodName: "RestoreSite",
+			Handler:    _ContentAdminService_RestoreSite_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "content.proto",
@@ -132,9 +132,10 @@ func (s *GatewayService) ProxyRequest(ctx context.Context, req *domain.ProxyRequ
 		forwardPath = "/api" + strings.TrimPrefix(req.Path, route.Prefix)
 	}
 
-	// 8. 创建HTTP客户端
+	// 8. 创建HTTP客户端（不跟随重定向，3xx 原样返回给调用方）
 	client := &http.Client{
-		Timeout: route.Timeout,
+		Timeout:       route.Timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
 	}
 
 	// 9. 构建请求（使用字节Reader，避免编码问题）
@@ -172,9 +173,9 @@ func (s *GatewayService) ProxyRequest(ctx context.Context, req *domain.ProxyRequ
 	}
 	// 追加 X-Forwarded-For
 	if prior, ok := proxyReq.Header["X-Forwarded-For"]; ok && len(prior) > 0 {
-		proxyReq.Header.Set("X-Forwarded-For", prior[0]+", "+req.Client)
+		proxyReq.Header.Set("X-Forwarded-For", prior[0]+", "+req.IP)
 	} else {
-		proxyReq.Header.Set("X-Forwarded-For", req.Client)
+		proxyReq.Header.Set("X-Forwarded-For", req.IP)
 	}
 
 	// 11. 发送请求
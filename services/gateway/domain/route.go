@@ -41,7 +41,8 @@ type ProxyRequest struct {
 	Path    string
 	Headers http.Header
 	Body    []byte
-	Client  string // 客户端IP
+	Client  string // 限流维度：客户端IP，已鉴权时为 uid_<用户ID>
+	IP      string // 客户端IP，写入 X-Forwarded-For
 }
 
 // ProxyResponse 代理响应
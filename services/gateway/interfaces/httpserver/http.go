@@ -1,9 +1,11 @@
 package httpserver
 
 import (
+	"errors"
 	"io"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"blog-system/common/pkg/dto"
 	"blog-system/common/pkg/errcode"
@@ -17,6 +19,9 @@ import (
 	webprom "github.com/CoucouMonEcho/go-framework/web/middlewares/prometheus"
 )
 
+// maxBodySize 转发的请求体上限（含文件上传），超出返回 413
+const maxBodySize = 32 << 20
+
 // HTTPServer HTTP服务器
 type HTTPServer struct {
 	gatewayService *application.GatewayService
@@ -53,7 +58,7 @@ func corsMiddleware() web.Middleware {
 		return func(ctx *web.Context) {
 			ctx.Resp.Header().Set("Access-Control-Allow-Origin", "*")
 			ctx.Resp.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			ctx.Resp.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-User-ID")
+			ctx.Resp.Header().Set("Access-Control-Allow-Headers", "Origin, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
 
 			if ctx.Req.Method == http.MethodOptions {
 				_ = ctx.RespJSON(http.StatusNoContent, "")
@@ -64,12 +69,35 @@ func corsMiddleware() web.Middleware {
 	}
 }
 
+// 免鉴权路径：登录、第三方登录回调；订阅源与 sitemap 供阅读器、聚合服务与搜索引擎匿名拉取，上传的文件公开访问
+var (
+	publicPaths    = []string{"/health", "/api/user/login", "/api/user/login/2fa", "/api/content/sitemap.xml"}
+	publicPrefixes = []string{"/api/user/oauth/", "/api/content/feed/", "/api/content/sitemap/", "/api/content/media/file/"}
+)
+
+// publicPath 是否免鉴权；这些请求不带 X-User-ID 转发（由 authMiddleware 统一剥离）
+func publicPath(path string) bool {
+	for _, p := range publicPaths {
+		if path == p {
+			return true
+		}
+	}
+	for _, p := range publicPrefixes {
+		if strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
 // authMiddleware 统一JWT鉴权
 func (s *HTTPServer) authMiddleware() web.Middleware {
 	return func(next web.Handler) web.Handler {
 		return func(ctx *web.Context) {
 			path := ctx.Req.URL.Path
-			if path == "/health" || path == "/api/user/login" {
+			// X-User-ID 只能由网关鉴权后写入，先删除客户端伪造的值（免鉴权路径同样适用，避免伪造用户维度的限流键）
+			ctx.Req.Header.Del("X-User-ID")
+			if publicPath(path) {
 				next(ctx)
 				return
 			}
@@ -118,8 +146,13 @@ func proxyHandler(gatewayService *application.GatewayService) web.Handler {
 		}
 
 		// 读取请求体
-		body, err := io.ReadAll(ctx.Req.Body)
+		body, err := io.ReadAll(http.MaxBytesReader(ctx.Resp, ctx.Req.Body, maxBodySize))
 		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				_ = ctx.RespJSON(http.StatusRequestEntityTooLarge, map[string]interface{}{"error": "请求体过大"})
+				return
+			}
 			logger.Log().Error("httpserver: 读取请求体失败: %v", err)
 			_ = ctx.RespJSON(http.StatusBadRequest, map[string]interface{}{"error": "读取请求体失败"})
 			return
@@ -132,6 +165,7 @@ func proxyHandler(gatewayService *application.GatewayService) web.Handler {
 			Headers: ctx.Req.Header,
 			Body:    body,
 			Client:  clientIP,
+			IP:      clientIP,
 		}
 
 		// 如果已鉴权，限流维度改为用户ID（下划线拼接）
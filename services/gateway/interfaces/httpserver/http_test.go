@@ -0,0 +1,71 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"blog-system/common/pkg/util"
+	"blog-system/services/gateway/application"
+
+	"github.com/CoucouMonEcho/go-framework/web"
+)
+
+// runAuth 经过 authMiddleware 处理请求，返回转发给后端时的 X-User-ID 与是否放行
+func runAuth(t *testing.T, path, authorization string) (string, bool, int) {
+	t.Helper()
+	hs := &HTTPServer{gatewayService: application.NewGatewayService(nil, nil, nil, nil, nil)}
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("X-User-ID", "1") // 客户端伪造
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	ctx := &web.Context{Req: req, Resp: httptest.NewRecorder()}
+	var (
+		uid    string
+		passed bool
+	)
+	hs.authMiddleware()(func(ctx *web.Context) {
+		passed = true
+		uid = ctx.Req.Header.Get("X-User-ID")
+	})(ctx)
+	return uid, passed, ctx.RespCode
+}
+
+func TestAuthMiddlewareStripsSpoofedUserID(t *testing.T) {
+	public := []string{
+		"/health",
+		"/api/user/login",
+		"/api/user/login/2fa",
+		"/api/user/oauth/github/callback",
+		"/api/content/feed/rss.xml",
+		"/api/content/sitemap.xml",
+		"/api/content/sitemap/articles-1.xml",
+		"/api/content/media/file/2024/05/a.png",
+	}
+	for _, path := range public {
+		uid, passed, _ := runAuth(t, path, "")
+		if !passed {
+			t.Errorf("%s: not allowed without token", path)
+		}
+		if uid != "" {
+			t.Errorf("%s: forwarded X-User-ID %q", path, uid)
+		}
+	}
+}
+
+func TestAuthMiddlewareProtectedPaths(t *testing.T) {
+	for _, path := range []string{"/api/user/info/1", "/api/content/media", "/api/user/login/2fa/extra", "/api/content/feeds"} {
+		if _, passed, code := runAuth(t, path, ""); passed || code != http.StatusUnauthorized {
+			t.Errorf("%s: passed=%t code=%d, want 401", path, passed, code)
+		}
+	}
+	token, err := util.GenerateToken(42, "user", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 已鉴权请求的 X-User-ID 以令牌为准，覆盖客户端传入的值
+	if uid, passed, _ := runAuth(t, "/api/user/info/42", "Bearer "+token); !passed || uid != "42" {
+		t.Fatalf("authenticated: passed=%t uid=%q", passed, uid)
+	}
+}
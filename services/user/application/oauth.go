@@ -0,0 +1,227 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"blog-system/common/pkg/logger"
+	"blog-system/services/user/domain"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// OAuthStateTTL 授权请求（state 与 code_verifier）有效期
+const OAuthStateTTL = 10 * time.Minute
+
+var usernameSanitizer = regexp.MustCompile(`[^A-Za-z0-9_\-.]`)
+
+// oauthState 授权请求上下文，按 state 暂存于缓存，回调时一次性取出
+type oauthState struct {
+	Provider     string `json:"provider"`
+	CodeVerifier string `json:"code_verifier"`
+}
+
+// OAuthProviders 已启用的第三方登录提供方
+func (s *UserAppService) OAuthProviders() []string {
+	names := make([]string, 0, len(s.providers))
+	for name := range s.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OAuthAuthorize 生成 state 与 PKCE 参数，返回提供方授权地址
+func (s *UserAppService) OAuthAuthorize(ctx context.Context, providerName string) (string, string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return "", "", errors.New("不支持的登录方式")
+	}
+	if s.cache == nil {
+		return "", "", errors.New("缓存不可用，暂不支持第三方登录")
+	}
+	state, err := randomURLToken(24)
+	if err != nil {
+		return "", "", err
+	}
+	verifier, err := randomURLToken(32)
+	if err != nil {
+		return "", "", err
+	}
+	data, _ := json.Marshal(oauthState{Provider: providerName, CodeVerifier: verifier})
+	if err := s.cache.Set(ctx, "oauth_state_"+state, string(data), OAuthStateTTL); err != nil {
+		logger.Log().Error("application: 保存 oauth state 失败: provider=%s err=%v", providerName, err)
+		return "", "", err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	challenge := base64.RawURLEncoding.EncodeToString(sum[:])
+	return provider.AuthCodeURL(state, challenge), state, nil
+}
+
+// OAuthCallback 校验 state，换取用户资料并登录（必要时绑定或创建本地账号）
+func (s *UserAppService) OAuthCallback(ctx context.Context, providerName, state, code string) (*domain.User, string, error) {
+	provider, ok := s.providers[providerName]
+	if !ok {
+		return nil, "", errors.New("不支持的登录方式")
+	}
+	if state == "" || code == "" {
+		return nil, "", errors.New("缺少 state 或 code")
+	}
+	if s.cache == nil {
+		return nil, "", errors.New("缓存不可用，暂不支持第三方登录")
+	}
+	raw, err := s.cache.LoadAndDelete(ctx, "oauth_state_"+state)
+	if err != nil {
+		logger.Log().Warn("application: oauth state 无效: provider=%s err=%v", providerName, err)
+		return nil, "", errors.New("state 无效或已过期")
+	}
+	var st oauthState
+	if err := json.Unmarshal(cacheBytes(raw), &st); err != nil || st.Provider != providerName {
+		return nil, "", errors.New("state 无效或已过期")
+	}
+	profile, err := provider.Exchange(ctx, code, st.CodeVerifier)
+	if err != nil {
+		logger.Log().Warn("application: oauth 换取用户资料失败: provider=%s err=%v", providerName, err)
+		return nil, "", errors.New("第三方登录失败")
+	}
+	user, err := s.resolveIdentity(ctx, providerName, profile)
+	if err != nil {
+		return nil, "", err
+	}
+	if user.Status != 0 {
+		return nil, "", errors.New("用户已被禁用")
+	}
+	logger.Log().Info("application: 第三方登录: provider=%s id=%d", providerName, user.ID)
+	return s.completeLogin(ctx, user)
+}
+
+// ListIdentities 用户已绑定的第三方身份
+func (s *UserAppService) ListIdentities(ctx context.Context, userID int64) ([]*domain.UserIdentity, error) {
+	return s.identityRepo.ListIdentitiesByUser(ctx, userID)
+}
+
+// resolveIdentity 已绑定则直接返回；否则按已验证邮箱绑定已有账号，无匹配账号时新建
+func (s *UserAppService) resolveIdentity(ctx context.Context, providerName string, profile *domain.ExternalProfile) (*domain.User, error) {
+	if identity, err := s.identityRepo.FindIdentity(ctx, providerName, profile.Subject); err == nil {
+		return s.userRepo.FindByID(ctx, identity.UserID)
+	}
+	// 未验证邮箱既不能绑定也不能注册，避免冒用他人邮箱接管账号
+	if profile.Email == "" || !profile.EmailVerified {
+		return nil, errors.New("第三方账号未提供已验证的邮箱")
+	}
+	user, err := s.userRepo.FindByEmail(ctx, profile.Email)
+	if err != nil {
+		if user, err = s.createOAuthUser(ctx, profile); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now()
+	identity := &domain.UserIdentity{
+		UserID:    user.ID,
+		Provider:  providerName,
+		Subject:   profile.Subject,
+		Email:     profile.Email,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.identityRepo.CreateIdentity(ctx, identity); err != nil {
+		return nil, err
+	}
+	logger.Log().Info("application: 绑定第三方身份: provider=%s user=%d", providerName, user.ID)
+	return user, nil
+}
+
+// createOAuthUser 以第三方资料创建读者账号，密码为随机值（仅能通过第三方登录）
+func (s *UserAppService) createOAuthUser(ctx context.Context, profile *domain.ExternalProfile) (*domain.User, error) {
+	username, err := s.availableUsername(ctx, profile)
+	if err != nil {
+		return nil, err
+	}
+	random, err := randomURLToken(32)
+	if err != nil {
+		return nil, err
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(random), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	user := &domain.User{
+		Username:  username,
+		Email:     profile.Email,
+		Password:  string(hashedPassword),
+		Role:      "user",
+		Status:    0,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		logger.Log().Error("application: 第三方登录创建用户失败: %v", err)
+		return nil, err
+	}
+	if user.ID == 0 {
+		// 驱动未返回自增ID时回查
+		if user, err = s.userRepo.FindByEmail(ctx, profile.Email); err != nil {
+			return nil, err
+		}
+	}
+	logger.Log().Info("application: 第三方登录注册: id=%d username=%s", user.ID, user.Username)
+	return user, nil
+}
+
+// availableUsername 以提供方用户名（或邮箱前缀）为基础生成未占用的用户名
+func (s *UserAppService) availableUsername(ctx context.Context, profile *domain.ExternalProfile) (string, error) {
+	base := usernameSanitizer.ReplaceAllString(profile.Username, "")
+	if base == "" {
+		base = usernameSanitizer.ReplaceAllString(strings.SplitN(profile.Email, "@", 2)[0], "")
+	}
+	if base == "" {
+		base = "reader"
+	}
+	if len(base) > 40 {
+		base = base[:40]
+	}
+	candidate := base
+	for i := 0; i < 5; i++ {
+		if _, err := s.userRepo.FindByUsername(ctx, candidate); err != nil {
+			return candidate, nil
+		}
+		n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+		if err != nil {
+			return "", err
+		}
+		candidate = fmt.Sprintf("%s_%06d", base, n.Int64())
+	}
+	return "", errors.New("无法生成可用的用户名")
+}
+
+// randomURLToken n 字节随机数的 base64url 编码
+func randomURLToken(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// cacheBytes 兼容本地缓存与 Redis 缓存返回的值类型
+func cacheBytes(v any) []byte {
+	switch t := v.(type) {
+	case string:
+		return []byte(t)
+	case []byte:
+		return t
+	default:
+		return nil
+	}
+}
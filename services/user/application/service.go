@@ -1,7 +1,6 @@
 package application
 
 import (
-	"blog-system/common/pkg/util"
 	"context"
 	"database/sql"
 	"encoding/json"
@@ -10,33 +9,77 @@ import (
 	"strings"
 	"time"
 
+	"blog-system/common/pkg/cacheaside"
 	"blog-system/common/pkg/logger"
+	"blog-system/common/pkg/perm"
+	"blog-system/common/pkg/util"
 	"blog-system/services/user/domain"
 
 	"github.com/CoucouMonEcho/go-framework/cache"
+	"github.com/CoucouMonEcho/go-framework/orm"
 	"golang.org/x/crypto/bcrypt"
 )
 
 // UserAppService 用户应用服务
 type UserAppService struct {
-	userRepo domain.UserRepository
-	cache    cache.Cache
+	userRepo     domain.UserRepository
+	roleRepo     domain.RoleRepository
+	identityRepo domain.IdentityRepository
+	providers    map[string]domain.OAuthProvider
+	cache        cache.Cache
+	userCache    *cacheaside.Cache
 }
 
 // NewUserService 创建用户服务
-func NewUserService(userRepo domain.UserRepository, cache cache.Cache) *UserAppService {
+func NewUserService(userRepo domain.UserRepository, roleRepo domain.RoleRepository, identityRepo domain.IdentityRepository,
+	providers map[string]domain.OAuthProvider, cache cache.Cache) *UserAppService {
 	return &UserAppService{
-		userRepo: userRepo,
-		cache:    cache,
+		userRepo:     userRepo,
+		roleRepo:     roleRepo,
+		identityRepo: identityRepo,
+		providers:    providers,
+		cache:        cache,
+		userCache: cacheaside.New(cache,
+			cacheaside.WithPrefix("user:"),
+			cacheaside.WithTTL(30*time.Minute),
+			cacheaside.WithNegativeTTL(time.Minute),
+			cacheaside.WithNotFound(func(err error) bool { return errors.Is(err, orm.ErrNoRows) }),
+		),
 	}
 }
 
 // Register 用户注册
 func (s *UserAppService) Register(ctx context.Context, username, email, password string) (*domain.User, error) {
-	if _, err := s.userRepo.FindByUsername(ctx, username); err == nil {
+	return s.createUser(ctx, &domain.User{Username: username, Email: email, Role: "user"}, password)
+}
+
+// AdminCreateUser 管理端创建用户，可指定角色/头像/状态
+func (s *UserAppService) AdminCreateUser(ctx context.Context, username, email, password, role, avatar string, status int) (*domain.User, error) {
+	if role == "" {
+		role = "user"
+	}
+	if _, err := s.roleRepo.FindRoleByCode(ctx, role); err != nil {
+		return nil, errors.New("角色不存在")
+	}
+	if status != 0 && status != 1 {
+		return nil, errors.New("状态不合法")
+	}
+	u := &domain.User{
+		Username: username,
+		Email:    email,
+		Role:     role,
+		Avatar:   &sql.NullString{String: avatar, Valid: avatar != ""},
+		Status:   status,
+	}
+	return s.createUser(ctx, u, password)
+}
+
+// createUser 校验唯一性、加密密码并落库
+func (s *UserAppService) createUser(ctx context.Context, user *domain.User, password string) (*domain.User, error) {
+	if _, err := s.userRepo.FindByUsername(ctx, user.Username); err == nil {
 		return nil, errors.New("用户名已存在")
 	}
-	if _, err := s.userRepo.FindByEmail(ctx, email); err == nil {
+	if _, err := s.userRepo.FindByEmail(ctx, user.Email); err == nil {
 		return nil, errors.New("邮箱已存在")
 	}
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
@@ -45,20 +88,14 @@ func (s *UserAppService) Register(ctx context.Context, username, email, password
 		return nil, err
 	}
 	now := time.Now()
-	user := &domain.User{
-		Username:  username,
-		Email:     email,
-		Password:  string(hashedPassword),
-		Role:      "user",
-		Status:    0, // 正常状态
-		CreatedAt: now,
-		UpdatedAt: now,
-	}
+	user.Password = string(hashedPassword)
+	user.CreatedAt = now
+	user.UpdatedAt = now
 	if err = s.userRepo.Create(ctx, user); err != nil {
 		logger.Log().Error("application: 用户创建失败: %v", err)
 		return nil, err
 	}
-	logger.Log().Info("application: 注册成功: username=%s", user.Username)
+	logger.Log().Info("application: 注册成功: username=%s role=%s", user.Username, user.Role)
 	return user, nil
 }
 
@@ -82,45 +119,64 @@ func (s *UserAppService) Login(ctx context.Context, username, password string) (
 		logger.Log().Warn("application: 登录失败: 密码错误, username=%s, err=%v", username, err)
 		return nil, "", errors.New("密码错误")
 	}
+	return s.completeLogin(ctx, user)
+}
+
+// completeLogin 第一因子通过后的统一出口：已启用两步验证时仅返回挑战令牌
+func (s *UserAppService) completeLogin(ctx context.Context, user *domain.User) (*domain.User, string, error) {
+	// 已启用两步验证：仅返回短期挑战令牌，需调用 LoginTwoFactor 换取正式令牌
+	if user.TotpEnabled {
+		challenge, jti, err := util.GenerateChallengeToken(user.ID)
+		if err != nil {
+			logger.Log().Error("application: 生成挑战令牌失败: id=%d err=%v", user.ID, err)
+			return nil, "", err
+		}
+		s.openChallenge(ctx, jti)
+		logger.Log().Info("application: 登录待两步验证: id=%d username=%s", user.ID, user.Username)
+		return user, "", &domain.TwoFactorChallenge{Token: challenge}
+	}
+	return s.issueToken(ctx, user)
+}
+
+// issueToken 查询角色权限并签发正式令牌
+func (s *UserAppService) issueToken(ctx context.Context, user *domain.User) (*domain.User, string, error) {
+	perms, err := s.roleRepo.ListPermissionsByRole(ctx, user.Role)
+	if err != nil {
+		logger.Log().Error("application: 查询角色权限失败: id=%d role=%s err=%v", user.ID, user.Role, err)
+		return nil, "", err
+	}
+	// 角色强制两步验证但用户未启用：签发无权限令牌，仅可完成绑定
+	if s.TwoFactorSetupRequired(ctx, user) {
+		logger.Log().Warn("application: 角色要求两步验证, 权限已收回: id=%d role=%s", user.ID, user.Role)
+		perms = nil
+	}
 	// 生成 JWT 令牌
-	token, err := util.GenerateToken(user.ID, user.Role)
+	token, err := util.GenerateToken(user.ID, user.Role, perms)
 	if err != nil {
 		logger.Log().Error("application: 生成token失败: id=%d username=%s err=%v", user.ID, user.Username, err)
 		return nil, "", err
 	}
-	// 将 token 写入缓存，值为用户JSON
-	if userData, er := json.Marshal(user); er == nil {
-		_ = s.cache.Set(ctx, "token_"+token, string(userData), 24*time.Hour)
+	// 将 token 写入缓存（网关据此校验令牌有效性），值仅为用户ID，资料以 GetUserInfo 为准
+	if s.cache != nil {
+		_ = s.cache.Set(ctx, "token_"+token, strconv.FormatInt(user.ID, 10), util.TokenTTL)
+		s.rememberToken(ctx, user.ID, token)
 	}
 	logger.Log().Info("application: 登录成功: id=%d username=%s", user.ID, user.Username)
 	return user, token, nil
 }
 
-// GetUserInfo 获取用户信息
+// GetUserInfo 获取用户信息（旁路缓存，合并并发回源，不存在的ID短暂缓存空值）
 func (s *UserAppService) GetUserInfo(ctx context.Context, id int64) (*domain.User, error) {
-	// 先从缓存获取
-	cacheKey := "user_" + strconv.FormatInt(id, 10)
-	if cached, err := s.cache.Get(ctx, cacheKey); err == nil {
-		// JSON反序列化缓存数据
-		var user domain.User
-		if cachedStr, ok := cached.(string); ok {
-			if err := json.Unmarshal([]byte(cachedStr), &user); err == nil {
-				logger.Log().Debug("application: 命中缓存: id=%d", id)
-				return &user, nil
-			}
-		}
+	user, err := cacheaside.Get(ctx, s.userCache, s.userInfoKey(id), func(ctx context.Context) (*domain.User, error) {
+		return s.userRepo.FindByID(ctx, id)
+	})
+	if errors.Is(err, cacheaside.ErrNotFound) {
+		return nil, errors.New("用户不存在")
 	}
-	// 从数据库获取
-	user, err := s.userRepo.FindByID(ctx, id)
 	if err != nil {
 		logger.Log().Error("application: 查询用户失败: id=%d, err=%v", id, err)
 		return nil, err
 	}
-	// 缓存用户信息 - JSON序列化并缓存用户数据
-	if userData, err := json.Marshal(user); err == nil {
-		// 设置缓存，过期时间30分钟
-		_ = s.cache.Set(ctx, cacheKey, string(userData), 30*time.Minute)
-	}
 	return user, nil
 }
 
@@ -131,6 +187,7 @@ func (s *UserAppService) UpdateUserInfo(ctx context.Context, id int64, updates m
 		logger.Log().Error("application: 更新失败: 读取用户错误 id=%d err=%v", id, err)
 		return err
 	}
+	oldRole := user.Role
 	// 更新字段
 	for key, value := range updates {
 		switch key {
@@ -152,14 +209,17 @@ func (s *UserAppService) UpdateUserInfo(ctx context.Context, id int64, updates m
 			}
 		}
 	}
+	roleChanged := user.Role != oldRole
 	user.UpdatedAt = time.Now()
 	if err := s.userRepo.Update(ctx, user); err != nil {
 		logger.Log().Error("application: 更新失败: 写入用户错误 id=%d err=%v", id, err)
 		return err
 	}
-	// 清除缓存
-	cacheKey := "user_" + strconv.FormatInt(id, 10)
-	_ = s.cache.Del(ctx, cacheKey)
+	s.evictUser(ctx, id)
+	// 角色变化后旧令牌中的权限已失效
+	if roleChanged {
+		s.revokeTokens(ctx, id)
+	}
 	logger.Log().Info("application: 更新成功: id=%d", id)
 	return nil
 }
@@ -184,7 +244,12 @@ func (s *UserAppService) ChangePassword(ctx context.Context, id int64, oldPasswo
 	}
 	user.Password = string(hashedPassword)
 	user.UpdatedAt = time.Now()
-	return s.userRepo.Update(ctx, user)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	s.evictUser(ctx, id)
+	s.revokeTokens(ctx, id)
+	return nil
 }
 
 // ResetPassword 重置密码
@@ -204,7 +269,135 @@ func (s *UserAppService) ListUsers(ctx context.Context, page, pageSize int) ([]*
 	return s.userRepo.List(ctx, page, pageSize)
 }
 
+// SearchUsers 按角色/状态/关键字筛选用户
+func (s *UserAppService) SearchUsers(ctx context.Context, filter domain.UserFilter, page, pageSize int) ([]*domain.User, int64, error) {
+	return s.userRepo.Search(ctx, filter, page, pageSize)
+}
+
 // ChangeUserStatus 更新用户状态
 func (s *UserAppService) ChangeUserStatus(ctx context.Context, id int64, status int) error {
-	return s.userRepo.UpdateStatus(ctx, id, status)
+	if err := s.userRepo.UpdateStatus(ctx, id, status); err != nil {
+		return err
+	}
+	s.evictUser(ctx, id)
+	// 禁用后立即吊销已签发令牌
+	if status != 0 {
+		s.revokeTokens(ctx, id)
+	}
+	return nil
+}
+
+// DeleteUser 删除用户：软删除置为禁用，硬删除物理删除记录
+func (s *UserAppService) DeleteUser(ctx context.Context, id int64, hard bool) error {
+	if _, err := s.userRepo.FindByID(ctx, id); err != nil {
+		return errors.New("用户不存在")
+	}
+	if !hard {
+		return s.ChangeUserStatus(ctx, id, 1)
+	}
+	if err := s.userRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.evictUser(ctx, id)
+	s.revokeTokens(ctx, id)
+	logger.Log().Info("application: 用户已物理删除: id=%d", id)
+	return nil
+}
+
+// AdminResetPassword 管理端强制重置密码（无需旧密码）
+func (s *UserAppService) AdminResetPassword(ctx context.Context, id int64, newPassword string) error {
+	if len(newPassword) < 6 {
+		return errors.New("密码长度不能少于6位")
+	}
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return errors.New("用户不存在")
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		logger.Log().Error("application: 重置密码失败: 加密错误 id=%d err=%v", id, err)
+		return err
+	}
+	user.Password = string(hashedPassword)
+	user.UpdatedAt = time.Now()
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	s.evictUser(ctx, id)
+	s.revokeTokens(ctx, id)
+	logger.Log().Info("application: 管理端重置密码: id=%d", id)
+	return nil
+}
+
+// GetUserPermissions 查询用户当前角色的权限编码
+func (s *UserAppService) GetUserPermissions(ctx context.Context, id int64) ([]string, error) {
+	user, err := s.GetUserInfo(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Status != 0 {
+		return nil, nil
+	}
+	return s.roleRepo.ListPermissionsByRole(ctx, user.Role)
+}
+
+// CheckPermission 校验用户是否拥有指定权限
+func (s *UserAppService) CheckPermission(ctx context.Context, id int64, permission string) (bool, error) {
+	perms, err := s.GetUserPermissions(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return perm.Has(perms, permission), nil
+}
+
+// ListRoles 角色列表
+func (s *UserAppService) ListRoles(ctx context.Context) ([]*domain.Role, error) {
+	return s.roleRepo.ListRoles(ctx)
+}
+
+// userInfoKey 用户信息缓存键
+func (s *UserAppService) userInfoKey(id int64) string {
+	return s.userCache.Key("info", strconv.FormatInt(id, 10))
+}
+
+// evictUser 清除用户信息缓存
+func (s *UserAppService) evictUser(ctx context.Context, id int64) {
+	s.userCache.Del(ctx, s.userInfoKey(id))
+}
+
+// rememberToken 记录用户已签发的令牌，顺带清理已过期的令牌
+func (s *UserAppService) rememberToken(ctx context.Context, id int64, token string) {
+	tokens := []string{token}
+	for _, t := range s.listTokens(ctx, id) {
+		if _, err := util.ParseToken(t); err == nil {
+			tokens = append(tokens, t)
+		}
+	}
+	if data, err := json.Marshal(tokens); err == nil {
+		_ = s.cache.Set(ctx, s.userCache.Key("tokens", strconv.FormatInt(id, 10)), string(data), util.TokenTTL)
+	}
+}
+
+// revokeTokens 吊销用户全部令牌（禁用、改密、角色变更、删除时调用）
+func (s *UserAppService) revokeTokens(ctx context.Context, id int64) {
+	if s.cache == nil {
+		return
+	}
+	tokens := s.listTokens(ctx, id)
+	for _, t := range tokens {
+		_ = s.cache.Del(ctx, "token_"+t)
+	}
+	_ = s.cache.Del(ctx, s.userCache.Key("tokens", strconv.FormatInt(id, 10)))
+	logger.Log().Info("application: 已吊销用户令牌: id=%d count=%d", id, len(tokens))
+}
+
+// listTokens 用户已签发的令牌
+func (s *UserAppService) listTokens(ctx context.Context, id int64) []string {
+	v, err := s.cache.Get(ctx, s.userCache.Key("tokens", strconv.FormatInt(id, 10)))
+	if err != nil {
+		return nil
+	}
+	var tokens []string
+	_ = json.Unmarshal(cacheBytes(v), &tokens)
+	return tokens
 }
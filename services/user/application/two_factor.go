@@ -0,0 +1,290 @@
+package application
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base32"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"blog-system/common/pkg/logger"
+	"blog-system/common/pkg/util"
+	"blog-system/services/user/domain"
+)
+
+const (
+	totpIssuer        = "blog-system"
+	recoveryCodeCount = 10
+
+	// maxChallengeFailures 单个挑战令牌允许的失败次数，达到后令牌作废，需重新输入密码
+	maxChallengeFailures = 5
+	// maxTwoFactorFailures 单个用户连续失败次数上限，达到后锁定 twoFactorLockTTL
+	maxTwoFactorFailures = 10
+	twoFactorLockTTL     = 15 * time.Minute
+)
+
+var errChallengeInvalid = errors.New("挑战令牌无效或已过期")
+
+// LoginTwoFactor 使用挑战令牌 + 动态码（或恢复码）完成登录；挑战令牌成功后即作废，失败达到上限也作废
+func (s *UserAppService) LoginTwoFactor(ctx context.Context, challengeToken, code string) (*domain.User, string, error) {
+	claims, err := util.ParseChallengeToken(challengeToken)
+	if err != nil {
+		logger.Log().Warn("application: 两步验证失败: 挑战令牌无效 err=%v", err)
+		return nil, "", errChallengeInvalid
+	}
+	failures, ok := s.takeChallenge(ctx, claims.ID)
+	if !ok {
+		logger.Log().Warn("application: 两步验证失败: 挑战令牌已使用或已作废 id=%d", claims.UserID)
+		return nil, "", errChallengeInvalid
+	}
+	user, err := s.userRepo.FindByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, "", errors.New("用户不存在")
+	}
+	if user.Status != 0 {
+		return nil, "", errors.New("用户已被禁用")
+	}
+	if !user.TotpEnabled {
+		return nil, "", errors.New("未启用两步验证")
+	}
+	if err := s.verifySecondFactor(ctx, user, code, true); err != nil {
+		logger.Log().Warn("application: 两步验证失败: id=%d failures=%d err=%v", user.ID, failures+1, err)
+		if !errors.Is(err, domain.ErrTwoFactorLocked) {
+			s.returnChallenge(ctx, claims, failures+1)
+		}
+		return nil, "", err
+	}
+	return s.issueToken(ctx, user)
+}
+
+// openChallenge 登记新签发的挑战令牌；未配置缓存时不限制单令牌次数，仅依赖按用户的失败计数
+func (s *UserAppService) openChallenge(ctx context.Context, jti string) {
+	if s.cache == nil {
+		return
+	}
+	if err := s.cache.Set(ctx, "2fa_challenge_"+jti, "0", util.ChallengeTTL); err != nil {
+		logger.Log().Warn("application: 登记挑战令牌失败: err=%v", err)
+	}
+}
+
+// takeChallenge 原子取出挑战令牌的失败次数，取出后令牌在本次校验结束前不可并发使用
+func (s *UserAppService) takeChallenge(ctx context.Context, jti string) (int, bool) {
+	if s.cache == nil {
+		return 0, true
+	}
+	if jti == "" {
+		return 0, false
+	}
+	raw, err := s.cache.LoadAndDelete(ctx, "2fa_challenge_"+jti)
+	if err != nil {
+		return 0, false
+	}
+	n, err := strconv.Atoi(string(cacheBytes(raw)))
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// returnChallenge 校验失败后放回挑战令牌，失败次数达到上限时不再放回
+func (s *UserAppService) returnChallenge(ctx context.Context, claims *util.Claims, failures int) {
+	if s.cache == nil || failures >= maxChallengeFailures || claims.ExpiresAt == nil {
+		return
+	}
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return
+	}
+	_ = s.cache.Set(ctx, "2fa_challenge_"+claims.ID, strconv.Itoa(failures), ttl)
+}
+
+// EnrollTOTP 生成待确认的 TOTP 密钥，返回密钥与 otpauth URI
+func (s *UserAppService) EnrollTOTP(ctx context.Context, id int64) (string, string, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return "", "", err
+	}
+	if user.TotpEnabled {
+		return "", "", errors.New("已启用两步验证")
+	}
+	secret, err := util.GenerateTOTPSecret()
+	if err != nil {
+		logger.Log().Error("application: 生成TOTP密钥失败: id=%d err=%v", id, err)
+		return "", "", err
+	}
+	if err := s.userRepo.UpdateTOTP(ctx, id, &sql.NullString{String: secret, Valid: true}, false); err != nil {
+		return "", "", err
+	}
+	account := user.Email
+	if account == "" {
+		account = user.Username
+	}
+	return secret, util.TOTPURI(totpIssuer, account, secret), nil
+}
+
+// ConfirmTOTP 校验首个动态码后启用两步验证，返回一次性恢复码明文
+func (s *UserAppService) ConfirmTOTP(ctx context.Context, id int64, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.TotpEnabled {
+		return nil, errors.New("已启用两步验证")
+	}
+	if user.TotpSecret == nil || !user.TotpSecret.Valid {
+		return nil, errors.New("请先获取两步验证密钥")
+	}
+	if err := s.verifySecondFactor(ctx, user, code, false); err != nil {
+		return nil, err
+	}
+	if err := s.userRepo.UpdateTOTP(ctx, id, user.TotpSecret, true); err != nil {
+		return nil, err
+	}
+	codes, err := s.resetRecoveryCodes(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	s.evictUser(ctx, id)
+	logger.Log().Info("application: 两步验证已启用: id=%d", id)
+	return codes, nil
+}
+
+// DisableTOTP 校验动态码（或恢复码）后关闭两步验证
+func (s *UserAppService) DisableTOTP(ctx context.Context, id int64, code string) error {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !user.TotpEnabled {
+		return errors.New("未启用两步验证")
+	}
+	if err := s.verifySecondFactor(ctx, user, code, true); err != nil {
+		return err
+	}
+	if err := s.userRepo.UpdateTOTP(ctx, id, nil, false); err != nil {
+		return err
+	}
+	if err := s.userRepo.ReplaceRecoveryCodes(ctx, id, nil); err != nil {
+		return err
+	}
+	s.evictUser(ctx, id)
+	logger.Log().Info("application: 两步验证已关闭: id=%d", id)
+	return nil
+}
+
+// RegenerateRecoveryCodes 校验动态码后重新生成恢复码
+func (s *UserAppService) RegenerateRecoveryCodes(ctx context.Context, id int64, code string) ([]string, error) {
+	user, err := s.userRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TotpEnabled {
+		return nil, errors.New("未启用两步验证")
+	}
+	if err := s.verifySecondFactor(ctx, user, code, false); err != nil {
+		return nil, err
+	}
+	return s.resetRecoveryCodes(ctx, id)
+}
+
+// TwoFactorSetupRequired 用户角色强制两步验证且尚未启用
+func (s *UserAppService) TwoFactorSetupRequired(ctx context.Context, user *domain.User) bool {
+	if user.TotpEnabled {
+		return false
+	}
+	role, err := s.roleRepo.FindRoleByCode(ctx, user.Role)
+	if err != nil {
+		return false
+	}
+	return role.RequireTwoFactor
+}
+
+// SetRoleRequireTwoFactor 设置角色是否强制两步验证
+func (s *UserAppService) SetRoleRequireTwoFactor(ctx context.Context, roleCode string, require bool) error {
+	if _, err := s.roleRepo.FindRoleByCode(ctx, roleCode); err != nil {
+		return errors.New("角色不存在")
+	}
+	return s.roleRepo.UpdateRequireTwoFactor(ctx, roleCode, require)
+}
+
+// verifySecondFactor 校验第二因子并维护按用户的失败计数：锁定期内直接拒绝，连续失败达到上限后锁定
+func (s *UserAppService) verifySecondFactor(ctx context.Context, user *domain.User, code string, allowRecovery bool) error {
+	now := time.Now()
+	if user.TotpLockedUntil != nil && now.Before(*user.TotpLockedUntil) {
+		return domain.ErrTwoFactorLocked
+	}
+	err := s.checkSecondFactor(ctx, user, code, allowRecovery, now)
+	if err == nil {
+		if user.TotpFailures > 0 || user.TotpLockedUntil != nil {
+			_ = s.userRepo.ResetTwoFactorFailures(ctx, user.ID)
+		}
+		return nil
+	}
+	if e := s.userRepo.RecordTwoFactorFailure(ctx, user.ID, maxTwoFactorFailures, now.Add(twoFactorLockTTL)); e != nil {
+		return e
+	}
+	if user.TotpFailures+1 >= maxTwoFactorFailures {
+		logger.Log().Warn("application: 两步验证连续失败已锁定: id=%d", user.ID)
+		return domain.ErrTwoFactorLocked
+	}
+	return err
+}
+
+// checkSecondFactor 先校验 TOTP（同一时间窗口只接受一次），失败则尝试消费一个恢复码
+func (s *UserAppService) checkSecondFactor(ctx context.Context, user *domain.User, code string, allowRecovery bool, now time.Time) error {
+	if user.TotpSecret != nil && user.TotpSecret.Valid {
+		if counter, ok := util.MatchTOTP(user.TotpSecret.String, code, now); ok {
+			return s.userRepo.AdvanceTOTPCounter(ctx, user.ID, counter)
+		}
+	}
+	if !allowRecovery {
+		return errors.New("验证码错误")
+	}
+	codes, err := s.userRepo.ListUnusedRecoveryCodes(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	hash := hashRecoveryCode(code)
+	for _, rc := range codes {
+		if rc.CodeHash == hash {
+			if err := s.userRepo.MarkRecoveryCodeUsed(ctx, rc.ID); err != nil {
+				return err
+			}
+			logger.Log().Info("application: 使用恢复码: id=%d code_id=%d", user.ID, rc.ID)
+			return nil
+		}
+	}
+	return errors.New("验证码错误")
+}
+
+// resetRecoveryCodes 生成新恢复码，仅持久化哈希
+func (s *UserAppService) resetRecoveryCodes(ctx context.Context, id int64) ([]string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+	buf := make([]byte, 5)
+	for i := 0; i < recoveryCodeCount; i++ {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, err
+		}
+		raw := strings.ToLower(base32.StdEncoding.EncodeToString(buf))
+		code := raw[:4] + "-" + raw[4:]
+		codes = append(codes, code)
+		hashes = append(hashes, hashRecoveryCode(code))
+	}
+	if err := s.userRepo.ReplaceRecoveryCodes(ctx, id, hashes); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// hashRecoveryCode 忽略大小写与连字符后取 SHA-256
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,206 @@
+package application
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"blog-system/common/pkg/util"
+	"blog-system/services/user/domain"
+
+	"github.com/CoucouMonEcho/go-framework/cache"
+)
+
+// fakeUserRepo 内存用户仓储，按 SQL 实现的语义模拟两步验证相关的条件更新
+type fakeUserRepo struct {
+	domain.UserRepository
+	user  *domain.User
+	codes []*domain.RecoveryCode
+}
+
+func (r *fakeUserRepo) FindByID(_ context.Context, id int64) (*domain.User, error) {
+	if r.user == nil || r.user.ID != id {
+		return nil, errors.New("not found")
+	}
+	u := *r.user
+	return &u, nil
+}
+
+func (r *fakeUserRepo) ListUnusedRecoveryCodes(_ context.Context, userID int64) ([]*domain.RecoveryCode, error) {
+	var out []*domain.RecoveryCode
+	for _, c := range r.codes {
+		if c.UserID == userID && c.UsedAt == nil {
+			cp := *c
+			out = append(out, &cp)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeUserRepo) MarkRecoveryCodeUsed(_ context.Context, id int64) error {
+	for _, c := range r.codes {
+		if c.ID == id && c.UsedAt == nil {
+			now := time.Now()
+			c.UsedAt = &now
+			return nil
+		}
+	}
+	return domain.ErrRecoveryCodeUsed
+}
+
+func (r *fakeUserRepo) AdvanceTOTPCounter(_ context.Context, _ int64, counter int64) error {
+	if r.user.TotpLastCounter >= counter {
+		return domain.ErrTOTPReplayed
+	}
+	r.user.TotpLastCounter = counter
+	return nil
+}
+
+func (r *fakeUserRepo) RecordTwoFactorFailure(_ context.Context, _ int64, max int, lockUntil time.Time) error {
+	r.user.TotpFailures++
+	if r.user.TotpFailures >= max {
+		r.user.TotpFailures = 0
+		r.user.TotpLockedUntil = &lockUntil
+	}
+	return nil
+}
+
+func (r *fakeUserRepo) ResetTwoFactorFailures(_ context.Context, _ int64) error {
+	r.user.TotpFailures = 0
+	r.user.TotpLockedUntil = nil
+	return nil
+}
+
+func newTwoFactorFixture(t *testing.T) (*UserAppService, *fakeUserRepo, string) {
+	t.Helper()
+	secret, err := util.GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &fakeUserRepo{user: &domain.User{
+		ID: 1, Username: "alice", TotpEnabled: true,
+		TotpSecret: &sql.NullString{String: secret, Valid: true},
+	}}
+	repo.codes = []*domain.RecoveryCode{
+		{ID: 1, UserID: 1, CodeHash: hashRecoveryCode("abcd-efgh")},
+		{ID: 2, UserID: 1, CodeHash: hashRecoveryCode("ijkl-mnop")},
+	}
+	svc := NewUserService(repo, nil, nil, nil, cache.NewBuildInMapCache(time.Minute))
+	return svc, repo, secret
+}
+
+func (r *fakeUserRepo) current() *domain.User {
+	u := *r.user
+	return &u
+}
+
+func TestVerifySecondFactorRecoveryCode(t *testing.T) {
+	svc, repo, _ := newTwoFactorFixture(t)
+	ctx := context.Background()
+
+	// 恢复码忽略大小写与连字符
+	if err := svc.verifySecondFactor(ctx, repo.current(), "ABCDEFGH", true); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if repo.codes[0].UsedAt == nil {
+		t.Fatal("recovery code not marked used")
+	}
+	if err := svc.verifySecondFactor(ctx, repo.current(), "abcd-efgh", true); err == nil {
+		t.Fatal("reused recovery code accepted")
+	}
+	if repo.user.TotpFailures != 1 {
+		t.Fatalf("failures = %d, want 1", repo.user.TotpFailures)
+	}
+	// 仅接受 TOTP 的场景不消费恢复码
+	if err := svc.verifySecondFactor(ctx, repo.current(), "ijkl-mnop", false); err == nil {
+		t.Fatal("recovery code accepted when not allowed")
+	}
+	if repo.codes[1].UsedAt != nil {
+		t.Fatal("recovery code consumed when not allowed")
+	}
+}
+
+// 并发消费时另一请求已抢先标记：未更新任何行应视为校验失败
+func TestVerifySecondFactorRecoveryCodeRace(t *testing.T) {
+	svc, repo, _ := newTwoFactorFixture(t)
+	ctx := context.Background()
+	user := repo.current()
+	now := time.Now()
+	repo.codes[0].UsedAt = &now // 已列出但在标记前被其他请求使用
+
+	stale := &staleCodesRepo{fakeUserRepo: repo, snapshot: []*domain.RecoveryCode{{ID: 1, UserID: 1, CodeHash: hashRecoveryCode("abcd-efgh")}}}
+	svc.userRepo = stale
+	err := svc.verifySecondFactor(ctx, user, "abcd-efgh", true)
+	if !errors.Is(err, domain.ErrRecoveryCodeUsed) {
+		t.Fatalf("err = %v, want ErrRecoveryCodeUsed", err)
+	}
+	if repo.user.TotpFailures != 1 {
+		t.Fatalf("failures = %d, want 1", repo.user.TotpFailures)
+	}
+}
+
+type staleCodesRepo struct {
+	*fakeUserRepo
+	snapshot []*domain.RecoveryCode
+}
+
+func (r *staleCodesRepo) ListUnusedRecoveryCodes(context.Context, int64) ([]*domain.RecoveryCode, error) {
+	return r.snapshot, nil
+}
+
+func TestVerifySecondFactorTOTPReplay(t *testing.T) {
+	svc, repo, secret := newTwoFactorFixture(t)
+	ctx := context.Background()
+	code, err := util.TOTPCode(secret, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.verifySecondFactor(ctx, repo.current(), code, true); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if err := svc.verifySecondFactor(ctx, repo.current(), code, true); !errors.Is(err, domain.ErrTOTPReplayed) {
+		t.Fatalf("replay err = %v, want ErrTOTPReplayed", err)
+	}
+}
+
+func TestVerifySecondFactorLock(t *testing.T) {
+	svc, repo, secret := newTwoFactorFixture(t)
+	ctx := context.Background()
+	for i := 1; i < maxTwoFactorFailures; i++ {
+		if err := svc.verifySecondFactor(ctx, repo.current(), "000000", true); err == nil || errors.Is(err, domain.ErrTwoFactorLocked) {
+			t.Fatalf("attempt %d: err = %v", i, err)
+		}
+	}
+	if err := svc.verifySecondFactor(ctx, repo.current(), "000000", true); !errors.Is(err, domain.ErrTwoFactorLocked) {
+		t.Fatalf("err = %v, want ErrTwoFactorLocked", err)
+	}
+	// 锁定期内正确的动态码同样被拒绝
+	code, _ := util.TOTPCode(secret, time.Now())
+	if err := svc.verifySecondFactor(ctx, repo.current(), code, true); !errors.Is(err, domain.ErrTwoFactorLocked) {
+		t.Fatalf("locked err = %v, want ErrTwoFactorLocked", err)
+	}
+}
+
+func TestLoginTwoFactorChallengeLimit(t *testing.T) {
+	svc, repo, _ := newTwoFactorFixture(t)
+	ctx := context.Background()
+	_, _, err := svc.completeLogin(ctx, repo.current())
+	var challenge *domain.TwoFactorChallenge
+	if !errors.As(err, &challenge) {
+		t.Fatalf("completeLogin err = %v, want challenge", err)
+	}
+	for i := 0; i < maxChallengeFailures; i++ {
+		if _, _, err := svc.LoginTwoFactor(ctx, challenge.Token, "000000"); err == nil || errors.Is(err, errChallengeInvalid) {
+			t.Fatalf("attempt %d: err = %v", i+1, err)
+		}
+	}
+	// 达到单令牌上限后令牌作废，即使用户未被锁定
+	if _, _, err := svc.LoginTwoFactor(ctx, challenge.Token, "abcd-efgh"); !errors.Is(err, errChallengeInvalid) {
+		t.Fatalf("err = %v, want errChallengeInvalid", err)
+	}
+	if repo.codes[0].UsedAt != nil {
+		t.Fatal("recovery code consumed with revoked challenge")
+	}
+}
@@ -0,0 +1,45 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// UserIdentity 第三方登录身份（provider + subject 唯一）
+type UserIdentity struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"` // 提供方侧的用户唯一标识
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (UserIdentity) TableName() string { return "blog_user_identity" }
+
+// ExternalProfile 提供方返回的用户资料
+type ExternalProfile struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+	Avatar        string
+}
+
+// OAuthProvider OAuth2 授权码 + PKCE 登录提供方
+type OAuthProvider interface {
+	// Name 提供方名称，如 github
+	Name() string
+	// AuthCodeURL 拼接授权地址，codeChallenge 为 S256 摘要
+	AuthCodeURL(state, codeChallenge string) string
+	// Exchange 用授权码与 code_verifier 换取令牌并拉取用户资料
+	Exchange(ctx context.Context, code, codeVerifier string) (*ExternalProfile, error)
+}
+
+// IdentityRepository 第三方身份仓储接口
+type IdentityRepository interface {
+	FindIdentity(ctx context.Context, provider, subject string) (*UserIdentity, error)
+	CreateIdentity(ctx context.Context, identity *UserIdentity) error
+	ListIdentitiesByUser(ctx context.Context, userID int64) ([]*UserIdentity, error)
+}
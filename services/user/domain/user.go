@@ -3,24 +3,67 @@ package domain
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"time"
 )
 
 // User 用户领域模型
 type User struct {
-	ID        int64           `json:"id"`
-	Username  string          `json:"username"`
-	Email     string          `json:"email"`
-	Password  string          `json:"-"` // 不序列化密码
-	Role      string          `json:"role"`
-	Avatar    *sql.NullString `json:"avatar"`
-	Status    int             `json:"status"` // 0:正常, 1:禁用
-	CreatedAt time.Time       `json:"created_at"`
-	UpdatedAt time.Time       `json:"updated_at"`
+	ID          int64           `json:"id"`
+	Username    string          `json:"username"`
+	Email       string          `json:"email"`
+	Password    string          `json:"-"` // 不序列化密码
+	Role        string          `json:"role"`
+	Avatar      *sql.NullString `json:"avatar"`
+	Status      int             `json:"status"` // 0:正常, 1:禁用
+	TotpSecret  *sql.NullString `json:"-"`      // 两步验证密钥（Base32），启用前为待确认状态
+	TotpEnabled bool            `json:"totp_enabled"`
+	// TotpLastCounter 最近一次通过校验的 TOTP 时间窗口计数，不大于它的动态码视为重放
+	TotpLastCounter int64      `json:"-"`
+	TotpFailures    int        `json:"-"` // 连续校验失败次数，成功后清零
+	TotpLockedUntil *time.Time `json:"-"` // 失败次数达到上限后锁定至该时间
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
 func (User) TableName() string { return "blog_user" }
 
+var (
+	// ErrTwoFactorLocked 两步验证连续失败次数过多，暂时锁定
+	ErrTwoFactorLocked = errors.New("验证失败次数过多，请稍后再试")
+	// ErrTOTPReplayed 动态码所在时间窗口已被使用过
+	ErrTOTPReplayed = errors.New("验证码已使用")
+	// ErrRecoveryCodeUsed 恢复码已被使用（并发消费时未抢到）
+	ErrRecoveryCodeUsed = errors.New("恢复码已使用")
+	// ErrUserHasArticles 用户仍是文章作者，不能物理删除
+	ErrUserHasArticles = errors.New("用户仍有文章，不能物理删除")
+)
+
+// RecoveryCode 两步验证恢复码（仅存哈希，一次性）
+type RecoveryCode struct {
+	ID        int64      `json:"id"`
+	UserID    int64      `json:"user_id"`
+	CodeHash  string     `json:"-"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func (RecoveryCode) TableName() string { return "blog_user_recovery_code" }
+
+// TwoFactorChallenge 登录需要两步验证时返回的错误，携带短期挑战令牌
+type TwoFactorChallenge struct {
+	Token string
+}
+
+func (e *TwoFactorChallenge) Error() string { return "需要两步验证" }
+
+// UserFilter 用户列表筛选条件，零值字段不参与过滤
+type UserFilter struct {
+	Role    string
+	Status  *int
+	Keyword string // 用户名/邮箱模糊匹配
+}
+
 // UserRepository 用户仓储接口
 type UserRepository interface {
 	Create(ctx context.Context, user *User) error
@@ -28,9 +71,63 @@ type UserRepository interface {
 	FindByUsername(ctx context.Context, username string) (*User, error)
 	FindByEmail(ctx context.Context, email string) (*User, error)
 	Update(ctx context.Context, user *User) error
+	// Delete 物理删除；用户仍有文章时返回 ErrUserHasArticles
 	Delete(ctx context.Context, id int64) error
 	List(ctx context.Context, page, pageSize int) ([]*User, int64, error)
+	Search(ctx context.Context, filter UserFilter, page, pageSize int) ([]*User, int64, error)
 	UpdateStatus(ctx context.Context, id int64, status int) error
+	UpdateTOTP(ctx context.Context, id int64, secret *sql.NullString, enabled bool) error
+	ReplaceRecoveryCodes(ctx context.Context, userID int64, hashes []string) error
+	ListUnusedRecoveryCodes(ctx context.Context, userID int64) ([]*RecoveryCode, error)
+	MarkRecoveryCodeUsed(ctx context.Context, id int64) error
+	// AdvanceTOTPCounter 仅当 counter 大于已记录的计数时更新，否则返回 ErrTOTPReplayed
+	AdvanceTOTPCounter(ctx context.Context, id, counter int64) error
+	// RecordTwoFactorFailure 失败次数加一，达到 max 时清零并锁定到 lockUntil
+	RecordTwoFactorFailure(ctx context.Context, id int64, max int, lockUntil time.Time) error
+	ResetTwoFactorFailures(ctx context.Context, id int64) error
+}
+
+// Role 角色
+type Role struct {
+	ID               int64           `json:"id"`
+	Code             string          `json:"code"`
+	Name             string          `json:"name"`
+	Description      *sql.NullString `json:"description"`
+	RequireTwoFactor bool            `json:"require_two_factor"` // 该角色用户须启用两步验证才获得权限
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+func (Role) TableName() string { return "blog_role" }
+
+// Permission 权限点
+type Permission struct {
+	ID        int64     `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (Permission) TableName() string { return "blog_permission" }
+
+// RolePermission 角色-权限 关联（多对多）
+type RolePermission struct {
+	ID           int64     `json:"id"`
+	RoleID       int64     `json:"role_id"`
+	PermissionID int64     `json:"permission_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (RolePermission) TableName() string { return "blog_role_permission" }
+
+// RoleRepository 角色权限仓储接口
+type RoleRepository interface {
+	FindRoleByCode(ctx context.Context, code string) (*Role, error)
+	ListRoles(ctx context.Context) ([]*Role, error)
+	ListPermissions(ctx context.Context) ([]*Permission, error)
+	ListPermissionsByRole(ctx context.Context, roleCode string) ([]string, error)
+	SetRolePermissions(ctx context.Context, roleID int64, permissionIDs []int64) error
+	UpdateRequireTwoFactor(ctx context.Context, code string, require bool) error
 }
 
 // UserService 用户领域服务
@@ -0,0 +1,236 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	conf "blog-system/common/pkg/config"
+	"blog-system/common/pkg/logger"
+	"blog-system/services/user/domain"
+)
+
+const (
+	KindGitHub = "github"
+	KindOIDC   = "oidc"
+)
+
+// GitHub 默认端点
+const (
+	githubAuthURL     = "https://github.com/login/oauth/authorize"
+	githubTokenURL    = "https://github.com/login/oauth/access_token"
+	githubUserInfoURL = "https://api.github.com/user"
+	githubEmailsURL   = "https://api.github.com/user/emails"
+)
+
+var _ domain.OAuthProvider = &Provider{}
+
+// Provider 通用 OAuth2 授权码 + PKCE 客户端，资料拉取方式按 Kind 区分
+type Provider struct {
+	cfg    conf.OAuthProviderConfig
+	client *http.Client
+}
+
+// NewProvider 根据配置创建提供方，client 为空时使用默认超时客户端
+func NewProvider(cfg conf.OAuthProviderConfig, client *http.Client) (*Provider, error) {
+	if cfg.Name == "" || cfg.ClientID == "" {
+		return nil, errors.New("oauth: name 与 client_id 不能为空")
+	}
+	switch cfg.Kind {
+	case KindGitHub:
+		if cfg.AuthURL == "" {
+			cfg.AuthURL = githubAuthURL
+		}
+		if cfg.TokenURL == "" {
+			cfg.TokenURL = githubTokenURL
+		}
+		if cfg.UserInfoURL == "" {
+			cfg.UserInfoURL = githubUserInfoURL
+		}
+		if cfg.EmailsURL == "" {
+			cfg.EmailsURL = githubEmailsURL
+		}
+		if len(cfg.Scopes) == 0 {
+			cfg.Scopes = []string{"read:user", "user:email"}
+		}
+	case KindOIDC:
+		if cfg.AuthURL == "" || cfg.TokenURL == "" || cfg.UserInfoURL == "" {
+			return nil, fmt.Errorf("oauth: %s 缺少 auth_url/token_url/userinfo_url", cfg.Name)
+		}
+		if len(cfg.Scopes) == 0 {
+			cfg.Scopes = []string{"openid", "email", "profile"}
+		}
+	default:
+		return nil, fmt.Errorf("oauth: 不支持的提供方类型 %q", cfg.Kind)
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &Provider{cfg: cfg, client: client}, nil
+}
+
+// NewProviders 批量创建提供方，配置错误的条目记录日志后跳过
+func NewProviders(cfgs []conf.OAuthProviderConfig) map[string]domain.OAuthProvider {
+	out := make(map[string]domain.OAuthProvider, len(cfgs))
+	for _, c := range cfgs {
+		p, err := NewProvider(c, nil)
+		if err != nil {
+			logger.Log().Error("oauth: 提供方配置无效: %v", err)
+			continue
+		}
+		out[p.Name()] = p
+	}
+	return out
+}
+
+// Name 提供方名称
+func (p *Provider) Name() string { return p.cfg.Name }
+
+// AuthCodeURL 拼接授权地址
+func (p *Provider) AuthCodeURL(state, codeChallenge string) string {
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.cfg.RedirectURL)
+	q.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	q.Set("state", state)
+	q.Set("code_challenge", codeChallenge)
+	q.Set("code_challenge_method", "S256")
+	sep := "?"
+	if strings.Contains(p.cfg.AuthURL, "?") {
+		sep = "&"
+	}
+	return p.cfg.AuthURL + sep + q.Encode()
+}
+
+// Exchange 授权码换取访问令牌并拉取用户资料
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier string) (*domain.ExternalProfile, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("client_secret", p.cfg.ClientSecret)
+	form.Set("code_verifier", codeVerifier)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	var tok struct {
+		AccessToken      string `json:"access_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := p.do(req, &tok); err != nil {
+		return nil, err
+	}
+	if tok.Error != "" {
+		return nil, fmt.Errorf("oauth: %s 换取令牌失败: %s %s", p.cfg.Name, tok.Error, tok.ErrorDescription)
+	}
+	if tok.AccessToken == "" {
+		return nil, fmt.Errorf("oauth: %s 未返回 access_token", p.cfg.Name)
+	}
+	if p.cfg.Kind == KindGitHub {
+		return p.githubProfile(ctx, tok.AccessToken)
+	}
+	return p.oidcProfile(ctx, tok.AccessToken)
+}
+
+// oidcProfile 通过 userinfo 端点获取标准声明
+func (p *Provider) oidcProfile(ctx context.Context, accessToken string) (*domain.ExternalProfile, error) {
+	var info struct {
+		Sub               string `json:"sub"`
+		Email             string `json:"email"`
+		EmailVerified     bool   `json:"email_verified"`
+		PreferredUsername string `json:"preferred_username"`
+		Name              string `json:"name"`
+		Picture           string `json:"picture"`
+	}
+	if err := p.getJSON(ctx, p.cfg.UserInfoURL, accessToken, &info); err != nil {
+		return nil, err
+	}
+	if info.Sub == "" {
+		return nil, fmt.Errorf("oauth: %s userinfo 缺少 sub", p.cfg.Name)
+	}
+	username := info.PreferredUsername
+	if username == "" {
+		username = info.Name
+	}
+	return &domain.ExternalProfile{
+		Subject:       info.Sub,
+		Email:         info.Email,
+		EmailVerified: info.EmailVerified,
+		Username:      username,
+		Avatar:        info.Picture,
+	}, nil
+}
+
+// githubProfile 获取 GitHub 用户与主邮箱（仅采信已验证邮箱）
+func (p *Provider) githubProfile(ctx context.Context, accessToken string) (*domain.ExternalProfile, error) {
+	var u struct {
+		ID        int64  `json:"id"`
+		Login     string `json:"login"`
+		AvatarURL string `json:"avatar_url"`
+	}
+	if err := p.getJSON(ctx, p.cfg.UserInfoURL, accessToken, &u); err != nil {
+		return nil, err
+	}
+	if u.ID == 0 {
+		return nil, errors.New("oauth: github 用户信息缺少 id")
+	}
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := p.getJSON(ctx, p.cfg.EmailsURL, accessToken, &emails); err != nil {
+		return nil, err
+	}
+	profile := &domain.ExternalProfile{Subject: strconv.FormatInt(u.ID, 10), Username: u.Login, Avatar: u.AvatarURL}
+	for _, e := range emails {
+		if e.Primary && e.Verified {
+			profile.Email, profile.EmailVerified = e.Email, true
+			break
+		}
+	}
+	return profile, nil
+}
+
+// getJSON 携带 Bearer 令牌请求 JSON
+func (p *Provider) getJSON(ctx context.Context, endpoint, accessToken string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+	return p.do(req, out)
+}
+
+// do 发送请求并解析 JSON 响应
+func (p *Provider) do(req *http.Request, out any) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		logger.Log().Error("oauth: 请求失败: provider=%s url=%s err=%v", p.cfg.Name, req.URL.Redacted(), err)
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		logger.Log().Warn("oauth: 响应异常: provider=%s url=%s status=%d", p.cfg.Name, req.URL.Redacted(), resp.StatusCode)
+		return fmt.Errorf("oauth: %s 返回状态码 %d", p.cfg.Name, resp.StatusCode)
+	}
+	return json.Unmarshal(body, out)
+}
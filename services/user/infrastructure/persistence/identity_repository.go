@@ -0,0 +1,56 @@
+package infrastructure
+
+import (
+	"context"
+
+	"blog-system/common/pkg/logger"
+	"blog-system/services/user/domain"
+
+	"github.com/CoucouMonEcho/go-framework/orm"
+)
+
+var _ domain.IdentityRepository = &IdentityRepository{}
+
+// IdentityRepository 第三方身份仓储实现
+type IdentityRepository struct {
+	db *orm.DB
+}
+
+// NewIdentityRepository 创建第三方身份仓储
+func NewIdentityRepository(db *orm.DB) *IdentityRepository {
+	return &IdentityRepository{db: db}
+}
+
+// FindIdentity 根据提供方与 subject 查找身份
+func (r *IdentityRepository) FindIdentity(ctx context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	identity, err := orm.NewSelector[domain.UserIdentity](r.db).
+		Where(orm.C("Provider").Eq(provider), orm.C("Subject").Eq(subject)).
+		Get(ctx)
+	if err != nil {
+		logger.Log().Warn("repository: FindIdentity 查询失败: provider=%s subject=%s err=%v", provider, subject, err)
+		return nil, err
+	}
+	return identity, nil
+}
+
+// CreateIdentity 绑定第三方身份
+func (r *IdentityRepository) CreateIdentity(ctx context.Context, identity *domain.UserIdentity) error {
+	if err := orm.NewInserter[domain.UserIdentity](r.db).Values(identity).Exec(ctx).Err(); err != nil {
+		logger.Log().Error("repository: CreateIdentity 失败: identity=%+v err=%v", identity, err)
+		return err
+	}
+	return nil
+}
+
+// ListIdentitiesByUser 查询用户已绑定的第三方身份
+func (r *IdentityRepository) ListIdentitiesByUser(ctx context.Context, userID int64) ([]*domain.UserIdentity, error) {
+	list, err := orm.NewSelector[domain.UserIdentity](r.db).
+		Where(orm.C("UserID").Eq(userID)).
+		OrderBy(orm.Asc("ID")).
+		GetMulti(ctx)
+	if err != nil {
+		logger.Log().Warn("repository: ListIdentitiesByUser 查询失败: user=%d err=%v", userID, err)
+		return nil, err
+	}
+	return list, nil
+}
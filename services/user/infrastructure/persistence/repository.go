@@ -2,13 +2,17 @@ package infrastructure
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"strings"
+	"time"
 
 	"blog-system/common/pkg/aggregate"
 	"blog-system/common/pkg/logger"
 	"blog-system/services/user/domain"
 
 	"github.com/CoucouMonEcho/go-framework/orm"
+	"github.com/go-sql-driver/mysql"
 )
 
 var _ domain.UserRepository = &UserRepository{}
@@ -32,6 +36,10 @@ func (r *UserRepository) Create(ctx context.Context, user *domain.User) error {
 		logger.Log().Error("repository: Create 用户失败: user=%+v err=%v", user, err)
 		return err
 	}
+	// 回填自增ID
+	if id, err := res.LastInsertId(); err == nil {
+		user.ID = id
+	}
 	return nil
 }
 
@@ -86,9 +94,14 @@ func (r *UserRepository) Update(ctx context.Context, user *domain.User) error {
 	return nil
 }
 
-// Delete 删除用户
+// Delete 物理删除用户（软删除请使用 UpdateStatus）
 func (r *UserRepository) Delete(ctx context.Context, id int64) error {
-	if err := orm.NewUpdater[domain.User](r.db).Set(orm.C("Status"), 1).Where(orm.C("ID").Eq(id)).Exec(ctx).Err(); err != nil {
+	if err := orm.NewDeleter[domain.User](r.db).Where(orm.C("ID").Eq(id)).Exec(ctx).Err(); err != nil {
+		// blog_article.author_id 为 ON DELETE RESTRICT，仍有文章时由外键拒绝
+		var me *mysql.MySQLError
+		if errors.As(err, &me) && me.Number == 1451 && strings.Contains(me.Message, "blog_article") {
+			return domain.ErrUserHasArticles
+		}
 		logger.Log().Error("repository: Delete 用户失败: id=%d err=%v", id, err)
 		return err
 	}
@@ -97,19 +110,37 @@ func (r *UserRepository) Delete(ctx context.Context, id int64) error {
 
 // List 用户列表
 func (r *UserRepository) List(ctx context.Context, page, pageSize int) ([]*domain.User, int64, error) {
+	return r.Search(ctx, domain.UserFilter{}, page, pageSize)
+}
+
+// Search 按角色/状态/关键字筛选用户
+func (r *UserRepository) Search(ctx context.Context, filter domain.UserFilter, page, pageSize int) ([]*domain.User, int64, error) {
+	var ps []orm.Predicate
+	if filter.Role != "" {
+		ps = append(ps, orm.C("Role").Eq(filter.Role))
+	}
+	if filter.Status != nil {
+		ps = append(ps, orm.C("Status").Eq(*filter.Status))
+	}
+	if kw := strings.TrimSpace(filter.Keyword); kw != "" {
+		like := "%" + kw + "%"
+		ps = append(ps, orm.Raw("(username LIKE ? OR email LIKE ?)", like, like).AsPredicate())
+	}
+
 	offset := (page - 1) * pageSize
-	users, err := orm.NewSelector[domain.User](r.db).OrderBy(orm.Desc("CreatedAt")).Limit(pageSize).Offset(offset).GetMulti(ctx)
+	users, err := orm.NewSelector[domain.User](r.db).Where(ps...).OrderBy(orm.Desc("CreatedAt")).Limit(pageSize).Offset(offset).GetMulti(ctx)
 	if err != nil {
-		logger.Log().Warn("repository: List 查询列表失败: page=%d size=%d err=%v", page, pageSize, err)
+		logger.Log().Warn("repository: Search 查询列表失败: filter=%+v page=%d size=%d err=%v", filter, page, pageSize, err)
 		return nil, 0, err
 	}
 
 	total, err := orm.NewSelector[aggregate.Result](r.db).
 		From(orm.TableOf(&domain.User{})).
 		Select(orm.Count("ID").As("count")).
+		Where(ps...).
 		Get(ctx)
 	if err != nil {
-		logger.Log().Warn("repository: List 统计总数失败: err=%v", err)
+		logger.Log().Warn("repository: Search 统计总数失败: err=%v", err)
 		return nil, 0, err
 	}
 
@@ -124,3 +155,98 @@ func (r *UserRepository) UpdateStatus(ctx context.Context, id int64, status int)
 	}
 	return nil
 }
+
+// UpdateTOTP 更新两步验证密钥与启用状态；密钥变化时清空防重放计数（须在 totp_secret 赋值前求值）
+func (r *UserRepository) UpdateTOTP(ctx context.Context, id int64, secret *sql.NullString, enabled bool) error {
+	if err := orm.RawQuery[domain.User](r.db,
+		"UPDATE blog_user SET totp_last_counter = IF(totp_secret <=> ?, totp_last_counter, 0), totp_secret = ?, totp_enabled = ?, updated_at = ? WHERE id = ?",
+		secret, secret, enabled, time.Now(), id).Exec(ctx).Err(); err != nil {
+		logger.Log().Error("repository: UpdateTOTP 失败: id=%d err=%v", id, err)
+		return err
+	}
+	return nil
+}
+
+// AdvanceTOTPCounter 条件更新保证同一时间窗口的动态码只能使用一次
+func (r *UserRepository) AdvanceTOTPCounter(ctx context.Context, id, counter int64) error {
+	res := orm.RawQuery[domain.User](r.db,
+		"UPDATE blog_user SET totp_last_counter = ? WHERE id = ? AND totp_last_counter < ?", counter, id, counter).Exec(ctx)
+	if err := res.Err(); err != nil {
+		logger.Log().Error("repository: AdvanceTOTPCounter 失败: id=%d err=%v", id, err)
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return domain.ErrTOTPReplayed
+	}
+	return nil
+}
+
+// RecordTwoFactorFailure 失败计数加一；达到上限时计数清零并写入锁定时间（MySQL 按从左到右的顺序求值 SET）
+func (r *UserRepository) RecordTwoFactorFailure(ctx context.Context, id int64, max int, lockUntil time.Time) error {
+	if err := orm.RawQuery[domain.User](r.db,
+		"UPDATE blog_user SET totp_failures = IF(totp_failures + 1 >= ?, 0, totp_failures + 1), "+
+			"totp_locked_until = IF(totp_failures = 0, ?, totp_locked_until) WHERE id = ?",
+		max, lockUntil, id).Exec(ctx).Err(); err != nil {
+		logger.Log().Error("repository: RecordTwoFactorFailure 失败: id=%d err=%v", id, err)
+		return err
+	}
+	return nil
+}
+
+// ResetTwoFactorFailures 校验成功后清零失败计数
+func (r *UserRepository) ResetTwoFactorFailures(ctx context.Context, id int64) error {
+	if err := orm.RawQuery[domain.User](r.db,
+		"UPDATE blog_user SET totp_failures = 0, totp_locked_until = NULL WHERE id = ? AND (totp_failures > 0 OR totp_locked_until IS NOT NULL)", id).
+		Exec(ctx).Err(); err != nil {
+		logger.Log().Error("repository: ResetTwoFactorFailures 失败: id=%d err=%v", id, err)
+		return err
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes 覆盖用户的恢复码（旧码全部作废）
+func (r *UserRepository) ReplaceRecoveryCodes(ctx context.Context, userID int64, hashes []string) error {
+	if err := orm.NewDeleter[domain.RecoveryCode](r.db).Where(orm.C("UserID").Eq(userID)).Exec(ctx).Err(); err != nil {
+		logger.Log().Error("repository: ReplaceRecoveryCodes 删除旧恢复码失败: user=%d err=%v", userID, err)
+		return err
+	}
+	if len(hashes) == 0 {
+		return nil
+	}
+	now := time.Now()
+	batch := make([]*domain.RecoveryCode, 0, len(hashes))
+	for _, h := range hashes {
+		batch = append(batch, &domain.RecoveryCode{UserID: userID, CodeHash: h, CreatedAt: now})
+	}
+	if err := orm.NewInserter[domain.RecoveryCode](r.db).Values(batch...).Exec(ctx).Err(); err != nil {
+		logger.Log().Error("repository: ReplaceRecoveryCodes 写入失败: user=%d err=%v", userID, err)
+		return err
+	}
+	return nil
+}
+
+// ListUnusedRecoveryCodes 查询未使用的恢复码
+func (r *UserRepository) ListUnusedRecoveryCodes(ctx context.Context, userID int64) ([]*domain.RecoveryCode, error) {
+	list, err := orm.NewSelector[domain.RecoveryCode](r.db).
+		Where(orm.C("UserID").Eq(userID), orm.Raw("used_at IS NULL").AsPredicate()).
+		GetMulti(ctx)
+	if err != nil {
+		logger.Log().Warn("repository: ListUnusedRecoveryCodes 查询失败: user=%d err=%v", userID, err)
+		return nil, err
+	}
+	return list, nil
+}
+
+// MarkRecoveryCodeUsed 标记恢复码已使用；未更新任何行（已被使用）时返回 ErrRecoveryCodeUsed
+func (r *UserRepository) MarkRecoveryCodeUsed(ctx context.Context, id int64) error {
+	res := orm.RawQuery[domain.RecoveryCode](r.db,
+		"UPDATE blog_user_recovery_code SET used_at = ? WHERE id = ? AND used_at IS NULL", time.Now(), id).Exec(ctx)
+	if err := res.Err(); err != nil {
+		logger.Log().Error("repository: MarkRecoveryCodeUsed 失败: id=%d err=%v", id, err)
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return domain.ErrRecoveryCodeUsed
+	}
+	return nil
+}
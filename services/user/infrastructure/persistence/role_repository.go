@@ -0,0 +1,106 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"blog-system/common/pkg/logger"
+	"blog-system/services/user/domain"
+
+	"github.com/CoucouMonEcho/go-framework/orm"
+)
+
+var _ domain.RoleRepository = &RoleRepository{}
+
+// RoleRepository 角色权限仓储实现
+type RoleRepository struct {
+	db *orm.DB
+}
+
+// NewRoleRepository 创建角色权限仓储
+func NewRoleRepository(db *orm.DB) *RoleRepository {
+	return &RoleRepository{db: db}
+}
+
+// FindRoleByCode 根据角色编码查找角色
+func (r *RoleRepository) FindRoleByCode(ctx context.Context, code string) (*domain.Role, error) {
+	role, err := orm.NewSelector[domain.Role](r.db).Where(orm.C("Code").Eq(code)).Get(ctx)
+	if err != nil {
+		logger.Log().Warn("repository: FindRoleByCode 查询失败: code=%s err=%v", code, err)
+		return nil, err
+	}
+	return role, nil
+}
+
+// ListRoles 全部角色
+func (r *RoleRepository) ListRoles(ctx context.Context) ([]*domain.Role, error) {
+	list, err := orm.NewSelector[domain.Role](r.db).OrderBy(orm.Asc("ID")).GetMulti(ctx)
+	if err != nil {
+		logger.Log().Warn("repository: ListRoles 查询失败: err=%v", err)
+		return nil, err
+	}
+	return list, nil
+}
+
+// ListPermissions 全部权限点
+func (r *RoleRepository) ListPermissions(ctx context.Context) ([]*domain.Permission, error) {
+	list, err := orm.NewSelector[domain.Permission](r.db).OrderBy(orm.Asc("ID")).GetMulti(ctx)
+	if err != nil {
+		logger.Log().Warn("repository: ListPermissions 查询失败: err=%v", err)
+		return nil, err
+	}
+	return list, nil
+}
+
+// ListPermissionsByRole 查询角色拥有的权限编码
+func (r *RoleRepository) ListPermissionsByRole(ctx context.Context, roleCode string) ([]string, error) {
+	rows, err := orm.NewSelector[domain.Permission](r.db).
+		Where(orm.Raw("EXISTS (SELECT 1 FROM blog_role_permission rp JOIN blog_role r ON r.id = rp.role_id WHERE rp.permission_id = blog_permission.id AND r.code = ?)", roleCode).AsPredicate()).
+		GetMulti(ctx)
+	if err != nil {
+		logger.Log().Warn("repository: ListPermissionsByRole 查询失败: role=%s err=%v", roleCode, err)
+		return nil, err
+	}
+	codes := make([]string, 0, len(rows))
+	for _, p := range rows {
+		codes = append(codes, p.Code)
+	}
+	return codes, nil
+}
+
+// SetRolePermissions 覆盖角色的权限集合
+func (r *RoleRepository) SetRolePermissions(ctx context.Context, roleID int64, permissionIDs []int64) error {
+	// 删除旧关联与写入新关联在同一事务中，避免中途失败后角色丢失全部权限
+	return r.db.DoTx(ctx, func(ctx context.Context, tx *orm.Tx) error {
+		if err := orm.NewDeleter[domain.RolePermission](tx).Where(orm.C("RoleID").Eq(roleID)).Exec(ctx).Err(); err != nil {
+			logger.Log().Error("repository: SetRolePermissions 删除旧关联失败: role=%d err=%v", roleID, err)
+			return err
+		}
+		if len(permissionIDs) == 0 {
+			return nil
+		}
+		now := time.Now()
+		batch := make([]*domain.RolePermission, 0, len(permissionIDs))
+		for _, pid := range permissionIDs {
+			batch = append(batch, &domain.RolePermission{RoleID: roleID, PermissionID: pid, CreatedAt: now})
+		}
+		if err := orm.NewInserter[domain.RolePermission](tx).Values(batch...).Exec(ctx).Err(); err != nil {
+			logger.Log().Error("repository: SetRolePermissions 写入关联失败: role=%d err=%v", roleID, err)
+			return err
+		}
+		return nil
+	}, nil)
+}
+
+// UpdateRequireTwoFactor 设置角色是否强制两步验证
+func (r *RoleRepository) UpdateRequireTwoFactor(ctx context.Context, code string, require bool) error {
+	if err := orm.NewUpdater[domain.Role](r.db).
+		Set(orm.C("RequireTwoFactor"), require).
+		Set(orm.C("UpdatedAt"), time.Now()).
+		Where(orm.C("Code").Eq(code)).
+		Exec(ctx).Err(); err != nil {
+		logger.Log().Error("repository: UpdateRequireTwoFactor 失败: code=%s err=%v", code, err)
+		return err
+	}
+	return nil
+}
@@ -2,8 +2,11 @@ package grpcserver
 
 import (
 	"context"
+	"time"
 
+	"blog-system/common/pkg/pagination"
 	"blog-system/services/user/application"
+	"blog-system/services/user/domain"
 	pb "blog-system/services/user/proto"
 )
 
@@ -20,22 +23,24 @@ func (s *GRPCServer) Register(ctx context.Context, req *pb.RegisterRequest) (*pb
 	if err != nil {
 		return &pb.RegisterResponse{Code: 1, Message: err.Error()}, nil
 	}
-	return &pb.RegisterResponse{Code: 0, Message: "success", Data: &pb.User{Id: u.ID, Username: u.Username, Email: u.Email, Role: u.Role}}, nil
+	return &pb.RegisterResponse{Code: 0, Message: "success", Data: toPBUser(u)}, nil
 }
 
-// ListUsers 用户列表
+// ListUsers 用户列表（支持角色/状态/关键字筛选）
 func (s *GRPCServer) ListUsers(ctx context.Context, req *pb.ListUsersRequest) (*pb.ListUsersResponse, error) {
-	list, total, err := s.app.ListUsers(ctx, int(req.Page), int(req.PageSize))
+	filter := domain.UserFilter{Role: req.Role, Keyword: req.Keyword}
+	if req.Status != nil {
+		status := int(*req.Status)
+		filter.Status = &status
+	}
+	page, pageSize := pagination.Normalize(int(req.Page), int(req.PageSize))
+	list, total, err := s.app.SearchUsers(ctx, filter, page, pageSize)
 	if err != nil {
 		return &pb.ListUsersResponse{Code: 1, Message: err.Error()}, nil
 	}
 	out := make([]*pb.User, 0, len(list))
 	for _, u := range list {
-		avatar := ""
-		if u.Avatar != nil && u.Avatar.Valid {
-			avatar = u.Avatar.String
-		}
-		out = append(out, &pb.User{Id: u.ID, Username: u.Username, Email: u.Email, Role: u.Role, Avatar: avatar, Status: int32(u.Status)})
+		out = append(out, toPBUser(u))
 	}
 	return &pb.ListUsersResponse{Code: 0, Message: "success", Data: out, Total: total}, nil
 }
@@ -75,11 +80,7 @@ func (s *GRPCServer) GetUserInfo(ctx context.Context, req *pb.GetUserInfoRequest
 	if err != nil {
 		return &pb.GetUserInfoResponse{Code: 1, Message: err.Error()}, nil
 	}
-	avatar := ""
-	if u.Avatar != nil && u.Avatar.Valid {
-		avatar = u.Avatar.String
-	}
-	return &pb.GetUserInfoResponse{Code: 0, Message: "success", Data: &pb.User{Id: u.ID, Username: u.Username, Email: u.Email, Role: u.Role, Avatar: avatar, Status: int32(u.Status)}}, nil
+	return &pb.GetUserInfoResponse{Code: 0, Message: "success", Data: toPBUser(u)}, nil
 }
 
 // ChangePassword 修改密码
@@ -97,3 +98,76 @@ func (s *GRPCServer) ResetPassword(ctx context.Context, req *pb.ResetPasswordReq
 	}
 	return &pb.ResetPasswordResponse{Code: 0, Message: "success"}, nil
 }
+
+// CheckPermission 校验用户权限
+func (s *GRPCServer) CheckPermission(ctx context.Context, req *pb.CheckPermissionRequest) (*pb.CheckPermissionResponse, error) {
+	allowed, err := s.app.CheckPermission(ctx, req.UserId, req.Permission)
+	if err != nil {
+		return &pb.CheckPermissionResponse{Code: 1, Message: err.Error()}, nil
+	}
+	return &pb.CheckPermissionResponse{Code: 0, Message: "success", Allowed: allowed}, nil
+}
+
+// ListUserPermissions 获取用户权限列表
+func (s *GRPCServer) ListUserPermissions(ctx context.Context, req *pb.ListUserPermissionsRequest) (*pb.ListUserPermissionsResponse, error) {
+	u, err := s.app.GetUserInfo(ctx, req.UserId)
+	if err != nil {
+		return &pb.ListUserPermissionsResponse{Code: 1, Message: err.Error()}, nil
+	}
+	perms, err := s.app.GetUserPermissions(ctx, req.UserId)
+	if err != nil {
+		return &pb.ListUserPermissionsResponse{Code: 1, Message: err.Error()}, nil
+	}
+	return &pb.ListUserPermissionsResponse{Code: 0, Message: "success", Role: u.Role, Permissions: perms}, nil
+}
+
+// UpdateRole 更新角色设置
+func (s *GRPCServer) UpdateRole(ctx context.Context, req *pb.UpdateRoleRequest) (*pb.UpdateRoleResponse, error) {
+	if err := s.app.SetRoleRequireTwoFactor(ctx, req.Code, req.RequireTwoFactor); err != nil {
+		return &pb.UpdateRoleResponse{Code: 1, Message: err.Error()}, nil
+	}
+	return &pb.UpdateRoleResponse{Code: 0, Message: "success"}, nil
+}
+
+// AdminCreateUser 管理端创建用户
+func (s *GRPCServer) AdminCreateUser(ctx context.Context, req *pb.AdminCreateUserRequest) (*pb.AdminCreateUserResponse, error) {
+	u, err := s.app.AdminCreateUser(ctx, req.Username, req.Email, req.Password, req.Role, req.Avatar, int(req.Status))
+	if err != nil {
+		return &pb.AdminCreateUserResponse{Code: 1, Message: err.Error()}, nil
+	}
+	return &pb.AdminCreateUserResponse{Code: 0, Message: "success", Data: toPBUser(u)}, nil
+}
+
+// DeleteUser 删除用户
+func (s *GRPCServer) DeleteUser(ctx context.Context, req *pb.DeleteUserRequest) (*pb.DeleteUserResponse, error) {
+	if err := s.app.DeleteUser(ctx, req.UserId, req.Hard); err != nil {
+		return &pb.DeleteUserResponse{Code: 1, Message: err.Error()}, nil
+	}
+	return &pb.DeleteUserResponse{Code: 0, Message: "success"}, nil
+}
+
+// AdminResetPassword 强制重置密码
+func (s *GRPCServer) AdminResetPassword(ctx context.Context, req *pb.AdminResetPasswordRequest) (*pb.AdminResetPasswordResponse, error) {
+	if err := s.app.AdminResetPassword(ctx, req.UserId, req.NewPassword); err != nil {
+		return &pb.AdminResetPasswordResponse{Code: 1, Message: err.Error()}, nil
+	}
+	return &pb.AdminResetPasswordResponse{Code: 0, Message: "success"}, nil
+}
+
+// toPBUser 领域用户转换为 pb
+func toPBUser(u *domain.User) *pb.User {
+	avatar := ""
+	if u.Avatar != nil && u.Avatar.Valid {
+		avatar = u.Avatar.String
+	}
+	return &pb.User{
+		Id:        u.ID,
+		Username:  u.Username,
+		Email:     u.Email,
+		Role:      u.Role,
+		Avatar:    avatar,
+		Status:    int32(u.Status),
+		CreatedAt: u.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: u.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -5,7 +5,11 @@ import (
 	"blog-system/common/pkg/errcode"
 	"blog-system/common/pkg/logger"
 	"blog-system/services/user/application"
+	"blog-system/services/user/domain"
+	"crypto/subtle"
+	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/CoucouMonEcho/go-framework/web"
@@ -14,6 +18,9 @@ import (
 	webprom "github.com/CoucouMonEcho/go-framework/web/middlewares/prometheus"
 )
 
+// oauthStateCookie 授权发起时写入的 state Cookie
+const oauthStateCookie = "oauth_state"
+
 // HTTPServer HTTP 服务器
 type HTTPServer struct {
 	userService *application.UserAppService
@@ -44,6 +51,17 @@ func (s *HTTPServer) registerRoutes() {
 	s.server.Get("/api/info/:user_id", s.GetUserInfo)
 	s.server.Post("/api/update", s.UpdateUserInfo)
 	s.server.Post("/api/password", s.ChangePassword)
+	// 两步验证
+	s.server.Post("/api/login/2fa", s.LoginTwoFactor)
+	s.server.Post("/api/2fa/enroll", s.EnrollTOTP)
+	s.server.Post("/api/2fa/confirm", s.ConfirmTOTP)
+	s.server.Post("/api/2fa/disable", s.DisableTOTP)
+	s.server.Post("/api/2fa/recovery_codes", s.RegenerateRecoveryCodes)
+	// 第三方登录
+	s.server.Get("/api/oauth/providers", s.OAuthProviders)
+	s.server.Get("/api/oauth/:provider/authorize", s.OAuthAuthorize)
+	s.server.Get("/api/oauth/:provider/callback", s.OAuthCallback)
+	s.server.Get("/api/identities", s.ListIdentities)
 }
 
 // HealthCheck 健康检查
@@ -66,16 +84,139 @@ func (s *HTTPServer) Login(ctx *web.Context) {
 		return
 	}
 	user, token, err := s.userService.Login(ctx.Req.Context(), req.Username, req.Password)
+	if s.respChallenge(ctx, err) {
+		return
+	}
 	if err != nil {
 		_ = ctx.RespJSON(http.StatusUnauthorized, dto.Error(errcode.ErrPasswordInvalid, err.Error()))
 		return
 	}
+	s.respLogin(ctx, user, token)
+}
+
+// respChallenge 登录需两步验证时输出挑战令牌
+func (s *HTTPServer) respChallenge(ctx *web.Context, err error) bool {
+	var challenge *domain.TwoFactorChallenge
+	if !errors.As(err, &challenge) {
+		return false
+	}
+	_ = ctx.RespJSONOK(dto.Success(map[string]any{
+		"two_factor_required": true,
+		"challenge_token":     challenge.Token,
+	}))
+	return true
+}
+
+// LoginTwoFactor 两步验证登录（挑战令牌 + 动态码或恢复码）
+func (s *HTTPServer) LoginTwoFactor(ctx *web.Context) {
+	var req struct {
+		ChallengeToken string `json:"challenge_token" binding:"required"`
+		Code           string `json:"code" binding:"required"`
+	}
+	if err := ctx.BindJSON(&req); err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	user, token, err := s.userService.LoginTwoFactor(ctx.Req.Context(), req.ChallengeToken, req.Code)
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusUnauthorized, dto.Error(errcode.ErrTwoFactorInvalid, err.Error()))
+		return
+	}
+	s.respLogin(ctx, user, token)
+}
+
+// respLogin 输出登录结果，角色强制两步验证而未绑定时提示完成绑定
+func (s *HTTPServer) respLogin(ctx *web.Context, user *domain.User, token string) {
+	_ = ctx.RespJSONOK(dto.Success(map[string]any{
+		"token":                     token,
+		"user":                      user,
+		"two_factor_setup_required": s.userService.TwoFactorSetupRequired(ctx.Req.Context(), user),
+	}))
+}
+
+// EnrollTOTP 生成两步验证密钥（需再调用 confirm 启用）
+func (s *HTTPServer) EnrollTOTP(ctx *web.Context) {
+	userID := currentUserID(ctx)
+	if userID == 0 {
+		_ = ctx.RespJSON(http.StatusUnauthorized, dto.Error(errcode.ErrUnauthorized, "未认证或无效的用户"))
+		return
+	}
+	secret, uri, err := s.userService.EnrollTOTP(ctx.Req.Context(), userID)
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
 	_ = ctx.RespJSONOK(dto.Success(map[string]any{
-		"token": token,
-		"user":  user,
+		"secret":      secret,
+		"otpauth_uri": uri,
 	}))
 }
 
+// ConfirmTOTP 校验动态码并启用两步验证，返回恢复码（仅展示一次）
+func (s *HTTPServer) ConfirmTOTP(ctx *web.Context) {
+	userID := currentUserID(ctx)
+	if userID == 0 {
+		_ = ctx.RespJSON(http.StatusUnauthorized, dto.Error(errcode.ErrUnauthorized, "未认证或无效的用户"))
+		return
+	}
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := ctx.BindJSON(&req); err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	codes, err := s.userService.ConfirmTOTP(ctx.Req.Context(), userID, req.Code)
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrTwoFactorInvalid, err.Error()))
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(map[string]any{"recovery_codes": codes}))
+}
+
+// DisableTOTP 关闭两步验证（需动态码或恢复码）
+func (s *HTTPServer) DisableTOTP(ctx *web.Context) {
+	userID := currentUserID(ctx)
+	if userID == 0 {
+		_ = ctx.RespJSON(http.StatusUnauthorized, dto.Error(errcode.ErrUnauthorized, "未认证或无效的用户"))
+		return
+	}
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := ctx.BindJSON(&req); err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	if err := s.userService.DisableTOTP(ctx.Req.Context(), userID, req.Code); err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrTwoFactorInvalid, err.Error()))
+		return
+	}
+	_ = ctx.RespJSONOK(dto.SuccessNil())
+}
+
+// RegenerateRecoveryCodes 重新生成恢复码（旧恢复码全部失效）
+func (s *HTTPServer) RegenerateRecoveryCodes(ctx *web.Context) {
+	userID := currentUserID(ctx)
+	if userID == 0 {
+		_ = ctx.RespJSON(http.StatusUnauthorized, dto.Error(errcode.ErrUnauthorized, "未认证或无效的用户"))
+		return
+	}
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := ctx.BindJSON(&req); err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	codes, err := s.userService.RegenerateRecoveryCodes(ctx.Req.Context(), userID, req.Code)
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrTwoFactorInvalid, err.Error()))
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(map[string]any{"recovery_codes": codes}))
+}
+
 // GetUserInfo 获取用户信息
 func (s *HTTPServer) GetUserInfo(ctx *web.Context) {
 	userID, err := ctx.PathValue("user_id").AsInt64()
@@ -93,12 +234,7 @@ func (s *HTTPServer) GetUserInfo(ctx *web.Context) {
 
 // UpdateUserInfo 更新用户信息（使用 JWT 中的 user_id）
 func (s *HTTPServer) UpdateUserInfo(ctx *web.Context) {
-	var userID int64
-	if v, ok := ctx.UserValues["user_id"]; ok {
-		if id, ok2 := v.(int64); ok2 {
-			userID = id
-		}
-	}
+	userID := currentUserID(ctx)
 	if userID == 0 {
 		_ = ctx.RespJSON(http.StatusUnauthorized, dto.Error(errcode.ErrUnauthorized, "未认证或无效的用户"))
 		return
@@ -132,12 +268,7 @@ func (s *HTTPServer) UpdateUserInfo(ctx *web.Context) {
 
 // ChangePassword 修改密码（使用 JWT 中的 user_id）
 func (s *HTTPServer) ChangePassword(ctx *web.Context) {
-	var userID int64
-	if v, ok := ctx.UserValues["user_id"]; ok {
-		if id, ok2 := v.(int64); ok2 {
-			userID = id
-		}
-	}
+	userID := currentUserID(ctx)
 	if userID == 0 {
 		_ = ctx.RespJSON(http.StatusUnauthorized, dto.Error(errcode.ErrUnauthorized, "未认证或无效的用户"))
 		return
@@ -158,6 +289,96 @@ func (s *HTTPServer) ChangePassword(ctx *web.Context) {
 	_ = ctx.RespJSONOK(dto.SuccessNil())
 }
 
+// OAuthProviders 已启用的第三方登录方式
+func (s *HTTPServer) OAuthProviders(ctx *web.Context) {
+	_ = ctx.RespJSONOK(dto.Success(s.userService.OAuthProviders()))
+}
+
+// OAuthAuthorize 获取第三方授权地址（前端跳转）
+func (s *HTTPServer) OAuthAuthorize(ctx *web.Context) {
+	provider, err := ctx.PathValue("provider").String()
+	if err != nil || provider == "" {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "provider 不合法"))
+		return
+	}
+	authURL, state, err := s.userService.OAuthAuthorize(ctx.Req.Context(), provider)
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, err.Error()))
+		return
+	}
+	// state 同时写入 HttpOnly Cookie，回调时比对，确保回调由发起授权的同一浏览器完成（防登录 CSRF）
+	ctx.SetCookie(&http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state,
+		Path:     "/",
+		MaxAge:   int(application.OAuthStateTTL / time.Second),
+		HttpOnly: true,
+		Secure:   ctx.Req.TLS != nil || ctx.Req.Header.Get("X-Forwarded-Proto") == "https",
+		SameSite: http.SameSiteLaxMode, // 提供方重定向回来是跨站顶层导航，Strict 不会携带
+	})
+	_ = ctx.RespJSONOK(dto.Success(map[string]any{
+		"authorize_url": authURL,
+		"state":         state,
+	}))
+}
+
+// OAuthCallback 第三方授权回调，成功后签发令牌
+func (s *HTTPServer) OAuthCallback(ctx *web.Context) {
+	provider, err := ctx.PathValue("provider").String()
+	if err != nil || provider == "" {
+		_ = ctx.RespJSON(http.StatusBadRequest, dto.Error(errcode.ErrParam, "provider 不合法"))
+		return
+	}
+	q := ctx.Req.URL.Query()
+	if e := q.Get("error"); e != "" {
+		_ = ctx.RespJSON(http.StatusUnauthorized, dto.Error(errcode.ErrUnauthorized, "授权被拒绝: "+e))
+		return
+	}
+	state := q.Get("state")
+	ck, err := ctx.Req.Cookie(oauthStateCookie)
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(ck.Value), []byte(state)) != 1 {
+		logger.Log().Warn("httpserver: oauth state 与 Cookie 不一致: provider=%s", provider)
+		_ = ctx.RespJSON(http.StatusUnauthorized, dto.Error(errcode.ErrUnauthorized, "state 无效或已过期"))
+		return
+	}
+	ctx.SetCookie(&http.Cookie{Name: oauthStateCookie, Value: "", Path: "/", MaxAge: -1, HttpOnly: true, SameSite: http.SameSiteLaxMode})
+	user, token, err := s.userService.OAuthCallback(ctx.Req.Context(), provider, state, q.Get("code"))
+	if s.respChallenge(ctx, err) {
+		return
+	}
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusUnauthorized, dto.Error(errcode.ErrUnauthorized, err.Error()))
+		return
+	}
+	s.respLogin(ctx, user, token)
+}
+
+// ListIdentities 当前用户已绑定的第三方身份
+func (s *HTTPServer) ListIdentities(ctx *web.Context) {
+	userID := currentUserID(ctx)
+	if userID == 0 {
+		_ = ctx.RespJSON(http.StatusUnauthorized, dto.Error(errcode.ErrUnauthorized, "未认证或无效的用户"))
+		return
+	}
+	list, err := s.userService.ListIdentities(ctx.Req.Context(), userID)
+	if err != nil {
+		_ = ctx.RespJSON(http.StatusInternalServerError, dto.Error(errcode.ErrInternal, err.Error()))
+		return
+	}
+	_ = ctx.RespJSONOK(dto.Success(list))
+}
+
+// currentUserID 当前登录用户ID（优先上下文，其次网关透传的 X-User-ID）
+func currentUserID(ctx *web.Context) int64 {
+	if v, ok := ctx.UserValues["user_id"]; ok {
+		if id, ok2 := v.(int64); ok2 {
+			return id
+		}
+	}
+	id, _ := strconv.ParseInt(ctx.Req.Header.Get("X-User-ID"), 10, 64)
+	return id
+}
+
 // Run 启动服务器
 func (s *HTTPServer) Run(addr string) error {
 	return s.server.Start(addr)
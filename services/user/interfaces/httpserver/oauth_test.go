@@ -0,0 +1,316 @@
+package httpserver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	conf "blog-system/common/pkg/config"
+	"blog-system/services/user/application"
+	"blog-system/services/user/domain"
+	"blog-system/services/user/infrastructure/oauth"
+
+	"github.com/CoucouMonEcho/go-framework/cache"
+	"github.com/CoucouMonEcho/go-framework/web"
+)
+
+var errNotFound = errors.New("not found")
+
+// memUsers 内存用户仓储（仅实现 OAuth 流程用到的方法）
+type memUsers struct {
+	domain.UserRepository
+	mu    sync.Mutex
+	users []*domain.User
+}
+
+func (r *memUsers) Create(_ context.Context, u *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u.ID = int64(len(r.users) + 1)
+	r.users = append(r.users, u)
+	return nil
+}
+
+func (r *memUsers) find(match func(*domain.User) bool) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, u := range r.users {
+		if match(u) {
+			cp := *u
+			return &cp, nil
+		}
+	}
+	return nil, errNotFound
+}
+
+func (r *memUsers) FindByID(_ context.Context, id int64) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool { return u.ID == id })
+}
+
+func (r *memUsers) FindByUsername(_ context.Context, name string) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool { return u.Username == name })
+}
+
+func (r *memUsers) FindByEmail(_ context.Context, email string) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool { return u.Email == email })
+}
+
+type memRoles struct{ domain.RoleRepository }
+
+func (memRoles) ListPermissionsByRole(context.Context, string) ([]string, error) { return nil, nil }
+
+func (memRoles) FindRoleByCode(_ context.Context, code string) (*domain.Role, error) {
+	return &domain.Role{Code: code}, nil
+}
+
+type memIdentities struct {
+	mu   sync.Mutex
+	list []*domain.UserIdentity
+}
+
+func (r *memIdentities) FindIdentity(_ context.Context, provider, subject string) (*domain.UserIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, i := range r.list {
+		if i.Provider == provider && i.Subject == subject {
+			return i, nil
+		}
+	}
+	return nil, errNotFound
+}
+
+func (r *memIdentities) CreateIdentity(_ context.Context, i *domain.UserIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.list = append(r.list, i)
+	return nil
+}
+
+func (r *memIdentities) ListIdentitiesByUser(_ context.Context, userID int64) ([]*domain.UserIdentity, error) {
+	var out []*domain.UserIdentity
+	for _, i := range r.list {
+		if i.UserID == userID {
+			out = append(out, i)
+		}
+	}
+	return out, nil
+}
+
+// fakeIdP 模拟 OIDC 提供方：authorize 记录 code_challenge，token 端点校验 PKCE，userinfo 返回当前资料
+type fakeIdP struct {
+	*httptest.Server
+	mu         sync.Mutex
+	challenges map[string]string // code -> code_challenge
+	profile    map[string]any
+}
+
+func newFakeIdP(t *testing.T) *fakeIdP {
+	p := &fakeIdP{challenges: map[string]string{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		p.mu.Lock()
+		challenge, ok := p.challenges[r.PostForm.Get("code")]
+		delete(p.challenges, r.PostForm.Get("code"))
+		p.mu.Unlock()
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+			_ = json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{"access_token": "at-" + r.PostForm.Get("code")})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer at-") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(p.profile)
+	})
+	p.Server = httptest.NewServer(mux)
+	t.Cleanup(p.Close)
+	return p
+}
+
+// authorize 模拟用户在提供方同意授权，返回回调参数 code
+func (p *fakeIdP) authorize(t *testing.T, authURL string) string {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("code_challenge_method") != "S256" || q.Get("state") == "" {
+		t.Fatalf("authorize url = %s", authURL)
+	}
+	code := "code-" + q.Get("state")[:8]
+	p.mu.Lock()
+	p.challenges[code] = q.Get("code_challenge")
+	p.mu.Unlock()
+	return code
+}
+
+type oauthFixture struct {
+	srv        *HTTPServer
+	idp        *fakeIdP
+	users      *memUsers
+	identities *memIdentities
+}
+
+func newOAuthFixture(t *testing.T) *oauthFixture {
+	idp := newFakeIdP(t)
+	provider, err := oauth.NewProvider(conf.OAuthProviderConfig{
+		Name: "fake", Kind: oauth.KindOIDC, ClientID: "cid", ClientSecret: "secret",
+		RedirectURL: "http://blog.test/api/user/oauth/fake/callback",
+		AuthURL:     idp.URL + "/authorize", TokenURL: idp.URL + "/token", UserInfoURL: idp.URL + "/userinfo",
+	}, idp.Client())
+	if err != nil {
+		t.Fatal(err)
+	}
+	users, identities := &memUsers{}, &memIdentities{}
+	svc := application.NewUserService(users, memRoles{}, identities,
+		map[string]domain.OAuthProvider{"fake": provider}, cache.NewBuildInMapCache(time.Minute))
+	// 不挂 NewHTTPServer 的监控中间件：Prometheus 指标全局只能注册一次
+	srv := &HTTPServer{userService: svc, server: web.NewHTTPServer()}
+	srv.registerRoutes()
+	return &oauthFixture{srv: srv, idp: idp, users: users, identities: identities}
+}
+
+type apiResp struct {
+	Code int `json:"code"`
+	Data struct {
+		AuthorizeURL string       `json:"authorize_url"`
+		State        string       `json:"state"`
+		Token        string       `json:"token"`
+		User         *domain.User `json:"user"`
+	} `json:"data"`
+}
+
+func (f *oauthFixture) do(t *testing.T, target string, cookies ...*http.Cookie) (*httptest.ResponseRecorder, apiResp) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for _, c := range cookies {
+		req.AddCookie(c)
+	}
+	rec := httptest.NewRecorder()
+	f.srv.server.ServeHTTP(rec, req)
+	var out apiResp
+	_ = json.Unmarshal(rec.Body.Bytes(), &out)
+	return rec, out
+}
+
+// login 发起授权 → 提供方同意 → 回调，返回回调响应
+func (f *oauthFixture) login(t *testing.T) (*httptest.ResponseRecorder, apiResp) {
+	t.Helper()
+	rec, auth := f.do(t, "/api/oauth/fake/authorize")
+	if rec.Code != http.StatusOK || auth.Data.AuthorizeURL == "" {
+		t.Fatalf("authorize: status=%d body=%s", rec.Code, rec.Body.String())
+	}
+	cookie := stateCookie(t, rec)
+	code := f.idp.authorize(t, auth.Data.AuthorizeURL)
+	return f.do(t, "/api/oauth/fake/callback?code="+code+"&state="+url.QueryEscape(auth.Data.State), cookie)
+}
+
+func stateCookie(t *testing.T, rec *httptest.ResponseRecorder) *http.Cookie {
+	t.Helper()
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == oauthStateCookie {
+			if !c.HttpOnly || c.SameSite != http.SameSiteLaxMode {
+				t.Fatalf("state cookie attributes: %+v", c)
+			}
+			return c
+		}
+	}
+	t.Fatal("state cookie not set")
+	return nil
+}
+
+func TestOAuthCallbackCreatesUser(t *testing.T) {
+	f := newOAuthFixture(t)
+	f.idp.profile = map[string]any{"sub": "s-1", "email": "new@example.com", "email_verified": true, "preferred_username": "newbie"}
+
+	rec, resp := f.login(t)
+	if rec.Code != http.StatusOK || resp.Data.Token == "" {
+		t.Fatalf("callback: status=%d body=%s", rec.Code, rec.Body.String())
+	}
+	if len(f.users.users) != 1 || f.users.users[0].Role != "user" || f.users.users[0].Username != "newbie" {
+		t.Fatalf("users = %+v", f.users.users)
+	}
+	if len(f.identities.list) != 1 || f.identities.list[0].UserID != f.users.users[0].ID {
+		t.Fatalf("identities = %+v", f.identities.list)
+	}
+
+	// 已绑定身份再次登录不重复创建
+	if rec, _ := f.login(t); rec.Code != http.StatusOK {
+		t.Fatalf("second login: status=%d body=%s", rec.Code, rec.Body.String())
+	}
+	if len(f.users.users) != 1 || len(f.identities.list) != 1 {
+		t.Fatalf("users=%d identities=%d", len(f.users.users), len(f.identities.list))
+	}
+}
+
+func TestOAuthCallbackBindsVerifiedEmail(t *testing.T) {
+	f := newOAuthFixture(t)
+	_ = f.users.Create(context.Background(), &domain.User{Username: "alice", Email: "alice@example.com", Role: "editor"})
+	f.idp.profile = map[string]any{"sub": "s-2", "email": "alice@example.com", "email_verified": true}
+
+	rec, resp := f.login(t)
+	if rec.Code != http.StatusOK || resp.Data.User == nil || resp.Data.User.Username != "alice" {
+		t.Fatalf("callback: status=%d body=%s", rec.Code, rec.Body.String())
+	}
+	if len(f.users.users) != 1 || len(f.identities.list) != 1 || f.identities.list[0].UserID != 1 {
+		t.Fatalf("users=%d identities=%+v", len(f.users.users), f.identities.list)
+	}
+}
+
+func TestOAuthCallbackRejectsUnverifiedEmail(t *testing.T) {
+	f := newOAuthFixture(t)
+	_ = f.users.Create(context.Background(), &domain.User{Username: "alice", Email: "alice@example.com", Role: "user"})
+	f.idp.profile = map[string]any{"sub": "s-3", "email": "alice@example.com", "email_verified": false}
+
+	if rec, _ := f.login(t); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("status=%d body=%s", rec.Code, rec.Body.String())
+	}
+	if len(f.identities.list) != 0 {
+		t.Fatalf("identities = %+v", f.identities.list)
+	}
+}
+
+func TestOAuthCallbackRequiresStateCookie(t *testing.T) {
+	f := newOAuthFixture(t)
+	f.idp.profile = map[string]any{"sub": "s-4", "email": "bob@example.com", "email_verified": true}
+
+	rec, auth := f.do(t, "/api/oauth/fake/authorize")
+	cookie := stateCookie(t, rec)
+	code := f.idp.authorize(t, auth.Data.AuthorizeURL)
+	callback := "/api/oauth/fake/callback?code=" + code + "&state=" + url.QueryEscape(auth.Data.State)
+
+	// 攻击者把自己的回调链接发给受害者：受害者浏览器没有对应 Cookie
+	if rec, _ := f.do(t, callback); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("without cookie: status=%d", rec.Code)
+	}
+	if rec, _ := f.do(t, callback, &http.Cookie{Name: oauthStateCookie, Value: "other"}); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("mismatched cookie: status=%d", rec.Code)
+	}
+	if len(f.users.users) != 0 {
+		t.Fatalf("users = %+v", f.users.users)
+	}
+	// 被拒绝的回调不消费 state，原浏览器仍可完成登录；state 仅可使用一次
+	if rec, _ := f.do(t, callback, cookie); rec.Code != http.StatusOK {
+		t.Fatalf("with cookie: status=%d body=%s", rec.Code, rec.Body.String())
+	}
+	if rec, _ := f.do(t, callback, cookie); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("replayed state: status=%d", rec.Code)
+	}
+}
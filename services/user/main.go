@@ -9,6 +9,7 @@ import (
 	conf "blog-system/common/pkg/config"
 	"blog-system/services/user/application"
 	infra "blog-system/services/user/infrastructure"
+	"blog-system/services/user/infrastructure/oauth"
 	persistence "blog-system/services/user/infrastructure/persistence"
 	grpcapi "blog-system/services/user/interfaces/grpcserver"
 	httpapi "blog-system/services/user/interfaces/httpserver"
@@ -45,10 +46,16 @@ func main() {
 
 	// 初始化仓储层
 	userRepo := persistence.NewUserRepository(db)
+	roleRepo := persistence.NewRoleRepository(db)
+	identityRepo := persistence.NewIdentityRepository(db)
 	logger.Log().Info("main: 用户仓储层初始化完成")
 
+	// 第三方登录提供方
+	providers := oauth.NewProviders(cfg.OAuth.Providers)
+	logger.Log().Info("main: 第三方登录提供方: %d 个", len(providers))
+
 	// 初始化应用服务
-	userService := application.NewUserService(userRepo, cache)
+	userService := application.NewUserService(userRepo, roleRepo, identityRepo, providers, cache)
 	logger.Log().Info("main: 用户应用服务初始化完成")
 
 	// 启动 HTTP 服务